@@ -0,0 +1,42 @@
+package go_cache
+
+import "time"
+
+// DeleteOlderThan Deletes the live items whose value was written longer than the given duration ago, whatever
+// their expiration time, e.g. values cached before a change of their schema, and returns the number of items
+// deleted. See DeleteCreatedBefore.
+func (c *Cache) DeleteOlderThan(age time.Duration) int {
+	return c.DeleteCreatedBefore(c.now().Add(-age))
+}
+
+// DeleteCreatedBefore Deletes the live items whose value was written before the given time, whatever their
+// expiration time, and returns the number of items deleted. Deleted items count as such in Stats, and their
+// callbacks set WithItemOnEvict are called. The cache is scanned in chunks like DeleteIdle, and items rewritten
+// between the scan and their deletion are kept.
+func (c *Cache) DeleteCreatedBefore(t time.Time) int {
+	cutoff := t.UnixNano()
+	var keys []string
+	c.forEachChunked(func(key string, item item) bool {
+		if item.created < cutoff {
+			keys = append(keys, key)
+		}
+		return true
+	})
+
+	deleted := 0
+	for start := 0; start < len(keys); start += iterationChunkSize {
+		end := min(start+iterationChunkSize, len(keys))
+
+		c.mu.Lock()
+		now := c.now().UnixNano()
+		for _, key := range keys[start:end] {
+			if item, found := c.get(key, now); found && item.created < cutoff {
+				c.delete(key, removalDeleted)
+				deleted++
+			}
+		}
+		c.unlock()
+	}
+
+	return deleted
+}
@@ -0,0 +1,81 @@
+package go_cache
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_DeleteOlderThan(t *testing.T) {
+	t.Run("cutoff", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		var evicted evictions
+		tc.SetWithOptions("aKey", "aValue", WithItemOnEvict(evicted.onEvict))
+		tc.Set("bKey", "bValue", 2*time.Hour)
+		clock.Advance(30 * time.Minute)
+		tc.Set("cKey", "cValue", 2*time.Hour)
+		clock.Advance(time.Minute)
+		tc.Set("dKey", "dValue", time.Hour)
+		tc.Set("aKey", "aValue2", NoExpiration)
+		clock.Advance(time.Minute)
+
+		assert.Equal(t, 0, tc.DeleteOlderThan(time.Hour))
+		assert.Equal(t, 2, tc.DeleteOlderThan(90*time.Second))
+		for _, key := range []string{"aKey", "dKey"} {
+			_, found := tc.Get(key)
+			assert.True(t, found, key)
+		}
+		for _, key := range []string{"bKey", "cKey"} {
+			_, found := tc.Get(key)
+			assert.False(t, found, key)
+		}
+		assert.Equal(t, []any{"aValue"}, evicted.get())
+		assert.Equal(t, uint64(2), tc.Stats().Removals.Deleted)
+
+		// The survivors keep their expiration time.
+		info, _ := tc.Info("dKey")
+		assert.True(t, clock.Now().Add(59*time.Minute).Equal(info.Expiration))
+		clock.Advance(59 * time.Minute)
+		_, found := tc.Get("dKey")
+		assert.False(t, found)
+		_, found = tc.Get("aKey")
+		assert.True(t, found)
+	})
+
+	t.Run("expiredNotCounted", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", time.Second)
+		tc.SetNegative("bKey", NoExpiration)
+		clock.Advance(time.Minute)
+
+		assert.Equal(t, 0, tc.DeleteOlderThan(0))
+		assert.Equal(t, uint64(0), tc.Stats().Removals.Deleted)
+	})
+
+	t.Run("chunks", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		n := 2*iterationChunkSize + 1
+		for i := range n {
+			tc.Set("old"+strconv.Itoa(i), i, NoExpiration)
+		}
+		clock.Advance(time.Second)
+		tc.Set("new", 0, NoExpiration)
+
+		assert.Equal(t, n, tc.DeleteCreatedBefore(clock.Now()))
+		assert.Equal(t, 1, tc.ItemCount())
+	})
+}
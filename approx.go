@@ -0,0 +1,118 @@
+package go_cache
+
+import (
+	"math"
+	"time"
+)
+
+// ApproxReport Describes the items of the cache as extrapolated from a sample of them, see ApproxStats.
+type ApproxReport struct {
+	// Total Number of items held by the cache, exact.
+	Total int
+	// Sampled Number of items the report was computed from.
+	Sampled int
+	// Exact Whether the sample covers the whole cache, in which case the report is exact.
+	Exact bool
+
+	// LiveRatio Share of the items which have not expired, and Live their extrapolated number.
+	LiveRatio float64
+	Live      int
+	// ExpiredRatio Share of the items which have expired but have not been deleted yet, and Expired their
+	// extrapolated number.
+	ExpiredRatio float64
+	Expired      int
+	// NoExpirationRatio Share of the items which never expire.
+	NoExpirationRatio float64
+	// RatioStdErr Standard error of the ratios, the largest it can be for the sample size. The actual ratios are
+	// within two standard errors of the reported ones about 95% of the time.
+	RatioStdErr float64
+
+	// MeanTTL Mean remaining time to live of the sampled live items which expire, 0 if there are none.
+	MeanTTL time.Duration
+	// MeanSize Mean estimated size of the sampled values, as computed by the sizer of the cache (see WithSizer),
+	// and EstimatedSize the extrapolated total size of the values.
+	MeanSize      float64
+	EstimatedSize int64
+}
+
+// ApproxStats Returns a report on the items of the cache extrapolated from about sampleSize of them, for caches
+// too large for Counts to be called often. Items are sampled in the random order in which Go iterates maps, by
+// chunks each read under its own read lock, so the cost of the report depends on the sample size only. Items
+// may be sampled more than once, which does not bias the report. Caches holding at most sampleSize items are
+// scanned whole, in which case the report is exact. A sample size less than one only reports Total.
+func (c *Cache) ApproxStats(sampleSize int) ApproxReport {
+	var report ApproxReport
+	if sampleSize < 1 {
+		report.Total = c.ItemCount()
+		return report
+	}
+
+	var sample []entry
+	var now int64
+	for len(sample) < sampleSize {
+		chunk := min(sampleSize-len(sample), iterationChunkSize)
+
+		c.mu.RLock()
+		report.Total = len(c.items)
+		if report.Total <= sampleSize {
+			// Small enough to be read whole, under a single lock so that no item is read twice.
+			sample = make([]entry, 0, report.Total)
+			chunk = report.Total
+		}
+		now = c.now().UnixNano()
+		// Each range starts from a random position in the map.
+		for key, it := range c.items {
+			if chunk--; chunk < 0 {
+				break
+			}
+			sample = append(sample, entry{key: key, item: it.promote(now)})
+		}
+		c.mu.RUnlock()
+
+		if report.Total <= sampleSize {
+			report.Exact = true
+			break
+		}
+	}
+
+	report.Sampled = len(sample)
+	if report.Sampled == 0 {
+		return report
+	}
+
+	var live, expired, noExpiration, expiring int
+	var ttl time.Duration
+	var size int64
+	for _, e := range sample {
+		switch {
+		case e.item.placeholder:
+		case e.item.isExpired(now):
+			expired++
+		case e.item.expiration == 0:
+			live++
+			noExpiration++
+		default:
+			live++
+			expiring++
+			ttl += time.Duration(e.item.expiration - now)
+		}
+		size += c.sizer(e.key, e.item.object)
+	}
+
+	n := float64(report.Sampled)
+	report.LiveRatio = float64(live) / n
+	report.ExpiredRatio = float64(expired) / n
+	report.NoExpirationRatio = float64(noExpiration) / n
+	report.Live = int(math.Round(report.LiveRatio * float64(report.Total)))
+	report.Expired = int(math.Round(report.ExpiredRatio * float64(report.Total)))
+	if !report.Exact {
+		report.RatioStdErr = math.Sqrt(0.25 / n)
+	}
+	if expiring > 0 {
+		report.MeanTTL = ttl / time.Duration(expiring)
+	}
+	report.MeanSize = float64(size) / n
+	report.EstimatedSize = int64(math.Round(report.MeanSize * float64(report.Total)))
+
+	return report
+}
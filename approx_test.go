@@ -0,0 +1,80 @@
+package go_cache
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_ApproxStats(t *testing.T) {
+	// newSampledCache Returns a cache of 10,000 values of 100 bytes, 30% of which have expired, 35% of which never
+	// expire and 35% of which expire in an hour.
+	newSampledCache := func() *Cache {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+
+		value := strings.Repeat("v", 100)
+		for i := 0; i < 10_000; i++ {
+			switch {
+			case i < 3_000:
+				tc.Set(strconv.Itoa(i), value, time.Second)
+			case i < 6_500:
+				tc.Set(strconv.Itoa(i), value, NoExpiration)
+			default:
+				tc.Set(strconv.Itoa(i), value, time.Hour+time.Second)
+			}
+		}
+		clock.Advance(time.Second)
+		return tc
+	}
+
+	t.Run("sampled", func(t *testing.T) {
+		tc := newSampledCache()
+		defer tc.Stop()
+
+		report := tc.ApproxStats(1_000)
+		assert.Equal(t, 10_000, report.Total)
+		assert.Equal(t, 1_000, report.Sampled)
+		assert.False(t, report.Exact)
+		assert.InDelta(t, 0.0158, report.RatioStdErr, 0.0001)
+
+		// Four standard errors keep the test from failing on an unlucky sample.
+		tolerance := 4 * report.RatioStdErr
+		assert.InDelta(t, 0.3, report.ExpiredRatio, tolerance)
+		assert.InDelta(t, 0.7, report.LiveRatio, tolerance)
+		assert.InDelta(t, 0.35, report.NoExpirationRatio, tolerance)
+		assert.InDelta(t, 3_000, report.Expired, tolerance*10_000)
+		assert.Equal(t, time.Hour, report.MeanTTL)
+		assert.Equal(t, float64(100), report.MeanSize)
+		assert.Equal(t, int64(1_000_000), report.EstimatedSize)
+	})
+
+	t.Run("exact", func(t *testing.T) {
+		tc := newSampledCache()
+		defer tc.Stop()
+
+		report := tc.ApproxStats(20_000)
+		assert.True(t, report.Exact)
+		assert.Equal(t, 10_000, report.Sampled)
+		assert.Equal(t, 3_000, report.Expired)
+		assert.Equal(t, 7_000, report.Live)
+		assert.Equal(t, float64(0), report.RatioStdErr)
+
+		counts := tc.Counts()
+		assert.Equal(t, counts.Expired, report.Expired)
+		assert.Equal(t, counts.Live, report.Live)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		assert.Equal(t, ApproxReport{Exact: true}, tc.ApproxStats(100))
+		tc.Set("aKey", "aValue", NoExpiration)
+		assert.Equal(t, ApproxReport{Total: 1}, tc.ApproxStats(0))
+	})
+}
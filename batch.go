@@ -0,0 +1,154 @@
+package go_cache
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidKey is returned for keys rejected by the batch operations, i.e. empty keys.
+var ErrInvalidKey = errors.New("invalid key")
+
+// BatchStatus Describes the outcome of a batch operation for a single key.
+type BatchStatus int
+
+const (
+	// BatchOK The operation succeeded for the key.
+	BatchOK BatchStatus = iota
+	// BatchNotFound The key was not in the cache, which is not an error for the operation (e.g. DeleteMany).
+	BatchNotFound
+	// BatchInvalid The key was rejected before being applied.
+	BatchInvalid
+	// BatchConflict The key already held a value (e.g. AddMany).
+	BatchConflict
+	// BatchFailed The operation failed for the key for another reason, e.g. the cache is closed.
+	BatchFailed
+)
+
+// KeyResult The outcome of a batch operation for a single key. Err is set for the statuses which are failures.
+type KeyResult struct {
+	Status BatchStatus
+	Err    error
+}
+
+// BatchResult The outcome of a batch operation, by key.
+type BatchResult map[string]KeyResult
+
+// Err Returns the errors of the keys which failed joined with errors.Join, or nil if none failed.
+func (r BatchResult) Err() error {
+	var errs []error
+	for _, result := range r {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// SetMany Adds the given items to the cache like Set, all with the same duration, under a single write lock.
+// Returns the outcome for each key along with the joined errors of the keys which failed, if any.
+func (c *Cache) SetMany(items map[string]any, duration time.Duration) (BatchResult, error) {
+	result := make(BatchResult, len(items))
+
+	c.mu.Lock()
+	for key, object := range items {
+		if r, ok := c.checkBatchKey(key); !ok {
+			result[key] = r
+			continue
+		}
+		if err := c.set(c.hashKey(key), object, duration); err != nil {
+			result[key] = KeyResult{Status: BatchFailed, Err: err}
+			continue
+		}
+		result[key] = KeyResult{Status: BatchOK}
+	}
+	c.unlock()
+
+	return result, result.Err()
+}
+
+// AddMany Inserts the given items to the cache like Add, all with the same duration, under a single write lock.
+// Keys which already hold a value are reported as BatchConflict with an error wrapping ErrItemAlreadyExists.
+// Returns the outcome for each key along with the joined errors of the keys which failed, if any.
+func (c *Cache) AddMany(items map[string]any, duration time.Duration) (BatchResult, error) {
+	result := make(BatchResult, len(items))
+
+	c.mu.Lock()
+	now := c.now().UnixNano()
+	for key, object := range items {
+		if r, ok := c.checkBatchKey(key); !ok {
+			result[key] = r
+			continue
+		}
+		hashed := c.hashKey(key)
+		if _, found := c.get(hashed, now); found {
+			result[key] = KeyResult{Status: BatchConflict, Err: fmt.Errorf("%w: %s", ErrItemAlreadyExists, key)}
+			continue
+		}
+		if err := c.set(hashed, object, duration); err != nil {
+			result[key] = KeyResult{Status: BatchFailed, Err: err}
+			continue
+		}
+		result[key] = KeyResult{Status: BatchOK}
+	}
+	c.unlock()
+
+	return result, result.Err()
+}
+
+// GetMany Looks up the values of the given keys like Get, under a single read lock, and returns those found.
+func (c *Cache) GetMany(keys []string) map[string]any {
+	values := make(map[string]any, len(keys))
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.now().UnixNano()
+	for _, key := range keys {
+		if item, state := c.lookup(c.hashKey(key), now); state == LookupHit {
+			values[key] = c.value(key, item)
+		}
+	}
+
+	return values
+}
+
+// DeleteMany Removes the given keys from the cache like Delete, under a single write lock.
+// Keys which were not in the cache are reported as BatchNotFound, which is not an error.
+// Returns the outcome for each key along with the joined errors of the keys which failed, if any.
+func (c *Cache) DeleteMany(keys []string) (BatchResult, error) {
+	result := make(BatchResult, len(keys))
+
+	c.mu.Lock()
+	now := c.now().UnixNano()
+	for _, key := range keys {
+		if r, ok := c.checkBatchKey(key); !ok {
+			result[key] = r
+			continue
+		}
+		hashed := c.hashKey(key)
+		if _, found := c.get(hashed, now); !found {
+			result[key] = KeyResult{Status: BatchNotFound}
+		} else {
+			result[key] = KeyResult{Status: BatchOK}
+		}
+		c.delete(hashed, removalDeleted)
+		c.bury(hashed)
+	}
+	c.unlock()
+
+	return result, result.Err()
+}
+
+// checkBatchKey Returns the outcome of a write of the given key by a batch operation if it cannot be applied.
+func (c *Cache) checkBatchKey(key string) (KeyResult, bool) {
+	switch {
+	case key == "":
+		return KeyResult{Status: BatchInvalid, Err: fmt.Errorf("%w: empty key", ErrInvalidKey)}, false
+	case c.closed:
+		return KeyResult{Status: BatchFailed, Err: fmt.Errorf("%w: %s", ErrCacheClosed, key)}, false
+	default:
+		return KeyResult{}, true
+	}
+}
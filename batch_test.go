@@ -0,0 +1,105 @@
+package go_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_SetMany(t *testing.T) {
+	t.Run("mixedKeys", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		result, err := tc.SetMany(map[string]any{
+			"aKey": "aValue",
+			"":     "emptyKey",
+			"bKey": "bValue",
+		}, DefaultExpiration)
+
+		assert.ErrorIs(t, err, ErrInvalidKey)
+		assert.Len(t, err.(interface{ Unwrap() []error }).Unwrap(), 1)
+		assert.Equal(t, BatchOK, result["aKey"].Status)
+		assert.NoError(t, result["aKey"].Err)
+		assert.Equal(t, BatchOK, result["bKey"].Status)
+		assert.Equal(t, BatchInvalid, result[""].Status)
+		assert.ErrorIs(t, result[""].Err, ErrInvalidKey)
+
+		value, found := tc.Get("bKey")
+		assert.True(t, found)
+		assert.Equal(t, "bValue", value)
+		assert.Equal(t, 2, tc.ItemCount())
+	})
+
+	t.Run("allValid", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		result, err := tc.SetMany(map[string]any{"aKey": 1, "bKey": 2}, 1*time.Minute)
+		assert.NoError(t, err)
+		assert.NoError(t, result.Err())
+		assert.Len(t, result, 2)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		tc.Stop()
+
+		result, err := tc.SetMany(map[string]any{"aKey": 1, "": 2}, DefaultExpiration)
+		assert.ErrorIs(t, err, ErrCacheClosed)
+		assert.ErrorIs(t, err, ErrInvalidKey)
+		assert.Equal(t, BatchFailed, result["aKey"].Status)
+	})
+}
+
+func TestCache_AddMany(t *testing.T) {
+	tc := NewCache(DefaultExpiration, 0)
+	defer tc.Stop()
+
+	tc.Set("aKey", "aValue", DefaultExpiration)
+
+	result, err := tc.AddMany(map[string]any{"aKey": "other", "bKey": "bValue"}, DefaultExpiration)
+	assert.ErrorIs(t, err, ErrItemAlreadyExists)
+	assert.Equal(t, BatchConflict, result["aKey"].Status)
+	assert.Equal(t, BatchOK, result["bKey"].Status)
+
+	value, _ := tc.Get("aKey")
+	assert.Equal(t, "aValue", value)
+	value, _ = tc.Get("bKey")
+	assert.Equal(t, "bValue", value)
+}
+
+func TestCache_GetMany(t *testing.T) {
+	tc := NewCacheWithOptions(WithHashedKeys())
+	defer tc.Stop()
+
+	tc.Set("aKey", "aValue", DefaultExpiration)
+	tc.Set("bKey", "bValue", DefaultExpiration)
+
+	values := tc.GetMany([]string{"aKey", "bKey", "cKey"})
+	assert.Equal(t, map[string]any{"aKey": "aValue", "bKey": "bValue"}, values)
+	assert.Equal(t, uint64(2), tc.Stats().Hits)
+	assert.Equal(t, uint64(1), tc.Stats().Misses)
+}
+
+func TestCache_DeleteMany(t *testing.T) {
+	tc := NewCache(DefaultExpiration, 0)
+	defer tc.Stop()
+
+	tc.Set("aKey", "aValue", DefaultExpiration)
+	tc.Set("bKey", "bValue", DefaultExpiration)
+
+	result, err := tc.DeleteMany([]string{"aKey", "cKey", ""})
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	assert.Equal(t, BatchResult{
+		"aKey": {Status: BatchOK},
+		"cKey": {Status: BatchNotFound},
+		"":     result[""],
+	}, result)
+	assert.Equal(t, BatchInvalid, result[""].Status)
+
+	_, found := tc.Get("aKey")
+	assert.False(t, found)
+	assert.Equal(t, 1, tc.ItemCount())
+}
@@ -0,0 +1,167 @@
+package go_cache
+
+import (
+	"hash/maphash"
+	"math"
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+// bloomFilter A set of keys answering membership queries with false positives but no false negatives, read and
+// written without locking.
+type bloomFilter struct {
+	seed   maphash.Seed
+	words  []atomic.Uint64
+	bits   uint64
+	hashes int
+	// capacity is the number of keys the filter was sized for.
+	capacity int
+	// added counts the keys added which were not in the filter yet, as far as it could tell.
+	added atomic.Int64
+}
+
+// newBloomFilter Returns an empty filter sized to hold the given number of keys with the given false-positive rate.
+func newBloomFilter(capacity int, fpRate float64) *bloomFilter {
+	capacity = max(capacity, 1)
+	if fpRate <= 0 || fpRate >= 1 {
+		fpRate = 0.01
+	}
+
+	m := math.Ceil(-float64(capacity) * math.Log(fpRate) / (math.Ln2 * math.Ln2))
+	words := (uint64(m) + 63) / 64
+	return &bloomFilter{
+		seed:     maphash.MakeSeed(),
+		words:    make([]atomic.Uint64, words),
+		bits:     words * 64,
+		hashes:   max(int(math.Round(m/float64(capacity)*math.Ln2)), 1),
+		capacity: capacity,
+	}
+}
+
+// add Adds the key to the filter.
+func (f *bloomFilter) add(key string) {
+	set := false
+	f.probe(key, func(word *atomic.Uint64, mask uint64) bool {
+		if word.Load()&mask == 0 {
+			word.Or(mask)
+			set = true
+		}
+		return true
+	})
+	if set {
+		f.added.Add(1)
+	}
+}
+
+// mayContain Reports whether the key may have been added to the filter, false if it certainly was not.
+func (f *bloomFilter) mayContain(key string) bool {
+	found := true
+	f.probe(key, func(word *atomic.Uint64, mask uint64) bool {
+		found = word.Load()&mask != 0
+		return found
+	})
+
+	return found
+}
+
+// probe Calls fn with the bits of the key, derived from a single hash by double hashing, until it returns false.
+func (f *bloomFilter) probe(key string, fn func(word *atomic.Uint64, mask uint64) bool) {
+	h := maphash.String(f.seed, key)
+	step := bits.RotateLeft64(h, 32) | 1
+	for range f.hashes {
+		i := h % f.bits
+		if !fn(&f.words[i/64], 1<<(i%64)) {
+			return
+		}
+		h += step
+	}
+}
+
+// bloom Holds the filter of a cache created WithBloomFilter.
+type bloom struct {
+	expectedItems int
+	fpRate        float64
+	filter        atomic.Pointer[bloomFilter]
+	// next is the filter being rebuilt, if any, which writes go to as well. It is guarded by the lock of the cache.
+	next *bloomFilter
+	// rebuildMu serializes the rebuilds.
+	rebuildMu sync.Mutex
+}
+
+func newBloom(expectedItems int, fpRate float64) *bloom {
+	b := &bloom{expectedItems: expectedItems, fpRate: fpRate}
+	b.filter.Store(newBloomFilter(expectedItems, fpRate))
+
+	return b
+}
+
+// mayContain Reports whether the key may be in the cache, always true if the cache has no filter.
+func (b *bloom) mayContain(key string) bool {
+	return b == nil || b.filter.Load().mayContain(key)
+}
+
+// add Adds the key to the filter, and to the one being rebuilt if any.
+// It must be called with the write lock held.
+func (b *bloom) add(key string) {
+	b.filter.Load().add(key)
+	if b.next != nil {
+		b.next.add(key)
+	}
+}
+
+// reset Replaces the filter with an empty one, e.g. once the cache is flushed.
+// It must be called with the write lock held.
+func (b *bloom) reset() {
+	b.filter.Store(newBloomFilter(b.filter.Load().capacity, b.fpRate))
+}
+
+// full Reports whether more keys were added to the filter than it was sized for, so that it no longer holds
+// the false-positive rate it was created with.
+func (b *bloom) full() bool {
+	f := b.filter.Load()
+
+	return f.added.Load() > int64(f.capacity)
+}
+
+// inBloom Reports whether the item makes its key a member of the filter: it holds a value, possibly one not
+// visible yet, as opposed to a negative entry.
+func (i item) inBloom() bool {
+	return i.pending != nil || !i.placeholder && !i.negative
+}
+
+// RebuildBloomFilter Replaces the filter of a cache created WithBloomFilter with one holding only the keys currently
+// in the cache, dropping the keys deleted since the last rebuild, and sized for twice the number of items if this is
+// more than the expected number of items. This can be used if the cleanupInterval passed to NewCache() is set to less
+// than 1. Writers are only blocked while the keys are listed, and lookups are never blocked; the keys written
+// during the rebuild are kept.
+func (c *Cache) RebuildBloomFilter() {
+	if c.bloom == nil {
+		return
+	}
+	c.bloom.rebuildMu.Lock()
+	defer c.bloom.rebuildMu.Unlock()
+
+	// From now on the writes go to the new filter as well, so that the keys written after they are listed are kept.
+	c.mu.Lock()
+	next := newBloomFilter(max(c.bloom.expectedItems, 2*len(c.items)), c.bloom.fpRate)
+	c.bloom.next = next
+	c.mu.Unlock()
+
+	c.mu.RLock()
+	keys := make([]string, 0, len(c.items))
+	for key, item := range c.items {
+		if item.inBloom() {
+			keys = append(keys, key)
+		}
+	}
+	c.mu.RUnlock()
+	for _, key := range keys {
+		next.add(key)
+	}
+
+	c.mu.Lock()
+	c.bloom.filter.Store(next)
+	c.bloom.next = nil
+	c.mu.Unlock()
+}
@@ -0,0 +1,222 @@
+package go_cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_BloomFilter(t *testing.T) {
+	t.Run("noFalseNegatives", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithBloomFilter(1000, 0.01))
+		defer tc.Stop()
+
+		// Ten times more keys than the filter was sized for, rebuilding it along the way.
+		for i := 0; i < 10_000; i++ {
+			tc.Set(fmt.Sprintf("key%d", i), i, NoExpiration)
+			if i%2 == 0 {
+				tc.Delete(fmt.Sprintf("key%d", i))
+			}
+			if i%3000 == 0 {
+				tc.RebuildBloomFilter()
+			}
+		}
+		for i := 1; i < 10_000; i += 2 {
+			a, found := tc.Get(fmt.Sprintf("key%d", i))
+			assert.True(t, found)
+			assert.Equal(t, i, a)
+		}
+	})
+
+	t.Run("loaderNotCalledForNeverSetKeys", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithBloomFilter(1000, 0.001))
+		defer tc.Stop()
+
+		var calls atomic.Int32
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			calls.Add(1)
+			return key + "Value", DefaultExpiration, nil
+		}
+		tc.Set("aKey", "aValue", DefaultExpiration)
+
+		a, err := tc.GetOrLoad(context.Background(), "aKey", loader)
+		assert.Nil(t, err)
+		assert.Equal(t, "aValue", a)
+		for i := 0; i < 100; i++ {
+			_, err = tc.GetOrLoad(context.Background(), fmt.Sprintf("unknown%d", i), loader)
+			assert.True(t, errors.Is(err, ErrItemNotFound))
+		}
+		assert.Equal(t, int32(0), calls.Load())
+
+		// Deleted keys stay in the filter until it is rebuilt.
+		tc.Delete("aKey")
+		a, err = tc.GetOrLoad(context.Background(), "aKey", loader)
+		assert.Nil(t, err)
+		assert.Equal(t, "aKeyValue", a)
+		assert.Equal(t, int32(1), calls.Load())
+
+		tc.Delete("aKey")
+		tc.RebuildBloomFilter()
+		_, err = tc.GetOrLoad(context.Background(), "aKey", loader)
+		assert.True(t, errors.Is(err, ErrItemNotFound))
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, uint64(101+1), tc.Stats().Misses)
+	})
+
+	t.Run("fetchAndLoadMany", func(t *testing.T) {
+		var calls atomic.Int32
+		tc := NewCacheWithOptions(WithBloomFilter(1000, 0.001), WithLoader(func(ctx context.Context, key string) (any, time.Duration, error) {
+			calls.Add(1)
+			return nil, 0, ErrItemNotFound
+		}))
+		defer tc.Stop()
+		tc.Set("aKey", "aValue", DefaultExpiration)
+
+		_, err := tc.Fetch(context.Background(), "unknown")
+		assert.True(t, errors.Is(err, ErrItemNotFound))
+		values, err := tc.GetOrLoadMany(context.Background(), []string{"aKey", "unknown"}, DefaultExpiration, func(ctx context.Context, missing []string) (map[string]any, error) {
+			calls.Add(1)
+			return nil, nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]any{"aKey": "aValue"}, values)
+		assert.Equal(t, int32(0), calls.Load())
+	})
+
+	t.Run("falsePositiveRate", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithBloomFilter(10_000, 0.01))
+		defer tc.Stop()
+
+		for i := 0; i < 10_000; i++ {
+			tc.Set(fmt.Sprintf("key%d", i), i, NoExpiration)
+		}
+		positives := 0
+		for i := 0; i < 100_000; i++ {
+			if tc.bloom.mayContain(fmt.Sprintf("unknown%d", i)) {
+				positives++
+			}
+		}
+		assert.Less(t, positives, 2*1000)
+	})
+
+	t.Run("pendingValues", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithBloomFilter(1000, 0.001))
+		defer tc.Stop()
+		clock := newFakeClock()
+		tc.now = clock.Now
+
+		tc.SetVisibleAt("aKey", "aValue", clock.Now().Add(10*time.Second), DefaultExpiration)
+		tc.RebuildBloomFilter()
+		clock.Advance(10 * time.Second)
+
+		a, found := tc.Get("aKey")
+		assert.True(t, found)
+		assert.Equal(t, "aValue", a)
+	})
+
+	t.Run("negativeEntries", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithBloomFilter(1000, 0.001))
+		defer tc.Stop()
+
+		tc.SetNegative("aKey", NoExpiration)
+		assert.False(t, tc.bloom.mayContain("aKey"))
+	})
+
+	t.Run("flush", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithBloomFilter(1000, 0.001))
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		tc.Flush()
+		assert.False(t, tc.bloom.mayContain("aKey"))
+
+		tc.Set("bKey", "bValue", DefaultExpiration)
+		_, found := tc.Get("bKey")
+		assert.True(t, found)
+	})
+
+	t.Run("rebuiltByJanitor", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithBloomFilter(100, 0.01), WithCleanupInterval(time.Millisecond))
+		defer tc.Stop()
+
+		for i := 0; i < 1000; i++ {
+			tc.Set(fmt.Sprintf("key%d", i), i, NoExpiration)
+			tc.Delete(fmt.Sprintf("key%d", i))
+		}
+		tc.Set("aKey", "aValue", NoExpiration)
+
+		assert.Eventually(t, func() bool {
+			return !tc.bloom.full()
+		}, time.Second, time.Millisecond)
+		_, found := tc.Get("aKey")
+		assert.True(t, found)
+	})
+
+	t.Run("concurrentWritesDuringRebuild", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithBloomFilter(100, 0.01))
+		defer tc.Stop()
+
+		var wg sync.WaitGroup
+		for w := 0; w < 4; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 1000; i++ {
+					tc.Set(fmt.Sprintf("key%d-%d", w, i), i, NoExpiration)
+				}
+			}()
+		}
+		for i := 0; i < 10; i++ {
+			tc.RebuildBloomFilter()
+		}
+		wg.Wait()
+
+		for w := 0; w < 4; w++ {
+			for i := 0; i < 1000; i++ {
+				_, found := tc.Get(fmt.Sprintf("key%d-%d", w, i))
+				assert.True(t, found)
+			}
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.RebuildBloomFilter()
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		_, found := tc.Get("aKey")
+		assert.True(t, found)
+	})
+}
+
+func BenchmarkCache_GetMissBloomFilter(b *testing.B) {
+	for _, bench := range []struct {
+		name string
+		opts []Option
+	}{
+		{name: "withoutFilter"},
+		{name: "withFilter", opts: []Option{WithBloomFilter(10_000, 0.01)}},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			tc := NewCacheWithOptions(bench.opts...)
+			defer tc.Stop()
+			for i := 0; i < 10_000; i++ {
+				tc.Set(fmt.Sprintf("key%d", i), i, NoExpiration)
+			}
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					tc.Get("unknown")
+				}
+			})
+		})
+	}
+}
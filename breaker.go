@@ -0,0 +1,162 @@
+package go_cache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// breakerMinCalls is the number of calls to the loader in a window below which the breaker does not open, so that
+// a single failure does not stop all loads.
+const breakerMinCalls = 5
+
+// BreakerState The state of the circuit breaker of the loader, see WithLoaderBreaker.
+type BreakerState int32
+
+const (
+	// BreakerClosed The loader is called as usual.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen The loader failed too often and is not called until the cool-down period is over.
+	BreakerOpen
+	// BreakerHalfOpen The cool-down period is over and a single call probes whether the loader recovered.
+	BreakerHalfOpen
+)
+
+// String Returns the state in lower case, e.g. "half-open".
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerStats The activity of the circuit breaker of the loader, see WithLoaderBreaker.
+type BreakerStats struct {
+	// State The current state of the breaker.
+	State BreakerState
+	// Trips Number of times the breaker opened.
+	Trips uint64
+	// Rejections Number of loads not attempted because the breaker was open, stale values served included.
+	Rejections uint64
+	// StaleServed Number of expired values returned in place of a load while the breaker was open.
+	StaleServed uint64
+}
+
+// breaker A circuit breaker shared by the loads of all keys, see WithLoaderBreaker. Its state only changes through
+// atomic operations, so that loads never wait for one another.
+type breaker struct {
+	threshold float64
+	window    int64
+	cooldown  int64
+
+	state atomic.Int32
+	// windowStart is the time the current window started at, and calls and failures the loads it counted.
+	windowStart atomic.Int64
+	calls       atomic.Int64
+	failures    atomic.Int64
+	// openedAt is the time the breaker last opened at.
+	openedAt atomic.Int64
+
+	trips       atomic.Uint64
+	rejections  atomic.Uint64
+	staleServed atomic.Uint64
+}
+
+func newBreaker(threshold float64, window, cooldown time.Duration) *breaker {
+	return &breaker{threshold: threshold, window: window.Nanoseconds(), cooldown: cooldown.Nanoseconds()}
+}
+
+// allow Reports whether a load may call the loader at the given time, the caller being the probe if it moved the
+// breaker from open to half-open, in which case it must report the outcome of its load with done.
+func (b *breaker) allow(now int64) (allowed, probe bool) {
+	switch BreakerState(b.state.Load()) {
+	case BreakerClosed:
+		return true, false
+	case BreakerOpen:
+		if now-b.openedAt.Load() >= b.cooldown && b.state.CompareAndSwap(int32(BreakerOpen), int32(BreakerHalfOpen)) {
+			return true, true
+		}
+	}
+	b.rejections.Add(1)
+
+	return false, false
+}
+
+// done Records the outcome of a load allowed at the given time: failed if the loader failed, or neither a success
+// nor a failure if ignored, e.g. when the caller gave up.
+func (b *breaker) done(now int64, probe, failed, ignored bool) {
+	if probe {
+		switch {
+		case ignored:
+			// The next load probes again.
+			b.state.Store(int32(BreakerOpen))
+		case failed:
+			b.open(now)
+		default:
+			b.reset(now)
+			b.state.Store(int32(BreakerClosed))
+		}
+		return
+	}
+	if ignored || BreakerState(b.state.Load()) != BreakerClosed {
+		return
+	}
+
+	if start := b.windowStart.Load(); now-start >= b.window && b.windowStart.CompareAndSwap(start, now) {
+		b.calls.Store(0)
+		b.failures.Store(0)
+	}
+	calls := b.calls.Add(1)
+	if !failed {
+		return
+	}
+	failures := b.failures.Add(1)
+	if calls >= breakerMinCalls && float64(failures)/float64(calls) >= b.threshold &&
+		b.state.CompareAndSwap(int32(BreakerClosed), int32(BreakerOpen)) {
+		b.openedAt.Store(now)
+		b.trips.Add(1)
+	}
+}
+
+// open Opens the breaker again after a failed probe.
+func (b *breaker) open(now int64) {
+	b.openedAt.Store(now)
+	b.trips.Add(1)
+	b.state.Store(int32(BreakerOpen))
+}
+
+// reset Starts a new window once the breaker closes.
+func (b *breaker) reset(now int64) {
+	b.windowStart.Store(now)
+	b.calls.Store(0)
+	b.failures.Store(0)
+}
+
+func (b *breaker) stats() BreakerStats {
+	return BreakerStats{
+		State:       BreakerState(b.state.Load()),
+		Trips:       b.trips.Load(),
+		Rejections:  b.rejections.Load(),
+		StaleServed: b.staleServed.Load(),
+	}
+}
+
+// staleValue Returns the expired value still held under the key, if any, to serve while the breaker is open.
+func (c *Cache) staleValue(key string) (any, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	item, found := c.items[key]
+	now := c.now().UnixNano()
+	item = item.promote(now)
+	if !found || c.closed || item.placeholder || item.negative || item.isLeased(now) || !item.isExpired(now) {
+		return nil, false
+	}
+
+	return c.value(key, item), true
+}
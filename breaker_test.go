@@ -0,0 +1,148 @@
+package go_cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_WithLoaderBreaker(t *testing.T) {
+	errDown := errors.New("database is down")
+
+	t.Run("openAndClose", func(t *testing.T) {
+		var calls atomic.Int32
+		var down atomic.Bool
+		down.Store(true)
+		tc := NewCacheWithOptions(WithLoaderBreaker(0.5, time.Minute, 10*time.Second),
+			WithLoader(func(ctx context.Context, key string) (any, time.Duration, error) {
+				calls.Add(1)
+				if down.Load() {
+					return nil, 0, errDown
+				}
+				return key + "Value", DefaultExpiration, nil
+			}))
+		defer tc.Stop()
+		clock := newFakeClock()
+		tc.now = clock.Now
+
+		// Under the minimum number of calls, the breaker stays closed.
+		for _, key := range []string{"aKey", "bKey", "cKey", "dKey"} {
+			_, err := tc.Fetch(context.Background(), key)
+			assert.ErrorIs(t, err, errDown)
+		}
+		assert.Equal(t, BreakerClosed, tc.Health().BreakerState)
+
+		_, err := tc.Fetch(context.Background(), "eKey")
+		assert.ErrorIs(t, err, errDown)
+		assert.Equal(t, BreakerOpen, tc.Health().BreakerState)
+		assert.Equal(t, HealthDegraded, tc.Health().Status)
+
+		down.Store(false)
+		for _, key := range []string{"aKey", "fKey"} {
+			_, err = tc.Fetch(context.Background(), key)
+			assert.ErrorIs(t, err, ErrBreakerOpen)
+		}
+		assert.Equal(t, int32(5), calls.Load())
+
+		// A failed probe opens the breaker for another cooldown.
+		down.Store(true)
+		clock.Advance(10 * time.Second)
+		_, err = tc.Fetch(context.Background(), "aKey")
+		assert.ErrorIs(t, err, errDown)
+		assert.Equal(t, int32(6), calls.Load())
+		clock.Advance(5 * time.Second)
+		_, err = tc.Fetch(context.Background(), "aKey")
+		assert.ErrorIs(t, err, ErrBreakerOpen)
+
+		down.Store(false)
+		clock.Advance(5 * time.Second)
+		a, err := tc.Fetch(context.Background(), "aKey")
+		assert.Nil(t, err)
+		assert.Equal(t, "aKeyValue", a)
+		assert.Equal(t, BreakerClosed, tc.Health().BreakerState)
+		assert.Equal(t, HealthOK, tc.Health().Status)
+
+		b, err := tc.Fetch(context.Background(), "bKey")
+		assert.Nil(t, err)
+		assert.Equal(t, "bKeyValue", b)
+		assert.Equal(t, int32(8), calls.Load())
+		assert.Equal(t, BreakerStats{State: BreakerClosed, Trips: 2, Rejections: 3}, tc.Stats().Breaker)
+	})
+
+	t.Run("staleValues", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithLoaderBreaker(1, time.Minute, time.Minute), WithRetainExpired())
+		defer tc.Stop()
+		clock := newFakeClock()
+		tc.now = clock.Now
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			return nil, 0, errDown
+		}
+
+		tc.Set("aKey", "aValue", time.Second)
+		clock.Advance(time.Second)
+		for i := 0; i < breakerMinCalls; i++ {
+			_, _ = tc.GetOrLoad(context.Background(), "bKey", loader)
+		}
+		assert.Equal(t, BreakerOpen, tc.Stats().Breaker.State)
+
+		a, err := tc.GetOrLoad(context.Background(), "aKey", loader)
+		assert.Nil(t, err)
+		assert.Equal(t, "aValue", a)
+		_, err = tc.GetOrLoad(context.Background(), "bKey", loader)
+		assert.ErrorIs(t, err, ErrBreakerOpen)
+		assert.Equal(t, BreakerStats{State: BreakerOpen, Trips: 1, Rejections: 2, StaleServed: 1}, tc.Stats().Breaker)
+	})
+
+	t.Run("notFailures", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithLoaderBreaker(0.5, time.Minute, time.Minute))
+		defer tc.Stop()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			if ctx.Err() != nil {
+				return nil, 0, ctx.Err()
+			}
+			return nil, 0, ErrItemNotFound
+		}
+
+		for i := 0; i < 2*breakerMinCalls; i++ {
+			_, _ = tc.GetOrLoad(ctx, "aKey", loader)
+			_, _ = tc.GetOrLoad(context.Background(), "aKey", loader)
+		}
+		assert.Equal(t, BreakerClosed, tc.Health().BreakerState)
+	})
+
+	t.Run("window", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithLoaderBreaker(0.5, time.Second, time.Minute))
+		defer tc.Stop()
+		clock := newFakeClock()
+		tc.now = clock.Now
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			if key == "aKey" {
+				return nil, 0, errDown
+			}
+			return key, NoExpiration, nil
+		}
+
+		// Failures of past windows are forgotten.
+		for i := 0; i < breakerMinCalls; i++ {
+			_, _ = tc.GetOrLoad(context.Background(), "aKey", loader)
+			clock.Advance(time.Second)
+		}
+		assert.Equal(t, BreakerClosed, tc.Health().BreakerState)
+
+		_, _ = tc.GetOrLoad(context.Background(), "bKey", loader)
+		_, _ = tc.GetOrLoad(context.Background(), "cKey", loader)
+		_, _ = tc.GetOrLoad(context.Background(), "dKey", loader)
+		_, _ = tc.GetOrLoad(context.Background(), "aKey", loader)
+		assert.Equal(t, BreakerClosed, tc.Health().BreakerState)
+		_, _ = tc.GetOrLoad(context.Background(), "aKey", loader)
+		assert.Equal(t, BreakerClosed, tc.Health().BreakerState)
+		_, _ = tc.GetOrLoad(context.Background(), "aKey", loader)
+		assert.Equal(t, BreakerOpen, tc.Health().BreakerState)
+	})
+}
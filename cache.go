@@ -3,13 +3,34 @@ package go_cache
 import (
 	"errors"
 	"fmt"
+	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 var (
 	ErrItemAlreadyExists = errors.New("item already exists")
 	ErrItemNotFound      = errors.New("item not found")
+	ErrLeased            = errors.New("item is leased")
+	ErrLeaseNotHeld      = errors.New("lease not held")
+	ErrVersionMismatch   = errors.New("version mismatch")
+	ErrNoLoader          = errors.New("no loader configured")
+	ErrHashedKeys        = errors.New("keys are not available with hashed keys")
+	ErrCacheClosed       = errors.New("cache is closed")
+	ErrLifetimeExceeded  = errors.New("item lifetime exceeded")
+	ErrItemExpired       = errors.New("item expired")
+	ErrRecentlyDeleted   = errors.New("item recently deleted")
+	ErrNotBytes          = errors.New("value is not a byte slice")
+	ErrValueTooLarge     = errors.New("value is too large")
+	ErrLoadFailureCached = errors.New("loader failure cached")
+	ErrBreakerOpen       = errors.New("loader circuit breaker is open")
+	ErrLoaderPanicked    = errors.New("loader panicked")
+	ErrInvalidOption     = errors.New("invalid option")
+	ErrWrongType         = errors.New("value is of the wrong type")
+	ErrNotNumeric        = errors.New("value is not numeric")
+	// ErrNotInteger is an alias of ErrNotNumeric, kept for compatibility.
+	ErrNotInteger = ErrNotNumeric
 )
 
 const (
@@ -21,17 +42,219 @@ const (
 )
 
 type Cache struct {
+	name string
 	stop chan struct{}
 	wg   sync.WaitGroup
 
+	// janitorMu guards the cleanup interval and the control channel of the cleanup goroutine,
+	// which is nil while the goroutine is not running.
+	janitorMu       sync.Mutex
+	cleanupInterval time.Duration
+	janitor         chan time.Duration
+
 	mu                sync.RWMutex
 	items             map[string]item
+	closed            bool
 	defaultExpiration time.Duration
+	// peak is the highest number of items held by the items map since it was last allocated.
+	peak            int
+	initialCapacity int
+	presizeOnFlush  bool
+	compactRatio    float64
+	retainExpired   bool
+	maxLifetime     time.Duration
+
+	// tombstones holds the time at which keys were deleted, for tombstoneTTL, see WithTombstones.
+	tombstones   map[string]int64
+	tombstoneTTL time.Duration
+	// expiring holds the keys of the items the cleanup pass has work for, see needsCleanup.
+	expiring map[string]struct{}
+
+	loader      Loader
+	negativeTTL time.Duration
+	loadTimeout time.Duration
+	loadMu      sync.Mutex
+	loads       map[string]*loadCall
+
+	stats  stats
+	sizer  Sizer
+	codec  Codec
+	hasher *keyHasher
+	order  *insertionOrder
+	// sorted is the ordered index of the keys, built by NewCache WithSortedKeys, or by the first Scan otherwise, and
+	// sortedKeys reports whether it was built WithSortedKeys, for RangeKeys and AscendPrefix.
+	sorted     *skipList
+	sortedKeys bool
+	// capacity is nil unless capacity limits were set, see WithMaxItems and WithMaxCost.
+	capacity       *capacity
+	evictionPolicy EvictionPolicy
+	onFull         func(pressure CapacityPressure)
+	// quotas holds the limits of the namespaces, see WithNamespaceQuota and WithNamespaceCostBudget.
+	quotas []*quota
+	// snapshotRetention is the number of snapshots kept by SaveSnapshot, 0 to keep them all.
+	snapshotRetention int
+
+	accessTracking bool
+	closeOnEvict   bool
+	// onEvicted is called with the items removed from the cache, and overwritten ones if overwriteEvictions is set,
+	// see WithOnEvicted.
+	onEvicted          func(key string, value any)
+	overwriteEvictions bool
+	// counters is set once a Counter was stored, so that removed counters get invalidated.
+	counters bool
+	// removed holds the values removed from the cache while holding the write lock, see unlock.
+	removed        []removal
+	errorHandler   func(err error)
+	watchers       map[string]map[*watcher]struct{}
+	prefixWatchers map[*watcher]struct{}
+
+	health health
+	// namespaces holds the prefixes of the namespaces created on the cache, for DebugHandler.
+	namespaces sync.Map
+	// latency is nil unless the cache was created WithLatencyTracking.
+	latency *latency
+	// coalescer is nil unless the cache was created WithWriteCoalescing.
+	coalescer *coalescer
+	// bloom is nil unless the cache was created WithBloomFilter.
+	bloom *bloom
+	// misses is nil unless the cache was created WithMissTracking.
+	misses *missTracker
+	// periodicFlush is nil unless the cache was created WithPeriodicFlush.
+	periodicFlush *periodicFlush
+	// onCleanup is called after each pass of the cleanup goroutine, see WithCleanupHook.
+	onCleanup func(c *Cache, pass CleanupPass)
+	// compression is nil unless the cache was created WithCompression.
+	compression *compression
+	// spill is nil unless the cache was created WithSpillover.
+	spill *spill
+	// errorCache is nil unless the cache was created WithErrorCaching.
+	errorCache *errorCache
+	// breaker is nil unless the cache was created WithLoaderBreaker.
+	breaker *breaker
+	// retry is nil unless the cache was created WithLoaderRetry.
+	retry *retry
+	// loadLimit is nil unless the cache was created WithMaxConcurrentLoads.
+	loadLimit *loadLimiter
+	// maxValueSize is the size above which values are rejected, 0 for no limit, see WithMaxValueSize.
+	maxValueSize int64
+	// changes is nil unless the cache was created WithChangeTracking.
+	changes *changeLog
+	// indexes is nil unless the cache was created WithIndex.
+	indexes map[string]*index
+	// weakValues is set if the cache was created WithWeakValues.
+	weakValues bool
+
+	now func() time.Time
 }
 
+// item The entry of a key in the items map. Items are stored by value rather than through pointers: writes copy the
+// item into the map without allocating, overwrites reuse the slot of the key, and readers get their own copy, so
+// no item is ever shared outside the lock. See BenchmarkCache_Set.
 type item struct {
 	object     any
 	expiration int64
+	version    uint64
+	// created holds the time at which the value of the item was written.
+	created int64
+	// timestamp holds the version given to SetIfNewer, 0 if the item was written otherwise.
+	timestamp int64
+	// accessed holds the time of the last read of the item, or of its creation if it has never been read.
+	// It is only set if the cache was created WithAccessTracking.
+	accessed *atomic.Int64
+
+	leaseToken      string
+	leaseExpiration int64
+
+	pending *pendingItem
+	// cost holds the estimated size of the value, only set while the cache tracks its capacity or namespace quotas,
+	// unless fixedCost is set, in which case it holds the cost given WithItemCost.
+	cost int64
+	// seq holds the sequence number of the last change of the item, only set if the cache was created
+	// WithChangeTracking.
+	seq uint64
+	// onEvict is called once the value leaves the cache, see WithItemOnEvict.
+	onEvict func(value any)
+
+	// The small fields come last, packed into a single word: the map stores items of up to 128 bytes inline.
+
+	// negative is set when the item records that the key does not exist upstream.
+	negative bool
+	// placeholder is set when the item holds no value of its own, only a pending one.
+	placeholder bool
+	fixedCost   bool
+	// compressed is set when the object holds the value compressed, see WithCompression.
+	compressed bool
+	// priority orders the items for capacity eviction, see SetWithPriority.
+	priority Priority
+	// weak is set when the object holds the value weakly, see WithWeakValues.
+	weak bool
+	// flushed is set when the item was marked as expired by SoftFlush, to be removed as flushed.
+	flushed bool
+}
+
+type pendingItem struct {
+	object     any
+	visibleAt  int64
+	expiration int64
+}
+
+// isExpired Reports whether the item expired at the given time, a value held weakly expiring once collected.
+func (i item) isExpired(now int64) bool {
+	return (i.expiration > 0 && i.expiration <= now) || (i.weak && i.object.(weakValue).collected())
+}
+
+// hasExpired Reports whether the item holds a value, as opposed to a negative entry or a placeholder,
+// which has expired.
+func (i item) hasExpired(now int64) bool {
+	return !i.placeholder && !i.negative && i.isExpired(now)
+}
+
+// expiredReason Returns the reason an expired item is removed for: flushed if marked by SoftFlush, expired otherwise.
+func (i item) expiredReason() removalReason {
+	if i.flushed {
+		return removalFlushed
+	}
+
+	return removalExpired
+}
+
+func (i item) isLeased(now int64) bool {
+	return i.leaseExpiration > now
+}
+
+func (i item) info() ItemInfo {
+	info := ItemInfo{Object: i.object}
+	if i.expiration > 0 {
+		info.Expiration = time.Unix(0, i.expiration)
+	}
+	if i.timestamp != 0 {
+		info.Timestamp = time.Unix(0, i.timestamp)
+	}
+	info.Priority = i.priority
+
+	return info
+}
+
+// needsCleanup Reports whether the cleanup pass may have to act on the item at some point: delete it once
+// expired or once its value held weakly is collected, make its pending value visible, or clear its lease once over.
+func (i item) needsCleanup() bool {
+	return i.expiration > 0 || i.pending != nil || i.leaseExpiration > 0 || i.weak
+}
+
+// promote Returns the item as seen at the given time, swapping in its pending value once it became visible.
+func (i item) promote(now int64) item {
+	if i.pending == nil || i.pending.visibleAt > now {
+		return i
+	}
+
+	return item{
+		object:     i.pending.object,
+		expiration: i.pending.expiration,
+		version:    i.version + 1,
+		created:    i.pending.visibleAt,
+		accessed:   i.accessed,
+		priority:   i.priority,
+	}
 }
 
 // NewCache Returns a new cache with a given default expiration duration and cleanup interval.
@@ -39,144 +262,672 @@ type item struct {
 // and must be deleted manually. If the cleanup interval is less than one, expired items are not
 // deleted from the cache before calling DeleteExpired().
 func NewCache(defaultExpiration, cleanupInterval time.Duration) *Cache {
-	if defaultExpiration <= 0 {
-		defaultExpiration = NoExpiration
+	return NewCacheWithOptions(WithDefaultExpiration(defaultExpiration), WithCleanupInterval(cleanupInterval))
+}
+
+// NewCacheWithOptions Returns a new cache configured with the given options.
+// Without any option, the items in the cache never expire and no cleanup goroutine is started.
+// Invalid options, which NewCacheE reports, are coerced to the closest valid value rather than failing: negative
+// durations and limits are ignored, ratios and rates are clamped to their range, and a cleanup interval shorter than
+// a microsecond is raised to a microsecond.
+func NewCacheWithOptions(opts ...Option) *Cache {
+	o := applyOptions(opts)
+	_ = o.validate(true)
+
+	return newCache(o)
+}
+
+// newCache Returns a new cache configured with the given options, once validated.
+func newCache(o options) *Cache {
+	if o.defaultExpiration <= 0 {
+		o.defaultExpiration = NoExpiration
+	}
+	if o.sizer == nil {
+		o.sizer = defaultSizer
+	}
+	if o.codec == nil {
+		o.codec = GobCodec{}
 	}
 
 	c := &Cache{
-		stop:              make(chan struct{}),
-		mu:                sync.RWMutex{},
-		items:             make(map[string]item),
-		defaultExpiration: defaultExpiration,
+		name:               o.name,
+		stop:               make(chan struct{}),
+		mu:                 sync.RWMutex{},
+		items:              make(map[string]item, o.initialCapacity),
+		expiring:           make(map[string]struct{}),
+		initialCapacity:    o.initialCapacity,
+		presizeOnFlush:     o.presizeOnFlush,
+		defaultExpiration:  o.defaultExpiration,
+		compactRatio:       o.compactRatio,
+		retainExpired:      o.retainExpired,
+		tombstoneTTL:       o.tombstoneTTL,
+		maxLifetime:        o.maxLifetime,
+		loader:             o.loader,
+		negativeTTL:        o.negativeTTL,
+		loadTimeout:        o.loadTimeout,
+		loads:              make(map[string]*loadCall),
+		watchers:           make(map[string]map[*watcher]struct{}),
+		prefixWatchers:     make(map[*watcher]struct{}),
+		sizer:              o.sizer,
+		codec:              o.codec,
+		snapshotRetention:  o.snapshotRetention,
+		accessTracking:     o.accessTracking,
+		closeOnEvict:       o.closeOnEvict,
+		onEvicted:          o.onEvicted,
+		overwriteEvictions: o.overwriteEvictions,
+		errorHandler:       o.errorHandler,
+		onFull:             o.onFull,
+		evictionPolicy:     o.evictionPolicy,
+		maxValueSize:       o.maxValueSize,
+		weakValues:         o.weakValues,
+		onCleanup:          o.onCleanup,
+		now:                time.Now,
+	}
+	if o.hashedKeys {
+		c.hasher = newKeyHasher()
+	}
+	if o.latencySampleRate > 0 {
+		c.latency = &latency{sampleRate: o.latencySampleRate}
+	}
+	if o.writeCoalescing > 0 {
+		c.coalescer = &coalescer{window: o.writeCoalescing, keys: make(map[string]*coalescedKey)}
+	}
+	if o.bloomExpectedItems > 0 {
+		c.bloom = newBloom(o.bloomExpectedItems, o.bloomFPRate)
+	}
+	if o.compressionCodec != nil {
+		c.compression = &compression{threshold: o.compressionThreshold, codec: o.compressionCodec}
+	}
+	if o.spillDir != "" {
+		c.spill = &spill{dir: o.spillDir, threshold: o.spillThreshold}
+		c.removeSpillFiles()
+	}
+	if o.flushInterval > 0 {
+		c.periodicFlush = &periodicFlush{interval: o.flushInterval, aligned: o.alignedFlush, onFlush: o.onFlush}
+	}
+	if o.missTracking > 0 {
+		c.misses = newMissTracker(o.missTracking)
+	}
+	if o.errorTTL > 0 {
+		c.errorCache = newErrorCache(o.errorTTL, o.maxErrorTTL)
+	}
+	if o.changeTracking > 0 {
+		c.changes = newChangeLog(o.changeTracking)
+	}
+	if o.breakerThreshold > 0 {
+		c.breaker = newBreaker(o.breakerThreshold, o.breakerWindow, o.breakerCooldown)
+	}
+	if o.retryAttempts > 1 {
+		c.retry = &retry{attempts: o.retryAttempts, backoff: o.retryBackoff, retryable: o.retryable}
+	}
+	if o.maxConcurrentLoads > 0 {
+		c.loadLimit = newLoadLimiter(o.maxConcurrentLoads)
+	}
+	for _, spec := range o.indexes {
+		if c.indexes == nil {
+			c.indexes = make(map[string]*index)
+		}
+		c.indexes[spec.name] = newIndex(spec.extract)
+	}
+	if o.insertionOrder {
+		c.order = newInsertionOrder(o.resetOnOverwrite)
+	}
+	if o.sortedKeys && !o.hashedKeys {
+		c.sorted = newSkipList()
+		c.sortedKeys = true
+	}
+
+	if o.maxItems > 0 || o.maxCost > 0 {
+		c.trackCapacity()
+		c.capacity.maxItems = o.maxItems
+		c.capacity.maxCost = o.maxCost
+	}
+
+	for prefix, maxItems := range o.namespaceQuotas {
+		c.setQuota(prefix, func(q *quota) { q.maxItems = maxItems })
+	}
+	for prefix, maxCost := range o.namespaceBudgets {
+		c.setQuota(prefix, func(q *quota) { q.maxCost = maxCost })
+	}
+
+	for key, item := range o.initialItems {
+		c.set(c.hashKey(key), item.Object, item.Duration)
 	}
 
-	if cleanupInterval > 0 {
-		c.wg.Add(1)
-		go func(cleanupInterval time.Duration) {
-			defer c.wg.Done()
-			c.cleanUp(cleanupInterval)
-		}(cleanupInterval)
+	if o.cleanupInterval > 0 {
+		c.cleanupInterval = o.cleanupInterval
+		c.startJanitor(o.cleanupInterval)
+	}
+	if o.snapshotStore != nil && o.snapshotInterval > 0 {
+		c.health.snapshotStart()
+		c.startSnapshotter(o.snapshotStore, o.snapshotInterval)
 	}
 
 	return c
 }
 
-// cleanUp Deletes all expired items from the cache. This can be used if the
-// cleanupInterval passed to NewCache() is set to less than 1.
-func (c *Cache) cleanUp(cleanupInterval time.Duration) {
+// startJanitor Starts the cleanup goroutine with the given interval. It must be called with janitorMu held,
+// or before the cache is returned by the constructor.
+func (c *Cache) startJanitor(cleanupInterval time.Duration) {
+	c.janitor = make(chan time.Duration)
+	c.health.janitorStart(cleanupInterval, c.now())
+	if c.periodicFlush != nil && c.periodicFlush.next == 0 {
+		c.periodicFlush.schedule(c.now())
+	}
+	c.wg.Add(1)
+	go func(control <-chan time.Duration) {
+		defer c.wg.Done()
+		c.cleanUp(cleanupInterval, control)
+	}(c.janitor)
+}
+
+// cleanUp Periodically deletes all expired items from the cache until the cache is stopped,
+// or until it receives a new interval less than one from the control channel.
+func (c *Cache) cleanUp(cleanupInterval time.Duration, control <-chan time.Duration) {
 	t := time.NewTicker(cleanupInterval)
 	defer t.Stop()
 
 	for {
 		select {
 		case <-c.stop:
+			c.health.janitorExit()
 			return
+		case d := <-control:
+			if d <= 0 {
+				return
+			}
+			t.Reset(d)
 		case <-t.C:
-			c.mu.Lock()
-			for key, object := range c.items {
-				if object.expiration > 0 && object.expiration <= time.Now().UnixNano() {
-					delete(c.items, key)
-				}
+			c.runCleanup()
+		}
+	}
+}
+
+// DeleteExpired Deletes all expired items from the cache. This can be used if the
+// cleanupInterval passed to NewCache() is set to less than 1.
+func (c *Cache) DeleteExpired() {
+	c.deleteExpired(false)
+}
+
+// deleteExpired Deletes all expired items from the cache, except the expired values if retain is set
+// (see WithRetainExpired), which are then made the first candidates for eviction instead.
+// Returns the number of items looked at, and of items deleted.
+func (c *Cache) deleteExpired(retain bool) (scanned, removed int) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.now().UnixNano()
+	for key := range c.tombstones {
+		if _, buried := c.deletedAt(key, now); !buried {
+			delete(c.tombstones, key)
+		}
+	}
+	// Only the items with a deadline need a look: the others never expire and have nothing to promote.
+	for key := range c.expiring {
+		scanned++
+		item := c.items[key]
+		if item.pending != nil && item.pending.visibleAt <= now {
+			op := WatchReplace
+			if item.placeholder || item.negative || item.isExpired(now) {
+				op = WatchSet
+			}
+			promoted := item.promote(now)
+			c.release(key, item, promoted, false)
+			item = promoted
+			c.store(key, item)
+			if c.indexes != nil {
+				c.indexInserted(key, item, item.object)
+			}
+			if c.watched() {
+				c.notify(key, op, c.value(key, item))
 			}
-			c.mu.Unlock()
+		}
+		if item.placeholder {
+			continue
+		}
+		if retain && item.hasExpired(now) {
+			if c.capacity != nil {
+				c.capacity.expired.inserted(key, false)
+			}
+			continue
+		}
+		if item.isExpired(now) {
+			c.delete(key, item.expiredReason())
+			removed++
+			continue
+		}
+		if item.leaseExpiration > 0 && !item.isLeased(now) {
+			item.leaseToken = ""
+			item.leaseExpiration = 0
+			c.store(key, item)
 		}
 	}
+
+	return scanned, removed
 }
 
 // Stop This will stop the cleanup goroutine and free up resources.
+// Once stopped, the cache no longer accepts writes: Set and the other methods without an error are no-ops,
+// the ones returning an error return ErrCacheClosed, and lookups miss. Calling Stop again is a no-op.
 func (c *Cache) Stop() {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return
+	}
+	c.closed = true
+	c.drainCoalesced()
+	c.mu.Unlock()
+
+	c.health.stop()
+	c.janitorMu.Lock()
 	close(c.stop)
+	c.janitorMu.Unlock()
 	c.wg.Wait()
 }
 
+// isClosed Reports whether Stop has been called on the cache.
+func (c *Cache) isClosed() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.closed
+}
+
 // Set Adds an item to the cache, replacing any existing item.
-// If the duration is 0 (DefaultExpiration), the cache's default expiration time is used.
+// If the duration is 0 (DefaultExpiration), the cache's default expiration time is used,
+// unless the object reports its own TTL (see Expirer).
 // If it is -1 (NoExpiration), the item never expires.
 // If the duration is positive, the item expires after that time has passed.
 func (c *Cache) Set(key string, object any, duration time.Duration) {
+	if l := c.latency; l != nil && l.sample() {
+		defer c.observe(&l.set, c.now())
+	}
+	key = c.hashKey(key)
+
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
 
 	c.set(key, object, duration)
 }
 
 // Add Inserts an item to the cache only if an item doesn't already exist for the given key,
-// or if the existing item has expired. Returns ErrItemAlreadyExists error otherwise.
+// or if the existing item has expired. Returns ErrItemAlreadyExists error otherwise, or ErrRecentlyDeleted error
+// if the key was deleted within the window set WithTombstones.
+// Expired items displaced by Add are counted in Stats.
 // If the duration is 0 (DefaultExpiration), the cache's default expiration time is used.
 // If it is -1 (NoExpiration), the item never expires.
 // If the duration is positive, the item expires after that time has passed.
 func (c *Cache) Add(key string, object any, duration time.Duration) error {
+	key = c.hashKey(key)
+
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
 
-	item, found := c.items[key]
-	isExpired := item.expiration > 0 && item.expiration <= time.Now().UnixNano()
-	if found && !isExpired {
+	return c.add(key, func() item { return c.newItem(key, object, duration) })
+}
+
+// add Inserts the item returned by newItem unless the key holds a live value or was recently deleted, see Add.
+func (c *Cache) add(key string, newItem func() item) error {
+	if c.closed {
+		return ErrCacheClosed
+	}
+	now := c.now().UnixNano()
+	current, found := c.get(key, now)
+	if found {
 		return fmt.Errorf("%w: %s", ErrItemAlreadyExists, key)
 	}
-	c.set(key, object, duration)
+	if _, buried := c.deletedAt(key, now); buried {
+		return fmt.Errorf("%w: %s", ErrRecentlyDeleted, key)
+	}
+	if err := c.insert(key, newItem()); err != nil {
+		return err
+	}
+	if current.hasExpired(now) {
+		c.stats.expiredDisplaced.Add(1)
+	}
 
 	return nil
 }
 
 // Replace Sets a new value for the cache only if the given key already exists,
-// and the existing item has not expired. Returns ErrItemNotFound error otherwise.
+// and the existing item has not expired. Returns ErrItemNotFound error otherwise,
+// also wrapping ErrItemExpired if the item had expired.
 // If the duration is 0 (DefaultExpiration), the cache's default expiration time is used.
 // If it is -1 (NoExpiration), the item never expires.
 // If the duration is positive, the item expires after that time has passed.
 func (c *Cache) Replace(key string, object any, duration time.Duration) error {
+	key = c.hashKey(key)
+
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
 
-	item, found := c.items[key]
-	isExpired := item.expiration > 0 && item.expiration <= time.Now().UnixNano()
-	if !found || isExpired {
+	return c.replace(key, func() item { return c.newItem(key, object, duration) })
+}
+
+// replace Inserts the item returned by newItem only if the key holds a live value, see Replace.
+func (c *Cache) replace(key string, newItem func() item) error {
+	if c.closed {
+		return ErrCacheClosed
+	}
+	now := c.now().UnixNano()
+	if current, found := c.get(key, now); !found {
+		if current.hasExpired(now) {
+			return fmt.Errorf("%w: %w: %s", ErrItemNotFound, ErrItemExpired, key)
+		}
 		return fmt.Errorf("%w: %s", ErrItemNotFound, key)
 	}
-	c.set(key, object, duration)
 
-	return nil
+	return c.insert(key, newItem())
+}
+
+func (c *Cache) set(key string, object any, duration time.Duration) error {
+	return c.insert(key, c.newItem(key, object, duration))
 }
 
-func (c *Cache) set(key string, object any, duration time.Duration) {
+// newItem Returns a new item holding the given object, meant to replace the one currently stored under the key.
+func (c *Cache) newItem(key string, object any, duration time.Duration) item {
+	now := c.now()
 	var expiration int64
-	if duration == DefaultExpiration {
-		duration = c.defaultExpiration
-	}
+	duration = c.resolveDuration(object, duration)
 	if duration > 0 {
-		expiration = time.Now().Add(duration).UnixNano()
+		expiration = now.Add(duration).UnixNano()
 	}
 
-	c.items[key] = item{
+	return item{
 		object:     object,
 		expiration: expiration,
+		version:    c.items[key].version + 1,
+		created:    now.UnixNano(),
 	}
 }
 
+// insert Stores the given item under the key, replacing any previous one.
+// Once the cache is closed, the item is dropped and only counted in the stats. An item whose value is larger than
+// the size set WithMaxValueSize is rejected before anything is evicted, returning an error wrapping ErrValueTooLarge.
+func (c *Cache) insert(key string, it item) error {
+	if c.closed {
+		c.stats.closedWrites.Add(1)
+		return nil
+	}
+	switch {
+	case it.pending != nil:
+		if err := c.checkSize(key, it.pending.object); err != nil {
+			return err
+		}
+	case !it.placeholder && !it.negative:
+		if err := c.checkSize(key, it.object); err != nil {
+			return err
+		}
+	}
+	if c.accessTracking && it.accessed == nil {
+		it.accessed = new(atomic.Int64)
+		it.accessed.Store(c.now().UnixNano())
+	}
+	value := it.object
+	if c.compression != nil {
+		it = c.compress(key, it)
+	}
+	if c.spill != nil {
+		var err error
+		if it, err = c.spillOut(key, it); err != nil {
+			return err
+		}
+	}
+	if (c.capacity != nil || c.quotas != nil) && !it.fixedCost {
+		it.cost = c.sizer(key, it.object)
+	}
+	if c.weakValues {
+		it = c.weaken(it)
+	}
+
+	previous, found := c.items[key]
+	c.store(key, it)
+	if len(c.tombstones) > 0 {
+		delete(c.tombstones, key)
+	}
+	if found {
+		c.release(key, previous, it, false)
+	}
+	if c.indexes != nil {
+		c.indexInserted(key, it, value)
+	}
+	if c.watched() && !it.placeholder && !it.negative && it.pending == nil {
+		op := WatchSet
+		if found && !previous.placeholder && !previous.negative && !previous.isExpired(c.now().UnixNano()) {
+			op = WatchReplace
+		}
+		c.notify(key, op, value)
+	}
+	if n := len(c.items); n > c.peak {
+		c.peak = n
+	}
+	if c.order != nil {
+		c.order.inserted(key, found && !previous.isExpired(c.now().UnixNano()))
+	}
+	if c.sorted != nil && !found {
+		c.sorted.insert(key)
+	}
+	if c.capacity != nil {
+		c.capacity.cost += it.cost - previous.cost
+		c.capacity.expired.removed(key)
+		c.capacity.added(key, it.priority, previous.priority, found)
+	}
+	// Evicting from the namespace first may be enough to stay within the capacity limits.
+	if c.quotas != nil {
+		c.quotaInserted(key, it, previous, found)
+	}
+	if c.capacity != nil && c.evict(0) > 0 {
+		c.full()
+	}
+
+	return nil
+}
+
+// store Writes the item under the key, keeping track of whether the cleanup pass has work for it.
+func (c *Cache) store(key string, it item) {
+	if c.changes != nil {
+		it.seq = c.changes.next()
+	}
+	c.items[key] = it
+	if c.counters {
+		if counter, ok := it.object.(*Counter); ok {
+			counter.expiration.Store(it.expiration)
+		}
+	}
+	if c.bloom != nil && it.inBloom() {
+		c.bloom.add(key)
+	}
+	if it.needsCleanup() {
+		c.expiring[key] = struct{}{}
+	} else {
+		delete(c.expiring, key)
+	}
+}
+
+// delete Removes the item stored under the key, if any, counting it in the stats with the given reason and notifying
+// watchers with WatchExpire for expired items, WatchDelete otherwise.
+func (c *Cache) delete(key string, reason removalReason) {
+	if c.watched() {
+		if it, found := c.items[key]; found && !it.placeholder && !it.negative {
+			op := WatchDelete
+			if reason == removalExpired {
+				op = WatchExpire
+			}
+			c.notify(key, op, c.value(key, it))
+		}
+	}
+	if it, found := c.items[key]; found {
+		if !it.placeholder {
+			c.stats.removals[reason].Add(1)
+		}
+		c.release(key, it, item{}, true)
+		if c.changes != nil {
+			c.changes.deleted(key)
+		}
+		if c.capacity != nil {
+			c.capacity.cost -= it.cost
+			c.capacity.removed(key, it.priority)
+		}
+		if c.quotas != nil {
+			c.quotaRemoved(key, it)
+		}
+		if c.indexes != nil {
+			c.indexRemoved(key)
+		}
+	}
+	delete(c.items, key)
+	delete(c.expiring, key)
+	if c.order != nil {
+		c.order.removed(key)
+	}
+	if c.sorted != nil {
+		c.sorted.remove(key)
+	}
+}
+
+// get Returns the item stored under the given key as seen at the given time,
+// reporting false if it does not exist, has expired, is not visible yet or is a negative entry.
+func (c *Cache) get(key string, now int64) (item, bool) {
+	item, found := c.items[key]
+	if !found || c.closed {
+		return item, false
+	}
+	item = item.promote(now)
+	if item.placeholder || item.negative || item.isExpired(now) {
+		return item, false
+	}
+
+	return item, true
+}
+
+// lookup Returns the item stored under the given key as seen by readers at the given time, recording the outcome in the stats.
+func (c *Cache) lookup(key string, now int64) (item, LookupState) {
+	item, found := c.items[key]
+	if found {
+		item = item.promote(now)
+		found = !c.closed && !item.placeholder && !item.isExpired(now) && !item.isLeased(now)
+	}
+
+	switch {
+	case !found:
+		c.miss(key)
+		return item, LookupMiss
+	case item.negative:
+		c.stats.negativeHits.Add(1)
+		return item, LookupNegativeHit
+	default:
+		c.stats.hits.Add(1)
+		if item.accessed != nil {
+			item.accessed.Store(now)
+		}
+		if c.capacity != nil {
+			c.capacity.accessed(key, item.priority)
+		}
+		if c.quotas != nil {
+			c.quotaAccessed(key)
+		}
+		return item, LookupHit
+	}
+}
+
+// value Returns the value of the item as it was written, reading it back from disk (see WithSpillover) and
+// decompressing it (see WithCompression) if needed. A value which cannot be read back is reported to the error
+// handler, and read as nil, like a value held weakly which was collected (see WithWeakValues).
+func (c *Cache) value(key string, it item) any {
+	if it.weak {
+		return it.object.(weakValue).value()
+	}
+	object := it.object
+	if f, ok := object.(*spillFile); ok {
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			c.handleError(fmt.Errorf("could not read spilled value of %s: %w", key, err))
+			return nil
+		}
+		object = data
+	}
+	if !it.compressed {
+		return object
+	}
+
+	data, err := c.compression.codec.Decompress(object.([]byte))
+	if err != nil {
+		c.handleError(fmt.Errorf("could not decompress value of %s: %w", key, err))
+		return nil
+	}
+
+	return data
+}
+
+// info Returns the description of the item, with its value as it was written.
+func (c *Cache) info(key string, it item) ItemInfo {
+	info := it.info()
+	info.Object = c.value(key, it)
+
+	return info
+}
+
 // Get Looks up a key's value from the cache.
 // If the key corresponds to an item in the cache, a copy of the value is returned.
 // If the key does not exist, nil is returned.
 // If the key is found but has expired, it is deleted from the cache and nil is returned.
+// If the key is currently leased (see Acquire) or holds a negative entry (see SetNegative), nil is returned.
+// With WithBloomFilter, the keys the filter has never seen miss without taking the lock.
 func (c *Cache) Get(key string) (any, bool) {
+	if l := c.latency; l != nil && l.sample() {
+		defer c.observe(&l.get, c.now())
+	}
+	value, _, found := c.getItem(c.hashKey(key))
+
+	return value, found
+}
+
+// GetWithExpiration Looks up a key's value from the cache like Get, also returning its expiration time, e.g. to
+// forward the remaining lifetime of a cached HTTP response as its max-age. The time is zero if the item never
+// expires. Like Get, it reports false for an item whose expiration time has passed, even if it was not deleted yet.
+func (c *Cache) GetWithExpiration(key string) (any, time.Time, bool) {
+	value, it, found := c.getItem(c.hashKey(key))
+	if !found || it.expiration == 0 {
+		return value, time.Time{}, found
+	}
+
+	return value, time.Unix(0, it.expiration), true
+}
+
+// getItem Looks up the live value of the hashed key along with its item, see Get.
+func (c *Cache) getItem(key string) (any, item, bool) {
+	if !c.bloom.mayContain(key) {
+		c.miss(key)
+		return nil, item{}, false
+	}
+
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	item, found := c.items[key]
-	isExpired := item.expiration > 0 && item.expiration <= time.Now().UnixNano()
-	if !found || isExpired {
-		return nil, false
+	it, state := c.lookup(key, c.now().UnixNano())
+	if state != LookupHit {
+		return nil, item{}, false
+	}
+	value := c.value(key, it)
+	if value == nil && it.weak {
+		// Collected since the lookup.
+		return nil, item{}, false
 	}
 
-	return item.object, true
+	return value, it, true
 }
 
 // Delete Removes the provided key from the cache.
 // If the key was not found, Delete is a no-op.
 func (c *Cache) Delete(key string) {
+	key = c.hashKey(key)
+
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
 
-	delete(c.items, key)
+	c.delete(key, removalDeleted)
+	c.bury(key)
 }
 
 // Flush Completely clears the cache.
@@ -184,9 +935,166 @@ func (c *Cache) Delete(key string) {
 // This is a no-op if the cache is already empty.
 func (c *Cache) Flush() {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	defer c.unlock()
+
+	for key, it := range c.flush() {
+		c.release(key, it, item{}, true)
+	}
+}
+
+// FlushAndReturn Deletes all items from the cache like Flush, and returns them so that the caller can take
+// over their contents. Expired items which have not been deleted yet are returned as well, with their
+// expiration time, while negative entries and values staged with SetVisibleAt that are not visible yet are not.
+// With hashed keys (see WithHashedKeys), the returned map is keyed by the hashed keys.
+func (c *Cache) FlushAndReturn() map[string]ItemInfo {
+	c.mu.Lock()
+	old := c.flush()
+	c.mu.Unlock()
+
+	now := c.now().UnixNano()
+	items := make(map[string]ItemInfo, len(old))
+	for key, item := range old {
+		if counter, ok := item.object.(*Counter); ok {
+			counter.invalidate()
+		}
+		promoted := item.promote(now)
+		if !promoted.placeholder && !promoted.negative {
+			items[key] = c.info(key, promoted)
+		}
+		if f, ok := item.object.(*spillFile); ok {
+			c.removeSpillFile(key, f)
+		}
+	}
+
+	return items
+}
+
+// flush Replaces the items of the cache with an empty map, returning the previous one.
+func (c *Cache) flush() map[string]item {
+	c.drainCoalesced()
+	if c.watched() {
+		c.notifyFlush()
+	}
+	old := c.items
+	for _, it := range old {
+		if !it.placeholder {
+			c.stats.removals[removalFlushed].Add(1)
+		}
+	}
+	size := 0
+	if c.presizeOnFlush {
+		size = max(len(old), c.initialCapacity)
+	}
+	c.items = make(map[string]item, size)
+	c.expiring = make(map[string]struct{})
+	c.peak = 0
+	if c.bloom != nil {
+		c.bloom.reset()
+	}
+	if c.changes != nil {
+		c.changes.flush()
+	}
+	if c.quotas != nil {
+		c.quotaFlushed()
+	}
+	if c.indexes != nil {
+		c.indexFlushed()
+	}
+	if c.order != nil {
+		c.order.reset()
+	}
+	if c.sorted != nil {
+		c.sorted.reset()
+	}
+	if c.capacity != nil {
+		c.capacity.cost = 0
+		for key, it := range old {
+			c.capacity.removed(key, it.priority)
+		}
+	}
+	if len(c.tombstones) > 0 {
+		c.tombstones = make(map[string]int64)
+	}
+
+	return old
+}
+
+// SoftFlush Marks every item in the cache as expired without deleting it, and returns the number of items marked.
+// Marked items immediately stop being returned by Get, and are then reclaimed by the cleanup goroutine
+// or DeleteExpired like any other expired item, but counted as flushed in Stats. Values staged with SetVisibleAt
+// are discarded.
+func (c *Cache) SoftFlush() int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	now := c.now().UnixNano()
+	marked := 0
+	for key, item := range c.items {
+		if item.placeholder {
+			c.delete(key, removalFlushed)
+			continue
+		}
+		expired := item
+		if !item.isExpired(now) {
+			marked++
+			expired.flushed = true
+		}
+		expired.expiration = now
+		expired.pending = nil
+		c.release(key, item, expired, false)
+		c.store(key, expired)
+	}
+
+	return marked
+}
+
+// Keys Returns the keys of all items in the cache which have not expired, in no particular order.
+// Returns ErrHashedKeys error if the cache was created WithHashedKeys, since the keys are not stored.
+func (c *Cache) Keys() ([]string, error) {
+	if c.hasher != nil {
+		return nil, ErrHashedKeys
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.now().UnixNano()
+	keys := make([]string, 0, len(c.items))
+	for key := range c.items {
+		if _, found := c.get(key, now); found {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+// Items Returns a copy of all items in the cache, in no particular order. This may include items that
+// have expired, but have not yet been cleaned up. Negative entries and values staged with SetVisibleAt
+// that are not visible yet are not included.
+// Returns ErrHashedKeys error if the cache was created WithHashedKeys, since the keys are not stored.
+func (c *Cache) Items() (map[string]ItemInfo, error) {
+	if c.hasher != nil {
+		return nil, ErrHashedKeys
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	items := make(map[string]ItemInfo, len(c.items))
+	if c.closed {
+		return items, nil
+	}
+	now := c.now().UnixNano()
+	for key, item := range c.items {
+		item = item.promote(now)
+		if item.placeholder || item.negative {
+			continue
+		}
+		items[key] = c.info(key, item)
+	}
 
-	c.items = map[string]item{}
+	return items, nil
 }
 
 // ItemCount Returns the number of items in the cache. This may include items that have expired,
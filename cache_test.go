@@ -1,6 +1,10 @@
 package go_cache
 
 import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -159,6 +163,46 @@ func TestCache_SetAndGet(t *testing.T) {
 	})
 }
 
+func TestCache_GetWithExpiration(t *testing.T) {
+	clock := newFakeClock()
+	tc := NewCache(time.Hour, 0)
+	tc.now = clock.Now
+	defer tc.Stop()
+
+	tc.Set("aKey", "aValue", DefaultExpiration)
+	tc.Set("bKey", "bValue", NoExpiration)
+	tc.Set("cKey", "cValue", time.Minute)
+	tc.SetNegative("dKey", NoExpiration)
+
+	a, expiration, found := tc.GetWithExpiration("aKey")
+	assert.True(t, found)
+	assert.Equal(t, "aValue", a)
+	assert.True(t, clock.Now().Add(time.Hour).Equal(expiration))
+
+	b, expiration, found := tc.GetWithExpiration("bKey")
+	assert.True(t, found)
+	assert.Equal(t, "bValue", b)
+	assert.True(t, expiration.IsZero())
+
+	clock.Advance(time.Minute)
+	// Expired but not deleted yet.
+	c, expiration, found := tc.GetWithExpiration("cKey")
+	assert.False(t, found)
+	assert.Nil(t, c)
+	assert.True(t, expiration.IsZero())
+	assert.Equal(t, 4, tc.ItemCount())
+
+	_, _, found = tc.GetWithExpiration("dKey")
+	assert.False(t, found)
+	_, _, found = tc.GetWithExpiration("eKey")
+	assert.False(t, found)
+
+	stats := tc.Stats()
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(2), stats.Misses)
+	assert.Equal(t, uint64(1), stats.NegativeHits)
+}
+
 func TestCache_AddAndGet(t *testing.T) {
 	t.Run("addNewItems", func(t *testing.T) {
 		tc := NewCache(NoExpiration, 0)
@@ -588,3 +632,499 @@ func TestCache_ItemCount(t *testing.T) {
 		assert.Equal(t, 1, ic)
 	})
 }
+
+func TestCache_DeleteExpired(t *testing.T) {
+	clock := newFakeClock()
+	tc := NewCache(20*time.Millisecond, 0)
+	tc.now = clock.Now
+	defer tc.Stop()
+
+	tc.Set("aKey", "aValue", DefaultExpiration)
+	tc.Set("bKey", "bValue", NoExpiration)
+	tc.Set("cKey", 3, 50*time.Millisecond)
+
+	clock.Advance(25 * time.Millisecond)
+
+	tc.DeleteExpired()
+
+	ic := tc.ItemCount()
+	assert.Equal(t, 2, ic)
+
+	clock.Advance(30 * time.Millisecond)
+
+	tc.DeleteExpired()
+
+	ic = tc.ItemCount()
+	assert.Equal(t, 1, ic)
+
+	b, found := tc.Get("bKey")
+	assert.Equal(t, "bValue", b)
+	assert.True(t, found)
+}
+
+func TestCache_SoftFlush(t *testing.T) {
+	t.Run("withDeleteExpired", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		tc.Set("bKey", "bValue", 1*time.Hour)
+		tc.Set("cKey", "cValue", 1*time.Nanosecond)
+
+		<-time.After(1 * time.Millisecond)
+
+		marked := tc.SoftFlush()
+		assert.Equal(t, 2, marked)
+
+		a, found := tc.Get("aKey")
+		assert.Nil(t, a)
+		assert.False(t, found)
+
+		b, found := tc.Get("bKey")
+		assert.Nil(t, b)
+		assert.False(t, found)
+
+		ic := tc.ItemCount()
+		assert.Equal(t, 3, ic)
+
+		tc.DeleteExpired()
+
+		ic = tc.ItemCount()
+		assert.Equal(t, 0, ic)
+
+		// The marked items are removed as flushed, the one already expired as expired.
+		removals := tc.Stats().Removals
+		assert.Equal(t, uint64(2), removals.Flushed)
+		assert.Equal(t, uint64(1), removals.Expired)
+	})
+
+	t.Run("withCleanUp", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 1*time.Millisecond)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		tc.Set("bKey", "bValue", DefaultExpiration)
+
+		marked := tc.SoftFlush()
+		assert.Equal(t, 2, marked)
+
+		<-time.After(10 * time.Millisecond)
+
+		ic := tc.ItemCount()
+		assert.Equal(t, 0, ic)
+	})
+
+	t.Run("addAfterSoftFlush", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+
+		tc.SoftFlush()
+
+		err := tc.Add("aKey", "a2Value", DefaultExpiration)
+		assert.Nil(t, err)
+
+		a, found := tc.Get("aKey")
+		assert.Equal(t, "a2Value", a)
+		assert.True(t, found)
+	})
+}
+
+func TestCache_Keys(t *testing.T) {
+	tc := NewCache(NoExpiration, 0)
+	defer tc.Stop()
+
+	keys, err := tc.Keys()
+	assert.Nil(t, err)
+	assert.Empty(t, keys)
+
+	tc.Set("aKey", "aValue", DefaultExpiration)
+	tc.Set("bKey", "bValue", DefaultExpiration)
+	tc.Set("cKey", "cValue", 1*time.Nanosecond)
+
+	<-time.After(1 * time.Millisecond)
+
+	keys, err = tc.Keys()
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"aKey", "bKey"}, keys)
+}
+
+func TestCache_Items(t *testing.T) {
+	t.Run("includesExpired", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(NoExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		tc.Set("bKey", "bValue", 1*time.Second)
+		tc.SetNegative("cKey", DefaultExpiration)
+		clock.Advance(2 * time.Second)
+
+		items, err := tc.Items()
+		assert.Nil(t, err)
+		assert.Len(t, items, 2)
+		assert.Equal(t, "aValue", items["aKey"].Object)
+		assert.True(t, items["aKey"].Expiration.IsZero())
+		assert.Equal(t, "bValue", items["bKey"].Object)
+		assert.True(t, clock.Now().Add(-1*time.Second).Equal(items["bKey"].Expiration))
+
+		tc.DeleteExpired()
+		items, err = tc.Items()
+		assert.Nil(t, err)
+		assert.Len(t, items, 1)
+	})
+
+	t.Run("hashedKeys", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithHashedKeys())
+		defer tc.Stop()
+
+		_, err := tc.Items()
+		assert.ErrorIs(t, err, ErrHashedKeys)
+	})
+}
+
+func TestCache_Stop(t *testing.T) {
+	t.Run("operationsAfterStop", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		tc.Stop()
+
+		_, found := tc.Get("aKey")
+		assert.False(t, found)
+		_, state := tc.Lookup("aKey")
+		assert.Equal(t, LookupMiss, state)
+
+		tc.Set("bKey", "bValue", DefaultExpiration)
+		tc.SetNegative("cKey", DefaultExpiration)
+		_, found = tc.Get("bKey")
+		assert.False(t, found)
+		assert.Equal(t, uint64(2), tc.Stats().ClosedWrites)
+
+		assert.ErrorIs(t, tc.Add("dKey", "dValue", DefaultExpiration), ErrCacheClosed)
+		assert.ErrorIs(t, tc.Replace("aKey", "aValue", DefaultExpiration), ErrCacheClosed)
+		assert.ErrorIs(t, tc.SetIfVersion("aKey", "aValue", DefaultExpiration, 1), ErrCacheClosed)
+		_, _, err := tc.Acquire("aKey", time.Minute)
+		assert.ErrorIs(t, err, ErrCacheClosed)
+		assert.ErrorIs(t, tc.Release("aKey", "token"), ErrCacheClosed)
+
+		called := false
+		_, err = tc.GetOrLoad(context.Background(), "eKey", func(ctx context.Context, key string) (any, time.Duration, error) {
+			called = true
+			return "eValue", DefaultExpiration, nil
+		})
+		assert.ErrorIs(t, err, ErrCacheClosed)
+		assert.False(t, called)
+	})
+
+	t.Run("idempotent", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 10*time.Millisecond)
+		tc.Stop()
+		tc.Stop()
+	})
+
+	t.Run("concurrentOperations", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			return key, DefaultExpiration, nil
+		}
+
+		var wg sync.WaitGroup
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < 1000; i++ {
+					key := fmt.Sprintf("key%d-%d", g, i)
+					switch i % 3 {
+					case 0:
+						tc.Set(key, i, DefaultExpiration)
+					case 1:
+						tc.Get(key)
+					default:
+						_, _ = tc.GetOrLoad(context.Background(), key, loader)
+					}
+				}
+			}(g)
+		}
+
+		<-time.After(1 * time.Millisecond)
+		tc.Stop()
+		count := tc.ItemCount()
+
+		wg.Wait()
+		assert.Equal(t, count, tc.ItemCount())
+	})
+}
+
+func TestCache_FlushAndReturn(t *testing.T) {
+	t.Run("returnsItems", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", 1*time.Minute)
+		tc.Set("bKey", "bValue", NoExpiration)
+		tc.Set("cKey", "cValue", 1*time.Second)
+		tc.SetNegative("dKey", DefaultExpiration)
+		tc.SetVisibleAt("eKey", "eValue", clock.Now().Add(1*time.Hour), DefaultExpiration)
+		clock.Advance(2 * time.Second)
+
+		items := tc.FlushAndReturn()
+		assert.Len(t, items, 3)
+		assert.Equal(t, "aValue", items["aKey"].Object)
+		assert.True(t, clock.Now().Add(58*time.Second).Equal(items["aKey"].Expiration))
+		assert.Equal(t, "bValue", items["bKey"].Object)
+		assert.True(t, items["bKey"].Expiration.IsZero())
+		assert.Equal(t, "cValue", items["cKey"].Object)
+		assert.True(t, clock.Now().Add(-1*time.Second).Equal(items["cKey"].Expiration))
+		assert.Equal(t, 0, tc.ItemCount())
+	})
+
+	t.Run("concurrentWriters", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		const writers, writes = 8, 1000
+		var wg sync.WaitGroup
+		for g := 0; g < writers; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < writes; i++ {
+					tc.Set(fmt.Sprintf("key%d-%d", g, i), i, DefaultExpiration)
+				}
+			}(g)
+		}
+
+		var flushed []map[string]ItemInfo
+		for i := 0; i < 10; i++ {
+			flushed = append(flushed, tc.FlushAndReturn())
+		}
+		wg.Wait()
+		flushed = append(flushed, tc.FlushAndReturn())
+
+		seen := make(map[string]int)
+		for _, items := range flushed {
+			for key := range items {
+				seen[key]++
+			}
+		}
+		assert.Len(t, seen, writers*writes)
+		for key, n := range seen {
+			assert.Equal(t, 1, n, key)
+		}
+	})
+}
+
+func TestCache_Allocations(t *testing.T) {
+	tc := NewCache(NoExpiration, 0)
+	defer tc.Stop()
+
+	// Items are stored by value in the map, so overwriting a key or rewriting a deleted one reuses its slot.
+	var value any = 1
+	tc.Set("aKey", value, DefaultExpiration)
+	allocs := testing.AllocsPerRun(100, func() {
+		tc.Set("aKey", value, DefaultExpiration)
+		tc.Get("aKey")
+		tc.Delete("aKey")
+		tc.Set("aKey", value, DefaultExpiration)
+	})
+	assert.Equal(t, float64(0), allocs)
+}
+
+func BenchmarkCache_Set(b *testing.B) {
+	keys := make([]string, 1024)
+	values := make([]any, len(keys))
+	for i := range keys {
+		keys[i] = "key" + strconv.Itoa(i)
+		// Values are boxed upfront, so that only the allocations of the cache are measured.
+		values[i] = i
+	}
+
+	b.Run("overwrite", func(b *testing.B) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			tc.Set(keys[i%len(keys)], values[i%len(values)], DefaultExpiration)
+		}
+	})
+
+	b.Run("insert", func(b *testing.B) {
+		inserted := make([]string, b.N)
+		for i := range inserted {
+			inserted[i] = "key" + strconv.Itoa(i)
+		}
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			tc.Set(inserted[i], values[i%len(values)], DefaultExpiration)
+		}
+	})
+
+	b.Run("mixed", func(b *testing.B) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			key := keys[i%len(keys)]
+			switch i % 4 {
+			case 0:
+				tc.Delete(key)
+			case 1:
+				tc.Get(key)
+			default:
+				tc.Set(key, values[i%len(values)], DefaultExpiration)
+			}
+		}
+	})
+}
+
+func TestCache_ExpiredErrors(t *testing.T) {
+	newExpiredCache := func() (*Cache, *fakeClock) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		tc.Set("expired", "aValue", time.Minute)
+		tc.Set("live", "aValue", NoExpiration)
+		clock.Advance(time.Minute)
+		return tc, clock
+	}
+
+	t.Run("addLive", func(t *testing.T) {
+		tc, _ := newExpiredCache()
+		defer tc.Stop()
+
+		err := tc.Add("live", "bValue", NoExpiration)
+		assert.ErrorIs(t, err, ErrItemAlreadyExists)
+		assert.NotErrorIs(t, err, ErrItemExpired)
+		assert.Equal(t, uint64(0), tc.Stats().ExpiredDisplaced)
+	})
+
+	t.Run("addExpired", func(t *testing.T) {
+		tc, _ := newExpiredCache()
+		defer tc.Stop()
+
+		assert.NoError(t, tc.Add("expired", "bValue", NoExpiration))
+		assert.NoError(t, tc.Add("missing", "bValue", NoExpiration))
+		assert.Equal(t, uint64(1), tc.Stats().ExpiredDisplaced)
+	})
+
+	t.Run("replaceMissing", func(t *testing.T) {
+		tc, _ := newExpiredCache()
+		defer tc.Stop()
+
+		err := tc.Replace("missing", "bValue", NoExpiration)
+		assert.ErrorIs(t, err, ErrItemNotFound)
+		assert.NotErrorIs(t, err, ErrItemExpired)
+	})
+
+	t.Run("replaceExpired", func(t *testing.T) {
+		tc, _ := newExpiredCache()
+		defer tc.Stop()
+
+		err := tc.Replace("expired", "bValue", NoExpiration)
+		assert.ErrorIs(t, err, ErrItemNotFound)
+		assert.ErrorIs(t, err, ErrItemExpired)
+		assert.ErrorContains(t, err, "expired")
+
+		// Once deleted, the item is just missing.
+		tc.DeleteExpired()
+		assert.NotErrorIs(t, tc.Replace("expired", "bValue", NoExpiration), ErrItemExpired)
+	})
+}
+
+func TestCache_ExpiringIndex(t *testing.T) {
+	expiring := func(tc *Cache) []string {
+		tc.mu.RLock()
+		defer tc.mu.RUnlock()
+
+		keys := make([]string, 0, len(tc.expiring))
+		for key := range tc.expiring {
+			keys = append(keys, key)
+		}
+		return keys
+	}
+
+	t.Run("tracksDeadlines", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", "bValue", time.Minute)
+		tc.Set("cKey", "cValue", time.Minute)
+		assert.ElementsMatch(t, []string{"bKey", "cKey"}, expiring(tc))
+
+		// Moving between expiring and non-expiring.
+		tc.Set("aKey", "aValue", time.Minute)
+		tc.Set("bKey", "bValue", NoExpiration)
+		assert.ElementsMatch(t, []string{"aKey", "cKey"}, expiring(tc))
+		assert.NoError(t, tc.Touch("cKey", NoExpiration))
+		assert.ElementsMatch(t, []string{"aKey"}, expiring(tc))
+
+		tc.Delete("aKey")
+		assert.Empty(t, expiring(tc))
+
+		tc.Set("dKey", "dValue", time.Minute)
+		tc.Flush()
+		assert.Empty(t, expiring(tc))
+	})
+
+	t.Run("leases", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		_, token, err := tc.Acquire("aKey", time.Minute)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"aKey"}, expiring(tc))
+		assert.NoError(t, tc.Release("aKey", token))
+		assert.Empty(t, expiring(tc))
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		for i := 0; i < 100; i++ {
+			tc.Set("persistent"+strconv.Itoa(i), i, NoExpiration)
+			tc.Set("expiring"+strconv.Itoa(i), i, time.Duration(i+1)*time.Second)
+		}
+		clock.Advance(50 * time.Second)
+		tc.DeleteExpired()
+
+		assert.Equal(t, 150, tc.ItemCount())
+		assert.Len(t, expiring(tc), 50)
+		_, found := tc.Get("expiring50")
+		assert.True(t, found)
+	})
+}
+
+func BenchmarkCache_DeleteExpired(b *testing.B) {
+	tc := NewCache(DefaultExpiration, 0)
+	defer tc.Stop()
+
+	for i := 0; i < 1_000_000; i++ {
+		tc.Set("persistent"+strconv.Itoa(i), i, NoExpiration)
+	}
+	for i := 0; i < 10_000; i++ {
+		tc.Set("expiring"+strconv.Itoa(i), i, time.Hour)
+	}
+
+	// Nothing expires: each pass only looks at the 10k items with a deadline.
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tc.DeleteExpired()
+	}
+}
@@ -0,0 +1,225 @@
+package go_cache
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// onFullInterval Minimum time between two calls to the function set WithOnFull.
+const onFullInterval = time.Second
+
+// CapacityPressure Describes a cache evicting items to stay within its capacity limits, see WithOnFull.
+type CapacityPressure struct {
+	Items    int
+	MaxItems int
+	Cost     int64
+	MaxCost  int64
+	// Evictions Number of items evicted since the previous report, or since the cache was created for the first one.
+	Evictions uint64
+	// Interval Time elapsed since the previous report, 0 for the first one.
+	Interval time.Duration
+}
+
+// EvictionRate Returns the number of items evicted per second since the previous report, 0 for the first one.
+func (p CapacityPressure) EvictionRate() float64 {
+	if p.Interval <= 0 {
+		return 0
+	}
+
+	return float64(p.Evictions) / p.Interval.Seconds()
+}
+
+// capacity Tracks what counts against the capacity limits of the cache, see WithMaxItems and WithMaxCost.
+type capacity struct {
+	maxItems int
+	maxCost  int64
+	// cost is the total estimated size of the items, as computed by the sizer when they were written.
+	cost int64
+	// policies order the items of each priority, from PriorityLow, those of PriorityNormal being ordered by the
+	// policy of the cache, and those of the other priorities by a policy of the same kind created on their first item.
+	policies [priorityLevels]EvictionPolicy
+	// expired holds the expired items kept WithRetainExpired, evicted before asking the policy for a victim.
+	expired *insertionOrder
+
+	// lastFull is the time of the last report to the function set WithOnFull, and lastEvictions the number of
+	// evictions counted in the stats at that time.
+	lastFull      time.Time
+	lastEvictions uint64
+	// pressure is the report to make once the write lock is released, see unlock.
+	pressure *CapacityPressure
+}
+
+// over Reports whether the given number of items, and the tracked cost, exceed the limits.
+func (cp *capacity) over(items int) bool {
+	return (cp.maxItems > 0 && items > cp.maxItems) || (cp.maxCost > 0 && cp.cost > cp.maxCost)
+}
+
+// trackCapacity Starts tracking the items of the cache against its capacity limits, if not done already.
+// Items already in the cache are ordered by the time their value was written.
+// It must be called with the write lock held.
+func (c *Cache) trackCapacity() {
+	if c.capacity != nil {
+		return
+	}
+
+	policy := c.evictionPolicy
+	if policy == nil {
+		policy = NewFIFOPolicy()
+	}
+	c.capacity = &capacity{expired: newInsertionOrder(false)}
+	c.capacity.policies[PriorityNormal.level()] = policy
+	keys := make([]string, 0, len(c.items))
+	for key, it := range c.items {
+		if !it.fixedCost {
+			it.cost = c.sizer(key, it.object)
+			c.items[key] = it
+		}
+		c.capacity.cost += it.cost
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		return cmp.Compare(c.items[a].created, c.items[b].created)
+	})
+	for _, key := range keys {
+		c.capacity.added(key, c.items[key].priority, 0, false)
+	}
+}
+
+// victim Returns the key of the next item to evict, expired items kept WithRetainExpired first, then the items of
+// the lowest priority.
+func (cp *capacity) victim() (string, bool) {
+	if e := cp.expired.keys.Front(); e != nil {
+		return e.Value.(string), true
+	}
+	for _, policy := range cp.policies {
+		if policy == nil {
+			continue
+		}
+		if key, found := policy.Victim(); found {
+			return key, true
+		}
+	}
+
+	return "", false
+}
+
+// added Tracks the key written with the given priority against the capacity limits, moving it from the policy of
+// its previous priority if it replaced an item.
+func (cp *capacity) added(key string, p, previous Priority, replaced bool) {
+	if replaced && previous != p {
+		cp.policies[previous.level()].OnRemove(key)
+	}
+	policy := cp.policies[p.level()]
+	if policy == nil {
+		policy = newPolicyLike(cp.policies[PriorityNormal.level()])
+		cp.policies[p.level()] = policy
+	}
+	policy.OnAdd(key)
+}
+
+// accessed Tells the policy of the priority of the key about a hit. It is called with the read lock held.
+func (cp *capacity) accessed(key string, p Priority) {
+	cp.policies[p.level()].OnAccess(key)
+}
+
+// removed Stops tracking the key of the given priority against the capacity limits.
+func (cp *capacity) removed(key string, p Priority) {
+	cp.expired.removed(key)
+	cp.policies[p.level()].OnRemove(key)
+}
+
+// evict Removes the items picked by the eviction policy until the cache is back within its capacity limits, or until
+// the given number of items was removed if it is positive, and returns the number of items removed.
+// It must be called with the write lock held.
+func (c *Cache) evict(limit int) int {
+	n := 0
+	for ; c.capacity.over(len(c.items)) && (limit <= 0 || n < limit); n++ {
+		key, found := c.capacity.victim()
+		if !found {
+			break
+		}
+		c.delete(key, removalEvicted)
+	}
+
+	return n
+}
+
+// full Prepares a report for the function set WithOnFull after a write caused evictions, unless one was made
+// less than onFullInterval ago. It must be called with the write lock held.
+func (c *Cache) full() {
+	now := c.now()
+	cp := c.capacity
+	if c.onFull == nil || (!cp.lastFull.IsZero() && now.Sub(cp.lastFull) < onFullInterval) {
+		return
+	}
+
+	evictions := c.stats.removals[removalEvicted].Load()
+	cp.pressure = &CapacityPressure{
+		Items:     len(c.items),
+		MaxItems:  cp.maxItems,
+		Cost:      cp.cost,
+		MaxCost:   cp.maxCost,
+		Evictions: evictions - cp.lastEvictions,
+	}
+	if !cp.lastFull.IsZero() {
+		cp.pressure.Interval = now.Sub(cp.lastFull)
+	}
+	cp.lastFull, cp.lastEvictions = now, evictions
+}
+
+// SetMaxItems Changes the maximum number of items held by the cache, see WithMaxItems. Shrinking the limit evicts
+// items right away, in batches so that other operations are not blocked for the whole pass.
+// A limit less than one removes it, without removing any item.
+func (c *Cache) SetMaxItems(n int) {
+	c.setCapacity(func(cp *capacity) {
+		cp.maxItems = max(n, 0)
+	})
+}
+
+// SetMaxCost Changes the maximum total estimated size of the items held by the cache, see WithMaxCost.
+// Shrinking the limit evicts items right away like SetMaxItems.
+// A limit less than one removes it, without removing any item.
+func (c *Cache) SetMaxCost(cost int64) {
+	c.setCapacity(func(cp *capacity) {
+		cp.maxCost = max(cost, 0)
+	})
+}
+
+func (c *Cache) setCapacity(update func(cp *capacity)) {
+	c.mu.Lock()
+	c.trackCapacity()
+	update(c.capacity)
+	n := c.evict(iterationChunkSize)
+	c.unlock()
+
+	for n == iterationChunkSize {
+		c.mu.Lock()
+		n = c.evict(iterationChunkSize)
+		c.unlock()
+	}
+}
+
+// MaxItems Returns the maximum number of items held by the cache, 0 if there is no limit.
+func (c *Cache) MaxItems() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.capacity == nil {
+		return 0
+	}
+
+	return c.capacity.maxItems
+}
+
+// MaxCost Returns the maximum total estimated size of the items held by the cache, 0 if there is no limit.
+func (c *Cache) MaxCost() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.capacity == nil {
+		return 0
+	}
+
+	return c.capacity.maxCost
+}
@@ -0,0 +1,206 @@
+package go_cache
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// orderedEvictable Records the order in which values are evicted.
+type orderedEvictable struct {
+	key     int
+	evicted *[]int
+}
+
+func (oe orderedEvictable) OnEvict() {
+	*oe.evicted = append(*oe.evicted, oe.key)
+}
+
+func TestCache_WithMaxItems(t *testing.T) {
+	t.Run("evictsOldestWritten", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMaxItems(2))
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", "bValue", NoExpiration)
+		// Writing aKey again makes bKey the oldest.
+		tc.Set("aKey", "aValue2", NoExpiration)
+		tc.Set("cKey", "cValue", NoExpiration)
+
+		_, found := tc.Get("bKey")
+		assert.False(t, found)
+		assert.Equal(t, 2, tc.ItemCount())
+		assert.Equal(t, uint64(1), tc.Stats().Evictions)
+	})
+
+	t.Run("maxCost", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMaxCost(10))
+		defer tc.Stop()
+
+		tc.Set("aKey", "12345", NoExpiration)
+		tc.Set("bKey", "12345", NoExpiration)
+		assert.Equal(t, 2, tc.ItemCount())
+
+		tc.Set("cKey", "123", NoExpiration)
+		_, found := tc.Get("aKey")
+		assert.False(t, found)
+
+		// An item larger than the cap is not kept.
+		tc.Set("dKey", "12345678901", NoExpiration)
+		_, found = tc.Get("dKey")
+		assert.False(t, found)
+		assert.Equal(t, 0, tc.ItemCount())
+	})
+}
+
+func TestCache_SetMaxItems(t *testing.T) {
+	t.Run("shrinkAndGrow", func(t *testing.T) {
+		var evicted []int
+		tc := NewCacheWithOptions(WithMaxItems(10_000), WithCloseOnEvict())
+		defer tc.Stop()
+
+		for i := 0; i < 10_000; i++ {
+			tc.Set(strconv.Itoa(i), orderedEvictable{key: i, evicted: &evicted}, NoExpiration)
+		}
+		assert.Empty(t, evicted)
+
+		tc.SetMaxItems(1_000)
+		assert.Equal(t, 1_000, tc.MaxItems())
+		assert.Equal(t, 1_000, tc.ItemCount())
+		assert.Equal(t, uint64(9_000), tc.Stats().Evictions)
+		if assert.Len(t, evicted, 9_000) {
+			for i, key := range evicted {
+				if !assert.Equal(t, i, key) {
+					break
+				}
+			}
+		}
+
+		tc.SetMaxItems(20_000)
+		for i := 10_000; i < 15_000; i++ {
+			tc.Set(strconv.Itoa(i), i, NoExpiration)
+		}
+		assert.Equal(t, 6_000, tc.ItemCount())
+		assert.Equal(t, uint64(9_000), tc.Stats().Evictions)
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMaxItems(10))
+		defer tc.Stop()
+
+		for i := 0; i < 10; i++ {
+			tc.Set(strconv.Itoa(i), i, NoExpiration)
+		}
+		tc.SetMaxItems(0)
+		assert.Equal(t, 0, tc.MaxItems())
+		for i := 10; i < 20; i++ {
+			tc.Set(strconv.Itoa(i), i, NoExpiration)
+		}
+		assert.Equal(t, 20, tc.ItemCount())
+		assert.Equal(t, uint64(0), tc.Stats().Evictions)
+	})
+
+	t.Run("withoutInitialLimit", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		for i := 0; i < 10; i++ {
+			tc.Set(strconv.Itoa(i), i, NoExpiration)
+			clock.Advance(1)
+		}
+		tc.SetMaxItems(5)
+
+		// Items written before the limit was set are evicted in the order they were written.
+		for i := 0; i < 10; i++ {
+			_, found := tc.Get(strconv.Itoa(i))
+			assert.Equal(t, i >= 5, found, i)
+		}
+	})
+}
+
+func TestCache_SetMaxCost(t *testing.T) {
+	tc := NewCache(DefaultExpiration, 0)
+	defer tc.Stop()
+
+	for i := 0; i < 10; i++ {
+		tc.Set(strconv.Itoa(i), "1234567890", NoExpiration)
+	}
+	tc.SetMaxCost(50)
+	assert.Equal(t, int64(50), tc.MaxCost())
+	assert.Equal(t, 5, tc.ItemCount())
+
+	tc.Delete("9")
+	tc.Set("aKey", "1234567890", NoExpiration)
+	assert.Equal(t, 5, tc.ItemCount())
+	assert.Equal(t, uint64(5), tc.Stats().Evictions)
+
+	tc.SetMaxCost(0)
+	tc.Set("bKey", "1234567890", NoExpiration)
+	assert.Equal(t, 6, tc.ItemCount())
+}
+
+func TestCache_WithOnFull(t *testing.T) {
+	t.Run("rateLimited", func(t *testing.T) {
+		// Empty values have no cost, so that only counts show in reports.
+		var reports []CapacityPressure
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithMaxItems(10), WithOnFull(func(pressure CapacityPressure) {
+			reports = append(reports, pressure)
+		}))
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		for i := 0; i < 10; i++ {
+			tc.Set(strconv.Itoa(i), struct{}{}, NoExpiration)
+		}
+		assert.Empty(t, reports)
+
+		for i := 10; i < 100; i++ {
+			tc.Set(strconv.Itoa(i), struct{}{}, NoExpiration)
+		}
+		if assert.Len(t, reports, 1) {
+			assert.Equal(t, CapacityPressure{Items: 10, MaxItems: 10, Evictions: 1}, reports[0])
+			assert.Equal(t, float64(0), reports[0].EvictionRate())
+		}
+
+		clock.Advance(onFullInterval / 2)
+		tc.Set("aKey", struct{}{}, NoExpiration)
+		assert.Len(t, reports, 1)
+
+		clock.Advance(onFullInterval / 2)
+		tc.Set("bKey", struct{}{}, NoExpiration)
+		if assert.Len(t, reports, 2) {
+			assert.Equal(t, CapacityPressure{Items: 10, MaxItems: 10, Evictions: 91, Interval: onFullInterval}, reports[1])
+			assert.Equal(t, float64(91), reports[1].EvictionRate())
+		}
+	})
+
+	t.Run("callsBackIntoCache", func(t *testing.T) {
+		var stats Stats
+		tc := NewCacheWithOptions(WithMaxItems(1))
+		defer tc.Stop()
+		tc.onFull = func(CapacityPressure) {
+			stats = tc.Stats()
+		}
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", "bValue", NoExpiration)
+		assert.Equal(t, uint64(1), stats.Evictions)
+	})
+
+	t.Run("notOnResize", func(t *testing.T) {
+		called := false
+		tc := NewCacheWithOptions(WithOnFull(func(CapacityPressure) {
+			called = true
+		}))
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", "bValue", NoExpiration)
+		tc.SetMaxItems(1)
+		assert.False(t, called)
+	})
+}
@@ -0,0 +1,63 @@
+package go_cache
+
+import "time"
+
+// CleanupPass Describes a pass of the cleanup goroutine, see WithCleanupInterval.
+type CleanupPass struct {
+	// Start The time the pass started.
+	Start time.Time
+	// Duration How long the pass took, including the periodic flush, compaction and the other work it does.
+	Duration time.Duration
+	// Scanned Number of items with an expiration time looked at.
+	Scanned int
+	// Removed Number of expired items deleted.
+	Removed int
+}
+
+// CleanupStats Activity of the cleanup goroutine since the cache was created. Removed items are counted in
+// Removals.Expired as well, along with those deleted by DeleteExpired, which is not a pass of the goroutine.
+type CleanupStats struct {
+	// Passes Number of passes of the cleanup goroutine.
+	Passes uint64
+	// Scanned Total number of items looked at by the passes.
+	Scanned uint64
+	// Removed Total number of expired items deleted by the passes.
+	Removed uint64
+	// Duration Total time spent in the passes.
+	Duration time.Duration
+	// Last The last pass, zero if none happened yet.
+	Last CleanupPass
+}
+
+// runCleanup Makes a pass of the cleanup goroutine, then records it and calls the hook set WithCleanupHook.
+func (c *Cache) runCleanup() {
+	start := c.now()
+	if c.periodicFlush != nil {
+		c.flushIfDue()
+	}
+	scanned, removed := c.deleteExpired(c.retainExpired)
+	if c.compactRatio > 0 {
+		c.compact(c.compactRatio)
+	}
+	if c.bloom != nil && c.bloom.full() {
+		c.RebuildBloomFilter()
+	}
+	if c.errorCache != nil {
+		c.errorCache.deleteExpired(c.now().UnixNano())
+	}
+
+	end := c.now()
+	pass := CleanupPass{Start: start, Duration: end.Sub(start), Scanned: scanned, Removed: removed}
+	c.health.janitorRan(end, pass)
+	if c.onCleanup != nil && !c.isClosed() {
+		c.onCleanup(c, pass)
+	}
+}
+
+// cleanupStats Returns the activity of the cleanup goroutine recorded so far.
+func (h *health) cleanupStats() CleanupStats {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.cleanup
+}
@@ -0,0 +1,103 @@
+package go_cache
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// slowEvictable A value whose release takes a millisecond of the fake clock.
+type slowEvictable struct {
+	clock *fakeClock
+}
+
+func (v *slowEvictable) OnEvict() {
+	v.clock.Advance(time.Millisecond)
+}
+
+func TestCache_CleanupStats(t *testing.T) {
+	t.Run("pass", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithCloseOnEvict())
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		for _, key := range []string{"aKey", "bKey", "cKey"} {
+			tc.Set(key, &slowEvictable{clock: clock}, time.Second)
+		}
+		tc.Set("dKey", "dValue", time.Hour)
+		tc.Set("eKey", "eValue", NoExpiration)
+		clock.Advance(time.Minute)
+		start := clock.Now()
+
+		tc.runCleanup()
+
+		stats := tc.Stats().Cleanup
+		assert.Equal(t, uint64(1), stats.Passes)
+		assert.Equal(t, uint64(4), stats.Scanned)
+		assert.Equal(t, uint64(3), stats.Removed)
+		assert.Equal(t, 3*time.Millisecond, stats.Duration)
+		assert.True(t, start.Equal(stats.Last.Start))
+		assert.Equal(t, 3*time.Millisecond, stats.Last.Duration)
+		assert.Equal(t, 4, stats.Last.Scanned)
+		assert.Equal(t, 3, stats.Last.Removed)
+		assert.Equal(t, uint64(3), tc.Stats().Removals.Expired)
+
+		clock.Advance(time.Hour)
+		tc.runCleanup()
+
+		stats = tc.Stats().Cleanup
+		assert.Equal(t, uint64(2), stats.Passes)
+		assert.Equal(t, uint64(5), stats.Scanned)
+		assert.Equal(t, uint64(4), stats.Removed)
+		assert.Equal(t, 3*time.Millisecond, stats.Duration)
+		assert.Equal(t, CleanupPass{Start: clock.Now(), Scanned: 1, Removed: 1}, stats.Last)
+	})
+
+	t.Run("deleteExpired", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", time.Nanosecond)
+		time.Sleep(time.Millisecond)
+		tc.DeleteExpired()
+
+		assert.Equal(t, CleanupStats{}, tc.Stats().Cleanup)
+		assert.Equal(t, uint64(1), tc.Stats().Removals.Expired)
+	})
+
+	t.Run("health", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithCleanupInterval(time.Hour))
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.runCleanup()
+		clock.Advance(90 * time.Minute)
+
+		report := tc.Health()
+		assert.Equal(t, 90*time.Minute, report.JanitorSinceLastRun)
+		assert.False(t, report.JanitorLate)
+	})
+}
+
+func TestCache_WithCleanupHook(t *testing.T) {
+	var removed atomic.Int64
+	tc := NewCacheWithOptions(
+		WithCleanupInterval(time.Millisecond),
+		WithCleanupHook(func(c *Cache, pass CleanupPass) {
+			removed.Add(int64(pass.Removed))
+		}),
+	)
+	defer tc.Stop()
+
+	tc.Set("aKey", "aValue", time.Nanosecond)
+	tc.Set("bKey", "bValue", time.Hour)
+
+	assert.Eventually(t, func() bool {
+		return removed.Load() == 1
+	}, 5*time.Second, time.Millisecond)
+	assert.Equal(t, uint64(1), tc.Stats().Cleanup.Removed)
+}
@@ -0,0 +1,306 @@
+// Command gocache Inspects and edits the snapshot files written by Cache.Save.
+//
+// Usage:
+//
+//	gocache inspect FILE
+//	gocache get FILE KEY
+//	gocache export FILE --format=json
+//	gocache merge A B -o OUT
+//	gocache expire-check FILE --at=TIME
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	go_cache "github.com/J4NN0/go-cache"
+)
+
+const usage = `usage:
+  gocache inspect FILE                 show counts, a TTL histogram and the largest items
+  gocache get FILE KEY                 show an item
+  gocache export FILE --format=json    write the items as JSON
+  gocache merge A B -o OUT             merge two snapshots, B winning on conflicts
+  gocache expire-check FILE --at=TIME  show which items would survive a load at TIME (RFC 3339)
+`
+
+// largestItems Number of items listed by inspect.
+const largestItems = 10
+
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "gocache:", err)
+		if errors.Is(err, errUsage) {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	cmd, args := args[0], args[1:]
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	format := fs.String("format", "json", "export format")
+	output := fs.String("o", "", "output file")
+	at := fs.String("at", "", "time to check expirations at, in RFC 3339 format")
+
+	positional, err := parse(fs, args)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errUsage, err)
+	}
+
+	switch {
+	case cmd == "inspect" && len(positional) == 1:
+		return withSnapshot(positional[0], func(s go_cache.Snapshot) error {
+			return inspect(out, s)
+		})
+	case cmd == "get" && len(positional) == 2:
+		return withSnapshot(positional[0], func(s go_cache.Snapshot) error {
+			return get(out, s, positional[1])
+		})
+	case cmd == "export" && len(positional) == 1:
+		if *format != "json" {
+			return fmt.Errorf("%w: unsupported format %q", errUsage, *format)
+		}
+		return withSnapshot(positional[0], func(s go_cache.Snapshot) error {
+			return export(out, s)
+		})
+	case cmd == "merge" && len(positional) == 2 && *output != "":
+		return merge(positional[0], positional[1], *output)
+	case cmd == "expire-check" && len(positional) == 1 && *at != "":
+		t, err := time.Parse(time.RFC3339, *at)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errUsage, err)
+		}
+		return withSnapshot(positional[0], func(s go_cache.Snapshot) error {
+			return expireCheck(out, s, t)
+		})
+	default:
+		return errUsage
+	}
+}
+
+// parse Parses the flags of fs found anywhere in args, and returns the positional arguments.
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func withSnapshot(name string, fn func(s go_cache.Snapshot) error) error {
+	s, err := readFile(name)
+	if err != nil {
+		return err
+	}
+
+	return fn(s)
+}
+
+func readFile(name string) (go_cache.Snapshot, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return go_cache.Snapshot{}, err
+	}
+	defer f.Close()
+
+	s, err := go_cache.ReadSnapshot(f)
+	if err != nil {
+		return go_cache.Snapshot{}, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return s, nil
+}
+
+var ttlBuckets = []struct {
+	label string
+	below time.Duration
+}{
+	{"< 1m", time.Minute},
+	{"< 1h", time.Hour},
+	{"< 1d", 24 * time.Hour},
+	{">= 1d", 1<<63 - 1},
+}
+
+func inspect(out io.Writer, s go_cache.Snapshot) error {
+	var never, expired int
+	counts := make([]int, len(ttlBuckets))
+	for _, item := range s.Items {
+		switch {
+		case item.Expiration.IsZero():
+			never++
+		case item.Expired(s.CreatedAt):
+			expired++
+		default:
+			ttl := item.Expiration.Sub(s.CreatedAt)
+			for i, bucket := range ttlBuckets {
+				if ttl < bucket.below {
+					counts[i]++
+					break
+				}
+			}
+		}
+	}
+
+	fmt.Fprintf(out, "Snapshot taken at %s\n", s.CreatedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(out, "Items: %d (%d never expire, %d expired when taken)\n", len(s.Items), never, expired)
+	fmt.Fprintf(out, "\nTTL when taken:\n")
+	fmt.Fprintf(out, "  %-8s %d\n", "expired", expired)
+	for i, bucket := range ttlBuckets {
+		fmt.Fprintf(out, "  %-8s %d\n", bucket.label, counts[i])
+	}
+	fmt.Fprintf(out, "  %-8s %d\n", "never", never)
+
+	items := append([]go_cache.SnapshotItem(nil), s.Items...)
+	sort.Slice(items, func(i, j int) bool {
+		if len(items[i].Data) != len(items[j].Data) {
+			return len(items[i].Data) > len(items[j].Data)
+		}
+		return items[i].Key < items[j].Key
+	})
+	fmt.Fprintf(out, "\nLargest items:\n")
+	for i, item := range items[:min(largestItems, len(items))] {
+		fmt.Fprintf(out, "  %2d. %s (%d bytes, %s)\n", i+1, item.Key, len(item.Data), item.Type)
+	}
+
+	return nil
+}
+
+func get(out io.Writer, s go_cache.Snapshot, key string) error {
+	for _, item := range s.Items {
+		if item.Key != key {
+			continue
+		}
+		fmt.Fprintf(out, "Key:        %s\n", item.Key)
+		fmt.Fprintf(out, "Type:       %s\n", item.Type)
+		fmt.Fprintf(out, "Expiration: %s\n", expiration(item))
+		fmt.Fprintf(out, "Size:       %d bytes\n", len(item.Data))
+		if value, err := (go_cache.GobCodec{}).Unmarshal(item.Data); err == nil {
+			fmt.Fprintf(out, "Value:      %v\n", value)
+		} else {
+			fmt.Fprintf(out, "Data:       %s\n", base64.StdEncoding.EncodeToString(item.Data))
+		}
+		return nil
+	}
+
+	return fmt.Errorf("key %q not found", key)
+}
+
+type exportedItem struct {
+	Key        string     `json:"key"`
+	Type       string     `json:"type"`
+	Expiration *time.Time `json:"expiration,omitempty"`
+	// Data The encoded value, as base64.
+	Data []byte `json:"data"`
+	// Value The decoded value, if it could be decoded with the default codec and represented as JSON.
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+func export(out io.Writer, s go_cache.Snapshot) error {
+	items := make([]exportedItem, 0, len(s.Items))
+	for _, item := range s.Items {
+		e := exportedItem{Key: item.Key, Type: item.Type, Data: item.Data}
+		if !item.Expiration.IsZero() {
+			expiration := item.Expiration.UTC()
+			e.Expiration = &expiration
+		}
+		if value, err := (go_cache.GobCodec{}).Unmarshal(item.Data); err == nil {
+			if raw, err := json.Marshal(value); err == nil {
+				e.Value = raw
+			}
+		}
+		items = append(items, e)
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(items)
+}
+
+func merge(a, b, output string) error {
+	sa, err := readFile(a)
+	if err != nil {
+		return err
+	}
+	sb, err := readFile(b)
+	if err != nil {
+		return err
+	}
+
+	merged := go_cache.Snapshot{CreatedAt: sa.CreatedAt}
+	if sb.CreatedAt.After(merged.CreatedAt) {
+		merged.CreatedAt = sb.CreatedAt
+	}
+	items := make(map[string]go_cache.SnapshotItem, len(sa.Items)+len(sb.Items))
+	for _, item := range append(sa.Items, sb.Items...) {
+		items[item.Key] = item
+	}
+	for _, item := range items {
+		merged.Items = append(merged.Items, item)
+	}
+	sort.Slice(merged.Items, func(i, j int) bool {
+		return merged.Items[i].Key < merged.Items[j].Key
+	})
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := go_cache.WriteSnapshot(f, merged); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func expireCheck(out io.Writer, s go_cache.Snapshot, at time.Time) error {
+	var expired []go_cache.SnapshotItem
+	for _, item := range s.Items {
+		if item.Expired(at) {
+			expired = append(expired, item)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].Key < expired[j].Key
+	})
+
+	fmt.Fprintf(out, "At %s, %d of %d items would be loaded, %d skipped as expired\n",
+		at.UTC().Format(time.RFC3339), len(s.Items)-len(expired), len(s.Items), len(expired))
+	for _, item := range expired {
+		fmt.Fprintf(out, "  %s (expired %s)\n", item.Key, expiration(item))
+	}
+
+	return nil
+}
+
+func expiration(item go_cache.SnapshotItem) string {
+	if item.Expiration.IsZero() {
+		return "never"
+	}
+
+	return item.Expiration.UTC().Format(time.RFC3339)
+}
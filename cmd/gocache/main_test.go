@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	go_cache "github.com/J4NN0/go-cache"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+var createdAt = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// opaque A value the command cannot decode, as for types registered by the application which saved the snapshot.
+type opaque []byte
+
+// writeFixture Writes a snapshot holding the given items to a temporary file, and returns its path.
+func writeFixture(t *testing.T, createdAt time.Time, items map[string]any, expirations map[string]time.Time) string {
+	t.Helper()
+
+	s := go_cache.Snapshot{CreatedAt: createdAt}
+	for _, key := range sortedKeys(items) {
+		item := go_cache.SnapshotItem{Key: key, Type: "string", Expiration: expirations[key]}
+		if data, ok := items[key].(opaque); ok {
+			item.Type, item.Data = "app.Custom", data
+		} else {
+			var err error
+			item.Data, err = go_cache.GobCodec{}.Marshal(items[key])
+			assert.NoError(t, err)
+		}
+		s.Items = append(s.Items, item)
+	}
+
+	name := filepath.Join(t.TempDir(), "snapshot")
+	f, err := os.Create(name)
+	assert.NoError(t, err)
+	defer f.Close()
+	assert.NoError(t, go_cache.WriteSnapshot(f, s))
+
+	return name
+}
+
+func sortedKeys(items map[string]any) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func fixture(t *testing.T) string {
+	return writeFixture(t, createdAt, map[string]any{
+		"aKey":    "aValue",
+		"bKey":    "a somewhat longer value",
+		"cKey":    opaque{0x0c, 0xff, 0x81, 0x03, 0x01, 0x01},
+		"dKey":    "dValue",
+		"eKey":    "eValue",
+		"stale":   "old",
+		"forever": "ever",
+	}, map[string]time.Time{
+		"aKey":  createdAt.Add(30 * time.Second),
+		"bKey":  createdAt.Add(10 * time.Minute),
+		"cKey":  createdAt.Add(2 * time.Hour),
+		"dKey":  createdAt.Add(48 * time.Hour),
+		"eKey":  createdAt.Add(5 * time.Minute),
+		"stale": createdAt.Add(-time.Minute),
+	})
+}
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	golden := filepath.Join("testdata", name+".golden")
+	if *update {
+		assert.NoError(t, os.WriteFile(golden, got, 0o644))
+	}
+	want, err := os.ReadFile(golden)
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+func TestRun_Golden(t *testing.T) {
+	file := fixture(t)
+
+	for name, args := range map[string][]string{
+		"inspect":      {"inspect", file},
+		"get":          {"get", file, "aKey"},
+		"getCustom":    {"get", file, "cKey"},
+		"export":       {"export", file, "--format=json"},
+		"expire-check": {"expire-check", file, "--at=2024-01-01T12:06:00Z"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			assert.NoError(t, run(args, &out))
+			assertGolden(t, name, out.Bytes())
+		})
+	}
+}
+
+func TestRun_Merge(t *testing.T) {
+	a := writeFixture(t, createdAt, map[string]any{
+		"aKey": "aValue",
+		"bKey": "bValue",
+	}, map[string]time.Time{
+		"aKey": createdAt.Add(time.Minute),
+	})
+	b := writeFixture(t, createdAt.Add(time.Hour), map[string]any{
+		"bKey": "newValue",
+		"cKey": "cValue",
+	}, map[string]time.Time{
+		"bKey": createdAt.Add(2 * time.Hour),
+	})
+	out := filepath.Join(t.TempDir(), "merged")
+
+	assert.NoError(t, run([]string{"merge", a, b, "-o", out}, &bytes.Buffer{}))
+
+	merged, err := readFile(out)
+	assert.NoError(t, err)
+	assert.True(t, merged.CreatedAt.Equal(createdAt.Add(time.Hour)))
+	if assert.Len(t, merged.Items, 3) {
+		want := map[string]struct {
+			value      any
+			expiration time.Time
+		}{
+			"aKey": {"aValue", createdAt.Add(time.Minute)},
+			"bKey": {"newValue", createdAt.Add(2 * time.Hour)},
+			"cKey": {"cValue", time.Time{}},
+		}
+		for _, item := range merged.Items {
+			value, err := go_cache.GobCodec{}.Unmarshal(item.Data)
+			assert.NoError(t, err)
+			assert.Equal(t, want[item.Key].value, value, item.Key)
+			assert.True(t, want[item.Key].expiration.Equal(item.Expiration), item.Key)
+		}
+	}
+
+	// The merged snapshot loads into a cache.
+	tc := go_cache.NewCache(go_cache.NoExpiration, 0)
+	defer tc.Stop()
+	f, err := os.Open(out)
+	assert.NoError(t, err)
+	defer f.Close()
+	assert.NoError(t, tc.Load(f))
+	value, found := tc.Get("cKey")
+	assert.True(t, found)
+	assert.Equal(t, "cValue", value)
+}
+
+func TestRun_Errors(t *testing.T) {
+	file := fixture(t)
+
+	t.Run("usage", func(t *testing.T) {
+		for _, args := range [][]string{
+			nil,
+			{"unknown", file},
+			{"inspect"},
+			{"merge", file, file},
+			{"expire-check", file},
+			{"expire-check", file, "--at=yesterday"},
+			{"export", file, "--format=xml"},
+		} {
+			assert.ErrorIs(t, run(args, &bytes.Buffer{}), errUsage, args)
+		}
+	})
+
+	t.Run("missingKey", func(t *testing.T) {
+		err := run([]string{"get", file, "zKey"}, &bytes.Buffer{})
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, errUsage)
+	})
+
+	t.Run("invalidSnapshot", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "invalid")
+		assert.NoError(t, os.WriteFile(name, []byte("not a snapshot"), 0o644))
+		assert.ErrorIs(t, run([]string{"inspect", name}, &bytes.Buffer{}), go_cache.ErrInvalidSnapshot)
+	})
+}
@@ -0,0 +1,82 @@
+package go_cache
+
+import "time"
+
+// coalescer Holds back the write events of the keys written again within the window set WithWriteCoalescing.
+type coalescer struct {
+	window time.Duration
+	// keys holds the keys whose last event was sent less than a window ago.
+	keys map[string]*coalescedKey
+}
+
+// coalescedKey The latest write event held back for a key until the end of its window, if pending is set.
+type coalescedKey struct {
+	timer   *time.Timer
+	pending bool
+	op      WatchOp
+	value   any
+}
+
+// coalesce Sends the write event of the key right away if no event was sent for it within the window, holding it
+// back until the end of the window otherwise, in place of any event held back already.
+// It must be called with the write lock held.
+func (c *Cache) coalesce(key string, op WatchOp, value any) {
+	k, found := c.coalescer.keys[key]
+	if !found {
+		c.send(key, op, value)
+		k = &coalescedKey{}
+		k.timer = time.AfterFunc(c.coalescer.window, func() {
+			c.windowEnded(key, k)
+		})
+		c.coalescer.keys[key] = k
+		return
+	}
+
+	// A key which held no live value at the start of the window was set, whatever the writes within it.
+	if !k.pending {
+		k.op = op
+	}
+	k.pending, k.value = true, value
+}
+
+// windowEnded Sends the event held back for the key, if any, and starts a new window for it.
+func (c *Cache) windowEnded(key string, k *coalescedKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.coalescer.keys[key] != k {
+		return
+	}
+	if !k.pending {
+		delete(c.coalescer.keys, key)
+		return
+	}
+	c.send(key, k.op, k.value)
+	k.pending, k.value = false, nil
+	k.timer.Reset(c.coalescer.window)
+}
+
+// endWindow Sends the event held back for the key, if any, so that it comes before the events which follow.
+// It must be called with the write lock held.
+func (c *Cache) endWindow(key string) {
+	k, found := c.coalescer.keys[key]
+	if !found {
+		return
+	}
+	k.timer.Stop()
+	delete(c.coalescer.keys, key)
+	if k.pending {
+		c.send(key, k.op, k.value)
+	}
+}
+
+// drainCoalesced Sends all the events held back, e.g. before the cache is flushed or stopped.
+// It must be called with the write lock held.
+func (c *Cache) drainCoalesced() {
+	if c.coalescer == nil {
+		return
+	}
+	for key := range c.coalescer.keys {
+		c.endWindow(key)
+	}
+}
@@ -0,0 +1,105 @@
+package go_cache
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_WithWriteCoalescing(t *testing.T) {
+	t.Run("rapidWrites", func(t *testing.T) {
+		const window = 50 * time.Millisecond
+		tc := NewCacheWithOptions(WithWriteCoalescing(window))
+		defer tc.Stop()
+
+		events := tc.Watch(context.Background(), "aKey")
+		start := time.Now()
+		for i := 0; i < 1_000; i++ {
+			tc.Set("aKey", i, NoExpiration)
+		}
+		elapsed := time.Since(start)
+
+		value, _ := tc.Get("aKey")
+		assert.Equal(t, 999, value)
+
+		// The first write is sent right away, then at most one per window.
+		first := receive(t, events)
+		assert.Equal(t, WatchEvent{Key: "aKey", Op: WatchSet, Value: 0}, first)
+		received := 1
+		last := first
+		for last.Value != 999 {
+			last = receive(t, events)
+			received++
+		}
+		assert.Equal(t, WatchReplace, last.Op)
+		assert.LessOrEqual(t, received, 2+int(elapsed/window))
+
+		select {
+		case event := <-events:
+			t.Fatalf("unexpected event %v", event)
+		case <-time.After(2 * window):
+		}
+	})
+
+	t.Run("keysCoalescedSeparately", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithWriteCoalescing(time.Hour))
+		defer tc.Stop()
+
+		events := tc.WatchPrefix(context.Background(), "")
+		for i := 0; i < 3; i++ {
+			tc.Set("aKey", i, NoExpiration)
+			tc.Set("bKey", strconv.Itoa(i), NoExpiration)
+		}
+		assert.Equal(t, WatchEvent{Key: "aKey", Op: WatchSet, Value: 0}, receive(t, events))
+		assert.Equal(t, WatchEvent{Key: "bKey", Op: WatchSet, Value: "0"}, receive(t, events))
+		assert.Len(t, events, 0)
+	})
+
+	t.Run("deleteSendsHeldEventFirst", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithWriteCoalescing(time.Hour))
+		defer tc.Stop()
+
+		events := tc.Watch(context.Background(), "aKey")
+		tc.Set("aKey", 1, NoExpiration)
+		tc.Set("aKey", 2, NoExpiration)
+		tc.Set("aKey", 3, NoExpiration)
+		tc.Delete("aKey")
+		// A new window starts once the key was removed.
+		tc.Set("aKey", 4, NoExpiration)
+
+		assert.Equal(t, WatchEvent{Key: "aKey", Op: WatchSet, Value: 1}, receive(t, events))
+		assert.Equal(t, WatchEvent{Key: "aKey", Op: WatchReplace, Value: 3}, receive(t, events))
+		assert.Equal(t, WatchEvent{Key: "aKey", Op: WatchDelete, Value: 3}, receive(t, events))
+		assert.Equal(t, WatchEvent{Key: "aKey", Op: WatchSet, Value: 4}, receive(t, events))
+	})
+
+	t.Run("flushDrains", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithWriteCoalescing(time.Hour))
+		defer tc.Stop()
+
+		events := tc.Watch(context.Background(), "aKey")
+		tc.Set("aKey", 1, NoExpiration)
+		tc.Set("aKey", 2, NoExpiration)
+		tc.Flush()
+
+		assert.Equal(t, WatchEvent{Key: "aKey", Op: WatchSet, Value: 1}, receive(t, events))
+		assert.Equal(t, WatchEvent{Key: "aKey", Op: WatchReplace, Value: 2}, receive(t, events))
+		assert.Equal(t, WatchEvent{Key: "aKey", Op: WatchDelete, Value: 2}, receive(t, events))
+	})
+
+	t.Run("stopDrains", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithWriteCoalescing(time.Hour))
+
+		events := tc.Watch(context.Background(), "aKey")
+		tc.Set("aKey", 1, NoExpiration)
+		tc.Set("aKey", 2, NoExpiration)
+		tc.Stop()
+
+		assert.Equal(t, WatchEvent{Key: "aKey", Op: WatchSet, Value: 1}, receive(t, events))
+		assert.Equal(t, WatchEvent{Key: "aKey", Op: WatchReplace, Value: 2}, receive(t, events))
+		assertClosed(t, events)
+	})
+}
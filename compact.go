@@ -0,0 +1,31 @@
+package go_cache
+
+// Compact Rebuilds the internal map of the cache into a right-sized one if items have been deleted since
+// it was allocated, releasing the memory held by the buckets of the deleted items, which Go maps never shrink.
+// The whole map is copied while holding the write lock, so it should be called sparingly on large caches.
+func (c *Cache) Compact() {
+	c.compact(1)
+}
+
+// compact Rebuilds the items map if the number of items dropped below the given ratio of its peak.
+func (c *Cache) compact(ratio float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if float64(len(c.items)) >= float64(c.peak)*ratio {
+		return
+	}
+
+	items := make(map[string]item, len(c.items))
+	for key, item := range c.items {
+		items[key] = item
+	}
+	c.items = items
+	c.peak = len(items)
+
+	expiring := make(map[string]struct{}, len(c.expiring))
+	for key := range c.expiring {
+		expiring[key] = struct{}{}
+	}
+	c.expiring = expiring
+}
@@ -0,0 +1,92 @@
+package go_cache
+
+import (
+	"reflect"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func heapInUse() uint64 {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.HeapInuse
+}
+
+func TestCache_Compact(t *testing.T) {
+	t.Run("reclaimsMemoryAfterMassDelete", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		const n = 200_000
+		for i := 0; i < n; i++ {
+			tc.Set(strconv.Itoa(i), i, DefaultExpiration)
+		}
+		for i := 0; i < n-n/100; i++ {
+			tc.Delete(strconv.Itoa(i))
+		}
+
+		before := heapInUse()
+		tc.Compact()
+		after := heapInUse()
+
+		assert.Less(t, after, before)
+		assert.Equal(t, n/100, tc.ItemCount())
+
+		v, found := tc.Get(strconv.Itoa(n - 1))
+		assert.Equal(t, n-1, v)
+		assert.True(t, found)
+	})
+
+	t.Run("noOpWithoutDeletes", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		tc.Set("bKey", "bValue", DefaultExpiration)
+
+		tc.mu.RLock()
+		before := reflect.ValueOf(tc.items).Pointer()
+		tc.mu.RUnlock()
+
+		tc.Compact()
+
+		tc.mu.RLock()
+		after := reflect.ValueOf(tc.items).Pointer()
+		tc.mu.RUnlock()
+		assert.Equal(t, before, after)
+	})
+
+	t.Run("withAutoCompact", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithCleanupInterval(1*time.Millisecond), WithAutoCompact(0.5))
+		defer tc.Stop()
+
+		for i := 0; i < 10; i++ {
+			tc.Set(strconv.Itoa(i), i, DefaultExpiration)
+		}
+		for i := 0; i < 4; i++ {
+			tc.Delete(strconv.Itoa(i))
+		}
+
+		<-time.After(10 * time.Millisecond)
+
+		tc.mu.RLock()
+		assert.Equal(t, 10, tc.peak)
+		tc.mu.RUnlock()
+
+		for i := 4; i < 8; i++ {
+			tc.Delete(strconv.Itoa(i))
+		}
+
+		<-time.After(10 * time.Millisecond)
+
+		tc.mu.RLock()
+		assert.Equal(t, 2, tc.peak)
+		tc.mu.RUnlock()
+		assert.Equal(t, 2, tc.ItemCount())
+	})
+}
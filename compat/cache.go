@@ -0,0 +1,248 @@
+// Package compat Provides the API of github.com/patrickmn/go-cache on top of go_cache, so that code written for
+// it can be migrated by swapping its import for:
+//
+//	import cache "github.com/J4NN0/go-cache/compat"
+//
+// The commonly used subset of that API is covered, with the same semantics. Notably, ItemCount and Items may
+// include items that have expired but have not yet been cleaned up, and a cache created with a cleanup
+// interval is stopped when it is garbage collected.
+package compat
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"time"
+
+	go_cache "github.com/J4NN0/go-cache"
+)
+
+const (
+	// NoExpiration For use with functions that take an expiration time.
+	NoExpiration time.Duration = -1
+	// DefaultExpiration For use with functions that take an expiration time. Equivalent to
+	// passing in the same expiration duration as was given to New() or NewFrom() when the cache was created.
+	DefaultExpiration time.Duration = 0
+)
+
+// Item An item of the cache, as returned by Items.
+type Item struct {
+	Object     any
+	Expiration int64
+}
+
+// Expired Returns true if the item has expired.
+func (item Item) Expired() bool {
+	if item.Expiration == 0 {
+		return false
+	}
+
+	return time.Now().UnixNano() > item.Expiration
+}
+
+// Cache A cache with the API of github.com/patrickmn/go-cache.
+type Cache struct {
+	cache *go_cache.Cache
+}
+
+// New Returns a new cache with a given default expiration duration and cleanup interval.
+// If the expiration duration is less than one (or NoExpiration), the items in the cache never expire
+// (by default), and must be deleted manually. If the cleanup interval is less than one, expired items
+// are not deleted from the cache before calling DeleteExpired().
+func New(defaultExpiration, cleanupInterval time.Duration) *Cache {
+	return newCache(go_cache.NewCache(defaultExpiration, cleanupInterval), cleanupInterval)
+}
+
+// NewFrom Returns a new cache like New, populated with the given items.
+// Items which have already expired are left out.
+func NewFrom(defaultExpiration, cleanupInterval time.Duration, items map[string]Item) *Cache {
+	initial := make(map[string]go_cache.InitialItem, len(items))
+	for k, v := range items {
+		if d, ok := v.duration(); ok {
+			initial[k] = go_cache.InitialItem{Object: v.Object, Duration: d}
+		}
+	}
+
+	return newCache(go_cache.NewCacheWithOptions(
+		go_cache.WithDefaultExpiration(defaultExpiration),
+		go_cache.WithCleanupInterval(cleanupInterval),
+		go_cache.WithInitialItemsDurations(initial),
+	), cleanupInterval)
+}
+
+func newCache(c *go_cache.Cache, cleanupInterval time.Duration) *Cache {
+	C := &Cache{cache: c}
+	if cleanupInterval > 0 {
+		// The cleanup goroutine only references the underlying cache, so the wrapper can be collected,
+		// at which point the goroutine is stopped.
+		runtime.SetFinalizer(C, func(C *Cache) {
+			C.cache.Stop()
+		})
+	}
+
+	return C
+}
+
+// duration Returns the duration after which the item expires from now, reporting false if it already has.
+func (item Item) duration() (time.Duration, bool) {
+	if item.Expiration == 0 {
+		return NoExpiration, true
+	}
+	d := time.Until(time.Unix(0, item.Expiration))
+
+	return d, d > 0
+}
+
+// Set Adds an item to the cache, replacing any existing item. If the duration is 0 (DefaultExpiration),
+// the cache's default expiration time is used. If it is -1 (NoExpiration), the item never expires.
+func (c *Cache) Set(k string, x any, d time.Duration) {
+	c.cache.Set(k, x, d)
+}
+
+// SetDefault Adds an item to the cache, replacing any existing item, using the default expiration.
+func (c *Cache) SetDefault(k string, x any) {
+	c.cache.Set(k, x, DefaultExpiration)
+}
+
+// Add Adds an item to the cache only if an item doesn't already exist for the given key,
+// or if the existing item has expired. Returns an error otherwise.
+func (c *Cache) Add(k string, x any, d time.Duration) error {
+	if err := c.cache.Add(k, x, d); err != nil {
+		if errors.Is(err, go_cache.ErrItemAlreadyExists) {
+			return fmt.Errorf("Item %s already exists", k)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Replace Sets a new value for the cache key only if it already exists, and the existing
+// item hasn't expired. Returns an error otherwise.
+func (c *Cache) Replace(k string, x any, d time.Duration) error {
+	if err := c.cache.Replace(k, x, d); err != nil {
+		if errors.Is(err, go_cache.ErrItemNotFound) {
+			return fmt.Errorf("Item %s doesn't exist", k)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Get Gets an item from the cache. Returns the item or nil, and a bool indicating whether the key was found.
+func (c *Cache) Get(k string) (any, bool) {
+	return c.cache.Get(k)
+}
+
+// GetWithExpiration Returns an item and its expiration time from the cache. It returns the item or nil,
+// the expiration time if one is set (if the item never expires a zero value for time.Time is returned),
+// and a bool indicating whether the key was found.
+func (c *Cache) GetWithExpiration(k string) (any, time.Time, bool) {
+	return c.cache.GetWithExpiration(k)
+}
+
+// Delete Deletes an item from the cache. Does nothing if the key is not in the cache.
+func (c *Cache) Delete(k string) {
+	c.cache.Delete(k)
+}
+
+// DeleteExpired Deletes all expired items from the cache.
+func (c *Cache) DeleteExpired() {
+	c.cache.DeleteExpired()
+}
+
+// Items Copies all unexpired items in the cache into a new map and returns it.
+// This may include items that have expired, but have not yet been cleaned up.
+func (c *Cache) Items() map[string]Item {
+	items, _ := c.cache.Items()
+	m := make(map[string]Item, len(items))
+	for k, v := range items {
+		item := Item{Object: v.Object}
+		if !v.Expiration.IsZero() {
+			item.Expiration = v.Expiration.UnixNano()
+		}
+		m[k] = item
+	}
+
+	return m
+}
+
+// ItemCount Returns the number of items in the cache. This may include items that have expired,
+// but have not yet been cleaned up.
+func (c *Cache) ItemCount() int {
+	return c.cache.ItemCount()
+}
+
+// Flush Deletes all items from the cache.
+func (c *Cache) Flush() {
+	c.cache.Flush()
+}
+
+// Save Writes the cache's items (using Gob) to an io.Writer.
+// The types of the items must have been registered with gob.Register.
+func (c *Cache) Save(w io.Writer) (err error) {
+	enc := gob.NewEncoder(w)
+	defer func() {
+		if x := recover(); x != nil {
+			err = fmt.Errorf("Error registering item types with Gob library")
+		}
+	}()
+
+	items := c.Items()
+	for _, v := range items {
+		gob.Register(v.Object)
+	}
+
+	return enc.Encode(&items)
+}
+
+// SaveFile Saves the cache's items to the given filename, creating the file if it doesn't exist,
+// and overwriting it if it does.
+func (c *Cache) SaveFile(fname string) error {
+	fp, err := os.Create(fname)
+	if err != nil {
+		return err
+	}
+	if err := c.Save(fp); err != nil {
+		fp.Close()
+		return err
+	}
+
+	return fp.Close()
+}
+
+// Load Adds (Gob-serialized) cache items from an io.Reader, excluding any items with keys that already
+// exist (and haven't expired) in the current cache.
+func (c *Cache) Load(r io.Reader) error {
+	dec := gob.NewDecoder(r)
+	items := map[string]Item{}
+	if err := dec.Decode(&items); err != nil {
+		return err
+	}
+	for k, v := range items {
+		if d, ok := v.duration(); ok {
+			_ = c.cache.Add(k, v.Object, d)
+		}
+	}
+
+	return nil
+}
+
+// LoadFile Loads and adds cache items from the given filename, excluding any items with keys that already
+// exist in the current cache.
+func (c *Cache) LoadFile(fname string) error {
+	fp, err := os.Open(fname)
+	if err != nil {
+		return err
+	}
+	if err := c.Load(fp); err != nil {
+		fp.Close()
+		return err
+	}
+
+	return fp.Close()
+}
@@ -0,0 +1,389 @@
+package compat
+
+import (
+	"bytes"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TestStruct struct {
+	Num      int
+	Children []*TestStruct
+}
+
+func TestCache_Types(t *testing.T) {
+	tc := New(DefaultExpiration, 0)
+
+	_, found := tc.Get("a")
+	assert.False(t, found)
+
+	tc.Set("a", 1, DefaultExpiration)
+	tc.Set("b", "b", DefaultExpiration)
+	tc.Set("c", 3.5, DefaultExpiration)
+
+	x, found := tc.Get("a")
+	assert.True(t, found)
+	assert.Equal(t, 1, x.(int)+2-2)
+
+	x, found = tc.Get("b")
+	assert.True(t, found)
+	assert.Equal(t, "bB", x.(string)+"B")
+
+	x, found = tc.Get("c")
+	assert.True(t, found)
+	assert.Equal(t, 4.7, x.(float64)+1.2)
+}
+
+func TestCache_Times(t *testing.T) {
+	tc := New(50*time.Millisecond, 1*time.Millisecond)
+	tc.Set("a", 1, DefaultExpiration)
+	tc.Set("b", 2, NoExpiration)
+	tc.Set("c", 3, 20*time.Millisecond)
+	tc.Set("d", 4, 70*time.Millisecond)
+
+	<-time.After(25 * time.Millisecond)
+	_, found := tc.Get("c")
+	assert.False(t, found)
+
+	<-time.After(30 * time.Millisecond)
+	_, found = tc.Get("a")
+	assert.False(t, found)
+	_, found = tc.Get("b")
+	assert.True(t, found)
+	_, found = tc.Get("d")
+	assert.True(t, found)
+
+	<-time.After(20 * time.Millisecond)
+	_, found = tc.Get("d")
+	assert.False(t, found)
+}
+
+func TestCache_NewFrom(t *testing.T) {
+	m := map[string]Item{
+		"a": {Object: 1, Expiration: 0},
+		"b": {Object: 2, Expiration: 0},
+		"c": {Object: 3, Expiration: time.Now().Add(-1 * time.Second).UnixNano()},
+	}
+	tc := NewFrom(DefaultExpiration, 0, m)
+
+	a, found := tc.Get("a")
+	assert.True(t, found)
+	assert.Equal(t, 1, a)
+	b, found := tc.Get("b")
+	assert.True(t, found)
+	assert.Equal(t, 2, b)
+	_, found = tc.Get("c")
+	assert.False(t, found)
+}
+
+func TestCache_StorePointerToStruct(t *testing.T) {
+	tc := New(DefaultExpiration, 0)
+	tc.Set("foo", &TestStruct{Num: 1}, DefaultExpiration)
+
+	x, found := tc.Get("foo")
+	assert.True(t, found)
+	x.(*TestStruct).Num++
+
+	y, _ := tc.Get("foo")
+	assert.Equal(t, 2, y.(*TestStruct).Num)
+}
+
+func TestCache_GetWithExpiration(t *testing.T) {
+	tc := New(DefaultExpiration, 0)
+
+	_, expiration, found := tc.GetWithExpiration("a")
+	assert.False(t, found)
+	assert.True(t, expiration.IsZero())
+
+	tc.Set("a", 1, DefaultExpiration)
+	tc.Set("b", 2, 5*time.Minute)
+
+	x, expiration, found := tc.GetWithExpiration("a")
+	assert.True(t, found)
+	assert.Equal(t, 1, x)
+	assert.True(t, expiration.IsZero())
+
+	x, expiration, found = tc.GetWithExpiration("b")
+	assert.True(t, found)
+	assert.Equal(t, 2, x)
+	assert.Equal(t, tc.Items()["b"].Expiration, expiration.UnixNano())
+	assert.WithinDuration(t, time.Now().Add(5*time.Minute), expiration, 1*time.Second)
+}
+
+func TestCache_Increment(t *testing.T) {
+	t.Run("int", func(t *testing.T) {
+		tc := New(DefaultExpiration, 0)
+		tc.Set("tint", 1, DefaultExpiration)
+
+		assert.NoError(t, tc.Increment("tint", 2))
+		x, _ := tc.Get("tint")
+		assert.Equal(t, 3, x)
+	})
+
+	t.Run("float", func(t *testing.T) {
+		tc := New(DefaultExpiration, 0)
+		tc.Set("float32", float32(1.5), DefaultExpiration)
+		tc.Set("float64", float64(1.5), DefaultExpiration)
+
+		assert.NoError(t, tc.Increment("float32", 2))
+		assert.NoError(t, tc.IncrementFloat("float64", 2.5))
+		x, _ := tc.Get("float32")
+		assert.Equal(t, float32(3.5), x)
+		x, _ = tc.Get("float64")
+		assert.Equal(t, float64(4), x)
+	})
+
+	t.Run("typed", func(t *testing.T) {
+		tc := New(DefaultExpiration, 0)
+		tc.Set("int8", int8(1), DefaultExpiration)
+		tc.Set("uint64", uint64(1), DefaultExpiration)
+		tc.Set("float64", float64(1.5), DefaultExpiration)
+
+		n8, err := tc.IncrementInt8("int8", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, int8(3), n8)
+
+		nu64, err := tc.IncrementUint64("uint64", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(3), nu64)
+
+		nf, err := tc.IncrementFloat64("float64", 1.5)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(3), nf)
+
+		_, err = tc.IncrementInt("int8", 1)
+		assert.EqualError(t, err, "The value for int8 is not an int")
+	})
+
+	t.Run("overflowInt", func(t *testing.T) {
+		tc := New(DefaultExpiration, 0)
+		tc.Set("int8", int8(127), DefaultExpiration)
+
+		assert.NoError(t, tc.Increment("int8", 1))
+		x, _ := tc.Get("int8")
+		assert.Equal(t, int8(-128), x)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tc := New(DefaultExpiration, 0)
+		tc.Set("string", "aValue", DefaultExpiration)
+
+		assert.EqualError(t, tc.Increment("missing", 1), "Item missing not found")
+		assert.EqualError(t, tc.Increment("string", 1), "The value for string is not an integer")
+		assert.EqualError(t, tc.IncrementFloat("string", 1), "The value for string does not have type float32 or float64")
+	})
+
+	t.Run("keepsExpiration", func(t *testing.T) {
+		tc := New(DefaultExpiration, 0)
+		tc.Set("tint", 1, 5*time.Minute)
+		before := tc.Items()["tint"].Expiration
+
+		assert.NoError(t, tc.Increment("tint", 1))
+		after := tc.Items()["tint"].Expiration
+		assert.InDelta(t, before, after, float64(time.Second))
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		tc := New(DefaultExpiration, 0)
+		tc.Set("tint", 0, DefaultExpiration)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					_, _ = tc.IncrementInt("tint", 1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		x, _ := tc.Get("tint")
+		assert.Equal(t, 800, x)
+	})
+}
+
+func TestCache_Decrement(t *testing.T) {
+	t.Run("int64", func(t *testing.T) {
+		tc := New(DefaultExpiration, 0)
+		tc.Set("int64", int64(5), DefaultExpiration)
+
+		assert.NoError(t, tc.Decrement("int64", 2))
+		x, _ := tc.Get("int64")
+		assert.Equal(t, int64(3), x)
+	})
+
+	t.Run("underflowUint", func(t *testing.T) {
+		tc := New(DefaultExpiration, 0)
+		tc.Set("uint8", uint8(0), DefaultExpiration)
+
+		assert.NoError(t, tc.Decrement("uint8", 1))
+		x, _ := tc.Get("uint8")
+		assert.Equal(t, uint8(255), x)
+	})
+
+	t.Run("typed", func(t *testing.T) {
+		tc := New(DefaultExpiration, 0)
+		tc.Set("uint", uint(5), DefaultExpiration)
+		tc.Set("float32", float32(5.5), DefaultExpiration)
+
+		n, err := tc.DecrementUint("uint", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(3), n)
+
+		assert.NoError(t, tc.DecrementFloat("float32", 0.5))
+		x, _ := tc.Get("float32")
+		assert.Equal(t, float32(5), x)
+	})
+}
+
+func TestCache_Add(t *testing.T) {
+	tc := New(DefaultExpiration, 0)
+
+	assert.NoError(t, tc.Add("foo", "bar", DefaultExpiration))
+	assert.EqualError(t, tc.Add("foo", "baz", DefaultExpiration), "Item foo already exists")
+}
+
+func TestCache_Replace(t *testing.T) {
+	tc := New(DefaultExpiration, 0)
+
+	assert.EqualError(t, tc.Replace("foo", "bar", DefaultExpiration), "Item foo doesn't exist")
+	tc.Set("foo", "bar", DefaultExpiration)
+	assert.NoError(t, tc.Replace("foo", "bar", DefaultExpiration))
+}
+
+func TestCache_Delete(t *testing.T) {
+	tc := New(DefaultExpiration, 0)
+	tc.Set("foo", "bar", DefaultExpiration)
+	tc.Delete("foo")
+
+	x, found := tc.Get("foo")
+	assert.False(t, found)
+	assert.Nil(t, x)
+}
+
+func TestCache_ItemCount(t *testing.T) {
+	tc := New(DefaultExpiration, 0)
+	tc.Set("foo", "1", DefaultExpiration)
+	tc.Set("bar", "2", DefaultExpiration)
+	tc.Set("baz", "3", DefaultExpiration)
+
+	assert.Equal(t, 3, tc.ItemCount())
+}
+
+func TestCache_Flush(t *testing.T) {
+	tc := New(DefaultExpiration, 0)
+	tc.Set("foo", "bar", DefaultExpiration)
+	tc.Set("baz", "yes", DefaultExpiration)
+	tc.Flush()
+
+	_, found := tc.Get("foo")
+	assert.False(t, found)
+	_, found = tc.Get("baz")
+	assert.False(t, found)
+}
+
+func TestCache_ItemsIncludeExpired(t *testing.T) {
+	tc := New(DefaultExpiration, 0)
+	tc.Set("foo", "bar", 1*time.Millisecond)
+	tc.Set("baz", "yes", DefaultExpiration)
+
+	<-time.After(5 * time.Millisecond)
+	_, found := tc.Get("foo")
+	assert.False(t, found)
+
+	items := tc.Items()
+	assert.Len(t, items, 2)
+	assert.True(t, items["foo"].Expired())
+	assert.False(t, items["baz"].Expired())
+	assert.Equal(t, 2, tc.ItemCount())
+
+	tc.DeleteExpired()
+	assert.Len(t, tc.Items(), 1)
+}
+
+func TestCache_Serialization(t *testing.T) {
+	tc := New(DefaultExpiration, 0)
+	tc.Set("a", "a", DefaultExpiration)
+	tc.Set("b", "b", DefaultExpiration)
+	tc.Set("c", "c", DefaultExpiration)
+	tc.Set("expired", "foo", 1*time.Millisecond)
+	tc.Set("*struct", &TestStruct{Num: 1}, DefaultExpiration)
+	tc.Set("[]struct", []TestStruct{{Num: 2}, {Num: 3}}, DefaultExpiration)
+	tc.Set("[]*struct", []*TestStruct{{Num: 4}, {Num: 5}}, DefaultExpiration)
+	tc.Set("structception", &TestStruct{
+		Num: 42,
+		Children: []*TestStruct{
+			{Num: 6174},
+			{Num: 4716},
+		},
+	}, DefaultExpiration)
+	<-time.After(5 * time.Millisecond)
+
+	fp := &bytes.Buffer{}
+	assert.NoError(t, tc.Save(fp))
+
+	oc := New(DefaultExpiration, 0)
+	oc.Set("a", "existing", DefaultExpiration)
+	assert.NoError(t, oc.Load(fp))
+
+	a, found := oc.Get("a")
+	assert.True(t, found)
+	assert.Equal(t, "existing", a)
+	b, found := oc.Get("b")
+	assert.True(t, found)
+	assert.Equal(t, "b", b)
+	_, found = oc.Get("expired")
+	assert.False(t, found)
+
+	s1, found := oc.Get("*struct")
+	assert.True(t, found)
+	assert.Equal(t, 1, s1.(*TestStruct).Num)
+
+	s2, found := oc.Get("[]struct")
+	assert.True(t, found)
+	assert.Equal(t, []TestStruct{{Num: 2}, {Num: 3}}, s2)
+
+	s4, found := oc.Get("structception")
+	assert.True(t, found)
+	assert.Equal(t, 42, s4.(*TestStruct).Num)
+	assert.Equal(t, 4716, s4.(*TestStruct).Children[1].Num)
+}
+
+func TestCache_FileSerialization(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "cache.gob")
+
+	tc := New(DefaultExpiration, 0)
+	tc.Add("a", "a", DefaultExpiration)
+	tc.Add("b", "b", DefaultExpiration)
+	assert.NoError(t, tc.SaveFile(fname))
+
+	oc := New(DefaultExpiration, 0)
+	oc.Add("a", "aa", 0)
+	assert.NoError(t, oc.LoadFile(fname))
+
+	a, found := oc.Get("a")
+	assert.True(t, found)
+	assert.Equal(t, "aa", a)
+	b, found := oc.Get("b")
+	assert.True(t, found)
+	assert.Equal(t, "b", b)
+}
+
+func TestCache_StoppedWhenCollected(t *testing.T) {
+	before := runtime.NumGoroutine()
+	tc := New(DefaultExpiration, 1*time.Millisecond)
+	tc.Set("a", 1, DefaultExpiration)
+	tc = nil
+
+	assert.Eventually(t, func() bool {
+		runtime.GC()
+		return runtime.NumGoroutine() <= before
+	}, 1*time.Second, 10*time.Millisecond)
+}
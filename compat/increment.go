@@ -0,0 +1,343 @@
+package compat
+
+import (
+	"errors"
+	"fmt"
+
+	go_cache "github.com/J4NN0/go-cache"
+)
+
+type number interface {
+	int | int8 | int16 | int32 | int64 | uint | uintptr | uint8 | uint16 | uint32 | uint64 | float32 | float64
+}
+
+// Increment Increments an item of type int, int8, int16, int32, int64, uintptr, uint, uint8, uint32, uint64,
+// float32 or float64 by n. Returns an error if the item's value is not an integer, if it was not found,
+// or if it is not possible to increment it by n.
+func (c *Cache) Increment(k string, n int64) error {
+	_, err := c.update(k, func(v any) (any, error) {
+		switch v := v.(type) {
+		case int:
+			return v + int(n), nil
+		case int8:
+			return v + int8(n), nil
+		case int16:
+			return v + int16(n), nil
+		case int32:
+			return v + int32(n), nil
+		case int64:
+			return v + int64(n), nil
+		case uint:
+			return v + uint(n), nil
+		case uintptr:
+			return v + uintptr(n), nil
+		case uint8:
+			return v + uint8(n), nil
+		case uint16:
+			return v + uint16(n), nil
+		case uint32:
+			return v + uint32(n), nil
+		case uint64:
+			return v + uint64(n), nil
+		case float32:
+			return v + float32(n), nil
+		case float64:
+			return v + float64(n), nil
+		default:
+			return nil, fmt.Errorf("The value for %s is not an integer", k)
+		}
+	})
+
+	return err
+}
+
+// IncrementFloat Increments an item of type float32 or float64 by n. Returns an error if the item's value
+// is not floating point, if it was not found, or if it is not possible to increment it by n.
+func (c *Cache) IncrementFloat(k string, n float64) error {
+	_, err := c.update(k, func(v any) (any, error) {
+		switch v := v.(type) {
+		case float32:
+			return v + float32(n), nil
+		case float64:
+			return v + n, nil
+		default:
+			return nil, fmt.Errorf("The value for %s does not have type float32 or float64", k)
+		}
+	})
+
+	return err
+}
+
+// Decrement Decrements an item of type int, int8, int16, int32, int64, uintptr, uint, uint8, uint32, uint64,
+// float32 or float64 by n. Returns an error if the item's value is not an integer, if it was not found,
+// or if it is not possible to decrement it by n.
+func (c *Cache) Decrement(k string, n int64) error {
+	_, err := c.update(k, func(v any) (any, error) {
+		switch v := v.(type) {
+		case int:
+			return v - int(n), nil
+		case int8:
+			return v - int8(n), nil
+		case int16:
+			return v - int16(n), nil
+		case int32:
+			return v - int32(n), nil
+		case int64:
+			return v - int64(n), nil
+		case uint:
+			return v - uint(n), nil
+		case uintptr:
+			return v - uintptr(n), nil
+		case uint8:
+			return v - uint8(n), nil
+		case uint16:
+			return v - uint16(n), nil
+		case uint32:
+			return v - uint32(n), nil
+		case uint64:
+			return v - uint64(n), nil
+		case float32:
+			return v - float32(n), nil
+		case float64:
+			return v - float64(n), nil
+		default:
+			return nil, fmt.Errorf("The value for %s is not an integer", k)
+		}
+	})
+
+	return err
+}
+
+// DecrementFloat Decrements an item of type float32 or float64 by n. Returns an error if the item's value
+// is not floating point, if it was not found, or if it is not possible to decrement it by n.
+func (c *Cache) DecrementFloat(k string, n float64) error {
+	_, err := c.update(k, func(v any) (any, error) {
+		switch v := v.(type) {
+		case float32:
+			return v - float32(n), nil
+		case float64:
+			return v - n, nil
+		default:
+			return nil, fmt.Errorf("The value for %s does not have type float32 or float64", k)
+		}
+	})
+
+	return err
+}
+
+// IncrementInt Increments an item of type int by n. Returns an error if the item's value is not an int,
+// or if it was not found. If there is no error, the incremented value is returned.
+func (c *Cache) IncrementInt(k string, n int) (int, error) {
+	return increment(c, k, n, "int")
+}
+
+// IncrementInt8 Increments an item of type int8 by n. Returns an error if the item's value is not an int8,
+// or if it was not found. If there is no error, the incremented value is returned.
+func (c *Cache) IncrementInt8(k string, n int8) (int8, error) {
+	return increment(c, k, n, "int8")
+}
+
+// IncrementInt16 Increments an item of type int16 by n. Returns an error if the item's value is not an int16,
+// or if it was not found. If there is no error, the incremented value is returned.
+func (c *Cache) IncrementInt16(k string, n int16) (int16, error) {
+	return increment(c, k, n, "int16")
+}
+
+// IncrementInt32 Increments an item of type int32 by n. Returns an error if the item's value is not an int32,
+// or if it was not found. If there is no error, the incremented value is returned.
+func (c *Cache) IncrementInt32(k string, n int32) (int32, error) {
+	return increment(c, k, n, "int32")
+}
+
+// IncrementInt64 Increments an item of type int64 by n. Returns an error if the item's value is not an int64,
+// or if it was not found. If there is no error, the incremented value is returned.
+func (c *Cache) IncrementInt64(k string, n int64) (int64, error) {
+	return increment(c, k, n, "int64")
+}
+
+// IncrementUint Increments an item of type uint by n. Returns an error if the item's value is not an uint,
+// or if it was not found. If there is no error, the incremented value is returned.
+func (c *Cache) IncrementUint(k string, n uint) (uint, error) {
+	return increment(c, k, n, "uint")
+}
+
+// IncrementUintptr Increments an item of type uintptr by n. Returns an error if the item's value is not an uintptr,
+// or if it was not found. If there is no error, the incremented value is returned.
+func (c *Cache) IncrementUintptr(k string, n uintptr) (uintptr, error) {
+	return increment(c, k, n, "uintptr")
+}
+
+// IncrementUint8 Increments an item of type uint8 by n. Returns an error if the item's value is not an uint8,
+// or if it was not found. If there is no error, the incremented value is returned.
+func (c *Cache) IncrementUint8(k string, n uint8) (uint8, error) {
+	return increment(c, k, n, "uint8")
+}
+
+// IncrementUint16 Increments an item of type uint16 by n. Returns an error if the item's value is not an uint16,
+// or if it was not found. If there is no error, the incremented value is returned.
+func (c *Cache) IncrementUint16(k string, n uint16) (uint16, error) {
+	return increment(c, k, n, "uint16")
+}
+
+// IncrementUint32 Increments an item of type uint32 by n. Returns an error if the item's value is not an uint32,
+// or if it was not found. If there is no error, the incremented value is returned.
+func (c *Cache) IncrementUint32(k string, n uint32) (uint32, error) {
+	return increment(c, k, n, "uint32")
+}
+
+// IncrementUint64 Increments an item of type uint64 by n. Returns an error if the item's value is not an uint64,
+// or if it was not found. If there is no error, the incremented value is returned.
+func (c *Cache) IncrementUint64(k string, n uint64) (uint64, error) {
+	return increment(c, k, n, "uint64")
+}
+
+// IncrementFloat32 Increments an item of type float32 by n. Returns an error if the item's value is not an float32,
+// or if it was not found. If there is no error, the incremented value is returned.
+func (c *Cache) IncrementFloat32(k string, n float32) (float32, error) {
+	return increment(c, k, n, "float32")
+}
+
+// IncrementFloat64 Increments an item of type float64 by n. Returns an error if the item's value is not an float64,
+// or if it was not found. If there is no error, the incremented value is returned.
+func (c *Cache) IncrementFloat64(k string, n float64) (float64, error) {
+	return increment(c, k, n, "float64")
+}
+
+// DecrementInt Decrements an item of type int by n. Returns an error if the item's value is not an int,
+// or if it was not found. If there is no error, the decremented value is returned.
+func (c *Cache) DecrementInt(k string, n int) (int, error) {
+	return decrement(c, k, n, "int")
+}
+
+// DecrementInt8 Decrements an item of type int8 by n. Returns an error if the item's value is not an int8,
+// or if it was not found. If there is no error, the decremented value is returned.
+func (c *Cache) DecrementInt8(k string, n int8) (int8, error) {
+	return decrement(c, k, n, "int8")
+}
+
+// DecrementInt16 Decrements an item of type int16 by n. Returns an error if the item's value is not an int16,
+// or if it was not found. If there is no error, the decremented value is returned.
+func (c *Cache) DecrementInt16(k string, n int16) (int16, error) {
+	return decrement(c, k, n, "int16")
+}
+
+// DecrementInt32 Decrements an item of type int32 by n. Returns an error if the item's value is not an int32,
+// or if it was not found. If there is no error, the decremented value is returned.
+func (c *Cache) DecrementInt32(k string, n int32) (int32, error) {
+	return decrement(c, k, n, "int32")
+}
+
+// DecrementInt64 Decrements an item of type int64 by n. Returns an error if the item's value is not an int64,
+// or if it was not found. If there is no error, the decremented value is returned.
+func (c *Cache) DecrementInt64(k string, n int64) (int64, error) {
+	return decrement(c, k, n, "int64")
+}
+
+// DecrementUint Decrements an item of type uint by n. Returns an error if the item's value is not an uint,
+// or if it was not found. If there is no error, the decremented value is returned.
+func (c *Cache) DecrementUint(k string, n uint) (uint, error) {
+	return decrement(c, k, n, "uint")
+}
+
+// DecrementUintptr Decrements an item of type uintptr by n. Returns an error if the item's value is not an uintptr,
+// or if it was not found. If there is no error, the decremented value is returned.
+func (c *Cache) DecrementUintptr(k string, n uintptr) (uintptr, error) {
+	return decrement(c, k, n, "uintptr")
+}
+
+// DecrementUint8 Decrements an item of type uint8 by n. Returns an error if the item's value is not an uint8,
+// or if it was not found. If there is no error, the decremented value is returned.
+func (c *Cache) DecrementUint8(k string, n uint8) (uint8, error) {
+	return decrement(c, k, n, "uint8")
+}
+
+// DecrementUint16 Decrements an item of type uint16 by n. Returns an error if the item's value is not an uint16,
+// or if it was not found. If there is no error, the decremented value is returned.
+func (c *Cache) DecrementUint16(k string, n uint16) (uint16, error) {
+	return decrement(c, k, n, "uint16")
+}
+
+// DecrementUint32 Decrements an item of type uint32 by n. Returns an error if the item's value is not an uint32,
+// or if it was not found. If there is no error, the decremented value is returned.
+func (c *Cache) DecrementUint32(k string, n uint32) (uint32, error) {
+	return decrement(c, k, n, "uint32")
+}
+
+// DecrementUint64 Decrements an item of type uint64 by n. Returns an error if the item's value is not an uint64,
+// or if it was not found. If there is no error, the decremented value is returned.
+func (c *Cache) DecrementUint64(k string, n uint64) (uint64, error) {
+	return decrement(c, k, n, "uint64")
+}
+
+// DecrementFloat32 Decrements an item of type float32 by n. Returns an error if the item's value is not an float32,
+// or if it was not found. If there is no error, the decremented value is returned.
+func (c *Cache) DecrementFloat32(k string, n float32) (float32, error) {
+	return decrement(c, k, n, "float32")
+}
+
+// DecrementFloat64 Decrements an item of type float64 by n. Returns an error if the item's value is not an float64,
+// or if it was not found. If there is no error, the decremented value is returned.
+func (c *Cache) DecrementFloat64(k string, n float64) (float64, error) {
+	return decrement(c, k, n, "float64")
+}
+
+func increment[T number](c *Cache, k string, n T, typeName string) (T, error) {
+	return updateNumber(c, k, typeName, func(v T) T { return v + n })
+}
+
+func decrement[T number](c *Cache, k string, n T, typeName string) (T, error) {
+	return updateNumber(c, k, typeName, func(v T) T { return v - n })
+}
+
+func updateNumber[T number](c *Cache, k, typeName string, fn func(T) T) (T, error) {
+	nv, err := c.update(k, func(v any) (any, error) {
+		rv, ok := v.(T)
+		if !ok {
+			return nil, fmt.Errorf("The value for %s is not an %s", k, typeName)
+		}
+		return fn(rv), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return nv.(T), nil
+}
+
+// update Replaces the value stored under the key with the one returned by fn, keeping its expiration time,
+// and returns the new value. The value is compared and swapped by version, so that concurrent updates of the
+// same key are not lost.
+func (c *Cache) update(k string, fn func(v any) (any, error)) (any, error) {
+	for {
+		v, version, found := c.cache.GetWithVersion(k)
+		if !found {
+			return nil, fmt.Errorf("Item %s not found", k)
+		}
+		info, found := c.cache.Info(k)
+		if !found {
+			return nil, fmt.Errorf("Item %s not found", k)
+		}
+		item := Item{}
+		if !info.Expiration.IsZero() {
+			item.Expiration = info.Expiration.UnixNano()
+		}
+		d, ok := item.duration()
+		if !ok {
+			return nil, fmt.Errorf("Item %s not found", k)
+		}
+
+		nv, err := fn(v)
+		if err != nil {
+			return nil, err
+		}
+		err = c.cache.SetIfVersion(k, nv, d, version)
+		if errors.Is(err, go_cache.ErrVersionMismatch) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return nv, nil
+	}
+}
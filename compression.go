@@ -0,0 +1,84 @@
+package go_cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// CompressionCodec Compresses the values of a cache created WithCompression, and decompresses them back.
+type CompressionCodec interface {
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+// GzipCompression The default CompressionCodec, compressing values with compress/gzip at the given level,
+// gzip.DefaultCompression if 0.
+type GzipCompression struct {
+	Level int
+}
+
+// Compress Returns the data compressed with gzip.
+func (g GzipCompression) Compress(data []byte) ([]byte, error) {
+	level := g.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(data); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Decompress Returns the data decompressed with gzip.
+func (GzipCompression) Decompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
+
+// compression Holds the settings of a cache created WithCompression.
+type compression struct {
+	threshold int
+	codec     CompressionCodec
+}
+
+// compress Returns the item with its value compressed if it is a byte slice longer than the threshold, and if
+// compressing it saves room.
+func (c *Cache) compress(key string, it item) item {
+	data, ok := it.object.([]byte)
+	if !ok || it.compressed || it.placeholder || it.negative || len(data) <= c.compression.threshold {
+		return it
+	}
+
+	compressed, err := c.compression.codec.Compress(data)
+	if err != nil {
+		c.handleError(fmt.Errorf("could not compress value of %s: %w", key, err))
+		return it
+	}
+	if len(compressed) >= len(data) {
+		return it
+	}
+	c.stats.compressedValues.Add(1)
+	c.stats.uncompressedBytes.Add(uint64(len(data)))
+	c.stats.compressedBytes.Add(uint64(len(compressed)))
+	it.object = compressed
+	it.compressed = true
+
+	return it
+}
@@ -0,0 +1,137 @@
+package go_cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// halvingCompression A CompressionCodec halving the data, only able to decompress runs of a single byte.
+type halvingCompression struct{}
+
+func (halvingCompression) Compress(data []byte) ([]byte, error) {
+	return data[:len(data)/2], nil
+}
+
+func (halvingCompression) Decompress(data []byte) ([]byte, error) {
+	if len(data) > 0 && data[0] == 'x' {
+		return nil, errors.New("not a run")
+	}
+	return bytes.Repeat(data[:1], 2*len(data)), nil
+}
+
+func TestCache_WithCompression(t *testing.T) {
+	t.Run("roundTrip", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithCompression(100, nil))
+		defer tc.Stop()
+
+		for _, n := range []int{0, 99, 100, 101, 1 << 20} {
+			value := bytes.Repeat([]byte("a"), n)
+			tc.Set("aKey", value, DefaultExpiration)
+
+			tc.mu.RLock()
+			compressed := tc.items["aKey"].compressed
+			tc.mu.RUnlock()
+			assert.Equal(t, n > 100, compressed, n)
+			a, found := tc.Get("aKey")
+			assert.True(t, found)
+			assert.Equal(t, value, a, n)
+		}
+
+		items, err := tc.Items()
+		assert.Nil(t, err)
+		assert.Equal(t, bytes.Repeat([]byte("a"), 1<<20), items["aKey"].Object)
+	})
+
+	t.Run("incompressible", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithCompression(100, nil))
+		defer tc.Stop()
+
+		value := make([]byte, 1000)
+		_, _ = rand.Read(value)
+		tc.Set("aKey", value, DefaultExpiration)
+		tc.Set("bKey", string(bytes.Repeat([]byte("a"), 1000)), DefaultExpiration)
+
+		tc.mu.RLock()
+		assert.False(t, tc.items["aKey"].compressed)
+		assert.False(t, tc.items["bKey"].compressed)
+		tc.mu.RUnlock()
+		a, _ := tc.Get("aKey")
+		assert.Equal(t, value, a)
+		assert.Equal(t, CompressionStats{}, tc.Stats().Compression)
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithCompression(10, halvingCompression{}))
+		defer tc.Stop()
+
+		tc.Set("aKey", bytes.Repeat([]byte("a"), 100), DefaultExpiration)
+		tc.Set("bKey", bytes.Repeat([]byte("b"), 50), DefaultExpiration)
+		tc.Set("cKey", []byte("c"), DefaultExpiration)
+
+		assert.Equal(t, CompressionStats{Values: 2, UncompressedBytes: 150, CompressedBytes: 75}, tc.Stats().Compression)
+	})
+
+	t.Run("maxCost", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithCompression(10, halvingCompression{}), WithMaxCost(100))
+		defer tc.Stop()
+
+		tc.Set("aKey", bytes.Repeat([]byte("a"), 100), DefaultExpiration)
+		tc.Set("bKey", bytes.Repeat([]byte("b"), 100), DefaultExpiration)
+
+		// Each value costs the 50 bytes it takes once compressed, so both fit.
+		tc.mu.RLock()
+		assert.Equal(t, int64(100), tc.capacity.cost)
+		tc.mu.RUnlock()
+		assert.Equal(t, 2, tc.ItemCount())
+
+		tc.Set("cKey", bytes.Repeat([]byte("c"), 100), DefaultExpiration)
+		assert.Equal(t, 2, tc.ItemCount())
+		assert.Equal(t, uint64(1), tc.Stats().Removals.Evicted)
+	})
+
+	t.Run("readers", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithCompression(10, nil))
+		defer tc.Stop()
+		value := bytes.Repeat([]byte("a"), 100)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		events := tc.Watch(ctx, "aKey")
+		tc.Set("aKey", value, DefaultExpiration)
+		assert.Equal(t, value, (<-events).Value)
+
+		assert.Equal(t, map[string]any{"aKey": value}, tc.GetMany([]string{"aKey"}))
+		a, state := tc.Lookup("aKey")
+		assert.Equal(t, LookupHit, state)
+		assert.Equal(t, value, a)
+		a, _, found := tc.GetWithVersion("aKey")
+		assert.True(t, found)
+		assert.Equal(t, value, a)
+		for key, a := range tc.All() {
+			assert.Equal(t, "aKey", key)
+			assert.Equal(t, value, a)
+		}
+
+		tc.Delete("aKey")
+		assert.Equal(t, value, (<-events).Value)
+	})
+
+	t.Run("decompressionError", func(t *testing.T) {
+		var errs []error
+		tc := NewCacheWithOptions(WithCompression(10, halvingCompression{}), WithErrorHandler(func(err error) {
+			errs = append(errs, err)
+		}))
+		defer tc.Stop()
+
+		tc.Set("aKey", bytes.Repeat([]byte("x"), 100), DefaultExpiration)
+		a, found := tc.Get("aKey")
+		assert.True(t, found)
+		assert.Nil(t, a)
+		assert.Len(t, errs, 1)
+	})
+}
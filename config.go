@@ -0,0 +1,78 @@
+package go_cache
+
+import (
+	"fmt"
+	"time"
+)
+
+// SetDefaultExpiration Changes the default expiration time used by the items written from now on with
+// DefaultExpiration. Items already in the cache keep their expiration time.
+// If the duration is less than one (or NoExpiration), such items never expire.
+func (c *Cache) SetDefaultExpiration(d time.Duration) {
+	if d <= 0 {
+		d = NoExpiration
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.defaultExpiration = d
+}
+
+// DefaultExpirationValue Returns the default expiration time of the cache, NoExpiration if items written
+// with DefaultExpiration never expire.
+func (c *Cache) DefaultExpirationValue() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.defaultExpiration
+}
+
+// SetCleanupInterval Changes the interval between the runs of the cleanup goroutine, the next run happening
+// one interval from now. If the interval is less than one, the cleanup goroutine is stopped and expired items
+// are no longer deleted from the cache before calling DeleteExpired(), while a positive interval starts it again.
+// Once the cache is stopped, SetCleanupInterval is a no-op.
+func (c *Cache) SetCleanupInterval(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+
+	c.janitorMu.Lock()
+	defer c.janitorMu.Unlock()
+
+	select {
+	case <-c.stop:
+		return
+	default:
+	}
+
+	c.cleanupInterval = d
+	switch {
+	case c.janitor == nil && d > 0:
+		c.startJanitor(d)
+	case c.janitor != nil && d > 0:
+		c.janitor <- d
+		c.health.janitorStart(d, c.now())
+	case c.janitor != nil:
+		c.janitor <- 0
+		c.janitor = nil
+		c.health.janitorDisable()
+	}
+}
+
+// CleanupInterval Returns the interval between the runs of the cleanup goroutine, 0 if it is not running.
+func (c *Cache) CleanupInterval() time.Duration {
+	c.janitorMu.Lock()
+	defer c.janitorMu.Unlock()
+
+	return c.cleanupInterval
+}
+
+// Name Returns the name of the cache set WithName, or one derived from its address if it was not given any.
+func (c *Cache) Name() string {
+	if c.name == "" {
+		return fmt.Sprintf("%p", c)
+	}
+
+	return c.name
+}
@@ -0,0 +1,103 @@
+package go_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_SetDefaultExpiration(t *testing.T) {
+	t.Run("appliesToLaterWrites", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(5*time.Minute, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		assert.Equal(t, 5*time.Minute, tc.DefaultExpirationValue())
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		tc.SetDefaultExpiration(10 * time.Minute)
+		assert.Equal(t, 10*time.Minute, tc.DefaultExpirationValue())
+		tc.Set("bKey", "bValue", DefaultExpiration)
+		tc.Set("cKey", "cValue", 1*time.Minute)
+
+		clock.Advance(2 * time.Minute)
+		_, found := tc.Get("cKey")
+		assert.False(t, found)
+
+		clock.Advance(4 * time.Minute)
+		_, found = tc.Get("aKey")
+		assert.False(t, found)
+		_, found = tc.Get("bKey")
+		assert.True(t, found)
+
+		clock.Advance(5 * time.Minute)
+		_, found = tc.Get("bKey")
+		assert.False(t, found)
+	})
+
+	t.Run("noExpiration", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(1*time.Minute, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.SetDefaultExpiration(0)
+		assert.Equal(t, NoExpiration, tc.DefaultExpirationValue())
+		tc.Set("aKey", "aValue", DefaultExpiration)
+
+		clock.Advance(24 * time.Hour)
+		_, found := tc.Get("aKey")
+		assert.True(t, found)
+	})
+}
+
+func TestCache_SetCleanupInterval(t *testing.T) {
+	t.Run("speedUpAndStop", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 1*time.Hour)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", 10*time.Millisecond)
+		<-time.After(50 * time.Millisecond)
+		assert.Equal(t, 1, tc.ItemCount())
+
+		tc.SetCleanupInterval(5 * time.Millisecond)
+		assert.Equal(t, 5*time.Millisecond, tc.CleanupInterval())
+		<-time.After(50 * time.Millisecond)
+		assert.Equal(t, 0, tc.ItemCount())
+
+		tc.SetCleanupInterval(0)
+		assert.Equal(t, time.Duration(0), tc.CleanupInterval())
+		report := tc.Health()
+		assert.False(t, report.JanitorEnabled)
+		assert.False(t, report.JanitorRunning)
+
+		tc.Set("bKey", "bValue", 10*time.Millisecond)
+		<-time.After(50 * time.Millisecond)
+		assert.Equal(t, 1, tc.ItemCount())
+	})
+
+	t.Run("startFromZero", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		assert.Equal(t, time.Duration(0), tc.CleanupInterval())
+
+		tc.Set("aKey", "aValue", 10*time.Millisecond)
+		tc.SetCleanupInterval(5 * time.Millisecond)
+		assert.True(t, tc.Health().JanitorRunning)
+
+		<-time.After(50 * time.Millisecond)
+		assert.Equal(t, 0, tc.ItemCount())
+	})
+
+	t.Run("afterStop", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 5*time.Millisecond)
+		tc.Stop()
+
+		tc.SetCleanupInterval(10 * time.Millisecond)
+		assert.Equal(t, 5*time.Millisecond, tc.CleanupInterval())
+		assert.False(t, tc.Health().JanitorRunning)
+	})
+}
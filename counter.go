@@ -0,0 +1,70 @@
+package go_cache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Counter An int64 counter stored in the cache, which can be updated and read without taking the cache lock.
+// A handle stays valid while its item is in the cache: once the item expires, or is removed or overwritten,
+// Add fails and Counter must be called again to get a new handle. Touching the item extends the handle with it.
+type Counter struct {
+	value atomic.Int64
+	// expiration holds the current expiration time of the item, 0 if it never expires, kept up to date by store.
+	expiration atomic.Int64
+	removed    atomic.Bool
+	now        func() time.Time
+}
+
+// Counter Returns the counter stored under the given key, creating it with the value 0 and the given duration,
+// with the same semantics as Set, if the key does not exist, has expired or holds another value, which is then
+// replaced. The counter expires with its item, whose expiration time can be extended with Touch.
+// Once the cache is stopped, the returned counter is detached from the cache and Add always fails.
+func (c *Cache) Counter(key string, duration time.Duration) *Counter {
+	key = c.hashKey(key)
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	if item, found := c.get(key, c.now().UnixNano()); found {
+		if counter, ok := item.object.(*Counter); ok {
+			return counter
+		}
+	}
+
+	counter := &Counter{now: c.now}
+	if c.closed {
+		counter.invalidate()
+		return counter
+	}
+	c.counters = true
+	c.insert(key, c.newItem(key, counter, duration))
+
+	return counter
+}
+
+// Add Adds n to the counter and returns the new value, reporting false without changing it if the handle is no
+// longer valid.
+func (ctr *Counter) Add(n int64) (int64, bool) {
+	if !ctr.Valid() {
+		return ctr.value.Load(), false
+	}
+
+	return ctr.value.Add(n), true
+}
+
+// Load Returns the current value of the counter, even if the handle is no longer valid.
+func (ctr *Counter) Load() int64 {
+	return ctr.value.Load()
+}
+
+// Valid Reports whether the counter is still stored in the cache and has not expired.
+func (ctr *Counter) Valid() bool {
+	expiration := ctr.expiration.Load()
+
+	return !ctr.removed.Load() && (expiration == 0 || ctr.now().UnixNano() < expiration)
+}
+
+func (ctr *Counter) invalidate() {
+	ctr.removed.Store(true)
+}
@@ -0,0 +1,124 @@
+package go_cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_Counter(t *testing.T) {
+	t.Run("withoutCacheLock", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		counter := tc.Counter("aKey", NoExpiration)
+
+		// Holding the cache lock shows that adding to the counter never takes it.
+		tc.mu.Lock()
+		const workers, increments = 100, 1000
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < increments; j++ {
+					_, ok := counter.Add(1)
+					assert.True(t, ok)
+				}
+			}()
+		}
+		wg.Wait()
+		tc.mu.Unlock()
+
+		assert.Equal(t, int64(workers*increments), counter.Load())
+		assert.Same(t, counter, tc.Counter("aKey", NoExpiration))
+		value, found := tc.Get("aKey")
+		assert.True(t, found)
+		assert.Same(t, counter, value)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		counter := tc.Counter("aKey", time.Minute)
+		v, ok := counter.Add(5)
+		assert.True(t, ok)
+		assert.Equal(t, int64(5), v)
+
+		clock.Advance(time.Minute)
+		v, ok = counter.Add(1)
+		assert.False(t, ok)
+		assert.Equal(t, int64(5), v)
+		assert.False(t, counter.Valid())
+
+		renewed := tc.Counter("aKey", time.Minute)
+		assert.NotSame(t, counter, renewed)
+		v, ok = renewed.Add(1)
+		assert.True(t, ok)
+		assert.Equal(t, int64(1), v)
+	})
+
+	t.Run("touched", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		counter := tc.Counter("aKey", time.Minute)
+		assert.NoError(t, tc.Touch("aKey", time.Hour))
+		clock.Advance(2 * time.Minute)
+
+		// The handle lives as long as its item.
+		assert.Same(t, counter, tc.Counter("aKey", time.Minute))
+		v, ok := counter.Add(1)
+		assert.True(t, ok)
+		assert.Equal(t, int64(1), v)
+
+		clock.Advance(time.Hour)
+		assert.False(t, counter.Valid())
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		for name, remove := range map[string]func(tc *Cache){
+			"delete":         func(tc *Cache) { tc.Delete("aKey") },
+			"overwrite":      func(tc *Cache) { tc.Set("aKey", "aValue", NoExpiration) },
+			"flush":          func(tc *Cache) { tc.Flush() },
+			"flushAndReturn": func(tc *Cache) { tc.FlushAndReturn() },
+		} {
+			t.Run(name, func(t *testing.T) {
+				tc := NewCache(DefaultExpiration, 0)
+				defer tc.Stop()
+
+				counter := tc.Counter("aKey", NoExpiration)
+				remove(tc)
+
+				_, ok := counter.Add(1)
+				assert.False(t, ok)
+			})
+		}
+	})
+
+	t.Run("replacesOtherValue", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		counter := tc.Counter("aKey", NoExpiration)
+		assert.Equal(t, int64(0), counter.Load())
+		value, _ := tc.Get("aKey")
+		assert.Same(t, counter, value)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		tc.Stop()
+
+		_, ok := tc.Counter("aKey", NoExpiration).Add(1)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,219 @@
+package go_cache
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// debugScanLimit Maximum number of items looked at by a request to the handler returned by StatsHandler.
+const debugScanLimit = 10_000
+
+// debugTTLBounds Upper bounds of the buckets of the TTL histogram of the handler returned by StatsHandler.
+var debugTTLBounds = []time.Duration{time.Second, 10 * time.Second, time.Minute, 10 * time.Minute, time.Hour, 24 * time.Hour}
+
+type debugResponse struct {
+	Name     string       `json:"name"`
+	Items    int          `json:"items"`
+	HitRatio float64      `json:"hitRatio"`
+	Stats    debugStats   `json:"stats"`
+	Counts   *debugCounts `json:"counts,omitempty"`
+	Health   debugHealth  `json:"health"`
+	TTL      *debugTTL    `json:"ttl,omitempty"`
+}
+
+type debugStats struct {
+	Hits             uint64        `json:"hits"`
+	Misses           uint64        `json:"misses"`
+	NegativeHits     uint64        `json:"negativeHits"`
+	ClosedWrites     uint64        `json:"closedWrites"`
+	OversizedWrites  uint64        `json:"oversizedWrites"`
+	Evictions        uint64        `json:"evictions"`
+	ExpiredDisplaced uint64        `json:"expiredDisplaced"`
+	Removals         debugRemovals `json:"removals"`
+	Cleanup          debugCleanup  `json:"cleanup"`
+}
+
+type debugRemovals struct {
+	Deleted uint64 `json:"deleted"`
+	Expired uint64 `json:"expired"`
+	Evicted uint64 `json:"evicted"`
+	Idle    uint64 `json:"idle"`
+	Flushed uint64 `json:"flushed"`
+}
+
+type debugCleanup struct {
+	Passes   uint64            `json:"passes"`
+	Scanned  uint64            `json:"scanned"`
+	Removed  uint64            `json:"removed"`
+	Duration string            `json:"duration"`
+	Last     *debugCleanupPass `json:"last,omitempty"`
+}
+
+type debugCleanupPass struct {
+	Start    time.Time `json:"start"`
+	Duration string    `json:"duration"`
+	Scanned  int       `json:"scanned"`
+	Removed  int       `json:"removed"`
+}
+
+type debugCounts struct {
+	Total        int `json:"total"`
+	Live         int `json:"live"`
+	Expired      int `json:"expired"`
+	NoExpiration int `json:"noExpiration"`
+}
+
+type debugHealth struct {
+	Status            string    `json:"status"`
+	JanitorEnabled    bool      `json:"janitorEnabled"`
+	JanitorRunning    bool      `json:"janitorRunning"`
+	JanitorLastRun    time.Time `json:"janitorLastRun"`
+	JanitorSinceLast  string    `json:"janitorSinceLastRun,omitempty"`
+	JanitorLate       bool      `json:"janitorLate"`
+	SnapshotEnabled   bool      `json:"snapshotEnabled"`
+	SnapshotLastRun   time.Time `json:"snapshotLastRun"`
+	SnapshotLastError string    `json:"snapshotLastError,omitempty"`
+}
+
+type debugTTL struct {
+	// Sampled is the number of items the histogram was computed from, at most debugScanLimit.
+	Sampled      int              `json:"sampled"`
+	Buckets      []debugTTLBucket `json:"buckets"`
+	NoExpiration int              `json:"noExpiration"`
+	Expired      int              `json:"expired"`
+}
+
+type debugTTLBucket struct {
+	// UpperBound is empty for the last bucket, holding the items expiring after the last bound.
+	UpperBound string `json:"le,omitempty"`
+	Count      int    `json:"count"`
+}
+
+// StatsHandler Returns a read-only HTTP handler describing the cache as JSON, meant to be mounted under /debug:
+// its name, number of items, hit ratio, stats and health. With ?verbose=1, it adds a histogram of the remaining
+// time to live of the items.
+//
+// The handler never takes the write lock, never serializes values, and bounds its work on large caches: the
+// breakdown of the items (see Counts) is only included if the cache holds at most 10,000 items, and the TTL
+// histogram is computed from at most as many items.
+func StatsHandler(c *Cache) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		verbose := r.URL.Query().Get("verbose") == "1"
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(c.debug(verbose))
+	})
+}
+
+// debug Describes the cache for StatsHandler.
+func (c *Cache) debug(verbose bool) debugResponse {
+	stats := c.loadStats()
+	resp := debugResponse{
+		Name: c.Name(),
+		Stats: debugStats{
+			Hits:             stats.Hits,
+			Misses:           stats.Misses,
+			NegativeHits:     stats.NegativeHits,
+			ClosedWrites:     stats.ClosedWrites,
+			OversizedWrites:  stats.OversizedWrites,
+			Evictions:        stats.Evictions,
+			ExpiredDisplaced: stats.ExpiredDisplaced,
+			Removals:         debugRemovals(stats.Removals),
+			Cleanup: debugCleanup{
+				Passes:   stats.Cleanup.Passes,
+				Scanned:  stats.Cleanup.Scanned,
+				Removed:  stats.Cleanup.Removed,
+				Duration: stats.Cleanup.Duration.String(),
+			},
+		},
+		Health: debugHealthOf(c.Health()),
+	}
+	if last := stats.Cleanup.Last; stats.Cleanup.Passes > 0 {
+		resp.Stats.Cleanup.Last = &debugCleanupPass{
+			Start:    last.Start,
+			Duration: last.Duration.String(),
+			Scanned:  last.Scanned,
+			Removed:  last.Removed,
+		}
+	}
+	if lookups := stats.Hits + stats.Misses + stats.NegativeHits; lookups > 0 {
+		resp.HitRatio = float64(stats.Hits) / float64(lookups)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	resp.Items = len(c.items)
+	var counts *Counts
+	if resp.Items <= debugScanLimit {
+		counts = &Counts{Total: resp.Items}
+	}
+	if verbose {
+		resp.TTL = &debugTTL{Buckets: make([]debugTTLBucket, len(debugTTLBounds)+1)}
+		for i, bound := range debugTTLBounds {
+			resp.TTL.Buckets[i].UpperBound = bound.String()
+		}
+	}
+	if counts == nil && resp.TTL == nil {
+		return resp
+	}
+
+	now := c.now().UnixNano()
+	n := 0
+	for _, item := range c.items {
+		if n++; n > debugScanLimit {
+			break
+		}
+		item = item.promote(now)
+		if counts != nil {
+			counts.add(item, now)
+		}
+		if resp.TTL != nil && !item.placeholder {
+			resp.TTL.Sampled++
+			switch {
+			case item.expiration == 0:
+				resp.TTL.NoExpiration++
+			case item.isExpired(now):
+				resp.TTL.Expired++
+			default:
+				ttl := time.Duration(item.expiration - now)
+				i := 0
+				for i < len(debugTTLBounds) && ttl > debugTTLBounds[i] {
+					i++
+				}
+				resp.TTL.Buckets[i].Count++
+			}
+		}
+	}
+	if counts != nil {
+		resp.Counts = (*debugCounts)(counts)
+	}
+
+	return resp
+}
+
+func debugHealthOf(h HealthReport) debugHealth {
+	d := debugHealth{
+		Status:          h.Status.String(),
+		JanitorEnabled:  h.JanitorEnabled,
+		JanitorRunning:  h.JanitorRunning,
+		JanitorLastRun:  h.JanitorLastRun,
+		JanitorLate:     h.JanitorLate,
+		SnapshotEnabled: h.SnapshotEnabled,
+		SnapshotLastRun: h.SnapshotLastRun,
+	}
+	if h.JanitorRunning {
+		d.JanitorSinceLast = h.JanitorSinceLastRun.String()
+	}
+	if h.SnapshotLastError != nil {
+		d.SnapshotLastError = h.SnapshotLastError.Error()
+	}
+
+	return d
+}
@@ -0,0 +1,145 @@
+package go_cache
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getDebug(t *testing.T, c *Cache, target string) map[string]any {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	StatsHandler(c).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return body
+}
+
+func TestStatsHandler(t *testing.T) {
+	t.Run("schema", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithName("sessions"))
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", "bValue", time.Second)
+		tc.Get("aKey")
+		tc.Get("aKey")
+		tc.Get("aKey")
+		tc.Get("cKey")
+		tc.Delete("aKey")
+		clock.Advance(time.Minute)
+
+		body := getDebug(t, tc, "/debug/cache")
+		assert.Equal(t, "sessions", body["name"])
+		assert.Equal(t, float64(1), body["items"])
+		assert.Equal(t, 0.75, body["hitRatio"])
+		assert.Equal(t, map[string]any{
+			"hits":             float64(3),
+			"misses":           float64(1),
+			"negativeHits":     float64(0),
+			"closedWrites":     float64(0),
+			"oversizedWrites":  float64(0),
+			"evictions":        float64(0),
+			"expiredDisplaced": float64(0),
+			"removals": map[string]any{
+				"deleted": float64(1), "expired": float64(0), "evicted": float64(0), "idle": float64(0), "flushed": float64(0),
+			},
+			"cleanup": map[string]any{
+				"passes": float64(0), "scanned": float64(0), "removed": float64(0), "duration": "0s",
+			},
+		}, body["stats"])
+		assert.Equal(t, map[string]any{
+			"total": float64(1), "live": float64(0), "expired": float64(1), "noExpiration": float64(0),
+		}, body["counts"])
+		health := body["health"].(map[string]any)
+		assert.Equal(t, "ok", health["status"])
+		assert.Equal(t, false, health["janitorEnabled"])
+		assert.NotContains(t, body, "ttl")
+	})
+
+	t.Run("verbose", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", "bValue", 30*time.Second)
+		tc.Set("cKey", "cValue", 45*time.Second)
+		tc.Set("dKey", "dValue", 48*time.Hour)
+
+		ttl := getDebug(t, tc, "/debug/cache?verbose=1")["ttl"].(map[string]any)
+		assert.Equal(t, float64(4), ttl["sampled"])
+		assert.Equal(t, float64(1), ttl["noExpiration"])
+		assert.Equal(t, float64(0), ttl["expired"])
+		assert.Equal(t, []any{
+			map[string]any{"le": "1s", "count": float64(0)},
+			map[string]any{"le": "10s", "count": float64(0)},
+			map[string]any{"le": "1m0s", "count": float64(2)},
+			map[string]any{"le": "10m0s", "count": float64(0)},
+			map[string]any{"le": "1h0m0s", "count": float64(0)},
+			map[string]any{"le": "24h0m0s", "count": float64(0)},
+			map[string]any{"count": float64(1)},
+		}, ttl["buckets"])
+	})
+
+	t.Run("readLockOnly", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		// The handler would block forever if it took the write lock.
+		tc.mu.RLock()
+		defer tc.mu.RUnlock()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			getDebug(t, tc, "/debug/cache?verbose=1")
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("handler blocked")
+		}
+	})
+
+	t.Run("largeCache", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		value := make([]byte, 1024)
+		for i := 0; i < 200_000; i++ {
+			tc.Set(strconv.Itoa(i), value, time.Hour)
+		}
+
+		rec := httptest.NewRecorder()
+		start := time.Now()
+		StatsHandler(tc).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/cache?verbose=1", nil))
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Less(t, rec.Body.Len(), 2048)
+
+		var body map[string]any
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, float64(200_000), body["items"])
+		assert.NotContains(t, body, "counts")
+		assert.Equal(t, float64(debugScanLimit), body["ttl"].(map[string]any)["sampled"])
+	})
+
+	t.Run("methodNotAllowed", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		rec := httptest.NewRecorder()
+		StatsHandler(tc).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/cache", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		assert.Equal(t, "GET, HEAD", rec.Header().Get("Allow"))
+	})
+}
@@ -0,0 +1,197 @@
+package go_cache
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+var (
+	// ErrNoChangeTracking is returned by SaveDiff when the cache was not created WithChangeTracking.
+	ErrNoChangeTracking = errors.New("changes are not tracked")
+	// ErrDiffTooOld is returned by SaveDiff when some of the deletions since the given sequence number were dropped
+	// from the bounded log of deletions, so that a full snapshot is needed instead.
+	ErrDiffTooOld = errors.New("changes since sequence number no longer tracked")
+)
+
+const diffFormat = "go-cache diff"
+
+// deletion A key removed from the cache, and the sequence number of its removal.
+type deletion struct {
+	key string
+	seq uint64
+}
+
+// changeLog Numbers the changes of a cache created WithChangeTracking, and remembers its recent deletions.
+type changeLog struct {
+	// seq is the sequence number of the last change.
+	seq uint64
+	// deletions is a ring of the most recent deletions, oldest first from start.
+	deletions []deletion
+	start     int
+	n         int
+	// truncated is the sequence number of the last deletion dropped from the ring, and flushed the one of the
+	// last flush, before which deletions do not matter.
+	truncated uint64
+	flushed   uint64
+}
+
+func newChangeLog(maxDeletions int) *changeLog {
+	return &changeLog{deletions: make([]deletion, max(maxDeletions, 1))}
+}
+
+// next Returns the sequence number of a new change.
+func (l *changeLog) next() uint64 {
+	l.seq++
+	return l.seq
+}
+
+// advance Makes the sequence numbers continue after the given one, e.g. that of a restored snapshot.
+func (l *changeLog) advance(seq uint64) {
+	l.seq = max(l.seq, seq)
+}
+
+// deleted Records the removal of the key, dropping the oldest deletion if the ring is full.
+func (l *changeLog) deleted(key string) {
+	d := deletion{key: key, seq: l.next()}
+	if l.n < len(l.deletions) {
+		l.deletions[(l.start+l.n)%len(l.deletions)] = d
+		l.n++
+		return
+	}
+	l.truncated = l.deletions[l.start].seq
+	l.deletions[l.start] = d
+	l.start = (l.start + 1) % len(l.deletions)
+}
+
+// flush Records the removal of all the keys, which makes the previous deletions irrelevant.
+func (l *changeLog) flush() {
+	l.flushed = l.next()
+	l.start, l.n, l.truncated = 0, 0, 0
+}
+
+// since Returns the keys deleted after the given sequence number, and whether the cache was flushed since.
+func (l *changeLog) since(seq uint64) ([]string, bool, error) {
+	if seq < l.truncated {
+		return nil, false, fmt.Errorf("%w: %d", ErrDiffTooOld, seq)
+	}
+	var keys []string
+	for i := 0; i < l.n; i++ {
+		if d := l.deletions[(l.start+i)%len(l.deletions)]; d.seq > seq {
+			keys = append(keys, d.key)
+		}
+	}
+
+	return keys, seq < l.flushed, nil
+}
+
+type diffHeader struct {
+	Format    string
+	Version   int
+	CreatedAt time.Time
+	// Since is the sequence number the diff starts after, and Seq the one it brings the cache up to.
+	Since uint64
+	Seq   uint64
+	// Flushed is set when the cache was flushed after Since, so that the items are to be flushed before applying
+	// the diff.
+	Flushed bool
+	Deleted []string
+}
+
+// SaveDiff Writes to w the changes of the cache since the given sequence number, returned by a previous call or
+// recorded in a snapshot written by Save, so that they can be applied with LoadDiff on top of an earlier restore:
+// the items written since, with their values encoded like by Save, and the keys removed since, for whatever reason.
+// Returns the sequence number to pass to the next call to continue the chain. Like Save, it does not block writers
+// for the whole save: changes happening meanwhile may be included, and are then included again in the next diff.
+// Returns ErrNoChangeTracking error if the cache was not created WithChangeTracking, ErrDiffTooOld error if
+// deletions since the sequence number are no longer tracked, and ErrHashedKeys error with WithHashedKeys.
+func (c *Cache) SaveDiff(w io.Writer, sinceSeq uint64) (uint64, error) {
+	if c.hasher != nil {
+		return 0, ErrHashedKeys
+	}
+	if c.changes == nil {
+		return 0, ErrNoChangeTracking
+	}
+
+	c.mu.RLock()
+	h := diffHeader{Format: diffFormat, Version: snapshotVersion, CreatedAt: c.now(), Since: sinceSeq, Seq: c.changes.seq}
+	var err error
+	h.Deleted, h.Flushed, err = c.changes.since(sinceSeq)
+	closed := c.closed
+	c.mu.RUnlock()
+	if err != nil {
+		return 0, err
+	}
+	if closed {
+		return 0, ErrCacheClosed
+	}
+
+	enc := gob.NewEncoder(w)
+	if err = enc.Encode(h); err != nil {
+		return 0, err
+	}
+	c.forEachValueChunked(func(key string, item item, value any) bool {
+		if item.seq <= sinceSeq {
+			return true
+		}
+		var data []byte
+		if data, err = c.codec.Marshal(value); err != nil {
+			err = fmt.Errorf("could not encode value of %s: %w", key, err)
+			return false
+		}
+		err = enc.Encode(c.snapshotItem(key, item, data))
+		return err == nil
+	})
+	if err == nil && c.isClosed() {
+		err = ErrCacheClosed
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return h.Seq, nil
+}
+
+// LoadDiff Applies changes written by SaveDiff to the cache, which must hold the snapshot or the chain of diffs they
+// follow: the cache is flushed first if the source was flushed meanwhile, the keys removed are deleted, and the items
+// written are loaded like by Load. With WithChangeTracking, the sequence numbers of the cache continue after the one
+// of the diff, so that a restored cache can go on with the chain.
+// Returns ErrHashedKeys error if the cache was created WithHashedKeys, since the keys were not stored.
+func (c *Cache) LoadDiff(r io.Reader) error {
+	if c.hasher != nil {
+		return ErrHashedKeys
+	}
+
+	dec := gob.NewDecoder(r)
+	var h diffHeader
+	if err := dec.Decode(&h); err != nil || h.Format != diffFormat {
+		return fmt.Errorf("%w: missing header", ErrInvalidSnapshot)
+	}
+	if h.Version > snapshotVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidSnapshot, h.Version)
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		c.unlock()
+		return ErrCacheClosed
+	}
+	if h.Flushed {
+		for key, it := range c.flush() {
+			c.release(key, it, item{}, true)
+		}
+	}
+	for _, key := range h.Deleted {
+		c.delete(key, removalDeleted)
+	}
+	if c.changes != nil {
+		c.changes.advance(h.Seq)
+	}
+	c.unlock()
+
+	return c.loadItems(nil, func(fn func(item SnapshotItem) error) error {
+		return decodeItems(dec, fn)
+	})
+}
@@ -0,0 +1,148 @@
+package go_cache
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_SaveDiff(t *testing.T) {
+	t.Run("roundTrip", func(t *testing.T) {
+		src := NewCacheWithOptions(WithChangeTracking(100))
+		defer src.Stop()
+		for _, key := range []string{"aKey", "bKey", "cKey", "dKey"} {
+			src.Set(key, key+"Value", NoExpiration)
+		}
+
+		var full bytes.Buffer
+		assert.Nil(t, src.Save(&full))
+		snapshot, err := ReadSnapshot(bytes.NewReader(full.Bytes()))
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(4), snapshot.Seq)
+
+		src.Set("aKey", "aValue2", NoExpiration)
+		src.Delete("bKey")
+		assert.Nil(t, src.Touch("cKey", time.Hour))
+		src.Delete("dKey")
+		src.Set("dKey", "dValue2", NoExpiration)
+		src.Set("eKey", "eValue", NoExpiration)
+
+		var diff bytes.Buffer
+		seq, err := src.SaveDiff(&diff, snapshot.Seq)
+		assert.Nil(t, err)
+		assert.Equal(t, uint64(10), seq)
+
+		src.Delete("eKey")
+		src.Set("fKey", "fValue", NoExpiration)
+		var next bytes.Buffer
+		_, err = src.SaveDiff(&next, seq)
+		assert.Nil(t, err)
+
+		dst := NewCacheWithOptions(WithChangeTracking(100))
+		defer dst.Stop()
+		dst.Set("other", "value", NoExpiration)
+		assert.Nil(t, dst.Load(&full))
+		assert.Nil(t, dst.LoadDiff(&diff))
+		assert.Nil(t, dst.LoadDiff(&next))
+		dst.Delete("other")
+
+		want, _ := src.Items()
+		got, _ := dst.Items()
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("onlyChanges", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithChangeTracking(100))
+		defer tc.Stop()
+		for i := 0; i < 100; i++ {
+			tc.Set(fmt.Sprintf("key%d", i), i, NoExpiration)
+		}
+		tc.Set("key0", -1, NoExpiration)
+		tc.Delete("key1")
+
+		var diff bytes.Buffer
+		_, err := tc.SaveDiff(&diff, 100)
+		assert.Nil(t, err)
+		var full bytes.Buffer
+		assert.Nil(t, tc.Save(&full))
+		assert.Less(t, diff.Len()*5, full.Len())
+
+		dst := NewCacheWithOptions()
+		defer dst.Stop()
+		dst.Set("key1", 1, NoExpiration)
+		assert.Nil(t, dst.LoadDiff(&diff))
+		items, _ := dst.Items()
+		assert.Equal(t, map[string]ItemInfo{"key0": {Object: -1}}, items)
+	})
+
+	t.Run("flush", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithChangeTracking(100))
+		defer tc.Stop()
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Flush()
+		tc.Set("bKey", "bValue", NoExpiration)
+
+		var diff bytes.Buffer
+		_, err := tc.SaveDiff(&diff, 1)
+		assert.Nil(t, err)
+
+		dst := NewCacheWithOptions()
+		defer dst.Stop()
+		dst.Set("aKey", "aValue", NoExpiration)
+		dst.Set("cKey", "cValue", NoExpiration)
+		assert.Nil(t, dst.LoadDiff(&diff))
+		items, _ := dst.Items()
+		assert.Equal(t, map[string]ItemInfo{"bKey": {Object: "bValue"}}, items)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		src := NewCacheWithOptions(WithChangeTracking(100))
+		defer src.Stop()
+		src.Set("aKey", "aValue", NoExpiration)
+		src.Set("bKey", "bValue", NoExpiration)
+		var full bytes.Buffer
+		assert.Nil(t, src.Save(&full))
+
+		// A cache restored from the snapshot goes on with its sequence numbers.
+		restored := NewCacheWithOptions(WithChangeTracking(100))
+		defer restored.Stop()
+		assert.Nil(t, restored.Load(bytes.NewReader(full.Bytes())))
+		restored.Set("cKey", "cValue", NoExpiration)
+		var diff bytes.Buffer
+		seq, err := restored.SaveDiff(&diff, 2)
+		assert.Nil(t, err)
+		assert.Greater(t, seq, uint64(2))
+
+		dst := NewCacheWithOptions()
+		defer dst.Stop()
+		assert.Nil(t, dst.Load(&full))
+		assert.Nil(t, dst.LoadDiff(&diff))
+		assert.Equal(t, 3, dst.ItemCount())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithChangeTracking(2))
+		defer tc.Stop()
+		for _, key := range []string{"aKey", "bKey", "cKey"} {
+			tc.Set(key, key, NoExpiration)
+		}
+		for _, key := range []string{"aKey", "bKey", "cKey"} {
+			tc.Delete(key)
+		}
+
+		var diff bytes.Buffer
+		_, err := tc.SaveDiff(&diff, 3)
+		assert.ErrorIs(t, err, ErrDiffTooOld)
+		_, err = tc.SaveDiff(&diff, 4)
+		assert.Nil(t, err)
+
+		untracked := NewCache(NoExpiration, 0)
+		defer untracked.Stop()
+		_, err = untracked.SaveDiff(&diff, 0)
+		assert.ErrorIs(t, err, ErrNoChangeTracking)
+		assert.ErrorIs(t, untracked.LoadDiff(bytes.NewReader([]byte("garbage"))), ErrInvalidSnapshot)
+	})
+}
@@ -0,0 +1,96 @@
+package go_cache
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// errorCache Remembers the recent failures of the loader, see WithErrorCaching.
+type errorCache struct {
+	ttl    time.Duration
+	maxTTL time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*cachedError
+}
+
+// cachedError The last failure of the loader for a key, and the number of failures in a row.
+type cachedError struct {
+	err      error
+	failures int
+	// until is the time, in nanoseconds, until which the error is returned without calling the loader.
+	until int64
+}
+
+func newErrorCache(ttl, maxTTL time.Duration) *errorCache {
+	if maxTTL < ttl {
+		maxTTL = ttl
+	}
+
+	return &errorCache{ttl: ttl, maxTTL: maxTTL, entries: make(map[string]*cachedError)}
+}
+
+// get Returns the error the loader failed with for the key, if it is still to be returned at the given time.
+func (e *errorCache) get(key string, now int64) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if entry, found := e.entries[key]; found && now < entry.until {
+		return entry.err
+	}
+
+	return nil
+}
+
+// failed Records a failure of the loader for the key, to be returned for a period doubling with each failure in a
+// row, up to maxTTL.
+func (e *errorCache) failed(key string, err error, now int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	entry, found := e.entries[key]
+	if !found {
+		entry = &cachedError{}
+		e.entries[key] = entry
+	}
+	entry.err = err
+	entry.failures++
+	d := e.ttl
+	for i := 1; i < entry.failures && d < e.maxTTL; i++ {
+		d *= 2
+	}
+	entry.until = now + min(d, e.maxTTL).Nanoseconds()
+}
+
+// succeeded Forgets the failures of the loader for the key once it succeeded.
+func (e *errorCache) succeeded(key string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.entries, key)
+}
+
+// deleteExpired Forgets the failures of the keys not retried for maxTTL since their error stopped being returned,
+// so that their backoff starts over.
+func (e *errorCache) deleteExpired(now int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for key, entry := range e.entries {
+		if now >= entry.until+e.maxTTL.Nanoseconds() {
+			delete(e.entries, key)
+		}
+	}
+}
+
+// cachedLoadError Returns an error wrapping both ErrLoadFailureCached and the error the loader last failed with for
+// the key, if it is still cached, see WithErrorCaching.
+func (c *Cache) cachedLoadError(key string) error {
+	err := c.errorCache.get(key, c.now().UnixNano())
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s: %w", ErrLoadFailureCached, key, err)
+}
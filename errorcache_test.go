@@ -0,0 +1,146 @@
+package go_cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_WithErrorCaching(t *testing.T) {
+	errDown := errors.New("database is down")
+
+	t.Run("backoff", func(t *testing.T) {
+		var calls atomic.Int32
+		var down atomic.Bool
+		down.Store(true)
+		tc := NewCacheWithOptions(WithErrorCaching(time.Second, 5*time.Second),
+			WithLoader(func(ctx context.Context, key string) (any, time.Duration, error) {
+				calls.Add(1)
+				if down.Load() {
+					return nil, 0, errDown
+				}
+				return key + "Value", DefaultExpiration, nil
+			}))
+		defer tc.Stop()
+		clock := newFakeClock()
+		tc.now = clock.Now
+
+		// Windows of 1s, 2s, 4s, then capped at 5s.
+		for i, window := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+			_, err := tc.Fetch(context.Background(), "aKey")
+			assert.ErrorIs(t, err, errDown)
+			assert.NotErrorIs(t, err, ErrLoadFailureCached)
+			assert.Equal(t, int32(i+1), calls.Load())
+
+			clock.Advance(window - time.Millisecond)
+			_, err = tc.Fetch(context.Background(), "aKey")
+			assert.ErrorIs(t, err, errDown)
+			assert.ErrorIs(t, err, ErrLoadFailureCached)
+			assert.Equal(t, int32(i+1), calls.Load())
+			clock.Advance(time.Millisecond)
+		}
+
+		down.Store(false)
+		a, err := tc.Fetch(context.Background(), "aKey")
+		assert.Nil(t, err)
+		assert.Equal(t, "aKeyValue", a)
+
+		// The backoff starts over after a success.
+		down.Store(true)
+		tc.Delete("aKey")
+		_, err = tc.Fetch(context.Background(), "aKey")
+		assert.ErrorIs(t, err, errDown)
+		clock.Advance(time.Second)
+		calls.Store(0)
+		_, err = tc.Fetch(context.Background(), "aKey")
+		assert.NotErrorIs(t, err, ErrLoadFailureCached)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("perKey", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithErrorCaching(time.Second, time.Minute))
+		defer tc.Stop()
+
+		var calls atomic.Int32
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			calls.Add(1)
+			if key == "aKey" {
+				return nil, 0, errDown
+			}
+			return key + "Value", DefaultExpiration, nil
+		}
+
+		for i := 0; i < 3; i++ {
+			_, err := tc.GetOrLoad(context.Background(), "aKey", loader)
+			assert.ErrorIs(t, err, errDown)
+			b, err := tc.GetOrLoad(context.Background(), "bKey", loader)
+			assert.Nil(t, err)
+			assert.Equal(t, "bKeyValue", b)
+		}
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("notFailures", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithErrorCaching(time.Minute, time.Minute))
+		defer tc.Stop()
+
+		var calls atomic.Int32
+		started := make(chan struct{})
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			calls.Add(1)
+			if key == "aKey" {
+				return nil, 0, ErrItemNotFound
+			}
+			close(started)
+			<-ctx.Done()
+			return nil, 0, ctx.Err()
+		}
+
+		_, err := tc.GetOrLoad(context.Background(), "aKey", loader)
+		assert.ErrorIs(t, err, ErrItemNotFound)
+		_, err = tc.GetOrLoad(context.Background(), "aKey", loader)
+		assert.NotErrorIs(t, err, ErrLoadFailureCached)
+		assert.Equal(t, int32(2), calls.Load())
+
+		// The load cancelled once its only caller gave up.
+		ctx, cancel := context.WithCancel(context.Background())
+		var call *loadCall
+		go func() {
+			<-started
+			tc.loadMu.Lock()
+			call = tc.loads["bKey"]
+			tc.loadMu.Unlock()
+			cancel()
+		}()
+		_, err = tc.GetOrLoad(ctx, "bKey", loader)
+		assert.ErrorIs(t, err, context.Canceled)
+		<-call.done
+		assert.Nil(t, tc.errorCache.get("bKey", tc.now().UnixNano()))
+	})
+
+	t.Run("forgotten", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithErrorCaching(time.Second, 4*time.Second))
+		defer tc.Stop()
+		clock := newFakeClock()
+		tc.now = clock.Now
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			return nil, 0, errDown
+		}
+
+		_, _ = tc.GetOrLoad(context.Background(), "aKey", loader)
+		clock.Advance(time.Second)
+		_, _ = tc.GetOrLoad(context.Background(), "aKey", loader)
+
+		// Once not retried for maxTTL past its window, the key starts over with ttl.
+		clock.Advance(6 * time.Second)
+		tc.errorCache.deleteExpired(tc.now().UnixNano())
+		_, _ = tc.GetOrLoad(context.Background(), "aKey", loader)
+		clock.Advance(time.Second)
+		_, err := tc.GetOrLoad(context.Background(), "aKey", loader)
+		assert.NotErrorIs(t, err, ErrLoadFailureCached)
+	})
+}
@@ -0,0 +1,150 @@
+package go_cache
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Evictable Is implemented by values which need to release resources when they are removed from a cache
+// created WithCloseOnEvict.
+type Evictable interface {
+	OnEvict()
+}
+
+// removal A value removed from the cache while holding the write lock, to be released once it is unlocked.
+type removal struct {
+	key    string
+	object any
+	// onEvict and onEvicted are set to call them with the value of the item, rather than to release the object.
+	onEvict   func(value any)
+	onEvicted func(key string, value any)
+	it        item
+}
+
+// unlock Releases the write lock, then releases the values removed from the cache while it was held,
+// and reports capacity pressure to the function set WithOnFull if a write caused evictions.
+// It must be used instead of c.mu.Unlock by the operations which can remove values.
+func (c *Cache) unlock() {
+	removed := c.removed
+	c.removed = nil
+	var pressure *CapacityPressure
+	if c.capacity != nil {
+		pressure = c.capacity.pressure
+		c.capacity.pressure = nil
+	}
+	c.mu.Unlock()
+
+	for _, r := range removed {
+		if r.onEvict != nil || r.onEvicted != nil {
+			value := c.value(r.key, r.it)
+			if value == nil && r.it.weak {
+				continue
+			}
+			if r.onEvict != nil {
+				r.onEvict(value)
+			}
+			if r.onEvicted != nil {
+				r.onEvicted(r.key, value)
+			}
+			continue
+		}
+		c.closeValue(r.key, r.object)
+	}
+	if pressure != nil {
+		c.onFull(*pressure)
+	}
+}
+
+// SetOnEvicted Sets the function called with the key and the value of the items removed from the cache, see
+// WithOnEvicted, nil to stop calling one. fn can call back into the cache, but when called on the cleanup goroutine
+// it must not call Stop or SetCleanupInterval.
+func (c *Cache) SetOnEvicted(fn func(key string, value any)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.onEvicted = fn
+}
+
+// release Records the values held by the item previously stored under the key as removed, except those still
+// held by the item replacing it. Nothing is recorded unless the cache was created WithCloseOnEvict, except the
+// values spilled to disk, whose files get deleted (see WithSpillover).
+// removed reports whether the item is removed from the cache, replacement being empty, rather than overwritten.
+// Removed counters (see Counter) are invalidated, and the callbacks set WithItemOnEvict and WithOnEvicted are
+// called unless the replacement is the same write, e.g. marked as expired.
+func (c *Cache) release(key string, previous, replacement item, removed bool) {
+	if (previous.onEvict != nil || c.onEvicted != nil) && !previous.placeholder && !previous.negative &&
+		(removed || previous.version != replacement.version || previous.created != replacement.created) {
+		r := removal{key: key, onEvict: previous.onEvict, it: previous}
+		if removed || c.overwriteEvictions {
+			r.onEvicted = c.onEvicted
+		}
+		if r.onEvict != nil || r.onEvicted != nil {
+			// Queued first, so that a value spilled to disk is read back before its file is deleted.
+			c.removed = append(c.removed, r)
+		}
+	}
+	if !c.closeOnEvict && !c.counters && c.spill == nil {
+		return
+	}
+
+	kept := replacement.values()
+	for _, object := range previous.values() {
+		if object == nil || (len(kept) > 0 && sameValue(object, kept[0])) || (len(kept) > 1 && sameValue(object, kept[1])) {
+			continue
+		}
+		if counter, ok := object.(*Counter); ok {
+			counter.invalidate()
+		}
+		if _, ok := object.(*spillFile); ok || c.closeOnEvict {
+			c.removed = append(c.removed, removal{key: key, object: object})
+		}
+	}
+}
+
+// values Returns the values held by the item, including its pending one.
+func (i item) values() []any {
+	var values []any
+	if !i.placeholder && !i.negative {
+		values = append(values, i.object)
+	}
+	if i.pending != nil {
+		values = append(values, i.pending.object)
+	}
+
+	return values
+}
+
+func (c *Cache) closeValue(key string, object any) {
+	if w, ok := object.(weakValue); ok {
+		// Collected values are not closed.
+		object = w.value()
+	}
+	switch v := object.(type) {
+	case *spillFile:
+		c.removeSpillFile(key, v)
+	case Evictable:
+		v.OnEvict()
+	case io.Closer:
+		if err := v.Close(); err != nil {
+			c.handleError(fmt.Errorf("could not close value of %s: %w", key, err))
+		}
+	}
+}
+
+// sameValue Reports whether a and b are the same value, without panicking on values which cannot be compared.
+func sameValue(a, b any) bool {
+	t := reflect.TypeOf(a)
+	if t == nil || t != reflect.TypeOf(b) || !t.Comparable() {
+		return false
+	}
+
+	return a == b
+}
+
+// handleError Reports an error which cannot be returned to a caller to the handler set WithErrorHandler, if any.
+func (c *Cache) handleError(err error) {
+	if c.errorHandler != nil {
+		c.errorHandler(err)
+	}
+}
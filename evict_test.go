@@ -0,0 +1,294 @@
+package go_cache
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCloser struct {
+	closed atomic.Int32
+	err    error
+	// onClose is called from Close, e.g. to call back into the cache.
+	onClose func()
+}
+
+func (tc *testCloser) Close() error {
+	tc.closed.Add(1)
+	if tc.onClose != nil {
+		tc.onClose()
+	}
+	return tc.err
+}
+
+type testEvictable struct {
+	evicted int
+}
+
+func (te *testEvictable) OnEvict() {
+	te.evicted++
+}
+
+func TestCache_WithCloseOnEvict(t *testing.T) {
+	t.Run("overwrite", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithCloseOnEvict())
+		defer tc.Stop()
+
+		first, second := &testCloser{}, &testCloser{}
+		tc.Set("aKey", first, DefaultExpiration)
+		tc.Set("aKey", first, DefaultExpiration)
+		assert.Equal(t, int32(0), first.closed.Load())
+
+		tc.Set("aKey", second, DefaultExpiration)
+		assert.Equal(t, int32(1), first.closed.Load())
+		assert.Equal(t, int32(0), second.closed.Load())
+
+		assert.NoError(t, tc.Replace("aKey", "aValue", DefaultExpiration))
+		assert.Equal(t, int32(1), first.closed.Load())
+		assert.Equal(t, int32(1), second.closed.Load())
+	})
+
+	t.Run("deleteExpireAndFlush", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithCloseOnEvict())
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		deleted, expired, flushed, kept := &testCloser{}, &testCloser{}, &testCloser{}, &testCloser{}
+		tc.Set("aKey", deleted, DefaultExpiration)
+		tc.Set("bKey", expired, 1*time.Second)
+		tc.Set("cKey", flushed, DefaultExpiration)
+
+		tc.Delete("aKey")
+		tc.Delete("aKey")
+		assert.Equal(t, int32(1), deleted.closed.Load())
+
+		clock.Advance(2 * time.Second)
+		tc.DeleteExpired()
+		assert.Equal(t, int32(1), expired.closed.Load())
+
+		tc.Flush()
+		assert.Equal(t, int32(1), flushed.closed.Load())
+
+		tc.Set("dKey", kept, DefaultExpiration)
+		items := tc.FlushAndReturn()
+		assert.Same(t, kept, items["dKey"].Object)
+		assert.Equal(t, int32(0), kept.closed.Load())
+	})
+
+	t.Run("pendingValues", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithCloseOnEvict())
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		current, staged, restaged := &testCloser{}, &testCloser{}, &testCloser{}
+		tc.Set("aKey", current, DefaultExpiration)
+		tc.SetVisibleAt("aKey", staged, clock.Now().Add(1*time.Minute), DefaultExpiration)
+		assert.Equal(t, int32(0), current.closed.Load())
+
+		tc.SetVisibleAt("aKey", restaged, clock.Now().Add(1*time.Minute), DefaultExpiration)
+		assert.Equal(t, int32(1), staged.closed.Load())
+		assert.Equal(t, int32(0), current.closed.Load())
+
+		clock.Advance(2 * time.Minute)
+		tc.DeleteExpired()
+		assert.Equal(t, int32(1), current.closed.Load())
+		assert.Equal(t, int32(0), restaged.closed.Load())
+	})
+
+	t.Run("outsideLock", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithCloseOnEvict())
+		defer tc.Stop()
+
+		value := &testCloser{}
+		value.onClose = func() {
+			tc.Set("closed", true, DefaultExpiration)
+		}
+		tc.Set("aKey", value, DefaultExpiration)
+		tc.Delete("aKey")
+
+		closed, found := tc.Get("closed")
+		assert.True(t, found)
+		assert.Equal(t, true, closed)
+	})
+
+	t.Run("errorHandler", func(t *testing.T) {
+		closeErr := errors.New("close failed")
+		var handled []error
+		tc := NewCacheWithOptions(WithCloseOnEvict(), WithErrorHandler(func(err error) {
+			handled = append(handled, err)
+		}))
+		defer tc.Stop()
+
+		tc.Set("aKey", &testCloser{err: closeErr}, DefaultExpiration)
+		tc.Delete("aKey")
+
+		assert.Len(t, handled, 1)
+		assert.ErrorIs(t, handled[0], closeErr)
+		assert.ErrorContains(t, handled[0], "aKey")
+	})
+
+	t.Run("evictable", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithCloseOnEvict())
+		defer tc.Stop()
+
+		value := &testEvictable{}
+		tc.Set("aKey", value, DefaultExpiration)
+		_, _ = tc.DeleteMany([]string{"aKey"})
+
+		assert.Equal(t, 1, value.evicted)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		value := &testCloser{}
+		tc.Set("aKey", value, DefaultExpiration)
+		tc.Delete("aKey")
+
+		assert.Equal(t, int32(0), value.closed.Load())
+	})
+}
+
+// evictedItems Records the keys and values passed to the function set WithOnEvicted.
+type evictedItems struct {
+	mu    sync.Mutex
+	items []string
+}
+
+func (e *evictedItems) onEvicted(key string, value any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.items = append(e.items, fmt.Sprintf("%s=%v", key, value))
+}
+
+func (e *evictedItems) get() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return slices.Clone(e.items)
+}
+
+func TestCache_WithOnEvicted(t *testing.T) {
+	t.Run("removals", func(t *testing.T) {
+		clock := newFakeClock()
+		var evicted evictedItems
+		tc := NewCacheWithOptions(WithOnEvicted(evicted.onEvicted), WithMaxItems(3))
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", "bValue", time.Second)
+		tc.Set("aKey", "aValue2", NoExpiration)
+		tc.Replace("aKey", "aValue3", NoExpiration)
+		assert.Empty(t, evicted.get())
+
+		tc.Delete("aKey")
+		clock.Advance(time.Second)
+		tc.DeleteExpired()
+		assert.Equal(t, []string{"aKey=aValue3", "bKey=bValue"}, evicted.get())
+
+		for _, key := range []string{"cKey", "dKey", "eKey", "fKey"} {
+			tc.Set(key, key, NoExpiration)
+		}
+		assert.Equal(t, []string{"aKey=aValue3", "bKey=bValue", "cKey=cKey"}, evicted.get())
+
+		tc.Flush()
+		assert.Len(t, evicted.get(), 6)
+
+		tc.Set("gKey", "gValue", NoExpiration)
+		tc.FlushAndReturn()
+		assert.Len(t, evicted.get(), 6)
+	})
+
+	t.Run("overwrites", func(t *testing.T) {
+		var evicted evictedItems
+		tc := NewCacheWithOptions(WithOnEvicted(evicted.onEvicted), WithOverwriteEvictions())
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("aKey", "aValue2", NoExpiration)
+		tc.Replace("aKey", "aValue3", NoExpiration)
+		tc.SoftFlush()
+		assert.Equal(t, []string{"aKey=aValue", "aKey=aValue2"}, evicted.get())
+		tc.DeleteExpired()
+		assert.Equal(t, []string{"aKey=aValue", "aKey=aValue2", "aKey=aValue3"}, evicted.get())
+	})
+
+	t.Run("janitor", func(t *testing.T) {
+		var evicted evictedItems
+		tc := NewCacheWithOptions(WithOnEvicted(evicted.onEvicted), WithCleanupInterval(time.Millisecond))
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", time.Millisecond)
+		assert.Eventually(t, func() bool {
+			return slices.Equal([]string{"aKey=aValue"}, evicted.get())
+		}, 5*time.Second, time.Millisecond)
+	})
+
+	t.Run("reentrant", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		// The callback runs once the lock is released, so it can write to the cache.
+		tc.SetOnEvicted(func(key string, value any) {
+			tc.Set("evicted:"+key, value, NoExpiration)
+		})
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Delete("aKey")
+
+		value, found := tc.Get("evicted:aKey")
+		assert.True(t, found)
+		assert.Equal(t, "aValue", value)
+
+		tc.SetOnEvicted(nil)
+		tc.Delete("evicted:aKey")
+		assert.Equal(t, 0, tc.ItemCount())
+	})
+
+	t.Run("reentrantJanitor", func(t *testing.T) {
+		var evicted evictedItems
+		tc := NewCacheWithOptions(WithCleanupInterval(time.Millisecond))
+		defer tc.Stop()
+
+		// On the cleanup goroutine too, the callback can read, write and delete, overwrites included.
+		tc.SetOnEvicted(func(key string, value any) {
+			evicted.onEvicted(key, value)
+			if key != "aKey" {
+				return
+			}
+			if _, found := tc.Get("bKey"); found {
+				tc.Set("bKey", "bValue2", NoExpiration)
+				tc.Delete("bKey")
+			}
+		})
+		tc.Set("bKey", "bValue", NoExpiration)
+		tc.Set("aKey", "aValue", time.Millisecond)
+
+		assert.Eventually(t, func() bool {
+			return slices.Equal([]string{"aKey=aValue", "bKey=bValue2"}, evicted.get())
+		}, 5*time.Second, time.Millisecond)
+		assert.Equal(t, 0, tc.ItemCount())
+	})
+
+	t.Run("notCoalesced", func(t *testing.T) {
+		var evicted evictedItems
+		tc := NewCacheWithOptions(WithOnEvicted(evicted.onEvicted), WithOverwriteEvictions(),
+			WithWriteCoalescing(time.Hour))
+		defer tc.Stop()
+
+		for _, value := range []string{"aValue", "aValue2", "aValue3"} {
+			tc.Set("aKey", value, NoExpiration)
+		}
+		assert.Equal(t, []string{"aKey=aValue", "aKey=aValue2"}, evicted.get())
+	})
+}
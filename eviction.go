@@ -0,0 +1,197 @@
+package go_cache
+
+import (
+	"container/list"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+)
+
+// EvictionPolicy Decides which item is evicted when the cache is over its capacity limits, see WithEvictionPolicy.
+// The cache tells the policy about the keys it holds and asks it for a victim when it must make room.
+//
+// The methods are called with the cache lock held, so they must be cheap (ideally constant time) and must never
+// call back into the cache. OnAdd, OnRemove and Victim are called with the write lock held, never concurrently.
+// OnAccess is called on hits with the read lock held, so it may be called concurrently with itself: a policy
+// updating its state there must synchronise it.
+//
+// A policy holds the state of one cache: each cache needs its own instance.
+type EvictionPolicy interface {
+	// OnAdd Is called when a value is written under the key, whether the key is new or not.
+	OnAdd(key string)
+	// OnAccess Is called when a read hits the item stored under the key.
+	OnAccess(key string)
+	// OnRemove Is called when the item stored under the key is removed from the cache, including by eviction.
+	OnRemove(key string)
+	// Victim Returns the key of the next item to evict, or false if there is none.
+	// The item is then removed from the cache, with a call to OnRemove.
+	Victim() (key string, ok bool)
+}
+
+// fifoPolicy Evicts the oldest written item, writing a key again making it the newest.
+type fifoPolicy struct {
+	order *insertionOrder
+}
+
+// NewFIFOPolicy Returns a policy evicting the oldest written item, writing a key again making it the newest.
+// It is the policy of caches created without WithEvictionPolicy.
+func NewFIFOPolicy() EvictionPolicy {
+	return &fifoPolicy{order: newInsertionOrder(true)}
+}
+
+func (p *fifoPolicy) OnAdd(key string) {
+	p.order.inserted(key, true)
+}
+
+func (p *fifoPolicy) OnAccess(string) {}
+
+func (p *fifoPolicy) OnRemove(key string) {
+	p.order.removed(key)
+}
+
+func (p *fifoPolicy) Victim() (string, bool) {
+	e := p.order.keys.Front()
+	if e == nil {
+		return "", false
+	}
+
+	return e.Value.(string), true
+}
+
+// lruPolicy Evicts the least recently written or read item.
+type lruPolicy struct {
+	fifoPolicy
+	// mu guards the order against concurrent hits.
+	mu sync.Mutex
+}
+
+// NewLRUPolicy Returns a policy evicting the least recently written or read item. Every hit moves the item to the
+// back of the order, under a lock shared by all the readers.
+func NewLRUPolicy() EvictionPolicy {
+	return &lruPolicy{fifoPolicy: fifoPolicy{order: newInsertionOrder(true)}}
+}
+
+func (p *lruPolicy) OnAccess(key string) {
+	p.mu.Lock()
+	p.order.inserted(key, false)
+	p.mu.Unlock()
+}
+
+// randomPolicy Evicts an item picked at random.
+type randomPolicy struct {
+	keys    []string
+	indexes map[string]int
+}
+
+// NewRandomPolicy Returns a policy evicting an item picked at random.
+func NewRandomPolicy() EvictionPolicy {
+	return &randomPolicy{indexes: make(map[string]int)}
+}
+
+func (p *randomPolicy) OnAdd(key string) {
+	if _, found := p.indexes[key]; found {
+		return
+	}
+	p.indexes[key] = len(p.keys)
+	p.keys = append(p.keys, key)
+}
+
+func (p *randomPolicy) OnAccess(string) {}
+
+func (p *randomPolicy) OnRemove(key string) {
+	i, found := p.indexes[key]
+	if !found {
+		return
+	}
+	last := len(p.keys) - 1
+	p.keys[i] = p.keys[last]
+	p.indexes[p.keys[i]] = i
+	p.keys = p.keys[:last]
+	delete(p.indexes, key)
+}
+
+func (p *randomPolicy) Victim() (string, bool) {
+	if len(p.keys) == 0 {
+		return "", false
+	}
+
+	return p.keys[rand.IntN(len(p.keys))], true
+}
+
+// clockPolicy Evicts like lruPolicy, approximately, without any lock on hits.
+type clockPolicy struct {
+	keys     *list.List
+	elements map[string]*list.Element
+	// hand is the next entry looked at by Victim, nil to start again from the front.
+	hand *list.Element
+}
+
+type clockEntry struct {
+	key        string
+	referenced atomic.Bool
+}
+
+// NewClockPolicy Returns a policy evicting like NewLRUPolicy, approximately, without any lock on hits: each item
+// has a reference bit set when it is read, and the eviction hand sweeps the items in the order they were first
+// written, clearing the bits it meets until it finds an item which was not read since its last pass.
+func NewClockPolicy() EvictionPolicy {
+	return &clockPolicy{keys: list.New(), elements: make(map[string]*list.Element)}
+}
+
+// OnAdd Adds new keys unreferenced, so that items written once and never read again are the first ones evicted,
+// and counts writing a key again as a reference.
+func (p *clockPolicy) OnAdd(key string) {
+	if e, found := p.elements[key]; found {
+		e.Value.(*clockEntry).referenced.Store(true)
+		return
+	}
+	p.elements[key] = p.keys.PushBack(&clockEntry{key: key})
+}
+
+func (p *clockPolicy) OnAccess(key string) {
+	// Hits only read the map, which is written with the write lock held.
+	if e, found := p.elements[key]; found {
+		entry := e.Value.(*clockEntry)
+		// Loading first avoids writing to memory shared by all cores on every hit.
+		if !entry.referenced.Load() {
+			entry.referenced.Store(true)
+		}
+	}
+}
+
+func (p *clockPolicy) OnRemove(key string) {
+	e, found := p.elements[key]
+	if !found {
+		return
+	}
+	if p.hand == e {
+		p.hand = e.Next()
+	}
+	p.keys.Remove(e)
+	delete(p.elements, key)
+}
+
+func (p *clockPolicy) Victim() (string, bool) {
+	// Every entry is met at most twice: once to clear its bit and once to evict it.
+	for range 2*p.keys.Len() + 2 {
+		if p.hand == nil {
+			if p.hand = p.keys.Front(); p.hand == nil {
+				return "", false
+			}
+		}
+		// The item just written is skipped, as if it had been written once room was made for it.
+		if p.hand == p.keys.Back() && p.keys.Len() > 1 {
+			p.hand = nil
+			continue
+		}
+		entry := p.hand.Value.(*clockEntry)
+		if entry.referenced.Load() {
+			entry.referenced.Store(false)
+			p.hand = p.hand.Next()
+			continue
+		}
+		return entry.key, true
+	}
+
+	return "", false
+}
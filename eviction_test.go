@@ -0,0 +1,213 @@
+package go_cache
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// hitRatio Replays a trace mixing a small hot set read most of the time with a large cold set, filling the cache
+// on misses, and returns the share of reads which hit.
+func hitRatio(policy func() EvictionPolicy) float64 {
+	tc := NewCacheWithOptions(WithMaxItems(100), WithEvictionPolicy(policy()))
+	defer tc.Stop()
+
+	r := rand.New(rand.NewPCG(1, 2))
+	hits, reads := 0, 20_000
+	for i := 0; i < reads; i++ {
+		key := strconv.Itoa(r.IntN(50))
+		if r.IntN(10) < 3 {
+			key = "cold" + strconv.Itoa(r.IntN(10_000))
+		}
+		if _, found := tc.Get(key); found {
+			hits++
+			continue
+		}
+		tc.Set(key, struct{}{}, NoExpiration)
+	}
+
+	return float64(hits) / float64(reads)
+}
+
+// largestPolicy Evicts the item with the largest cost, as given by the costs set by the test.
+type largestPolicy struct {
+	costs   map[string]int
+	keys    map[string]struct{}
+	removed []string
+}
+
+func (p *largestPolicy) OnAdd(key string) {
+	p.keys[key] = struct{}{}
+}
+
+func (p *largestPolicy) OnAccess(string) {}
+
+func (p *largestPolicy) OnRemove(key string) {
+	delete(p.keys, key)
+	p.removed = append(p.removed, key)
+}
+
+func (p *largestPolicy) Victim() (string, bool) {
+	victim, found := "", false
+	for key := range p.keys {
+		if !found || p.costs[key] > p.costs[victim] {
+			victim, found = key, true
+		}
+	}
+
+	return victim, found
+}
+
+func TestCache_WithEvictionPolicy(t *testing.T) {
+	t.Run("custom", func(t *testing.T) {
+		policy := &largestPolicy{costs: map[string]int{"aKey": 1, "bKey": 3, "cKey": 2, "dKey": 1}, keys: map[string]struct{}{}}
+		tc := NewCacheWithOptions(WithMaxItems(3), WithEvictionPolicy(policy))
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", "bValue", NoExpiration)
+		tc.Set("cKey", "cValue", NoExpiration)
+		tc.Set("dKey", "dValue", NoExpiration)
+		keys, _ := tc.Keys()
+		assert.ElementsMatch(t, []string{"aKey", "cKey", "dKey"}, keys)
+
+		tc.Delete("aKey")
+		assert.Equal(t, []string{"bKey", "aKey"}, policy.removed)
+		tc.Flush()
+		assert.Empty(t, policy.keys)
+	})
+
+	t.Run("clock", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMaxItems(3), WithEvictionPolicy(NewClockPolicy()))
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", "bValue", NoExpiration)
+		tc.Set("cKey", "cValue", NoExpiration)
+		tc.Get("aKey")
+		tc.Get("cKey")
+
+		// aKey gets a second chance, bKey was never read.
+		tc.Set("dKey", "dValue", NoExpiration)
+		_, found := tc.Get("bKey")
+		assert.False(t, found)
+
+		// The hand cleared the bit of aKey on its way, but then met cKey, still referenced, and dKey, never read.
+		tc.Set("eKey", "eValue", NoExpiration)
+		keys, _ := tc.Keys()
+		assert.ElementsMatch(t, []string{"aKey", "cKey", "eKey"}, keys)
+		assert.Equal(t, 3, tc.ItemCount())
+		assert.Equal(t, uint64(2), tc.Stats().Evictions)
+	})
+
+	t.Run("clockAllReferenced", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMaxItems(2), WithEvictionPolicy(NewClockPolicy()))
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", "bValue", NoExpiration)
+		tc.Get("aKey")
+		tc.Get("bKey")
+
+		// After a full sweep, the hand is back on the oldest item.
+		tc.Set("cKey", "cValue", NoExpiration)
+		_, found := tc.Get("aKey")
+		assert.False(t, found)
+		assert.Equal(t, 2, tc.ItemCount())
+	})
+
+	t.Run("clockHandOnDelete", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMaxItems(3), WithEvictionPolicy(NewClockPolicy()))
+		defer tc.Stop()
+
+		for _, key := range []string{"aKey", "bKey", "cKey"} {
+			tc.Set(key, key, NoExpiration)
+			tc.Get(key)
+		}
+		tc.Set("dKey", "dValue", NoExpiration)
+		tc.Delete("bKey")
+		tc.Set("eKey", "eValue", NoExpiration)
+		tc.Set("fKey", "fValue", NoExpiration)
+
+		assert.Equal(t, 3, tc.ItemCount())
+		_, found := tc.Get("cKey")
+		assert.False(t, found)
+	})
+
+	t.Run("lru", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMaxItems(2), WithEvictionPolicy(NewLRUPolicy()))
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", "bValue", NoExpiration)
+		tc.Get("aKey")
+		tc.Set("cKey", "cValue", NoExpiration)
+
+		_, found := tc.Get("aKey")
+		assert.True(t, found)
+		_, found = tc.Get("bKey")
+		assert.False(t, found)
+	})
+
+	t.Run("random", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMaxItems(10), WithEvictionPolicy(NewRandomPolicy()))
+		defer tc.Stop()
+
+		for i := 0; i < 100; i++ {
+			tc.Set(strconv.Itoa(i), i, NoExpiration)
+		}
+		assert.Equal(t, 10, tc.ItemCount())
+		assert.Equal(t, uint64(90), tc.Stats().Evictions)
+	})
+
+	t.Run("setMaxItems", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithEvictionPolicy(NewClockPolicy()))
+		defer tc.Stop()
+
+		for i := 0; i < 10; i++ {
+			tc.Set(strconv.Itoa(i), i, NoExpiration)
+		}
+		tc.SetMaxItems(5)
+		assert.Equal(t, 5, tc.ItemCount())
+
+		tc.Get("9")
+		tc.Set("aKey", "aValue", NoExpiration)
+		_, found := tc.Get("9")
+		assert.True(t, found)
+	})
+
+	t.Run("hitRatio", func(t *testing.T) {
+		clock, random := hitRatio(NewClockPolicy), hitRatio(NewRandomPolicy)
+		t.Logf("hit ratio: clock %.3f, lru %.3f, fifo %.3f, random %.3f",
+			clock, hitRatio(NewLRUPolicy), hitRatio(NewFIFOPolicy), random)
+		assert.Greater(t, clock, random+0.05)
+	})
+}
+
+func BenchmarkCache_GetEvictionPolicy(b *testing.B) {
+	for _, bc := range []struct {
+		name   string
+		policy func() EvictionPolicy
+	}{
+		{"clock", NewClockPolicy},
+		{"lru", NewLRUPolicy},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			tc := NewCacheWithOptions(WithMaxItems(1_000), WithEvictionPolicy(bc.policy()))
+			defer tc.Stop()
+			for i := 0; i < 1_000; i++ {
+				tc.Set(strconv.Itoa(i), i, NoExpiration)
+			}
+
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					tc.Get(strconv.Itoa(i % 1_000))
+					i++
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package go_cache
+
+import "time"
+
+// Expirer Is implemented by values which know how long they remain valid, e.g. tokens or DNS records.
+// When such a value is written with DefaultExpiration, its CacheTTL is used instead of the cache's default
+// expiration time, with the same semantics as the duration passed to Set. Explicit durations always win.
+type Expirer interface {
+	CacheTTL() time.Duration
+}
+
+// resolveDuration Returns the duration an object written with the given duration is cached for,
+// resolving DefaultExpiration to the object's own TTL if it is an Expirer, or the cache's default otherwise.
+func (c *Cache) resolveDuration(object any, duration time.Duration) time.Duration {
+	if duration != DefaultExpiration {
+		return duration
+	}
+	if e, ok := object.(Expirer); ok {
+		if d := e.CacheTTL(); d != DefaultExpiration {
+			return d
+		}
+	}
+
+	return c.defaultExpiration
+}
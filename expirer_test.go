@@ -0,0 +1,67 @@
+package go_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testToken struct {
+	ttl time.Duration
+}
+
+func (t testToken) CacheTTL() time.Duration {
+	return t.ttl
+}
+
+func TestCache_Expirer(t *testing.T) {
+	t.Run("defaultExpirationUsesValueTTL", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(1*time.Hour, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", testToken{ttl: 90 * time.Second}, DefaultExpiration)
+		tc.Set("bKey", testToken{ttl: 90 * time.Second}, 10*time.Second)
+		tc.Set("cKey", testToken{ttl: NoExpiration}, DefaultExpiration)
+		tc.Set("dKey", testToken{}, DefaultExpiration)
+
+		info, _ := tc.Info("aKey")
+		assert.True(t, clock.Now().Add(90*time.Second).Equal(info.Expiration))
+		info, _ = tc.Info("bKey")
+		assert.True(t, clock.Now().Add(10*time.Second).Equal(info.Expiration))
+		info, _ = tc.Info("cKey")
+		assert.True(t, info.Expiration.IsZero())
+		info, _ = tc.Info("dKey")
+		assert.True(t, clock.Now().Add(1*time.Hour).Equal(info.Expiration))
+	})
+
+	t.Run("addAndReplace", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(1*time.Hour, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		assert.NoError(t, tc.Add("aKey", testToken{ttl: 90 * time.Second}, DefaultExpiration))
+		clock.Advance(1 * time.Minute)
+		assert.NoError(t, tc.Replace("aKey", testToken{ttl: 30 * time.Second}, DefaultExpiration))
+
+		clock.Advance(31 * time.Second)
+		_, found := tc.Get("aKey")
+		assert.False(t, found)
+	})
+
+	t.Run("namespace", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(1*time.Hour, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		ns := tc.Namespace("ns:", WithNamespaceDefaultExpiration(10*time.Minute))
+		ns.Set("aKey", testToken{ttl: 90 * time.Second}, DefaultExpiration)
+
+		info, _ := tc.Info("ns:aKey")
+		assert.True(t, clock.Now().Add(90*time.Second).Equal(info.Expiration))
+	})
+}
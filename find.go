@@ -0,0 +1,32 @@
+package go_cache
+
+// FindKeys Returns the keys of the live items whose value satisfies pred, at most limit of them (at least one), in
+// no particular order, e.g. to investigate which keys hold a poisoned value. It goes through the whole cache, in
+// O(n), so it is meant for diagnostics rather than regular lookups: see WithIndex for those. The cache is read in
+// chunks like All, so writers are never blocked for the whole scan, and the scan stops as soon as limit keys are
+// found. pred is called without holding the lock. With hashed keys (see WithHashedKeys), the hashes are returned
+// instead of the keys.
+func (c *Cache) FindKeys(pred func(value any) bool, limit int) []string {
+	if limit < 1 {
+		limit = 1
+	}
+
+	var keys []string
+	c.forEachValueChunked(func(key string, _ item, value any) bool {
+		if pred(value) {
+			keys = append(keys, key)
+		}
+		return len(keys) < limit
+	})
+
+	return keys
+}
+
+// KeysForValue Returns the keys of the live items holding the given value, at most limit of them, like FindKeys.
+// Values are compared with ==, which for pointers means the same pointer, and values of types which are not
+// comparable, e.g. slices or maps, never match.
+func (c *Cache) KeysForValue(v any, limit int) []string {
+	return c.FindKeys(func(value any) bool {
+		return sameValue(value, v)
+	}, limit)
+}
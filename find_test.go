@@ -0,0 +1,110 @@
+package go_cache
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_FindKeys(t *testing.T) {
+	t.Run("predicate", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		for i := 0; i < 10; i++ {
+			tc.Set(strconv.Itoa(i), i, NoExpiration)
+		}
+
+		keys := tc.FindKeys(func(value any) bool {
+			n, ok := value.(int)
+			return ok && n%3 == 0
+		}, 100)
+		assert.ElementsMatch(t, []string{"0", "3", "6", "9"}, keys)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		for i := 0; i < 3*iterationChunkSize; i++ {
+			tc.Set(strconv.Itoa(i), i, NoExpiration)
+		}
+
+		calls := 0
+		keys := tc.FindKeys(func(any) bool {
+			calls++
+			return true
+		}, 5)
+		assert.Len(t, keys, 5)
+		assert.Equal(t, 5, calls)
+
+		assert.Len(t, tc.FindKeys(func(any) bool { return true }, 0), 1)
+	})
+
+	t.Run("skipsExpired", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "value", 1*time.Second)
+		tc.Set("bKey", "value", NoExpiration)
+		clock.Advance(2 * time.Second)
+
+		assert.Equal(t, []string{"bKey"}, tc.FindKeys(func(value any) bool { return value == "value" }, 10))
+	})
+
+	t.Run("spilledValuesDeletedByPred", func(t *testing.T) {
+		var errs []error
+		tc := NewCacheWithOptions(WithSpillover(t.TempDir(), 16), WithErrorHandler(func(err error) {
+			errs = append(errs, err)
+		}))
+		defer tc.Stop()
+
+		for i := 0; i < 3; i++ {
+			tc.Set(strconv.Itoa(i), largeValue(64), NoExpiration)
+		}
+
+		// The values of the chunk are read before pred runs, so deleting the spilled files does not lose them.
+		keys := tc.FindKeys(func(value any) bool {
+			tc.Flush()
+			return len(value.([]byte)) == 64
+		}, 10)
+		assert.Len(t, keys, 3)
+		assert.Empty(t, errs)
+	})
+}
+
+func TestCache_KeysForValue(t *testing.T) {
+	t.Run("samePointer", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		poisoned := &snapshotValue{Name: "poisoned"}
+		tc.Set("aKey", poisoned, NoExpiration)
+		tc.Set("bKey", poisoned, NoExpiration)
+		tc.Set("cKey", poisoned, NoExpiration)
+		// An equal value behind another pointer is not the same value.
+		tc.Set("dKey", &snapshotValue{Name: "poisoned"}, NoExpiration)
+		tc.Set("eKey", []byte("poisoned"), NoExpiration)
+
+		assert.ElementsMatch(t, []string{"aKey", "bKey", "cKey"}, tc.KeysForValue(poisoned, 10))
+		assert.Len(t, tc.KeysForValue(poisoned, 2), 2)
+	})
+
+	t.Run("comparableValues", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", snapshotValue{Name: "a", Count: 1}, NoExpiration)
+		tc.Set("bKey", snapshotValue{Name: "a", Count: 1}, NoExpiration)
+		tc.Set("cKey", "a", NoExpiration)
+		tc.Set("dKey", []byte("a"), NoExpiration)
+
+		assert.ElementsMatch(t, []string{"aKey", "bKey"}, tc.KeysForValue(snapshotValue{Name: "a", Count: 1}, 10))
+		assert.Equal(t, []string{"cKey"}, tc.KeysForValue("a", 10))
+		assert.Empty(t, tc.KeysForValue([]byte("a"), 10))
+	})
+}
@@ -0,0 +1,135 @@
+// Package fsloader Provides a loader reading the values of a cache from the files of an fs.FS, e.g. an embed.FS
+// or os.DirFS, for use with WithLoader or GetOrLoad.
+package fsloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sync"
+	"time"
+
+	go_cache "github.com/J4NN0/go-cache"
+)
+
+// Option Configures a loader created with New.
+type Option func(*Loader)
+
+// WithTransform Sets a function turning the content of a file into the value cached for it,
+// e.g. to parse a template. By default, the content is cached as []byte.
+func WithTransform(transform func(name string, data []byte) (any, error)) Option {
+	return func(l *Loader) {
+		l.transform = transform
+	}
+}
+
+// WithTTL Makes the loader cache files for the given duration, with the same semantics as the duration passed to
+// Set. By default, files are cached with the default expiration time of the cache.
+func WithTTL(d time.Duration) Option {
+	return func(l *Loader) {
+		l.ttl = func(time.Time) time.Duration { return d }
+	}
+}
+
+// WithModTimeTTL Makes the loader cache each file for the duration returned by the given function from its
+// modification time, e.g. longer for files which have not changed in a while.
+func WithModTimeTTL(ttl func(modTime time.Time) time.Duration) Option {
+	return func(l *Loader) {
+		l.ttl = ttl
+	}
+}
+
+// Loader Loads the file named by a key from a file system, see Load.
+type Loader struct {
+	fsys      fs.FS
+	transform func(name string, data []byte) (any, error)
+	ttl       func(modTime time.Time) time.Duration
+
+	mu sync.Mutex
+	// modTimes holds the modification time of the files loaded, for Invalidate.
+	modTimes map[string]time.Time
+}
+
+// New Returns a loader reading files from the given file system.
+func New(fsys fs.FS, opts ...Option) *Loader {
+	l := &Loader{
+		fsys:      fsys,
+		transform: func(_ string, data []byte) (any, error) { return data, nil },
+		ttl:       func(time.Time) time.Duration { return go_cache.DefaultExpiration },
+		modTimes:  make(map[string]time.Time),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// Load Reads the file named by the key, and is meant to be passed to WithLoader or GetOrLoad.
+// A missing file loads nothing: an error wrapping both go_cache.ErrItemNotFound and fs.ErrNotExist is returned.
+func (l *Loader) Load(_ context.Context, key string) (any, time.Duration, error) {
+	info, err := fs.Stat(l.fsys, key)
+	if err == nil {
+		var data []byte
+		if data, err = fs.ReadFile(l.fsys, key); err == nil {
+			var value any
+			if value, err = l.transform(key, data); err != nil {
+				return nil, 0, fmt.Errorf("could not transform %s: %w", key, err)
+			}
+			l.mu.Lock()
+			l.modTimes[key] = info.ModTime()
+			l.mu.Unlock()
+			return value, l.ttl(info.ModTime()), nil
+		}
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, 0, fmt.Errorf("%w: %s: %w", go_cache.ErrItemNotFound, key, err)
+	}
+
+	return nil, 0, err
+}
+
+// Invalidate Deletes from the cache the files loaded which have been modified or removed since,
+// and returns their names.
+func (l *Loader) Invalidate(c *go_cache.Cache) []string {
+	l.mu.Lock()
+	loaded := make(map[string]time.Time, len(l.modTimes))
+	for name, modTime := range l.modTimes {
+		loaded[name] = modTime
+	}
+	l.mu.Unlock()
+
+	var changed []string
+	for name, modTime := range loaded {
+		info, err := fs.Stat(l.fsys, name)
+		if err == nil && info.ModTime().Equal(modTime) {
+			continue
+		}
+		c.Delete(name)
+		changed = append(changed, name)
+
+		l.mu.Lock()
+		if l.modTimes[name].Equal(modTime) {
+			delete(l.modTimes, name)
+		}
+		l.mu.Unlock()
+	}
+
+	return changed
+}
+
+// Watch Calls Invalidate at the given interval until ctx is done.
+func (l *Loader) Watch(ctx context.Context, c *go_cache.Cache, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			l.Invalidate(c)
+		}
+	}
+}
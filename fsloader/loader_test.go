@@ -0,0 +1,121 @@
+package fsloader
+
+import (
+	"context"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	go_cache "github.com/J4NN0/go-cache"
+	"github.com/stretchr/testify/assert"
+)
+
+var modTime = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func TestLoader(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("load", func(t *testing.T) {
+		fsys := fstest.MapFS{"templates/index.html": {Data: []byte("<h1>{{.}}</h1>"), ModTime: modTime}}
+		l := New(fsys, WithTTL(time.Hour))
+		tc := go_cache.NewCacheWithOptions(go_cache.WithLoader(l.Load))
+		defer tc.Stop()
+
+		value, err := tc.Fetch(ctx, "templates/index.html")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("<h1>{{.}}</h1>"), value)
+
+		info, found := tc.Info("templates/index.html")
+		assert.True(t, found)
+		assert.False(t, info.Expiration.IsZero())
+	})
+
+	t.Run("transform", func(t *testing.T) {
+		fsys := fstest.MapFS{"a.txt": {Data: []byte("hello")}}
+		l := New(fsys, WithTransform(func(name string, data []byte) (any, error) {
+			return strings.ToUpper(string(data)), nil
+		}))
+
+		value, _, err := l.Load(ctx, "a.txt")
+		assert.NoError(t, err)
+		assert.Equal(t, "HELLO", value)
+	})
+
+	t.Run("modTimeTTL", func(t *testing.T) {
+		fsys := fstest.MapFS{"a.txt": {Data: []byte("hello"), ModTime: modTime}}
+		l := New(fsys, WithModTimeTTL(func(m time.Time) time.Duration {
+			assert.True(t, modTime.Equal(m))
+			return 42 * time.Second
+		}))
+
+		_, ttl, err := l.Load(ctx, "a.txt")
+		assert.NoError(t, err)
+		assert.Equal(t, 42*time.Second, ttl)
+	})
+
+	t.Run("notFound", func(t *testing.T) {
+		l := New(fstest.MapFS{})
+
+		_, _, err := l.Load(ctx, "missing.txt")
+		assert.ErrorIs(t, err, go_cache.ErrItemNotFound)
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"a.txt": {Data: []byte("a"), ModTime: modTime},
+			"b.txt": {Data: []byte("b"), ModTime: modTime},
+			"c.txt": {Data: []byte("c"), ModTime: modTime},
+		}
+		l := New(fsys)
+		tc := go_cache.NewCacheWithOptions(go_cache.WithLoader(l.Load))
+		defer tc.Stop()
+		for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+			_, err := tc.Fetch(ctx, name)
+			assert.NoError(t, err)
+		}
+
+		assert.Empty(t, l.Invalidate(tc))
+
+		fsys["a.txt"] = &fstest.MapFile{Data: []byte("a2"), ModTime: modTime.Add(time.Minute)}
+		delete(fsys, "b.txt")
+		assert.ElementsMatch(t, []string{"a.txt", "b.txt"}, l.Invalidate(tc))
+
+		_, found := tc.Get("a.txt")
+		assert.False(t, found)
+		_, found = tc.Get("b.txt")
+		assert.False(t, found)
+		_, found = tc.Get("c.txt")
+		assert.True(t, found)
+
+		value, err := tc.Fetch(ctx, "a.txt")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("a2"), value)
+		assert.Empty(t, l.Invalidate(tc))
+	})
+
+	t.Run("watch", func(t *testing.T) {
+		fsys := fstest.MapFS{"a.txt": {Data: []byte("a"), ModTime: modTime}}
+		l := New(fsys)
+		tc := go_cache.NewCacheWithOptions(go_cache.WithLoader(l.Load))
+		defer tc.Stop()
+		_, err := tc.Fetch(ctx, "a.txt")
+		assert.NoError(t, err)
+
+		fsys["a.txt"] = &fstest.MapFile{Data: []byte("a2"), ModTime: modTime.Add(time.Minute)}
+		ctx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			l.Watch(ctx, tc, 10*time.Millisecond)
+		}()
+		<-time.After(50 * time.Millisecond)
+		cancel()
+		<-done
+
+		_, found := tc.Get("a.txt")
+		assert.False(t, found)
+	})
+}
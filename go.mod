@@ -1,6 +1,6 @@
 module github.com/J4NN0/go-cache
 
-go 1.19
+go 1.24
 
 require github.com/stretchr/testify v1.8.4
 
@@ -0,0 +1,4 @@
+// Package cachepb Holds the protocol buffers definition of the gRPC service of grpccache and its generated code.
+package cachepb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative cache.proto
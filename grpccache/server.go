@@ -0,0 +1,144 @@
+// Package grpccache Exposes a cache over gRPC, so that processes not written in Go can share it, e.g. as a sidecar.
+// The service is defined in cachepb/cache.proto. Values travel as bytes along with the type of their content.
+// It is a module of its own, so that only its users depend on gRPC and protobuf.
+package grpccache
+
+import (
+	"context"
+	"fmt"
+
+	go_cache "github.com/J4NN0/go-cache"
+	"github.com/J4NN0/go-cache/grpccache/cachepb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Value A value stored in the cache through the service. Values written by Go code as []byte or string
+// can be read through the service as well, as application/octet-stream and text/plain respectively.
+type Value struct {
+	Data        []byte
+	ContentType string
+}
+
+type service struct {
+	cachepb.UnimplementedCacheServer
+
+	c *go_cache.Cache
+}
+
+// NewServer Returns a new gRPC server, created with the given options (e.g. interceptors),
+// on which the service exposing the cache is registered.
+func NewServer(c *go_cache.Cache, opts ...grpc.ServerOption) *grpc.Server {
+	s := grpc.NewServer(opts...)
+	cachepb.RegisterCacheServer(s, NewService(c))
+
+	return s
+}
+
+// NewService Returns the service exposing the cache, to register on an existing gRPC server.
+func NewService(c *go_cache.Cache) cachepb.CacheServer {
+	return &service{c: c}
+}
+
+func (s *service) Get(_ context.Context, req *cachepb.GetRequest) (*cachepb.GetResponse, error) {
+	object, found := s.c.Get(req.GetKey())
+	if !found {
+		return &cachepb.GetResponse{}, nil
+	}
+	value, err := toValue(req.GetKey(), object)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cachepb.GetResponse{Found: true, Value: value}, nil
+}
+
+func (s *service) Set(_ context.Context, req *cachepb.SetRequest) (*cachepb.SetResponse, error) {
+	duration := go_cache.DefaultExpiration
+	switch {
+	case req.GetNoExpiration():
+		duration = go_cache.NoExpiration
+	case req.GetTtl() != nil:
+		if err := req.GetTtl().CheckValid(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if duration = req.GetTtl().AsDuration(); duration <= 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "ttl must be positive, got %s", duration)
+		}
+	}
+
+	s.c.Set(req.GetKey(), Value{Data: req.GetValue().GetData(), ContentType: req.GetValue().GetContentType()}, duration)
+
+	return &cachepb.SetResponse{}, nil
+}
+
+func (s *service) Delete(_ context.Context, req *cachepb.DeleteRequest) (*cachepb.DeleteResponse, error) {
+	s.c.Delete(req.GetKey())
+
+	return &cachepb.DeleteResponse{}, nil
+}
+
+func (s *service) Flush(context.Context, *cachepb.FlushRequest) (*cachepb.FlushResponse, error) {
+	s.c.Flush()
+
+	return &cachepb.FlushResponse{}, nil
+}
+
+func (s *service) Stats(context.Context, *cachepb.StatsRequest) (*cachepb.StatsResponse, error) {
+	stats := s.c.Stats()
+
+	return &cachepb.StatsResponse{
+		Hits:         stats.Hits,
+		Misses:       stats.Misses,
+		NegativeHits: stats.NegativeHits,
+		Total:        int64(stats.Counts.Total),
+		Live:         int64(stats.Counts.Live),
+		Expired:      int64(stats.Counts.Expired),
+	}, nil
+}
+
+func (s *service) Watch(req *cachepb.WatchRequest, stream grpc.ServerStreamingServer[cachepb.WatchEvent]) error {
+	events := s.c.WatchPrefix(stream.Context(), req.GetPrefix())
+	// Sending the headers tells the client that no change happening from now on can be missed.
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	for event := range events {
+		e := &cachepb.WatchEvent{Key: event.Key, Op: watchOps[event.Op]}
+		if event.Op == go_cache.WatchSet || event.Op == go_cache.WatchReplace {
+			value, err := toValue(event.Key, event.Value)
+			if err != nil {
+				return err
+			}
+			e.Value = value
+		}
+		if err := stream.Send(e); err != nil {
+			return err
+		}
+	}
+
+	return stream.Context().Err()
+}
+
+var watchOps = map[go_cache.WatchOp]cachepb.WatchOp{
+	go_cache.WatchSet:     cachepb.WatchOp_WATCH_OP_SET,
+	go_cache.WatchReplace: cachepb.WatchOp_WATCH_OP_REPLACE,
+	go_cache.WatchDelete:  cachepb.WatchOp_WATCH_OP_DELETE,
+	go_cache.WatchExpire:  cachepb.WatchOp_WATCH_OP_EXPIRE,
+}
+
+func toValue(key string, object any) (*cachepb.Value, error) {
+	switch v := object.(type) {
+	case Value:
+		return &cachepb.Value{Data: v.Data, ContentType: v.ContentType}, nil
+	case []byte:
+		return &cachepb.Value{Data: v, ContentType: "application/octet-stream"}, nil
+	case string:
+		return &cachepb.Value{Data: []byte(v), ContentType: "text/plain; charset=utf-8"}, nil
+	default:
+		return nil, status.Error(codes.FailedPrecondition, fmt.Sprintf("value of %s has unsupported type %T", key, object))
+	}
+}
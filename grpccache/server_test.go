@@ -0,0 +1,190 @@
+package grpccache
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	go_cache "github.com/J4NN0/go-cache"
+	"github.com/J4NN0/go-cache/grpccache/cachepb"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// newClient Serves the cache over an in-memory connection and returns a client connected to it.
+func newClient(t *testing.T, c *go_cache.Cache, opts ...grpc.ServerOption) cachepb.CacheClient {
+	lis := bufconn.Listen(1 << 20)
+	s := NewServer(c, opts...)
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return cachepb.NewCacheClient(conn)
+}
+
+func TestServer_GetSetDelete(t *testing.T) {
+	tc := go_cache.NewCache(go_cache.DefaultExpiration, 0)
+	defer tc.Stop()
+	client := newClient(t, tc)
+	ctx := context.Background()
+
+	resp, err := client.Get(ctx, &cachepb.GetRequest{Key: "aKey"})
+	assert.NoError(t, err)
+	assert.False(t, resp.GetFound())
+
+	_, err = client.Set(ctx, &cachepb.SetRequest{
+		Key:   "aKey",
+		Value: &cachepb.Value{Data: []byte(`{"a":1}`), ContentType: "application/json"},
+		Ttl:   durationpb.New(1 * time.Minute),
+	})
+	assert.NoError(t, err)
+
+	resp, err = client.Get(ctx, &cachepb.GetRequest{Key: "aKey"})
+	assert.NoError(t, err)
+	assert.True(t, resp.GetFound())
+	assert.Equal(t, []byte(`{"a":1}`), resp.GetValue().GetData())
+	assert.Equal(t, "application/json", resp.GetValue().GetContentType())
+
+	info, found := tc.Info("aKey")
+	assert.True(t, found)
+	assert.WithinDuration(t, time.Now().Add(1*time.Minute), info.Expiration, 5*time.Second)
+
+	_, err = client.Delete(ctx, &cachepb.DeleteRequest{Key: "aKey"})
+	assert.NoError(t, err)
+	_, found = tc.Get("aKey")
+	assert.False(t, found)
+}
+
+func TestServer_Set(t *testing.T) {
+	tc := go_cache.NewCache(1*time.Hour, 0)
+	defer tc.Stop()
+	client := newClient(t, tc)
+	ctx := context.Background()
+
+	_, err := client.Set(ctx, &cachepb.SetRequest{Key: "default", Value: &cachepb.Value{Data: []byte("a")}})
+	assert.NoError(t, err)
+	info, _ := tc.Info("default")
+	assert.WithinDuration(t, time.Now().Add(1*time.Hour), info.Expiration, 5*time.Second)
+
+	_, err = client.Set(ctx, &cachepb.SetRequest{Key: "forever", Value: &cachepb.Value{Data: []byte("b")}, NoExpiration: true})
+	assert.NoError(t, err)
+	info, _ = tc.Info("forever")
+	assert.True(t, info.Expiration.IsZero())
+
+	_, err = client.Set(ctx, &cachepb.SetRequest{Key: "negative", Ttl: durationpb.New(-1 * time.Second)})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServer_GetGoValues(t *testing.T) {
+	tc := go_cache.NewCache(go_cache.DefaultExpiration, 0)
+	defer tc.Stop()
+	client := newClient(t, tc)
+	ctx := context.Background()
+
+	tc.Set("bytes", []byte{1, 2}, go_cache.DefaultExpiration)
+	tc.Set("string", "aValue", go_cache.DefaultExpiration)
+	tc.Set("int", 1, go_cache.DefaultExpiration)
+
+	resp, err := client.Get(ctx, &cachepb.GetRequest{Key: "bytes"})
+	assert.NoError(t, err)
+	assert.Equal(t, "application/octet-stream", resp.GetValue().GetContentType())
+
+	resp, err = client.Get(ctx, &cachepb.GetRequest{Key: "string"})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("aValue"), resp.GetValue().GetData())
+
+	_, err = client.Get(ctx, &cachepb.GetRequest{Key: "int"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestServer_FlushAndStats(t *testing.T) {
+	tc := go_cache.NewCache(go_cache.DefaultExpiration, 0)
+	defer tc.Stop()
+	client := newClient(t, tc)
+	ctx := context.Background()
+
+	tc.Set("aKey", "aValue", go_cache.DefaultExpiration)
+	tc.Set("bKey", "bValue", go_cache.DefaultExpiration)
+	_, _ = client.Get(ctx, &cachepb.GetRequest{Key: "aKey"})
+	_, _ = client.Get(ctx, &cachepb.GetRequest{Key: "cKey"})
+
+	stats, err := client.Stats(ctx, &cachepb.StatsRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), stats.GetHits())
+	assert.Equal(t, uint64(1), stats.GetMisses())
+	assert.Equal(t, int64(2), stats.GetTotal())
+	assert.Equal(t, int64(2), stats.GetLive())
+
+	_, err = client.Flush(ctx, &cachepb.FlushRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, tc.ItemCount())
+}
+
+func TestServer_Watch(t *testing.T) {
+	tc := go_cache.NewCache(go_cache.DefaultExpiration, 0)
+	defer tc.Stop()
+	client := newClient(t, tc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &cachepb.WatchRequest{Prefix: "user:"})
+	assert.NoError(t, err)
+
+	_, err = stream.Header()
+	assert.NoError(t, err)
+
+	tc.Set("other:aKey", "ignored", go_cache.DefaultExpiration)
+	tc.Set("user:aKey", "aValue", go_cache.DefaultExpiration)
+	tc.Set("user:aKey", "aNewValue", go_cache.DefaultExpiration)
+	tc.Delete("user:aKey")
+
+	expected := []*cachepb.WatchEvent{
+		{Key: "user:aKey", Op: cachepb.WatchOp_WATCH_OP_SET, Value: &cachepb.Value{Data: []byte("aValue"), ContentType: "text/plain; charset=utf-8"}},
+		{Key: "user:aKey", Op: cachepb.WatchOp_WATCH_OP_REPLACE, Value: &cachepb.Value{Data: []byte("aNewValue"), ContentType: "text/plain; charset=utf-8"}},
+		{Key: "user:aKey", Op: cachepb.WatchOp_WATCH_OP_DELETE},
+	}
+	for _, want := range expected {
+		event, err := stream.Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, want.GetKey(), event.GetKey())
+		assert.Equal(t, want.GetOp(), event.GetOp())
+		assert.Equal(t, want.GetValue().GetData(), event.GetValue().GetData())
+		assert.Equal(t, want.GetValue().GetContentType(), event.GetValue().GetContentType())
+	}
+
+	cancel()
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
+
+func TestServer_Interceptor(t *testing.T) {
+	tc := go_cache.NewCache(go_cache.DefaultExpiration, 0)
+	defer tc.Stop()
+
+	var methods []string
+	client := newClient(t, tc, grpc.UnaryInterceptor(
+		func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			methods = append(methods, info.FullMethod)
+			return handler(ctx, req)
+		}))
+
+	_, err := client.Get(context.Background(), &cachepb.GetRequest{Key: "aKey"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/gocache.v1.Cache/Get"}, methods)
+}
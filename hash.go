@@ -0,0 +1,38 @@
+package go_cache
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"hash/maphash"
+)
+
+// keyHasher Hashes keys into 128 bits made of two 64-bit hashes with independent random seeds, stored as a
+// 32 character hexadecimal string. The full key is not kept to verify a match, so two keys with the same hash
+// would share an entry. With random seeds chosen per cache, such collisions cannot be crafted from outside,
+// and the chance of an accidental one stays below 10^-18 up to ten billion keys, which is the trade-off
+// chosen in favor of memory. The seeds are not persisted: hashes differ between caches and processes.
+type keyHasher struct {
+	seeds [2]maphash.Seed
+}
+
+func newKeyHasher() *keyHasher {
+	return &keyHasher{seeds: [2]maphash.Seed{maphash.MakeSeed(), maphash.MakeSeed()}}
+}
+
+func (h *keyHasher) hash(key string) string {
+	var sum [16]byte
+	binary.BigEndian.PutUint64(sum[:8], maphash.String(h.seeds[0], key))
+	binary.BigEndian.PutUint64(sum[8:], maphash.String(h.seeds[1], key))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// hashKey Returns the key items are stored under for the given key, which is the key itself unless
+// the cache was created WithHashedKeys. Exported methods call it once, before accessing the items.
+func (c *Cache) hashKey(key string) string {
+	if c.hasher == nil {
+		return key
+	}
+
+	return c.hasher.hash(key)
+}
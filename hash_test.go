@@ -0,0 +1,136 @@
+package go_cache
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func longKey(i int) string {
+	return "https://example.com/" + strings.Repeat("segment/", 256) + strconv.Itoa(i)
+}
+
+func TestCache_WithHashedKeys(t *testing.T) {
+	t.Run("basicOperations", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithHashedKeys())
+		defer tc.Stop()
+
+		tc.Set(longKey(1), "aValue", DefaultExpiration)
+
+		err := tc.Add(longKey(2), "bValue", DefaultExpiration)
+		assert.Nil(t, err)
+
+		err = tc.Add(longKey(1), "a2Value", DefaultExpiration)
+		assert.ErrorIs(t, err, ErrItemAlreadyExists)
+
+		err = tc.Replace(longKey(2), "b2Value", DefaultExpiration)
+		assert.Nil(t, err)
+
+		a, found := tc.Get(longKey(1))
+		assert.Equal(t, "aValue", a)
+		assert.True(t, found)
+
+		b, found := tc.Get(longKey(2))
+		assert.Equal(t, "b2Value", b)
+		assert.True(t, found)
+
+		tc.Delete(longKey(1))
+
+		a, found = tc.Get(longKey(1))
+		assert.Nil(t, a)
+		assert.False(t, found)
+
+		ic := tc.ItemCount()
+		assert.Equal(t, 1, ic)
+	})
+
+	t.Run("withInitialItems", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithHashedKeys(), WithInitialItems(map[string]any{"aKey": "aValue"}, NoExpiration))
+		defer tc.Stop()
+
+		a, found := tc.Get("aKey")
+		assert.Equal(t, "aValue", a)
+		assert.True(t, found)
+	})
+
+	t.Run("keysUnavailable", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithHashedKeys())
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+
+		keys, err := tc.Keys()
+		assert.ErrorIs(t, err, ErrHashedKeys)
+		assert.Nil(t, keys)
+	})
+
+	t.Run("loaderReceivesOriginalKey", func(t *testing.T) {
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			return key, DefaultExpiration, nil
+		}
+		tc := NewCacheWithOptions(WithHashedKeys(), WithLoader(loader))
+		defer tc.Stop()
+
+		a, err := tc.Fetch(context.Background(), "aKey")
+		assert.Nil(t, err)
+		assert.Equal(t, "aKey", a)
+
+		a, found := tc.Get("aKey")
+		assert.Equal(t, "aKey", a)
+		assert.True(t, found)
+	})
+
+	t.Run("lessMemoryWithLongKeys", func(t *testing.T) {
+		const n = 10_000
+
+		fill := func(tc *Cache) uint64 {
+			before := heapInUse()
+			for i := 0; i < n; i++ {
+				tc.Set(longKey(i), i, DefaultExpiration)
+			}
+			return heapInUse() - before
+		}
+
+		plain := NewCache(NoExpiration, 0)
+		defer plain.Stop()
+		hashed := NewCacheWithOptions(WithHashedKeys())
+		defer hashed.Stop()
+
+		plainUsage := fill(plain)
+		hashedUsage := fill(hashed)
+		t.Logf("heap usage for %d keys of %d bytes: plain %d, hashed %d", n, len(longKey(0)), plainUsage, hashedUsage)
+
+		assert.Less(t, hashedUsage*4, plainUsage)
+	})
+}
+
+func BenchmarkCache_SetLongKeys(b *testing.B) {
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = longKey(i)
+	}
+
+	b.Run("plain", func(b *testing.B) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			tc.Set(keys[i%len(keys)], i, DefaultExpiration)
+		}
+	})
+
+	b.Run("hashed", func(b *testing.B) {
+		tc := NewCacheWithOptions(WithHashedKeys())
+		defer tc.Stop()
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			tc.Set(keys[i%len(keys)], i, DefaultExpiration)
+		}
+	})
+}
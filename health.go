@@ -0,0 +1,188 @@
+package go_cache
+
+import (
+	"sync"
+	"time"
+)
+
+// HealthStatus Summarises a HealthReport.
+type HealthStatus int
+
+const (
+	// HealthOK The cache is running and all its background work is up to date.
+	HealthOK HealthStatus = iota
+	// HealthDegraded The cache still serves requests, but some of its background work is late or failing, or the
+	// breaker of its loader is not closed (see WithLoaderBreaker).
+	HealthDegraded
+	// HealthStopped Stop has been called on the cache.
+	HealthStopped
+)
+
+// String Returns the status in lower case, e.g. "ok".
+func (s HealthStatus) String() string {
+	switch s {
+	case HealthOK:
+		return "ok"
+	case HealthDegraded:
+		return "degraded"
+	case HealthStopped:
+		return "stopped"
+	default:
+		return "unknown"
+	}
+}
+
+// janitorLateAfter is the number of cleanup intervals without a cleanup pass after which the janitor is late.
+const janitorLateAfter = 3
+
+// HealthReport Describes the state of the cache and of its background work, see Cache.Health.
+type HealthReport struct {
+	Status  HealthStatus
+	Stopped bool
+
+	// JanitorEnabled Whether the cache was given a cleanup interval, so a cleanup goroutine should be running.
+	JanitorEnabled bool
+	// JanitorRunning Whether the cleanup goroutine is running.
+	JanitorRunning bool
+	// JanitorLastRun The time of the last cleanup pass, zero if none happened yet.
+	JanitorLastRun time.Time
+	// JanitorSinceLastRun The time elapsed since the end of the last cleanup pass, or since the cleanup goroutine
+	// started if no pass happened yet, zero unless it is running. A value growing past the cleanup interval means
+	// the passes do not keep up, see Stats.Cleanup for how long they take.
+	JanitorSinceLastRun time.Duration
+	// JanitorLate Whether no cleanup pass happened for several cleanup intervals.
+	JanitorLate bool
+
+	// SnapshotEnabled Whether the cache was created WithAutoSnapshot.
+	SnapshotEnabled bool
+	// SnapshotLastRun The time of the last automatic snapshot, successful or not, zero if none happened yet.
+	SnapshotLastRun time.Time
+	// SnapshotLastError The error of the last automatic snapshot, nil if it succeeded.
+	SnapshotLastError error
+
+	// BreakerEnabled Whether the cache was created WithLoaderBreaker.
+	BreakerEnabled bool
+	// BreakerState The state of the circuit breaker of the loader, BreakerClosed unless enabled.
+	BreakerState BreakerState
+}
+
+// health holds what the background work of the cache records for Health.
+// It has its own mutex so that neither recording nor reporting waits for the cache lock.
+type health struct {
+	mu sync.Mutex
+
+	stopped bool
+
+	janitorInterval time.Duration
+	janitorRunning  bool
+	janitorStarted  time.Time
+	janitorLastRun  time.Time
+	cleanup         CleanupStats
+
+	snapshotEnabled   bool
+	snapshotLastRun   time.Time
+	snapshotLastError error
+}
+
+func (h *health) janitorStart(interval time.Duration, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.janitorInterval = interval
+	h.janitorRunning = true
+	h.janitorStarted = now
+}
+
+func (h *health) janitorRan(now time.Time, pass CleanupPass) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.janitorLastRun = now
+	h.cleanup.Passes++
+	h.cleanup.Scanned += uint64(pass.Scanned)
+	h.cleanup.Removed += uint64(pass.Removed)
+	h.cleanup.Duration += pass.Duration
+	h.cleanup.Last = pass
+}
+
+func (h *health) janitorExit() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.janitorRunning = false
+}
+
+func (h *health) janitorDisable() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.janitorInterval = 0
+	h.janitorRunning = false
+}
+
+func (h *health) snapshotStart() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.snapshotEnabled = true
+}
+
+func (h *health) snapshotRan(now time.Time, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.snapshotLastRun = now
+	h.snapshotLastError = err
+}
+
+func (h *health) stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.stopped = true
+}
+
+// Health Returns a report on the state of the cache, meant for readiness probes.
+// It does not take the cache lock nor scan the items, so it is cheap and never waits for other operations.
+func (c *Cache) Health() HealthReport {
+	now := c.now()
+
+	h := &c.health
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r := HealthReport{
+		Stopped:        h.stopped,
+		JanitorEnabled: h.janitorInterval > 0,
+		JanitorRunning: h.janitorRunning,
+		JanitorLastRun: h.janitorLastRun,
+
+		SnapshotEnabled:   h.snapshotEnabled,
+		SnapshotLastRun:   h.snapshotLastRun,
+		SnapshotLastError: h.snapshotLastError,
+	}
+	if c.breaker != nil {
+		r.BreakerEnabled = true
+		r.BreakerState = BreakerState(c.breaker.state.Load())
+	}
+	if r.JanitorRunning {
+		last := h.janitorLastRun
+		if last.IsZero() {
+			last = h.janitorStarted
+		}
+		r.JanitorSinceLastRun = now.Sub(last)
+		r.JanitorLate = r.JanitorSinceLastRun > janitorLateAfter*h.janitorInterval
+	}
+
+	switch {
+	case r.Stopped:
+		r.Status = HealthStopped
+	case r.JanitorEnabled && (!r.JanitorRunning || r.JanitorLate), r.SnapshotLastError != nil,
+		r.BreakerState != BreakerClosed:
+		r.Status = HealthDegraded
+	default:
+		r.Status = HealthOK
+	}
+
+	return r
+}
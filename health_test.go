@@ -0,0 +1,58 @@
+package go_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_Health(t *testing.T) {
+	t.Run("withoutJanitor", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		assert.Equal(t, HealthReport{Status: HealthOK}, tc.Health())
+	})
+
+	t.Run("janitorRunning", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 10*time.Millisecond)
+		defer tc.Stop()
+
+		<-time.After(50 * time.Millisecond)
+
+		report := tc.Health()
+		assert.Equal(t, HealthOK, report.Status)
+		assert.True(t, report.JanitorEnabled)
+		assert.True(t, report.JanitorRunning)
+		assert.False(t, report.JanitorLate)
+		assert.False(t, report.JanitorLastRun.IsZero())
+	})
+
+	t.Run("janitorLate", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 10*time.Millisecond)
+		defer tc.Stop()
+
+		// Holding the lock stalls the janitor in its cleanup pass, while Health must still answer.
+		tc.mu.Lock()
+		<-time.After(100 * time.Millisecond)
+
+		report := tc.Health()
+		tc.mu.Unlock()
+
+		assert.Equal(t, HealthDegraded, report.Status)
+		assert.True(t, report.JanitorRunning)
+		assert.True(t, report.JanitorLate)
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 10*time.Millisecond)
+		tc.Stop()
+
+		report := tc.Health()
+		assert.Equal(t, HealthStopped, report.Status)
+		assert.True(t, report.Stopped)
+		assert.True(t, report.JanitorEnabled)
+		assert.False(t, report.JanitorRunning)
+	})
+}
@@ -0,0 +1,71 @@
+package go_cache
+
+import (
+	"iter"
+	"time"
+)
+
+// Idle Returns an iterator over the keys of the live items which have not been read for longer than the given
+// duration, or since their creation if they have never been read. The cache must have been created
+// WithAccessTracking, otherwise nothing is yielded. The duration is measured from the time the iteration starts,
+// and the cache is read in chunks, with the same consistency guarantees as All.
+func (c *Cache) Idle(d time.Duration) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		if !c.accessTracking {
+			return
+		}
+
+		cutoff := c.now().Add(-d).UnixNano()
+		c.forEachChunked(func(key string, item item) bool {
+			if item.accessed.Load() >= cutoff {
+				return true
+			}
+			return yield(key)
+		})
+	}
+}
+
+// IdleLongerThan Returns the keys of the live items which have not been read for longer than the given duration,
+// see Idle.
+func (c *Cache) IdleLongerThan(d time.Duration) []string {
+	var keys []string
+	for key := range c.Idle(d) {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// DeleteIdle Deletes the live items which have not been read for longer than the given duration, see Idle,
+// and returns the number of items deleted. Items read between the scan and their deletion are kept.
+func (c *Cache) DeleteIdle(d time.Duration) int {
+	if !c.accessTracking {
+		return 0
+	}
+
+	cutoff := c.now().Add(-d).UnixNano()
+	var keys []string
+	c.forEachChunked(func(key string, item item) bool {
+		if item.accessed.Load() < cutoff {
+			keys = append(keys, key)
+		}
+		return true
+	})
+
+	deleted := 0
+	for start := 0; start < len(keys); start += iterationChunkSize {
+		end := min(start+iterationChunkSize, len(keys))
+
+		c.mu.Lock()
+		now := c.now().UnixNano()
+		for _, key := range keys[start:end] {
+			if item, found := c.get(key, now); found && item.accessed.Load() < cutoff {
+				c.delete(key, removalIdle)
+				deleted++
+			}
+		}
+		c.unlock()
+	}
+
+	return deleted
+}
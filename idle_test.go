@@ -0,0 +1,93 @@
+package go_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_IdleLongerThan(t *testing.T) {
+	t.Run("readAndUnreadItems", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithAccessTracking())
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		tc.Set("bKey", "bValue", DefaultExpiration)
+		tc.Set("cKey", "cValue", DefaultExpiration)
+
+		clock.Advance(30 * time.Minute)
+
+		tc.Get("aKey")
+		tc.Set("dKey", "dValue", DefaultExpiration)
+
+		clock.Advance(40 * time.Minute)
+
+		tc.Get("bKey")
+
+		idle := tc.IdleLongerThan(1 * time.Hour)
+		assert.Equal(t, []string{"cKey"}, idle)
+
+		idle = tc.IdleLongerThan(35 * time.Minute)
+		assert.ElementsMatch(t, []string{"aKey", "cKey", "dKey"}, idle)
+
+		var keys []string
+		for key := range tc.Idle(35 * time.Minute) {
+			keys = append(keys, key)
+			break
+		}
+		assert.Len(t, keys, 1)
+	})
+
+	t.Run("withoutAccessTracking", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(NoExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+
+		clock.Advance(2 * time.Hour)
+
+		idle := tc.IdleLongerThan(1 * time.Hour)
+		assert.Empty(t, idle)
+
+		deleted := tc.DeleteIdle(1 * time.Hour)
+		assert.Equal(t, 0, deleted)
+	})
+}
+
+func TestCache_DeleteIdle(t *testing.T) {
+	clock := newFakeClock()
+	tc := NewCacheWithOptions(WithAccessTracking())
+	tc.now = clock.Now
+	defer tc.Stop()
+
+	tc.Set("aKey", "aValue", DefaultExpiration)
+	tc.Set("bKey", "bValue", DefaultExpiration)
+	tc.Set("cKey", "cValue", 30*time.Minute)
+	tc.Set("dKey", "dValue", DefaultExpiration)
+
+	clock.Advance(50 * time.Minute)
+
+	tc.Get("aKey")
+	_, _, _ = tc.GetWithVersion("dKey")
+
+	clock.Advance(20 * time.Minute)
+
+	deleted := tc.DeleteIdle(1 * time.Hour)
+	assert.Equal(t, 1, deleted)
+
+	b, found := tc.Get("bKey")
+	assert.Nil(t, b)
+	assert.False(t, found)
+
+	ic := tc.ItemCount()
+	assert.Equal(t, 3, ic)
+
+	a, found := tc.Get("aKey")
+	assert.Equal(t, "aValue", a)
+	assert.True(t, found)
+}
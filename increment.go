@@ -0,0 +1,176 @@
+package go_cache
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// IncrementWithTTL Adds n to the numeric value stored under the given key and returns the new value, like
+// Increment. If the key does not exist or has expired, it is created with the int64 value n and the given duration,
+// with the same semantics as Set, while an existing counter keeps its expiration time: the duration never extends
+// it. This makes fixed window counters, e.g. for rate limiting, a single atomic call.
+// Returns ErrNotNumeric error if the key holds a value which is not a number, and ErrWrongType error if the new
+// value does not fit in an int64, see Increment.
+func (c *Cache) IncrementWithTTL(key string, n int64, duration time.Duration) (int64, error) {
+	hashedKey := c.hashKey(key)
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.closed {
+		return 0, ErrCacheClosed
+	}
+	item, found := c.get(hashedKey, c.now().UnixNano())
+	if !found {
+		if err := c.set(hashedKey, n, duration); err != nil {
+			return 0, err
+		}
+		return n, nil
+	}
+
+	return c.addNumber(key, hashedKey, item, n, false)
+}
+
+// Increment Adds delta to the numeric value stored under the given key and returns the new value as an int64.
+// Values of any integer type are supported and keep their type, the arithmetic wrapping around on overflow as in Go,
+// as well as float32 and float64 as long as the new value is a whole number (see IncrementFloat otherwise).
+// The item keeps its expiration time.
+// Returns ErrItemNotFound error if the key has no live value, ErrNotNumeric error if its value is not a number, and
+// ErrWrongType error if the new value is an unsigned integer larger than math.MaxInt64 or a float which is not a
+// whole number, in which case the value is left as is.
+func (c *Cache) Increment(key string, delta int64) (int64, error) {
+	return c.incrementNumber(key, delta, false)
+}
+
+// Decrement Subtracts delta from the numeric value stored under the given key and returns the new value, with the
+// same semantics as Increment.
+func (c *Cache) Decrement(key string, delta int64) (int64, error) {
+	return c.incrementNumber(key, delta, true)
+}
+
+// IncrementFloat Adds delta, which may be negative, to the float32 or float64 value stored under the given key and
+// returns the new value. The item keeps its expiration time.
+// Returns ErrItemNotFound error if the key has no live value, and ErrNotNumeric error if its value is not a float.
+func (c *Cache) IncrementFloat(key string, delta float64) (float64, error) {
+	hashedKey := c.hashKey(key)
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.closed {
+		return 0, ErrCacheClosed
+	}
+	item, found := c.get(hashedKey, c.now().UnixNano())
+	if !found {
+		return 0, fmt.Errorf("%w: %s", ErrItemNotFound, key)
+	}
+
+	var f float64
+	switch v := item.object.(type) {
+	case float32:
+		v += float32(delta)
+		item.object, f = v, float64(v)
+	case float64:
+		v += delta
+		item.object, f = v, v
+	default:
+		return 0, fmt.Errorf("%w: %s: %s", ErrNotNumeric, key, typeMismatch("float", item.object))
+	}
+	item.version++
+	if err := c.insert(hashedKey, item); err != nil {
+		return 0, err
+	}
+
+	return f, nil
+}
+
+// incrementNumber Adds delta to, or subtracts it from, the numeric value stored under the given key.
+func (c *Cache) incrementNumber(key string, delta int64, decrement bool) (int64, error) {
+	hashedKey := c.hashKey(key)
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.closed {
+		return 0, ErrCacheClosed
+	}
+	item, found := c.get(hashedKey, c.now().UnixNano())
+	if !found {
+		return 0, fmt.Errorf("%w: %s", ErrItemNotFound, key)
+	}
+
+	return c.addNumber(key, hashedKey, item, delta, decrement)
+}
+
+// addNumber Stores the live item of the hashed key with delta added to, or subtracted from, its numeric value, and
+// returns the new value, see Increment. The caller must hold the write lock.
+func (c *Cache) addNumber(key, hashedKey string, item item, delta int64, decrement bool) (int64, error) {
+	var (
+		n   int64
+		err error
+	)
+	switch v := item.object.(type) {
+	case int:
+		item.object, n, err = addDelta(v, delta, decrement)
+	case int8:
+		item.object, n, err = addDelta(v, delta, decrement)
+	case int16:
+		item.object, n, err = addDelta(v, delta, decrement)
+	case int32:
+		item.object, n, err = addDelta(v, delta, decrement)
+	case int64:
+		item.object, n, err = addDelta(v, delta, decrement)
+	case uint:
+		item.object, n, err = addDelta(v, delta, decrement)
+	case uint8:
+		item.object, n, err = addDelta(v, delta, decrement)
+	case uint16:
+		item.object, n, err = addDelta(v, delta, decrement)
+	case uint32:
+		item.object, n, err = addDelta(v, delta, decrement)
+	case uint64:
+		item.object, n, err = addDelta(v, delta, decrement)
+	case uintptr:
+		item.object, n, err = addDelta(v, delta, decrement)
+	case float32:
+		item.object, n, err = addDelta(v, delta, decrement)
+	case float64:
+		item.object, n, err = addDelta(v, delta, decrement)
+	default:
+		return 0, fmt.Errorf("%w: %s: %s", ErrNotNumeric, key, typeMismatch("number", item.object))
+	}
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s: %w", ErrWrongType, key, err)
+	}
+	item.version++
+	if err := c.insert(hashedKey, item); err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// addDelta Returns the value with delta added to it, or subtracted from it, both as is and as an int64.
+// Returns an error if the new value cannot be represented exactly as an int64.
+func addDelta[T ~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+	~float32 | ~float64](v T, delta int64, decrement bool) (any, int64, error) {
+	if decrement {
+		v -= T(delta)
+	} else {
+		v += T(delta)
+	}
+
+	switch u := any(v).(type) {
+	case uint, uint64, uintptr:
+		n, err := unsignedToInt64(u, uint64(v))
+		return v, n, err
+	case float32, float64:
+		f := float64(v)
+		if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return nil, 0, fmt.Errorf("%T %v is not a whole number in the range of int64", u, u)
+		}
+	}
+
+	return v, int64(v), nil
+}
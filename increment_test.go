@@ -0,0 +1,279 @@
+package go_cache
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_IncrementWithTTL(t *testing.T) {
+	t.Run("fixedWindow", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		// One increment per second for 25 seconds, in windows of 10 seconds.
+		var values []int64
+		for i := 0; i < 25; i++ {
+			v, err := tc.IncrementWithTTL("aKey", 1, 10*time.Second)
+			assert.NoError(t, err)
+			values = append(values, v)
+			clock.Advance(time.Second)
+		}
+
+		assert.Equal(t, []int64{
+			1, 2, 3, 4, 5, 6, 7, 8, 9, 10,
+			1, 2, 3, 4, 5, 6, 7, 8, 9, 10,
+			1, 2, 3, 4, 5,
+		}, values)
+		info, found := tc.Info("aKey")
+		assert.True(t, found)
+		assert.True(t, clock.Now().Add(5*time.Second).Equal(info.Expiration))
+	})
+
+	t.Run("keepsVersionGoing", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		_, _ = tc.IncrementWithTTL("aKey", 2, NoExpiration)
+		v, err := tc.IncrementWithTTL("aKey", -5, NoExpiration)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(-3), v)
+
+		value, version, found := tc.GetWithVersion("aKey")
+		assert.True(t, found)
+		assert.Equal(t, int64(-3), value)
+		assert.Equal(t, uint64(2), version)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		const workers, increments = 20, 100
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < increments; j++ {
+					_, _ = tc.IncrementWithTTL("aKey", 1, time.Hour)
+				}
+			}()
+		}
+		wg.Wait()
+
+		value, _ := tc.Get("aKey")
+		assert.Equal(t, int64(workers*increments), value)
+	})
+
+	t.Run("notInteger", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		_, err := tc.IncrementWithTTL("aKey", 1, NoExpiration)
+		assert.ErrorIs(t, err, ErrNotNumeric)
+		assert.ErrorIs(t, err, ErrNotInteger)
+		assert.EqualError(t, err, "value is not numeric: aKey: expected number, got string")
+	})
+
+	t.Run("kinds", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		// Same types as Increment, set by hand.
+		tc.Set("aKey", 1, NoExpiration)
+		tc.Set("bKey", uint8(1), NoExpiration)
+		v, err := tc.IncrementWithTTL("aKey", 2, time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), v)
+		_, err = tc.IncrementWithTTL("bKey", 2, time.Hour)
+		assert.NoError(t, err)
+
+		value, _ := tc.Get("aKey")
+		assert.Equal(t, 3, value)
+		value, _ = tc.Get("bKey")
+		assert.Equal(t, uint8(3), value)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		tc.Stop()
+
+		_, err := tc.IncrementWithTTL("aKey", 1, NoExpiration)
+		assert.ErrorIs(t, err, ErrCacheClosed)
+	})
+}
+
+func TestCache_Increment(t *testing.T) {
+	t.Run("kinds", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		for value, expected := range map[any]any{
+			int(40): int(42), int8(40): int8(42), int16(40): int16(42), int32(40): int32(42), int64(40): int64(42),
+			uint(40): uint(42), uint8(40): uint8(42), uint16(40): uint16(42), uint32(40): uint32(42),
+			uint64(40): uint64(42), uintptr(40): uintptr(42), float32(40): float32(42), 40.0: 42.0,
+		} {
+			tc.Set("aKey", value, NoExpiration)
+			n, err := tc.Increment("aKey", 2)
+			assert.NoError(t, err, "%T", value)
+			assert.Equal(t, int64(42), n, "%T", value)
+			v, _ := tc.Get("aKey")
+			assert.Equal(t, expected, v, "%T", value)
+		}
+	})
+
+	t.Run("decrement", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", 10, NoExpiration)
+		tc.Set("bKey", uint8(1), NoExpiration)
+
+		n, err := tc.Decrement("aKey", 15)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(-5), n)
+		n, err = tc.Decrement("aKey", -7)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		// The arithmetic wraps around in the stored type.
+		n, err = tc.Decrement("bKey", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(255), n)
+		v, _ := tc.Get("bKey")
+		assert.Equal(t, uint8(255), v)
+	})
+
+	t.Run("notRepresentable", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", uint64(math.MaxInt64), NoExpiration)
+		tc.Set("bKey", 40.5, NoExpiration)
+
+		_, err := tc.Increment("aKey", 1)
+		assert.ErrorIs(t, err, ErrWrongType)
+		assert.EqualError(t, err, "value is of the wrong type: aKey: uint64 9223372036854775808 overflows int64")
+		_, err = tc.Increment("bKey", 2)
+		assert.EqualError(t, err,
+			"value is of the wrong type: bKey: float64 42.5 is not a whole number in the range of int64")
+
+		// The values are left as is.
+		value, _ := tc.Get("aKey")
+		assert.Equal(t, uint64(math.MaxInt64), value)
+		value, _ = tc.Get("bKey")
+		assert.Equal(t, 40.5, value)
+		n, err := tc.Decrement("bKey", -1)
+		assert.ErrorIs(t, err, ErrWrongType)
+		assert.Equal(t, int64(0), n)
+	})
+
+	t.Run("keepsExpiration", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", 1, time.Minute)
+		expiration := clock.Now().Add(time.Minute)
+		clock.Advance(30 * time.Second)
+
+		_, err := tc.Increment("aKey", 1)
+		assert.NoError(t, err)
+		info, _ := tc.Info("aKey")
+		assert.True(t, expiration.Equal(info.Expiration))
+		assert.Equal(t, 2, info.Object)
+
+		clock.Advance(30 * time.Second)
+		_, err = tc.Increment("aKey", 1)
+		assert.ErrorIs(t, err, ErrItemNotFound)
+		_, err = tc.Decrement("bKey", 1)
+		assert.ErrorIs(t, err, ErrItemNotFound)
+		assert.False(t, hasItem(tc, "bKey"))
+	})
+
+	t.Run("notNumeric", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "42", NoExpiration)
+		tc.Set("bKey", nil, NoExpiration)
+
+		_, err := tc.Increment("aKey", 1)
+		assert.ErrorIs(t, err, ErrNotNumeric)
+		assert.EqualError(t, err, "value is not numeric: aKey: expected number, got string")
+		_, err = tc.Decrement("bKey", 1)
+		assert.EqualError(t, err, "value is not numeric: bKey: expected number, got nil")
+		v, _ := tc.Get("aKey")
+		assert.Equal(t, "42", v)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", 0, NoExpiration)
+		const workers, increments = 20, 100
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < increments; j++ {
+					_, _ = tc.Increment("aKey", 2)
+					_, _ = tc.Decrement("aKey", 1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		v, _ := tc.Get("aKey")
+		assert.Equal(t, workers*increments, v)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		tc.Set("aKey", 1, NoExpiration)
+		tc.Stop()
+
+		_, err := tc.Increment("aKey", 1)
+		assert.ErrorIs(t, err, ErrCacheClosed)
+	})
+}
+
+func TestCache_IncrementFloat(t *testing.T) {
+	clock := newFakeClock()
+	tc := NewCache(DefaultExpiration, 0)
+	tc.now = clock.Now
+	defer tc.Stop()
+
+	tc.Set("aKey", 40.5, time.Minute)
+	tc.Set("bKey", float32(1), NoExpiration)
+	tc.Set("cKey", 1, NoExpiration)
+
+	f, err := tc.IncrementFloat("aKey", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 42.5, f)
+	f, err = tc.IncrementFloat("bKey", -0.5)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, f)
+	value, _ := tc.Get("bKey")
+	assert.Equal(t, float32(0.5), value)
+
+	info, _ := tc.Info("aKey")
+	assert.True(t, clock.Now().Add(time.Minute).Equal(info.Expiration))
+	assert.Equal(t, 42.5, info.Object)
+
+	_, err = tc.IncrementFloat("cKey", 1)
+	assert.EqualError(t, err, "value is not numeric: cKey: expected float, got int")
+	clock.Advance(time.Minute)
+	_, err = tc.IncrementFloat("aKey", 1)
+	assert.ErrorIs(t, err, ErrItemNotFound)
+}
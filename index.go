@@ -0,0 +1,145 @@
+package go_cache
+
+// indexSpec An index requested WithIndex.
+type indexSpec struct {
+	name    string
+	extract func(key string, value any) (string, bool)
+}
+
+// index Maps index keys to the keys of the items whose value they were extracted from, see WithIndex.
+type index struct {
+	extract func(key string, value any) (string, bool)
+	// buckets holds the keys indexed under each index key, and keys the index key of each key indexed.
+	buckets map[string]map[string]struct{}
+	keys    map[string]string
+}
+
+func newIndex(extract func(key string, value any) (string, bool)) *index {
+	return &index{extract: extract, buckets: make(map[string]map[string]struct{}), keys: make(map[string]string)}
+}
+
+// add Indexes the key under the index key extracted from its new value, moving it from its previous bucket if any.
+func (x *index) add(key string, value any) {
+	indexKey, ok := x.extract(key, value)
+	if current, indexed := x.keys[key]; indexed {
+		if ok && current == indexKey {
+			return
+		}
+		x.remove(key)
+	}
+	if !ok {
+		return
+	}
+
+	bucket, found := x.buckets[indexKey]
+	if !found {
+		bucket = make(map[string]struct{})
+		x.buckets[indexKey] = bucket
+	}
+	bucket[key] = struct{}{}
+	x.keys[key] = indexKey
+}
+
+// remove Stops indexing the key, dropping its bucket once empty.
+func (x *index) remove(key string) {
+	indexKey, indexed := x.keys[key]
+	if !indexed {
+		return
+	}
+
+	delete(x.keys, key)
+	bucket := x.buckets[indexKey]
+	delete(bucket, key)
+	if len(bucket) == 0 {
+		delete(x.buckets, indexKey)
+	}
+}
+
+func (x *index) reset() {
+	x.buckets = make(map[string]map[string]struct{})
+	x.keys = make(map[string]string)
+}
+
+// indexInserted Updates the indexes after the item was written under the key with the given value.
+// Items holding no value are not indexed, and a value staged with SetVisibleAt is only indexed once visible.
+// It must be called with the write lock held.
+func (c *Cache) indexInserted(key string, it item, value any) {
+	if it.pending != nil && !it.placeholder {
+		// The visible value did not change.
+		return
+	}
+	for _, x := range c.indexes {
+		if it.placeholder || it.negative {
+			x.remove(key)
+		} else {
+			x.add(key, value)
+		}
+	}
+}
+
+// indexRemoved Stops indexing the item removed from under the key. It must be called with the write lock held.
+func (c *Cache) indexRemoved(key string) {
+	for _, x := range c.indexes {
+		x.remove(key)
+	}
+}
+
+// indexFlushed Empties the indexes once the cache is flushed. It must be called with the write lock held.
+func (c *Cache) indexFlushed() {
+	for _, x := range c.indexes {
+		x.reset()
+	}
+}
+
+// GetByIndex Returns the keys of the live items whose value is indexed under the given index key by the index of
+// the given name, see WithIndex, in no particular order. Returns nil if there is no such index.
+// With hashed keys (see WithHashedKeys), the hashes are returned instead of the keys.
+func (c *Cache) GetByIndex(name, indexKey string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	x, found := c.indexes[name]
+	if !found {
+		return nil
+	}
+	now := c.now().UnixNano()
+	bucket := x.buckets[indexKey]
+	keys := make([]string, 0, len(bucket))
+	for key := range bucket {
+		if _, found := c.get(key, now); found {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// DeleteByIndex Deletes the items whose value is indexed under the given index key by the index of the given name,
+// see WithIndex, and returns the number of live items deleted. Expired items indexed under the key are deleted too,
+// but counted as expired in the stats rather than returned.
+func (c *Cache) DeleteByIndex(name, indexKey string) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	x, found := c.indexes[name]
+	if !found || c.closed {
+		return 0
+	}
+	bucket := x.buckets[indexKey]
+	keys := make([]string, 0, len(bucket))
+	for key := range bucket {
+		keys = append(keys, key)
+	}
+	now := c.now().UnixNano()
+	deleted := 0
+	for _, key := range keys {
+		if _, found := c.get(key, now); !found {
+			c.delete(key, c.items[key].expiredReason())
+			continue
+		}
+		c.delete(key, removalDeleted)
+		deleted++
+	}
+
+	return deleted
+}
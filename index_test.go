@@ -0,0 +1,140 @@
+package go_cache
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tenantValue struct {
+	TenantID int
+	Name     string
+}
+
+func byTenant(_ string, value any) (string, bool) {
+	v, ok := value.(tenantValue)
+	if !ok {
+		return "", false
+	}
+
+	return strconv.Itoa(v.TenantID), true
+}
+
+func TestCache_WithIndex(t *testing.T) {
+	t.Run("getAndDelete", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithIndex("tenant", byTenant))
+		defer tc.Stop()
+
+		tc.Set("aKey", tenantValue{TenantID: 42, Name: "a"}, NoExpiration)
+		tc.Set("bKey", tenantValue{TenantID: 42, Name: "b"}, NoExpiration)
+		tc.Set("cKey", tenantValue{TenantID: 7, Name: "c"}, NoExpiration)
+		tc.Set("dKey", "not indexed", NoExpiration)
+
+		assert.ElementsMatch(t, []string{"aKey", "bKey"}, tc.GetByIndex("tenant", "42"))
+		assert.Equal(t, []string{"cKey"}, tc.GetByIndex("tenant", "7"))
+		assert.Empty(t, tc.GetByIndex("tenant", "1"))
+		assert.Nil(t, tc.GetByIndex("unknown", "42"))
+
+		assert.Equal(t, 2, tc.DeleteByIndex("tenant", "42"))
+		assert.Empty(t, tc.GetByIndex("tenant", "42"))
+		assert.Equal(t, 2, tc.ItemCount())
+		assert.Equal(t, 0, tc.DeleteByIndex("tenant", "42"))
+		assert.Equal(t, 0, tc.DeleteByIndex("unknown", "7"))
+	})
+
+	t.Run("valuesMove", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithIndex("tenant", byTenant))
+		defer tc.Stop()
+
+		tc.Set("aKey", tenantValue{TenantID: 42}, NoExpiration)
+		tc.Set("bKey", tenantValue{TenantID: 42}, NoExpiration)
+		tc.Set("aKey", tenantValue{TenantID: 7}, NoExpiration)
+		tc.Set("bKey", "not indexed", NoExpiration)
+		tc.Set("cKey", tenantValue{TenantID: 42}, NoExpiration)
+
+		assert.Equal(t, []string{"cKey"}, tc.GetByIndex("tenant", "42"))
+		assert.Equal(t, []string{"aKey"}, tc.GetByIndex("tenant", "7"))
+
+		assert.Equal(t, 1, tc.DeleteByIndex("tenant", "7"))
+		_, found := tc.Get("aKey")
+		assert.False(t, found)
+		_, found = tc.Get("bKey")
+		assert.True(t, found)
+
+		tc.Delete("cKey")
+		assert.Empty(t, tc.GetByIndex("tenant", "42"))
+		assert.Empty(t, tc.indexes["tenant"].buckets)
+		assert.Empty(t, tc.indexes["tenant"].keys)
+	})
+
+	t.Run("multipleIndexes", func(t *testing.T) {
+		byName := func(_ string, value any) (string, bool) {
+			v, ok := value.(tenantValue)
+			return v.Name, ok
+		}
+		tc := NewCacheWithOptions(WithIndex("tenant", byTenant), WithIndex("name", byName))
+		defer tc.Stop()
+
+		tc.Set("aKey", tenantValue{TenantID: 42, Name: "x"}, NoExpiration)
+		tc.Set("bKey", tenantValue{TenantID: 7, Name: "x"}, NoExpiration)
+
+		assert.ElementsMatch(t, []string{"aKey", "bKey"}, tc.GetByIndex("name", "x"))
+		assert.Equal(t, 1, tc.DeleteByIndex("tenant", "42"))
+		assert.Equal(t, []string{"bKey"}, tc.GetByIndex("name", "x"))
+	})
+
+	t.Run("expiration", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithIndex("tenant", byTenant))
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", tenantValue{TenantID: 42}, 1*time.Second)
+		tc.Set("bKey", tenantValue{TenantID: 42}, NoExpiration)
+		clock.Advance(2 * time.Second)
+
+		// The expired item is skipped until removed, and leaves the index once it is.
+		assert.Equal(t, []string{"bKey"}, tc.GetByIndex("tenant", "42"))
+		tc.DeleteExpired()
+		assert.Equal(t, map[string]string{"bKey": "42"}, tc.indexes["tenant"].keys)
+
+		tc.Set("cKey", tenantValue{TenantID: 42}, 1*time.Second)
+		clock.Advance(2 * time.Second)
+		assert.Equal(t, 1, tc.DeleteByIndex("tenant", "42"))
+		assert.Equal(t, uint64(2), tc.Stats().Removals.Expired)
+		assert.Empty(t, tc.indexes["tenant"].keys)
+		assert.Equal(t, 0, tc.ItemCount())
+	})
+
+	t.Run("negativeAndStaged", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithIndex("tenant", byTenant))
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", tenantValue{TenantID: 42}, NoExpiration)
+		tc.SetNegative("aKey", DefaultExpiration)
+		assert.Empty(t, tc.GetByIndex("tenant", "42"))
+
+		tc.Set("bKey", tenantValue{TenantID: 42}, NoExpiration)
+		tc.SetVisibleAt("bKey", tenantValue{TenantID: 7}, clock.Now().Add(1*time.Second), NoExpiration)
+		assert.Equal(t, []string{"bKey"}, tc.GetByIndex("tenant", "42"))
+
+		clock.Advance(2 * time.Second)
+		tc.DeleteExpired()
+		assert.Empty(t, tc.GetByIndex("tenant", "42"))
+		assert.Equal(t, []string{"bKey"}, tc.GetByIndex("tenant", "7"))
+	})
+
+	t.Run("flush", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithIndex("tenant", byTenant))
+		defer tc.Stop()
+
+		tc.Set("aKey", tenantValue{TenantID: 42}, NoExpiration)
+		tc.Flush()
+		assert.Empty(t, tc.GetByIndex("tenant", "42"))
+		assert.Empty(t, tc.indexes["tenant"].keys)
+	})
+}
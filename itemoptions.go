@@ -0,0 +1,125 @@
+package go_cache
+
+import "time"
+
+// ItemOption Configures an item written by SetWithOptions, AddWithOptions or ReplaceWithOptions.
+type ItemOption func(*itemOptions)
+
+type itemOptions struct {
+	expiry    Expiry
+	expireAt  time.Time
+	cost      int64
+	fixedCost bool
+	priority  Priority
+	onEvict   func(value any)
+}
+
+// WithItemExpiry Sets when the item expires, DefaultTTL by default.
+func WithItemExpiry(e Expiry) ItemOption {
+	return func(o *itemOptions) {
+		o.expiry = e
+		o.expireAt = time.Time{}
+	}
+}
+
+// WithItemExpireAt Makes the item expire at the given time, right away if it has passed, overriding
+// WithItemExpiry. The zero time leaves the expiry as set WithItemExpiry.
+func WithItemExpireAt(t time.Time) ItemOption {
+	return func(o *itemOptions) {
+		o.expireAt = t
+	}
+}
+
+// WithItemCost Sets the cost of the item counted towards the limits set WithMaxCost and WithNamespaceCostBudget,
+// instead of the size estimated by the sizer of the cache, e.g. for a value whose size the sizer cannot see.
+// A negative cost is counted as 0.
+func WithItemCost(cost int64) ItemOption {
+	return func(o *itemOptions) {
+		o.cost = max(cost, 0)
+		o.fixedCost = true
+	}
+}
+
+// WithItemPriority Sets the priority of the item for capacity eviction, see SetWithPriority.
+func WithItemPriority(p Priority) ItemOption {
+	return func(o *itemOptions) {
+		o.priority = min(max(p, PriorityLow), PriorityHigh)
+	}
+}
+
+// WithItemOnEvict Makes the cache call fn with the value of the item once it leaves the cache, whatever the reason:
+// deleted, expired and deleted, evicted, flushed, or overwritten by another write, but not when handed over to the
+// caller of FlushAndReturn. fn is called after the cache lock is released, by the goroutine which removed the value,
+// e.g. the cleanup goroutine for expired values, and at most once per write. A value held weakly which was collected
+// is not passed to fn, see WithWeakValues.
+func WithItemOnEvict(fn func(value any)) ItemOption {
+	return func(o *itemOptions) {
+		o.onEvict = fn
+	}
+}
+
+// SetWithOptions Adds an item to the cache like Set, replacing any existing item, configured by the given options
+// all at once: e.g. an item written with both WithItemCost and WithItemPriority is evicted by priority with its given
+// cost. Returns ErrCacheClosed error once the cache is stopped, or an error wrapping ErrValueTooLarge if the value is
+// larger than the size set WithMaxValueSize.
+func (c *Cache) SetWithOptions(key string, object any, opts ...ItemOption) error {
+	key = c.hashKey(key)
+	o := applyItemOptions(opts)
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.closed {
+		return ErrCacheClosed
+	}
+
+	return c.insert(key, c.newItemWithOptions(key, object, o))
+}
+
+// AddWithOptions Inserts an item to the cache like Add, only if the key does not hold a live value, configured by
+// the given options like SetWithOptions.
+func (c *Cache) AddWithOptions(key string, object any, opts ...ItemOption) error {
+	key = c.hashKey(key)
+	o := applyItemOptions(opts)
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.add(key, func() item { return c.newItemWithOptions(key, object, o) })
+}
+
+// ReplaceWithOptions Sets a new value for the key like Replace, only if it holds a live value, configured by the
+// given options like SetWithOptions.
+func (c *Cache) ReplaceWithOptions(key string, object any, opts ...ItemOption) error {
+	key = c.hashKey(key)
+	o := applyItemOptions(opts)
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	return c.replace(key, func() item { return c.newItemWithOptions(key, object, o) })
+}
+
+func applyItemOptions(opts []ItemOption) itemOptions {
+	o := itemOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// newItemWithOptions Returns a new item holding the given object like newItem, configured by the options.
+func (c *Cache) newItemWithOptions(key string, object any, o itemOptions) item {
+	it := c.newItem(key, object, NoExpiration)
+	if o.expireAt.IsZero() {
+		it.expiration = c.expiration(object, o.expiry, time.Unix(0, it.created))
+	} else {
+		it.expiration = max(o.expireAt.UnixNano(), 1)
+	}
+	it.cost, it.fixedCost = o.cost, o.fixedCost
+	it.priority = o.priority
+	it.onEvict = o.onEvict
+
+	return it
+}
@@ -0,0 +1,162 @@
+package go_cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// evictions Records the values passed to the callbacks set WithItemOnEvict.
+type evictions struct {
+	mu     sync.Mutex
+	values []any
+}
+
+func (e *evictions) onEvict(value any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.values = append(e.values, value)
+}
+
+func (e *evictions) get() []any {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.values
+}
+
+func TestCache_SetWithOptions(t *testing.T) {
+	t.Run("combined", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithMaxCost(100))
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		var evicted evictions
+		err := tc.SetWithOptions("aKey", "aValue",
+			WithItemExpiry(TTL(time.Minute)),
+			WithItemCost(60),
+			WithItemPriority(PriorityHigh),
+			WithItemOnEvict(evicted.onEvict),
+		)
+		assert.NoError(t, err)
+
+		info, _ := tc.Info("aKey")
+		assert.True(t, clock.Now().Add(time.Minute).Equal(info.Expiration))
+		assert.Equal(t, PriorityHigh, info.Priority)
+		assert.Equal(t, int64(60), tc.capacity.cost)
+
+		// The cost counts towards the cap, and the priority keeps the item over the normal ones.
+		tc.SetWithOptions("bKey", "bValue", WithItemCost(30))
+		tc.SetWithOptions("cKey", "cValue", WithItemCost(30))
+		assert.True(t, hasItem(tc, "aKey"))
+		assert.False(t, hasItem(tc, "bKey"))
+		assert.True(t, hasItem(tc, "cKey"))
+		assert.Equal(t, int64(90), tc.capacity.cost)
+		assert.Empty(t, evicted.get())
+
+		clock.Advance(time.Minute)
+		_, found := tc.Get("aKey")
+		assert.False(t, found)
+		tc.DeleteExpired()
+		assert.Equal(t, []any{"aValue"}, evicted.get())
+		assert.Equal(t, int64(30), tc.capacity.cost)
+	})
+
+	t.Run("expireAt", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(time.Hour, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		at := clock.Now().Add(90 * time.Second)
+		tc.SetWithOptions("aKey", "aValue", WithItemExpiry(NoTTL()), WithItemExpireAt(at))
+		tc.SetWithOptions("bKey", "bValue", WithItemExpireAt(clock.Now().Add(-time.Second)))
+		tc.SetWithOptions("cKey", "cValue")
+
+		info, _ := tc.Info("aKey")
+		assert.True(t, at.Equal(info.Expiration))
+		_, found := tc.Get("bKey")
+		assert.False(t, found)
+		info, _ = tc.Info("cKey")
+		assert.True(t, clock.Now().Add(time.Hour).Equal(info.Expiration))
+	})
+
+	t.Run("onEvict", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMaxItems(1))
+		defer tc.Stop()
+
+		var evicted evictions
+		tc.SetWithOptions("aKey", "aValue", WithItemOnEvict(evicted.onEvict))
+		tc.SetWithOptions("aKey", "aValue2", WithItemOnEvict(evicted.onEvict))
+		assert.Equal(t, []any{"aValue"}, evicted.get())
+
+		// Marked as expired, the value is still in the cache until deleted.
+		tc.SoftFlush()
+		assert.Equal(t, []any{"aValue"}, evicted.get())
+		tc.Delete("aKey")
+		assert.Equal(t, []any{"aValue", "aValue2"}, evicted.get())
+
+		tc.SetWithOptions("aKey", "aValue3", WithItemOnEvict(evicted.onEvict))
+		tc.Set("bKey", "bValue", NoExpiration)
+		assert.Equal(t, []any{"aValue", "aValue2", "aValue3"}, evicted.get())
+
+		tc.SetWithOptions("aKey", "aValue4", WithItemOnEvict(evicted.onEvict))
+		tc.Flush()
+		assert.Equal(t, []any{"aValue", "aValue2", "aValue3", "aValue4"}, evicted.get())
+
+		tc.SetWithOptions("aKey", "aValue5", WithItemOnEvict(evicted.onEvict))
+		tc.FlushAndReturn()
+		assert.Len(t, evicted.get(), 4)
+	})
+
+	t.Run("onEvictSpilled", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithSpillover(t.TempDir(), 16))
+		defer tc.Stop()
+
+		var evicted evictions
+		value := largeValue(64)
+		tc.SetWithOptions("aKey", value, WithItemOnEvict(evicted.onEvict))
+		tc.Delete("aKey")
+		assert.Equal(t, []any{value}, evicted.get())
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		tc.Stop()
+
+		assert.ErrorIs(t, tc.SetWithOptions("aKey", "aValue"), ErrCacheClosed)
+	})
+}
+
+func TestCache_AddWithOptions(t *testing.T) {
+	tc := NewCacheWithOptions(WithMaxCost(100))
+	defer tc.Stop()
+
+	assert.NoError(t, tc.AddWithOptions("aKey", "aValue", WithItemCost(10), WithItemPriority(PriorityLow)))
+	assert.ErrorIs(t, tc.AddWithOptions("aKey", "aValue2", WithItemCost(20)), ErrItemAlreadyExists)
+
+	info, _ := tc.Info("aKey")
+	assert.Equal(t, "aValue", info.Object)
+	assert.Equal(t, PriorityLow, info.Priority)
+	assert.Equal(t, int64(10), tc.capacity.cost)
+}
+
+func TestCache_ReplaceWithOptions(t *testing.T) {
+	tc := NewCacheWithOptions(WithMaxCost(100))
+	defer tc.Stop()
+
+	assert.ErrorIs(t, tc.ReplaceWithOptions("aKey", "aValue", WithItemCost(10)), ErrItemNotFound)
+	assert.False(t, hasItem(tc, "aKey"))
+
+	tc.Set("aKey", "aValue", NoExpiration)
+	assert.NoError(t, tc.ReplaceWithOptions("aKey", "aValue2", WithItemCost(20), WithItemPriority(PriorityHigh)))
+
+	info, _ := tc.Info("aKey")
+	assert.Equal(t, "aValue2", info.Object)
+	assert.Equal(t, PriorityHigh, info.Priority)
+	assert.Equal(t, int64(20), tc.capacity.cost)
+}
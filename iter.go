@@ -0,0 +1,84 @@
+package go_cache
+
+import (
+	"iter"
+	"sort"
+	"time"
+)
+
+// All Returns an iterator over the keys and values of the items in the cache which have not expired,
+// e.g. to be used as `for key, value := range c.All()`, without copying the whole cache beforehand.
+// Keys are snapshotted when the iteration starts and each value is read again right before being yielded,
+// so the cache is not locked while the loop body runs and may be modified from it. Items added during the
+// iteration are not seen, and items removed or expired during the iteration are not yielded anymore.
+// With hashed keys (see WithHashedKeys), the hashes are yielded instead of the keys.
+func (c *Cache) All() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		c.forEachChunked(func(key string, _ item) bool {
+			value, found := c.liveValue(key, true)
+			if !found {
+				return true
+			}
+			return yield(key, value)
+		})
+	}
+}
+
+// KeysSeq Returns an iterator over the keys of the items in the cache which have not expired,
+// with the same consistency guarantees as All.
+func (c *Cache) KeysSeq() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for key := range c.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// ItemInfo Describes an item of the cache.
+type ItemInfo struct {
+	Object any
+	// Expiration The time at which the item expires, or the zero time if it never expires.
+	Expiration time.Time
+	// Timestamp The version the item was written with by SetIfNewer, or the zero time if it was written otherwise.
+	Timestamp time.Time
+	// Priority The priority the item was written with, see SetWithPriority.
+	Priority Priority
+}
+
+// ByExpiration Returns an iterator over the items in the cache which have not expired, ordered by expiration
+// time, soonest first. Items which never expire come last if withNoExpiration is true, and are skipped otherwise.
+// The items are snapshotted and sorted when the iteration starts: the order and expiration times reflect the
+// state of the cache at that time, and the values yielded are the ones snapshotted.
+func (c *Cache) ByExpiration(withNoExpiration bool) iter.Seq2[string, ItemInfo] {
+	return func(yield func(string, ItemInfo) bool) {
+		type infoEntry struct {
+			key  string
+			info ItemInfo
+		}
+		var entries []infoEntry
+		c.forEachValueChunked(func(key string, item item, value any) bool {
+			if item.expiration > 0 || withNoExpiration {
+				info := item.info()
+				info.Object = value
+				entries = append(entries, infoEntry{key: key, info: info})
+			}
+			return true
+		})
+
+		sort.SliceStable(entries, func(i, j int) bool {
+			ei, ej := entries[i].info.Expiration, entries[j].info.Expiration
+			if ei.IsZero() || ej.IsZero() {
+				return ej.IsZero() && !ei.IsZero()
+			}
+			return ei.Before(ej)
+		})
+
+		for _, e := range entries {
+			if !yield(e.key, e.info) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package go_cache
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_All(t *testing.T) {
+	t.Run("skipExpiredItems", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		tc.Set("bKey", 1, DefaultExpiration)
+		tc.Set("cKey", "cValue", 1*time.Nanosecond)
+
+		<-time.After(1 * time.Millisecond)
+
+		items := map[string]any{}
+		for key, value := range tc.All() {
+			items[key] = value
+		}
+		assert.Equal(t, map[string]any{"aKey": "aValue", "bKey": 1}, items)
+	})
+
+	t.Run("earlyBreak", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		for i := 0; i < 3*iterationChunkSize; i++ {
+			tc.Set(strconv.Itoa(i), i, DefaultExpiration)
+		}
+
+		seen := 0
+		for range tc.All() {
+			seen++
+			if seen == 10 {
+				break
+			}
+		}
+		assert.Equal(t, 10, seen)
+	})
+
+	t.Run("concurrentMutation", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		const n = 3 * iterationChunkSize
+		for i := 0; i < n; i++ {
+			tc.Set(strconv.Itoa(i), i, DefaultExpiration)
+		}
+
+		seen := map[string]bool{}
+		deleted := map[string]bool{}
+		for key := range tc.All() {
+			assert.False(t, seen[key], "key %s yielded twice", key)
+			assert.False(t, deleted[key], "deleted key %s yielded", key)
+			seen[key] = true
+
+			i, _ := strconv.Atoi(key)
+			mirror := strconv.Itoa(n - 1 - i)
+			tc.Delete(mirror)
+			deleted[mirror] = true
+			tc.Set("new"+key, i, DefaultExpiration)
+		}
+		assert.LessOrEqual(t, len(seen), n)
+	})
+}
+
+func TestCache_KeysSeq(t *testing.T) {
+	tc := NewCache(NoExpiration, 0)
+	defer tc.Stop()
+
+	tc.Set("aKey", "aValue", DefaultExpiration)
+	tc.Set("bKey", "bValue", DefaultExpiration)
+	tc.Set("cKey", "cValue", DefaultExpiration)
+
+	var keys []string
+	for key := range tc.KeysSeq() {
+		keys = append(keys, key)
+	}
+	assert.ElementsMatch(t, []string{"aKey", "bKey", "cKey"}, keys)
+
+	keys = nil
+	for key := range tc.KeysSeq() {
+		keys = append(keys, key)
+		break
+	}
+	assert.Len(t, keys, 1)
+}
+
+func TestCache_ByExpiration(t *testing.T) {
+	clock := newFakeClock()
+	tc := NewCache(NoExpiration, 0)
+	tc.now = clock.Now
+	defer tc.Stop()
+
+	tc.Set("aKey", "aValue", 30*time.Second)
+	tc.Set("bKey", "bValue", NoExpiration)
+	tc.Set("cKey", "cValue", 10*time.Second)
+	tc.Set("dKey", "dValue", 20*time.Second)
+	tc.Set("eKey", "eValue", 5*time.Second)
+
+	clock.Advance(5 * time.Second)
+
+	t.Run("withNoExpiration", func(t *testing.T) {
+		var keys []string
+		var infos []ItemInfo
+		for key, info := range tc.ByExpiration(true) {
+			keys = append(keys, key)
+			infos = append(infos, info)
+		}
+		assert.Equal(t, []string{"cKey", "dKey", "aKey", "bKey"}, keys)
+		assert.Equal(t, "cValue", infos[0].Object)
+		assert.True(t, clock.Now().Add(5*time.Second).Equal(infos[0].Expiration))
+		assert.Equal(t, ItemInfo{Object: "bValue"}, infos[3])
+		assert.True(t, infos[3].Expiration.IsZero())
+	})
+
+	t.Run("withoutNoExpiration", func(t *testing.T) {
+		var keys []string
+		for key := range tc.ByExpiration(false) {
+			keys = append(keys, key)
+		}
+		assert.Equal(t, []string{"cKey", "dKey", "aKey"}, keys)
+	})
+
+	t.Run("earlyBreak", func(t *testing.T) {
+		var keys []string
+		for key := range tc.ByExpiration(true) {
+			keys = append(keys, key)
+			break
+		}
+		assert.Equal(t, []string{"cKey"}, keys)
+	})
+}
@@ -0,0 +1,97 @@
+package go_cache
+
+// iterationChunkSize Number of items examined per read lock acquisition by chunked iterations.
+const iterationChunkSize = 1024
+
+type entry struct {
+	key  string
+	item item
+}
+
+// forEachChunked Calls fn for each live item of the cache, taking the read lock only to snapshot the keys and then
+// to read chunks of iterationChunkSize items at a time, so that writers are never blocked for the whole iteration.
+// fn is called without holding the lock. Items added after the keys were snapshotted are not visited, and items
+// deleted or expired before their chunk is read are skipped. The iteration stops as soon as fn returns false.
+func (c *Cache) forEachChunked(fn func(key string, item item) bool) {
+	c.iterateChunked(false, func(key string, item item, _ any) bool {
+		return fn(key, item)
+	})
+}
+
+// forEachValueChunked Calls fn for each live item of the cache like forEachChunked, along with its value, resolved
+// while the read lock of its chunk is held (see liveValue). Weak values collected meanwhile are skipped.
+func (c *Cache) forEachValueChunked(fn func(key string, item item, value any) bool) {
+	c.iterateChunked(true, fn)
+}
+
+// iterateChunked Implements forEachChunked, and forEachValueChunked if resolve is true.
+func (c *Cache) iterateChunked(resolve bool, fn func(key string, item item, value any) bool) {
+	c.mu.RLock()
+	keys := make([]string, 0, len(c.items))
+	for key := range c.items {
+		keys = append(keys, key)
+	}
+	c.mu.RUnlock()
+
+	entries := make([]entry, 0, iterationChunkSize)
+	var values []any
+	if resolve {
+		values = make([]any, 0, iterationChunkSize)
+	}
+	for start := 0; start < len(keys); start += iterationChunkSize {
+		end := start + iterationChunkSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		entries, values = entries[:0], values[:0]
+		c.mu.RLock()
+		now := c.now().UnixNano()
+		for _, key := range keys[start:end] {
+			item, found := c.get(key, now)
+			if !found {
+				continue
+			}
+			if resolve {
+				value := c.value(key, item)
+				if value == nil && item.weak {
+					continue
+				}
+				values = append(values, value)
+			}
+			entries = append(entries, entry{key: key, item: item})
+		}
+		c.mu.RUnlock()
+
+		for i, e := range entries {
+			var value any
+			if resolve {
+				value = values[i]
+			}
+			if !fn(e.key, e.item, value) {
+				return
+			}
+		}
+	}
+}
+
+// liveValue Reads the live item held under the key again, and returns its value unless it is leased. With resolve,
+// the value is resolved while the read lock is held, since the spilled file, compressed data or weak pointer backing
+// it can be removed or replaced by a concurrent write as soon as it is released, and weak values collected meanwhile
+// are reported as missing. Without resolve, only whether the item is live is reported.
+func (c *Cache) liveValue(key string, resolve bool) (any, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.now().UnixNano()
+	item, found := c.get(key, now)
+	if !found || item.isLeased(now) {
+		return nil, false
+	}
+	if !resolve {
+		return nil, true
+	}
+	value := c.value(key, item)
+
+	return value, value != nil || !item.weak
+}
@@ -0,0 +1,192 @@
+package go_cache
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrMalformedLine is returned by LoadJSONLines in strict mode when a line cannot be decoded.
+var ErrMalformedLine = errors.New("malformed line")
+
+// LoadOption Configures LoadJSONLines, and Load for WithLoadPrefix.
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	defaultTTL    time.Duration
+	strict        bool
+	batchSize     int
+	progressEvery int
+	progress      func(LoadReport)
+	// rename returns the key to store an item under, and false for items to skip, see WithLoadPrefix.
+	rename func(key string) (string, bool)
+}
+
+// WithLoadPrefix Makes Load and LoadJSONLines only store the items whose key starts with the given prefix, under
+// the same key with the prefix replaced by replacement, e.g. "sessions:" by "s2:". Passing the prefix as replacement
+// keeps the keys as they are. The other items are skipped, without being counted as malformed.
+func WithLoadPrefix(prefix, replacement string) LoadOption {
+	return func(o *loadOptions) {
+		o.rename = func(key string) (string, bool) {
+			if !strings.HasPrefix(key, prefix) {
+				return "", false
+			}
+			return replacement + key[len(prefix):], true
+		}
+	}
+}
+
+// WithLoadDefaultTTL Sets the duration used for lines without a positive ttl_seconds, DefaultExpiration by default.
+func WithLoadDefaultTTL(d time.Duration) LoadOption {
+	return func(o *loadOptions) {
+		o.defaultTTL = d
+	}
+}
+
+// WithStrictLoad Makes LoadJSONLines stop at the first malformed line and return an error wrapping
+// ErrMalformedLine, instead of counting and skipping it.
+func WithStrictLoad() LoadOption {
+	return func(o *loadOptions) {
+		o.strict = true
+	}
+}
+
+// WithLoadBatchSize Sets the number of items written each time the write lock is taken, 1000 by default.
+func WithLoadBatchSize(n int) LoadOption {
+	return func(o *loadOptions) {
+		o.batchSize = n
+	}
+}
+
+// WithLoadProgress Makes LoadJSONLines call fn with the current report every n lines read.
+func WithLoadProgress(n int, fn func(LoadReport)) LoadOption {
+	return func(o *loadOptions) {
+		o.progressEvery = n
+		o.progress = fn
+	}
+}
+
+// LoadReport Describes the outcome of LoadJSONLines.
+type LoadReport struct {
+	// Lines Number of non-empty lines read.
+	Lines int
+	// Loaded Number of items stored in the cache.
+	Loaded int
+	// Malformed Number of lines which could not be decoded.
+	Malformed int
+	// TooLarge Number of items rejected for a value larger than the size set WithMaxValueSize.
+	TooLarge int
+}
+
+type jsonLine struct {
+	Key        *string         `json:"key"`
+	Value      json.RawMessage `json:"value"`
+	TTLSeconds float64         `json:"ttl_seconds"`
+}
+
+// LoadJSONLines Stores into the cache the items read from r, a stream of newline-delimited JSON objects of the form
+// {"key": "...", "value": ..., "ttl_seconds": ...}, without buffering the whole stream. Values are decoded as by
+// encoding/json into an any. A positive ttl_seconds is used as the duration of the item, a negative one means
+// NoExpiration, and a missing or zero one falls back to the default TTL (see WithLoadDefaultTTL).
+// Lines which cannot be decoded or have no key are counted and skipped unless WithStrictLoad is set. Items are
+// written in batches (see WithLoadBatchSize), so readers may observe a partially loaded stream.
+// An error is returned if r fails or the cache is stopped, along with the report of what was loaded so far.
+func (c *Cache) LoadJSONLines(r io.Reader, opts ...LoadOption) (LoadReport, error) {
+	o := loadOptions{defaultTTL: DefaultExpiration, batchSize: 1000}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.batchSize < 1 {
+		o.batchSize = 1
+	}
+
+	report := LoadReport{}
+	batch := make(map[string]InitialItem, o.batchSize)
+	flush := func() error {
+		c.mu.Lock()
+		defer c.unlock()
+
+		if c.closed {
+			return ErrCacheClosed
+		}
+		for key, item := range batch {
+			if err := c.set(c.hashKey(key), item.Object, item.Duration); err != nil {
+				report.TooLarge++
+				continue
+			}
+			report.Loaded++
+		}
+		clear(batch)
+
+		return nil
+	}
+
+	br := bufio.NewReader(r)
+	for lineNumber := 1; ; lineNumber++ {
+		line, readErr := br.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			if err := flush(); err != nil {
+				return report, err
+			}
+			return report, readErr
+		}
+
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			report.Lines++
+
+			var l jsonLine
+			if err := json.Unmarshal(line, &l); err != nil || l.Key == nil {
+				if o.strict {
+					if err := flush(); err != nil {
+						return report, err
+					}
+					return report, fmt.Errorf("%w: line %d", ErrMalformedLine, lineNumber)
+				}
+				report.Malformed++
+			} else {
+				var value any
+				if len(l.Value) > 0 {
+					_ = json.Unmarshal(l.Value, &value)
+				}
+				key, accepted := *l.Key, true
+				if o.rename != nil {
+					key, accepted = o.rename(key)
+				}
+				if accepted {
+					batch[key] = InitialItem{Object: value, Duration: l.duration(o.defaultTTL)}
+				}
+				if len(batch) >= o.batchSize {
+					if err := flush(); err != nil {
+						return report, err
+					}
+				}
+			}
+
+			if o.progress != nil && o.progressEvery > 0 && report.Lines%o.progressEvery == 0 {
+				o.progress(report)
+			}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	return report, flush()
+}
+
+func (l jsonLine) duration(defaultTTL time.Duration) time.Duration {
+	switch {
+	case l.TTLSeconds > 0:
+		return time.Duration(l.TTLSeconds * float64(time.Second))
+	case l.TTLSeconds < 0:
+		return NoExpiration
+	default:
+		return defaultTTL
+	}
+}
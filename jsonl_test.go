@@ -0,0 +1,151 @@
+package go_cache
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_LoadJSONLines(t *testing.T) {
+	t.Run("fixture", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(1*time.Hour, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		f, err := os.Open("testdata/load.jsonl")
+		assert.NoError(t, err)
+		defer f.Close()
+
+		report, err := tc.LoadJSONLines(f, WithLoadDefaultTTL(2*time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, LoadReport{Lines: 8, Loaded: 5, Malformed: 3}, report)
+
+		value, found := tc.Get("aKey")
+		assert.True(t, found)
+		assert.Equal(t, "aValue", value)
+
+		value, found = tc.Get("bKey")
+		assert.True(t, found)
+		assert.Equal(t, map[string]any{"name": "b", "count": float64(2)}, value)
+
+		value, found = tc.Get("cKey")
+		assert.True(t, found)
+		assert.Equal(t, []any{float64(1), float64(2), float64(3)}, value)
+
+		_, found = tc.Get("eKey")
+		assert.False(t, found)
+
+		value, found = tc.Get("fKey")
+		assert.True(t, found)
+		assert.Nil(t, value)
+
+		clock.Advance(1 * time.Second)
+		_, found = tc.Get("fKey")
+		assert.False(t, found)
+
+		clock.Advance(90 * time.Minute)
+		_, found = tc.Get("aKey")
+		assert.False(t, found)
+		_, found = tc.Get("bKey")
+		assert.True(t, found)
+
+		clock.Advance(1 * time.Hour)
+		_, found = tc.Get("bKey")
+		assert.False(t, found)
+		_, found = tc.Get("dKey")
+		assert.True(t, found)
+	})
+
+	t.Run("defaultTTLFallsBackToCacheDefault", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(1*time.Minute, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		_, err := tc.LoadJSONLines(strings.NewReader(`{"key": "aKey", "value": "aValue"}`))
+		assert.NoError(t, err)
+
+		_, found := tc.Get("aKey")
+		assert.True(t, found)
+
+		clock.Advance(2 * time.Minute)
+		_, found = tc.Get("aKey")
+		assert.False(t, found)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		input := `{"key": "aKey", "value": 1}
+{"key": "bKey", "value": 2
+{"key": "cKey", "value": 3}`
+
+		report, err := tc.LoadJSONLines(strings.NewReader(input), WithStrictLoad())
+		assert.ErrorIs(t, err, ErrMalformedLine)
+		assert.ErrorContains(t, err, "line 2")
+		assert.Equal(t, LoadReport{Lines: 2, Loaded: 1}, report)
+
+		_, found := tc.Get("aKey")
+		assert.True(t, found)
+		_, found = tc.Get("cKey")
+		assert.False(t, found)
+	})
+
+	t.Run("batchesAndProgress", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		var sb strings.Builder
+		for i := 0; i < 25; i++ {
+			sb.WriteString(`{"key": "` + strings.Repeat("k", i+1) + `", "value": 1}` + "\n")
+		}
+
+		var progress []LoadReport
+		report, err := tc.LoadJSONLines(strings.NewReader(sb.String()),
+			WithLoadBatchSize(10),
+			WithLoadProgress(10, func(r LoadReport) { progress = append(progress, r) }))
+		assert.NoError(t, err)
+		assert.Equal(t, LoadReport{Lines: 25, Loaded: 25}, report)
+		assert.Equal(t, []LoadReport{{Lines: 10, Loaded: 10}, {Lines: 20, Loaded: 20}}, progress)
+		assert.Equal(t, 25, tc.ItemCount())
+	})
+
+	t.Run("readError", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		readErr := errors.New("read failed")
+		r := io.MultiReader(strings.NewReader(`{"key": "aKey", "value": 1}`+"\n"), iotest.ErrReader(readErr))
+
+		report, err := tc.LoadJSONLines(r)
+		assert.ErrorIs(t, err, readErr)
+		assert.Equal(t, LoadReport{Lines: 1, Loaded: 1}, report)
+
+		_, found := tc.Get("aKey")
+		assert.True(t, found)
+	})
+}
+
+func TestCache_LoadJSONLinesPrefix(t *testing.T) {
+	tc := NewCache(NoExpiration, 0)
+	defer tc.Stop()
+
+	input := `{"key": "sessions:a", "value": "aValue"}
+{"key": "users:a", "value": "uValue"}
+`
+	report, err := tc.LoadJSONLines(strings.NewReader(input), WithLoadPrefix("sessions:", "s2:"))
+	assert.Nil(t, err)
+	assert.Equal(t, LoadReport{Lines: 2, Loaded: 1}, report)
+	a, found := tc.Get("s2:a")
+	assert.True(t, found)
+	assert.Equal(t, "aValue", a)
+	assert.Equal(t, 1, tc.ItemCount())
+}
@@ -0,0 +1,113 @@
+package go_cache
+
+import (
+	"math"
+	"math/bits"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+// latencyBuckets Number of buckets of the latency histograms: the first one holds durations up to 128ns, each next
+// one durations up to twice the previous bound, up to about 2s, and the last one everything above.
+const latencyBuckets = 26
+
+// LatencyBucket A bucket of a LatencyHistogram.
+type LatencyBucket struct {
+	// UpperBound Longest duration counted in the bucket, math.MaxInt64 for the last one.
+	UpperBound time.Duration
+	// Count Number of sampled operations which took longer than the bound of the previous bucket, up to UpperBound.
+	Count uint64
+}
+
+// LatencyHistogram Distribution of the duration of the sampled calls to an operation, see WithLatencyTracking.
+type LatencyHistogram struct {
+	// Buckets Buckets of the histogram, by increasing bound. Bounds are the same for every histogram.
+	Buckets []LatencyBucket
+	// Count Number of sampled calls.
+	Count uint64
+	// Sum Total duration of the sampled calls.
+	Sum time.Duration
+}
+
+// Quantile Returns an upper bound of the given quantile (e.g. 0.99) of the durations, i.e. the bound of the bucket
+// holding it, 0 if nothing was sampled.
+func (h LatencyHistogram) Quantile(q float64) time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+
+	rank := uint64(math.Ceil(q * float64(h.Count)))
+	var seen uint64
+	for _, b := range h.Buckets {
+		if seen += b.Count; seen >= max(rank, 1) {
+			return b.UpperBound
+		}
+	}
+
+	return h.Buckets[len(h.Buckets)-1].UpperBound
+}
+
+// LatencyStats Durations of the operations of the cache as seen by the cache itself, lock waits included,
+// see WithLatencyTracking.
+type LatencyStats struct {
+	Get       LatencyHistogram
+	Set       LatencyHistogram
+	GetOrLoad LatencyHistogram
+}
+
+type latencyHistogram struct {
+	buckets [latencyBuckets]atomic.Uint64
+	sum     atomic.Int64
+}
+
+// observe Counts the given duration in the histogram.
+func (h *latencyHistogram) observe(d time.Duration) {
+	// Bucket i holds the durations in (128ns << (i-1), 128ns << i].
+	i := bits.Len64(uint64(max(d-1, 0)) >> 7)
+	h.buckets[min(i, latencyBuckets-1)].Add(1)
+	h.sum.Add(int64(d))
+}
+
+func (h *latencyHistogram) snapshot() LatencyHistogram {
+	s := LatencyHistogram{Buckets: make([]LatencyBucket, latencyBuckets), Sum: time.Duration(h.sum.Load())}
+	for i := range h.buckets {
+		s.Buckets[i] = LatencyBucket{UpperBound: 128 << i, Count: h.buckets[i].Load()}
+		s.Count += s.Buckets[i].Count
+	}
+	s.Buckets[latencyBuckets-1].UpperBound = math.MaxInt64
+
+	return s
+}
+
+// latency Holds the latency histograms of a cache created WithLatencyTracking.
+type latency struct {
+	sampleRate float64
+	get        latencyHistogram
+	set        latencyHistogram
+	getOrLoad  latencyHistogram
+}
+
+// sample Reports whether the current call should be timed.
+func (l *latency) sample() bool {
+	return l.sampleRate >= 1 || rand.Float64() < l.sampleRate
+}
+
+// observe Counts the time elapsed since the given start in the histogram. It is meant to be deferred by sampled
+// operations before they take the lock, so that waiting for it is counted.
+func (c *Cache) observe(h *latencyHistogram, start time.Time) {
+	h.observe(c.now().Sub(start))
+}
+
+// LatencyStats Returns the latency histograms of the cache, empty unless it was created WithLatencyTracking.
+func (c *Cache) LatencyStats() LatencyStats {
+	if c.latency == nil {
+		return LatencyStats{}
+	}
+
+	return LatencyStats{
+		Get:       c.latency.get.snapshot(),
+		Set:       c.latency.set.snapshot(),
+		GetOrLoad: c.latency.getOrLoad.snapshot(),
+	}
+}
@@ -0,0 +1,114 @@
+package go_cache
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencyHistogram(t *testing.T) {
+	var h latencyHistogram
+	for _, d := range []time.Duration{0, 100, 128, 129, 256, 5 * time.Millisecond, time.Hour} {
+		h.observe(d)
+	}
+
+	s := h.snapshot()
+	assert.Equal(t, uint64(7), s.Count)
+	assert.Equal(t, uint64(3), s.Buckets[0].Count)
+	assert.Equal(t, time.Duration(128), s.Buckets[0].UpperBound)
+	assert.Equal(t, uint64(2), s.Buckets[1].Count)
+	assert.Equal(t, time.Duration(256), s.Buckets[1].UpperBound)
+	// 5ms falls in (4.19ms, 8.39ms].
+	assert.Equal(t, uint64(1), s.Buckets[16].Count)
+	assert.Equal(t, uint64(1), s.Buckets[latencyBuckets-1].Count)
+	assert.Equal(t, time.Duration(math.MaxInt64), s.Buckets[latencyBuckets-1].UpperBound)
+
+	assert.Equal(t, time.Duration(128), s.Quantile(0.4))
+	assert.Equal(t, time.Duration(256), s.Quantile(0.5))
+	assert.Equal(t, time.Duration(128<<16), s.Quantile(0.8))
+	assert.Equal(t, time.Duration(math.MaxInt64), s.Quantile(0.99))
+	assert.Equal(t, time.Duration(0), LatencyHistogram{}.Quantile(0.99))
+}
+
+func TestCache_WithLatencyTracking(t *testing.T) {
+	t.Run("lockContention", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithLatencyTracking(1))
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+
+		// Holding the lock delays the Get below by at least 20ms.
+		tc.mu.Lock()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			tc.Get("aKey")
+		}()
+		<-time.After(20 * time.Millisecond)
+		tc.mu.Unlock()
+		<-done
+
+		stats := tc.LatencyStats()
+		assert.Equal(t, uint64(1), stats.Get.Count)
+		assert.GreaterOrEqual(t, stats.Get.Sum, 20*time.Millisecond)
+		assert.GreaterOrEqual(t, stats.Get.Quantile(0.99), 20*time.Millisecond)
+		assert.Less(t, stats.Get.Quantile(0.99), time.Second)
+		assert.Equal(t, uint64(1), stats.Set.Count)
+	})
+
+	t.Run("getOrLoad", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithLatencyTracking(1))
+		defer tc.Stop()
+
+		_, err := tc.GetOrLoad(context.Background(), "aKey", func(context.Context, string) (any, time.Duration, error) {
+			<-time.After(10 * time.Millisecond)
+			return "aValue", NoExpiration, nil
+		})
+		assert.NoError(t, err)
+
+		stats := tc.LatencyStats()
+		assert.Equal(t, uint64(1), stats.GetOrLoad.Count)
+		assert.GreaterOrEqual(t, stats.GetOrLoad.Sum, 10*time.Millisecond)
+	})
+
+	t.Run("sampled", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithLatencyTracking(0.5))
+		defer tc.Stop()
+
+		for i := 0; i < 1_000; i++ {
+			tc.Set(strconv.Itoa(i), i, NoExpiration)
+		}
+		count := tc.LatencyStats().Set.Count
+		assert.Greater(t, count, uint64(300))
+		assert.Less(t, count, uint64(700))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Get("aKey")
+		assert.Nil(t, tc.latency)
+		assert.Equal(t, LatencyStats{}, tc.LatencyStats())
+	})
+}
+
+func BenchmarkCache_GetLatencyTracking(b *testing.B) {
+	for _, rate := range []float64{0, 0.01, 1} {
+		b.Run(strconv.FormatFloat(rate, 'f', -1, 64), func(b *testing.B) {
+			tc := NewCacheWithOptions(WithLatencyTracking(rate))
+			defer tc.Stop()
+			tc.Set("aKey", "aValue", NoExpiration)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tc.Get("aKey")
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package go_cache
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Acquire Leases the item stored under the given key for the given duration and returns its value
+// together with an opaque token identifying the lease. While the lease is held the item is invisible
+// to Get and further calls to Acquire fail with ErrLeased, until the lease is released with Release
+// or it expires. Returns ErrItemNotFound if the key does not exist or has expired.
+// Writing the key again with Set or Replace drops any lease held on it.
+func (c *Cache) Acquire(key string, lease time.Duration) (any, string, error) {
+	key = c.hashKey(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, "", ErrCacheClosed
+	}
+	now := c.now().UnixNano()
+	item, found := c.get(key, now)
+	if !found {
+		return nil, "", fmt.Errorf("%w: %s", ErrItemNotFound, key)
+	}
+	if item.isLeased(now) {
+		return nil, "", fmt.Errorf("%w: %s", ErrLeased, key)
+	}
+
+	token, err := newLeaseToken()
+	if err != nil {
+		return nil, "", err
+	}
+	item.leaseToken = token
+	item.leaseExpiration = c.now().Add(lease).UnixNano()
+	c.store(key, item)
+
+	return c.value(key, item), token, nil
+}
+
+// Release Gives back the lease identified by token, making the item visible again.
+// Returns ErrLeaseNotHeld if the token does not match the lease currently held on the item,
+// e.g. because the lease has already expired and been acquired by someone else.
+func (c *Cache) Release(key, token string) error {
+	key = c.hashKey(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, err := c.leasedItem(key, token)
+	if err != nil {
+		return err
+	}
+	item.leaseToken = ""
+	item.leaseExpiration = 0
+	c.store(key, item)
+
+	return nil
+}
+
+// ExtendLease Pushes the expiration of the lease identified by token to the given duration from now.
+// Returns ErrLeaseNotHeld if the token does not match the lease currently held on the item.
+func (c *Cache) ExtendLease(key, token string, d time.Duration) error {
+	key = c.hashKey(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, err := c.leasedItem(key, token)
+	if err != nil {
+		return err
+	}
+	item.leaseExpiration = c.now().Add(d).UnixNano()
+	c.store(key, item)
+
+	return nil
+}
+
+func (c *Cache) leasedItem(key, token string) (item, error) {
+	if c.closed {
+		return item{}, ErrCacheClosed
+	}
+	now := c.now().UnixNano()
+	item, found := c.get(key, now)
+	if !found {
+		return item, fmt.Errorf("%w: %s", ErrItemNotFound, key)
+	}
+	if !item.isLeased(now) || item.leaseToken != token {
+		return item, fmt.Errorf("%w: %s", ErrLeaseNotHeld, key)
+	}
+
+	return item, nil
+}
+
+func newLeaseToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("could not generate lease token: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,187 @@
+package go_cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_Acquire(t *testing.T) {
+	t.Run("competingAcquirers", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		var acquired, leased int
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _, err := tc.Acquire("aKey", time.Minute)
+				mu.Lock()
+				defer mu.Unlock()
+				if err == nil {
+					acquired++
+				} else {
+					assert.ErrorIs(t, err, ErrLeased)
+					leased++
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 1, acquired)
+		assert.Equal(t, 1, leased)
+
+		a, found := tc.Get("aKey")
+		assert.Nil(t, a)
+		assert.False(t, found)
+	})
+
+	t.Run("notExistingItem", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		a, token, err := tc.Acquire("aKey", time.Minute)
+		assert.ErrorIs(t, err, ErrItemNotFound)
+		assert.Nil(t, a)
+		assert.Empty(t, token)
+	})
+
+	t.Run("leaseTimeoutRecovery", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+
+		_, firstToken, err := tc.Acquire("aKey", 20*time.Millisecond)
+		assert.Nil(t, err)
+
+		_, _, err = tc.Acquire("aKey", 20*time.Millisecond)
+		assert.ErrorIs(t, err, ErrLeased)
+
+		<-time.After(25 * time.Millisecond)
+
+		a, found := tc.Get("aKey")
+		assert.Equal(t, "aValue", a)
+		assert.True(t, found)
+
+		a, secondToken, err := tc.Acquire("aKey", time.Minute)
+		assert.Nil(t, err)
+		assert.Equal(t, "aValue", a)
+		assert.NotEqual(t, firstToken, secondToken)
+
+		err = tc.Release("aKey", firstToken)
+		assert.ErrorIs(t, err, ErrLeaseNotHeld)
+	})
+
+	t.Run("leaseClearedByCleanUp", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 1*time.Millisecond)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+
+		_, _, err := tc.Acquire("aKey", 10*time.Millisecond)
+		assert.Nil(t, err)
+
+		<-time.After(25 * time.Millisecond)
+
+		tc.mu.RLock()
+		item := tc.items["aKey"]
+		tc.mu.RUnlock()
+		assert.Empty(t, item.leaseToken)
+		assert.Zero(t, item.leaseExpiration)
+	})
+}
+
+func TestCache_Release(t *testing.T) {
+	t.Run("withValidToken", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+
+		_, token, err := tc.Acquire("aKey", time.Minute)
+		assert.Nil(t, err)
+
+		err = tc.Release("aKey", token)
+		assert.Nil(t, err)
+
+		a, found := tc.Get("aKey")
+		assert.Equal(t, "aValue", a)
+		assert.True(t, found)
+
+		_, _, err = tc.Acquire("aKey", time.Minute)
+		assert.Nil(t, err)
+	})
+
+	t.Run("withWrongToken", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+
+		_, _, err := tc.Acquire("aKey", time.Minute)
+		assert.Nil(t, err)
+
+		err = tc.Release("aKey", "wrongToken")
+		assert.ErrorIs(t, err, ErrLeaseNotHeld)
+
+		a, found := tc.Get("aKey")
+		assert.Nil(t, a)
+		assert.False(t, found)
+	})
+
+	t.Run("withoutLease", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+
+		err := tc.Release("aKey", "")
+		assert.ErrorIs(t, err, ErrLeaseNotHeld)
+	})
+}
+
+func TestCache_ExtendLease(t *testing.T) {
+	t.Run("withValidToken", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+
+		_, token, err := tc.Acquire("aKey", 20*time.Millisecond)
+		assert.Nil(t, err)
+
+		err = tc.ExtendLease("aKey", token, time.Minute)
+		assert.Nil(t, err)
+
+		<-time.After(25 * time.Millisecond)
+
+		_, _, err = tc.Acquire("aKey", time.Minute)
+		assert.ErrorIs(t, err, ErrLeased)
+	})
+
+	t.Run("withWrongToken", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+
+		_, _, err := tc.Acquire("aKey", 20*time.Millisecond)
+		assert.Nil(t, err)
+
+		err = tc.ExtendLease("aKey", "wrongToken", time.Minute)
+		assert.ErrorIs(t, err, ErrLeaseNotHeld)
+
+		<-time.After(25 * time.Millisecond)
+
+		_, _, err = tc.Acquire("aKey", time.Minute)
+		assert.Nil(t, err)
+	})
+}
@@ -0,0 +1,274 @@
+package go_cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// Loader Loads the value of a key missing from the cache, returning it along with the duration it should
+// be cached for, with the same semantics as the duration passed to Set. A loader reports that the key does
+// not exist upstream by returning an error wrapping ErrItemNotFound.
+type Loader func(ctx context.Context, key string) (any, time.Duration, error)
+
+// errLoadTimeout is the cause of the cancellation of a load running for longer than set WithLoadTimeout.
+var errLoadTimeout = errors.New("load timed out")
+
+type loadCall struct {
+	done  chan struct{}
+	value any
+	err   error
+	// waiters is the number of callers waiting for the call, and cancel cancels the context of the loader once they
+	// all gave up, if the call was started by startLoad.
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// GetOrLoad Looks up a key's value from the cache, calling the given loader and caching its result on a miss.
+// Concurrent calls for the same key share a single call to the loader.
+// If the key holds a negative entry or the loader returns ErrItemNotFound, an error wrapping ErrItemNotFound
+// is returned, and in the latter case a negative entry is stored if the cache was created WithNegativeTTL.
+// Once the cache is stopped, the loader is not called and ErrCacheClosed is returned. A panic of the loader is
+// recovered and returned to all the callers as an error wrapping ErrLoaderPanicked, which is also reported to the
+// handler set WithErrorHandler.
+// With WithBloomFilter, the keys the filter has never seen are not loaded: an error wrapping ErrItemNotFound is
+// returned right away. With WithErrorCaching, a recent failure of the loader for the key is returned again instead
+// of calling it, wrapped along with ErrLoadFailureCached. With WithLoaderBreaker, the loader is not called while the
+// breaker is open: an expired value still held under the key is returned instead if any, as by GetStale, otherwise
+// an error wrapping ErrBreakerOpen. With WithLoaderRetry, the loader is retried within the single call shared by
+// concurrent callers, and only the outcome of its last attempt is cached, negatively cached or returned.
+// A caller whose context is done stops waiting for the loader and returns ctx.Err(), while the loader goes on for the
+// other callers and still caches its result, see WithLoadTimeout.
+func (c *Cache) GetOrLoad(ctx context.Context, key string, loader Loader) (any, error) {
+	if l := c.latency; l != nil && l.sample() {
+		defer c.observe(&l.getOrLoad, c.now())
+	}
+	hashed := c.hashKey(key)
+	if !c.bloom.mayContain(hashed) {
+		c.miss(hashed)
+		return nil, fmt.Errorf("%w: %s", ErrItemNotFound, key)
+	}
+	c.mu.RLock()
+	item, state := c.lookup(hashed, c.now().UnixNano())
+	var value any
+	if state == LookupHit {
+		value = c.value(hashed, item)
+	}
+	c.mu.RUnlock()
+
+	switch state {
+	case LookupHit:
+		return value, nil
+	case LookupNegativeHit:
+		return nil, fmt.Errorf("%w: %s", ErrItemNotFound, key)
+	}
+	if c.errorCache != nil {
+		if err := c.cachedLoadError(key); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.load(ctx, key, loader)
+}
+
+// Fetch Looks up a key's value from the cache like GetOrLoad, using the loader the cache was created with.
+// Returns ErrNoLoader error if the cache was created without WithLoader.
+func (c *Cache) Fetch(ctx context.Context, key string) (any, error) {
+	if c.loader == nil {
+		return nil, ErrNoLoader
+	}
+
+	return c.GetOrLoad(ctx, key, c.loader)
+}
+
+// Refresh Calls the loader the cache was created with for the given key, whether its value is live or not, e.g.
+// once notified that it changed upstream, and replaces the value with the one loaded. Readers keep getting the
+// previous value during the load, and it is left untouched if the load fails or reports the key missing, the error
+// being returned. A load already in flight for the key is waited for instead of starting another one. Unlike Fetch,
+// the errors cached WithErrorCaching are ignored. Returns ErrNoLoader error if the cache was created without
+// WithLoader.
+func (c *Cache) Refresh(ctx context.Context, key string) error {
+	if c.loader == nil {
+		return ErrNoLoader
+	}
+	_, err := c.load(ctx, key, c.loader)
+
+	return err
+}
+
+// load Calls the loader for the given key, or waits for the call already in flight for it. The loader runs in its
+// own goroutine, with a context which is not cancelled with the one of the caller, so that a caller giving up returns
+// ctx.Err() right away without failing the call for the others. The loader is cancelled once all the callers gave up.
+func (c *Cache) load(ctx context.Context, key string, loader Loader) (any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	call, leader := c.joinLoad(key)
+	if leader {
+		c.startLoad(ctx, []*loadCall{call}, func(loadCtx context.Context) {
+			c.runLoad(loadCtx, key, loader, call)
+		})
+	}
+
+	return c.waitLoad(ctx, key, call)
+}
+
+// startLoad Runs the load of the given calls in its own goroutine, with the context returned by loadContext, which
+// is cancelled once all the callers of all the calls gave up.
+func (c *Cache) startLoad(ctx context.Context, calls []*loadCall, run func(ctx context.Context)) {
+	loadCtx, cancel := c.loadContext(ctx)
+	c.loadMu.Lock()
+	// Called by leaveLoad, with loadMu held.
+	wanted := len(calls)
+	for _, call := range calls {
+		call.cancel = func() {
+			if wanted--; wanted == 0 {
+				cancel()
+			}
+		}
+	}
+	c.loadMu.Unlock()
+	go func() {
+		defer cancel()
+		run(loadCtx)
+	}()
+}
+
+// waitLoad Waits for the call in flight for the given key and returns its outcome, or ctx.Err() once the context is
+// done, leaving the call.
+func (c *Cache) waitLoad(ctx context.Context, key string, call *loadCall) (any, error) {
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		c.leaveLoad(key, call)
+		return nil, ctx.Err()
+	}
+}
+
+// loadContext Returns the context of a loader started by load, keeping the values of the context of the caller but
+// not its cancellation, and bounded by the timeout set WithLoadTimeout if any.
+func (c *Cache) loadContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx = context.WithoutCancel(ctx)
+	if c.loadTimeout > 0 {
+		return context.WithTimeoutCause(ctx, c.loadTimeout, errLoadTimeout)
+	}
+
+	return context.WithCancel(ctx)
+}
+
+// leaveLoad Stops waiting for the call in flight for the given key, cancelling it if no caller is left, in which
+// case later callers start a new call.
+func (c *Cache) leaveLoad(key string, call *loadCall) {
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+
+	call.waiters--
+	if call.waiters > 0 || call.cancel == nil {
+		return
+	}
+	call.cancel()
+	if c.loads[key] == call {
+		delete(c.loads, key)
+	}
+}
+
+// joinLoad Returns the call in flight for the given key, or registers a new one
+// reporting true if the caller is then in charge of running it with runLoad.
+func (c *Cache) joinLoad(key string) (*loadCall, bool) {
+	c.loadMu.Lock()
+	defer c.loadMu.Unlock()
+
+	if call, found := c.loads[key]; found {
+		call.waiters++
+		return call, false
+	}
+	call := &loadCall{done: make(chan struct{}), waiters: 1}
+	c.loads[key] = call
+
+	return call, true
+}
+
+func (c *Cache) runLoad(ctx context.Context, key string, loader Loader, call *loadCall) {
+	call.value, call.err = c.callLoader(ctx, key, loader)
+
+	c.loadMu.Lock()
+	if c.loads[key] == call {
+		delete(c.loads, key)
+	}
+	c.loadMu.Unlock()
+	close(call.done)
+}
+
+func (c *Cache) callLoader(ctx context.Context, key string, loader Loader) (any, error) {
+	if c.isClosed() {
+		return nil, ErrCacheClosed
+	}
+	var probe bool
+	if c.breaker != nil {
+		var allowed bool
+		if allowed, probe = c.breaker.allow(c.now().UnixNano()); !allowed {
+			if value, found := c.staleValue(c.hashKey(key)); found {
+				c.breaker.staleServed.Add(1)
+				return value, nil
+			}
+			return nil, fmt.Errorf("%w: %s", ErrBreakerOpen, key)
+		}
+	}
+
+	var object any
+	var duration time.Duration
+	var err error
+	if c.retry != nil {
+		object, duration, err = c.loadWithRetry(ctx, key, loader)
+	} else {
+		object, duration, err = c.invokeLoader(ctx, key, loader)
+	}
+	notFound := errors.Is(err, ErrItemNotFound)
+	// A load timing out is a failure, while one cancelled since its callers gave up is not.
+	gaveUp := ctx.Err() != nil && !errors.Is(context.Cause(ctx), errLoadTimeout)
+	if c.breaker != nil {
+		c.breaker.done(c.now().UnixNano(), probe, err != nil && !notFound, err != nil && gaveUp)
+	}
+	if err != nil {
+		if notFound && c.negativeTTL != 0 {
+			c.setNegativeUnlessLive(key, c.negativeTTL)
+		}
+		// A missing key is not a failure, nor are the callers giving up.
+		if c.errorCache != nil && !notFound && !gaveUp {
+			c.errorCache.failed(key, err, c.now().UnixNano())
+		}
+		return nil, err
+	}
+	if c.errorCache != nil {
+		c.errorCache.succeeded(key)
+	}
+	c.Set(key, object, duration)
+
+	return object, nil
+}
+
+// invokeLoader Calls the loader, once a slot is free if the cache was created WithMaxConcurrentLoads, turning a panic
+// into an error wrapping ErrLoaderPanicked along with the panic value and the stack, reported to the error handler
+// too, so that the callers waiting for the load are not left hanging.
+func (c *Cache) invokeLoader(ctx context.Context, key string, loader Loader) (
+	object any, duration time.Duration, err error,
+) {
+	if c.loadLimit != nil {
+		if err := c.loadLimit.acquire(ctx); err != nil {
+			return nil, 0, err
+		}
+		defer c.loadLimit.release()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			object, duration = nil, 0
+			err = fmt.Errorf("%w: %s: %v\n%s", ErrLoaderPanicked, key, r, debug.Stack())
+			c.handleError(err)
+		}
+	}()
+
+	return loader(ctx, key)
+}
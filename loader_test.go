@@ -0,0 +1,376 @@
+package go_cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_GetOrLoad(t *testing.T) {
+	t.Run("loadOnMiss", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		var calls atomic.Int32
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			calls.Add(1)
+			return key + "Value", DefaultExpiration, nil
+		}
+
+		a, err := tc.GetOrLoad(context.Background(), "aKey", loader)
+		assert.Nil(t, err)
+		assert.Equal(t, "aKeyValue", a)
+
+		a, err = tc.GetOrLoad(context.Background(), "aKey", loader)
+		assert.Nil(t, err)
+		assert.Equal(t, "aKeyValue", a)
+
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("concurrentCallsShareLoad", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		var calls atomic.Int32
+		release := make(chan struct{})
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			calls.Add(1)
+			<-release
+			return "aValue", DefaultExpiration, nil
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				a, err := tc.GetOrLoad(context.Background(), "aKey", loader)
+				assert.Nil(t, err)
+				assert.Equal(t, "aValue", a)
+			}()
+		}
+		<-time.After(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("loaderError", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		errLoad := errors.New("load failed")
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			return nil, 0, errLoad
+		}
+
+		a, err := tc.GetOrLoad(context.Background(), "aKey", loader)
+		assert.ErrorIs(t, err, errLoad)
+		assert.Nil(t, a)
+
+		ic := tc.ItemCount()
+		assert.Equal(t, 0, ic)
+	})
+
+	t.Run("notFoundStoresNegativeEntry", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithNegativeTTL(1 * time.Minute))
+		defer tc.Stop()
+
+		var calls atomic.Int32
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			calls.Add(1)
+			return nil, 0, fmt.Errorf("%w: %s", ErrItemNotFound, key)
+		}
+
+		_, err := tc.GetOrLoad(context.Background(), "aKey", loader)
+		assert.ErrorIs(t, err, ErrItemNotFound)
+
+		_, err = tc.GetOrLoad(context.Background(), "aKey", loader)
+		assert.ErrorIs(t, err, ErrItemNotFound)
+
+		assert.Equal(t, int32(1), calls.Load())
+
+		_, state := tc.Lookup("aKey")
+		assert.Equal(t, LookupNegativeHit, state)
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+
+		a, err := tc.GetOrLoad(context.Background(), "aKey", loader)
+		assert.Nil(t, err)
+		assert.Equal(t, "aValue", a)
+	})
+
+	t.Run("notFoundWithoutNegativeTTL", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			return nil, 0, ErrItemNotFound
+		}
+
+		_, err := tc.GetOrLoad(context.Background(), "aKey", loader)
+		assert.ErrorIs(t, err, ErrItemNotFound)
+
+		_, state := tc.Lookup("aKey")
+		assert.Equal(t, LookupMiss, state)
+	})
+
+	t.Run("cancelledWaiter", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		var calls atomic.Int32
+		release := make(chan struct{})
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			calls.Add(1)
+			<-release
+			return "aValue", DefaultExpiration, ctx.Err()
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := make(chan error, 3)
+		var wg sync.WaitGroup
+		for _, ctx := range []context.Context{ctx, context.Background(), context.Background()} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := tc.GetOrLoad(ctx, "aKey", loader)
+				errs <- err
+			}()
+		}
+		<-time.After(10 * time.Millisecond)
+
+		// The cancelled waiter returns while the load goes on, whether it started it or not.
+		cancel()
+		select {
+		case err := <-errs:
+			assert.ErrorIs(t, err, context.Canceled)
+		case <-time.After(time.Second):
+			assert.Fail(t, "cancelled waiter did not return")
+		}
+		close(release)
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			assert.Nil(t, err)
+		}
+
+		a, found := tc.Get("aKey")
+		assert.True(t, found)
+		assert.Equal(t, "aValue", a)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("allWaitersCancelled", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		cancelled := make(chan error, 1)
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			<-ctx.Done()
+			cancelled <- ctx.Err()
+			return nil, 0, ctx.Err()
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := tc.GetOrLoad(ctx, "aKey", loader)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorIs(t, <-cancelled, context.Canceled)
+
+		_, err = tc.GetOrLoad(ctx, "aKey", loader)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestCache_GetOrLoadPanic(t *testing.T) {
+	var reported []error
+	var mu sync.Mutex
+	tc := NewCacheWithOptions(WithErrorHandler(func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, err)
+	}))
+	defer tc.Stop()
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+		if calls.Add(1) == 1 {
+			<-release
+			panic("boom")
+		}
+		return key + "Value", DefaultExpiration, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := tc.GetOrLoad(context.Background(), "aKey", loader)
+			assert.ErrorIs(t, err, ErrLoaderPanicked)
+			assert.ErrorContains(t, err, "boom")
+		}()
+	}
+	<-time.After(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	a, err := tc.GetOrLoad(context.Background(), "aKey", loader)
+	assert.Nil(t, err)
+	assert.Equal(t, "aKeyValue", a)
+	assert.Equal(t, int32(2), calls.Load())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, reported, 1)
+	assert.ErrorIs(t, reported[0], ErrLoaderPanicked)
+	assert.ErrorContains(t, reported[0], "invokeLoader")
+}
+
+func TestCache_WithLoadTimeout(t *testing.T) {
+	tc := NewCacheWithOptions(WithLoadTimeout(10 * time.Millisecond))
+	defer tc.Stop()
+
+	type ctxKey struct{}
+	loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+		if key == "aKey" {
+			<-ctx.Done()
+			return nil, 0, ctx.Err()
+		}
+		// The loader sees the values of the context of the caller.
+		return ctx.Value(ctxKey{}), DefaultExpiration, nil
+	}
+
+	start := time.Now()
+	_, err := tc.GetOrLoad(context.Background(), "aKey", loader)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+
+	b, err := tc.GetOrLoad(context.WithValue(context.Background(), ctxKey{}, "bValue"), "bKey", loader)
+	assert.Nil(t, err)
+	assert.Equal(t, "bValue", b)
+}
+
+func TestCache_Fetch(t *testing.T) {
+	t.Run("withLoader", func(t *testing.T) {
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			return key + "Value", 20 * time.Millisecond, nil
+		}
+		tc := NewCacheWithOptions(WithLoader(loader))
+		defer tc.Stop()
+
+		a, err := tc.Fetch(context.Background(), "aKey")
+		assert.Nil(t, err)
+		assert.Equal(t, "aKeyValue", a)
+
+		<-time.After(25 * time.Millisecond)
+
+		a, found := tc.Get("aKey")
+		assert.Nil(t, a)
+		assert.False(t, found)
+	})
+
+	t.Run("withoutLoader", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		a, err := tc.Fetch(context.Background(), "aKey")
+		assert.ErrorIs(t, err, ErrNoLoader)
+		assert.Nil(t, a)
+	})
+}
+
+func TestCache_Refresh(t *testing.T) {
+	t.Run("servesPreviousValue", func(t *testing.T) {
+		var version atomic.Int32
+		release := make(chan struct{})
+		tc := NewCacheWithOptions(WithLoader(func(ctx context.Context, key string) (any, time.Duration, error) {
+			<-release
+			return fmt.Sprintf("aValue%d", version.Add(1)), time.Minute, nil
+		}))
+		defer tc.Stop()
+		tc.Set("aKey", "aValue0", NoExpiration)
+
+		done := make(chan error)
+		go func() {
+			done <- tc.Refresh(context.Background(), "aKey")
+		}()
+		for i := 0; i < 10; i++ {
+			a, found := tc.Get("aKey")
+			assert.True(t, found)
+			assert.Equal(t, "aValue0", a)
+		}
+		close(release)
+		assert.Nil(t, <-done)
+
+		a, expiration, state := tc.GetStale("aKey")
+		assert.Equal(t, StateLive, state)
+		assert.Equal(t, "aValue1", a)
+		assert.False(t, expiration.IsZero())
+	})
+
+	t.Run("failurePreservesValue", func(t *testing.T) {
+		errLoad := errors.New("load failed")
+		tc := NewCacheWithOptions(WithNegativeTTL(time.Minute),
+			WithLoader(func(ctx context.Context, key string) (any, time.Duration, error) {
+				if key == "aKey" {
+					return nil, 0, errLoad
+				}
+				return nil, 0, ErrItemNotFound
+			}))
+		defer tc.Stop()
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", "bValue", NoExpiration)
+
+		assert.ErrorIs(t, tc.Refresh(context.Background(), "aKey"), errLoad)
+		assert.ErrorIs(t, tc.Refresh(context.Background(), "bKey"), ErrItemNotFound)
+
+		a, _ := tc.Get("aKey")
+		assert.Equal(t, "aValue", a)
+		b, _ := tc.Get("bKey")
+		assert.Equal(t, "bValue", b)
+	})
+
+	t.Run("sharesLoad", func(t *testing.T) {
+		var calls atomic.Int32
+		release := make(chan struct{})
+		tc := NewCacheWithOptions(WithLoader(func(ctx context.Context, key string) (any, time.Duration, error) {
+			calls.Add(1)
+			<-release
+			return "aValue", DefaultExpiration, nil
+		}))
+		defer tc.Stop()
+
+		done := make(chan error)
+		go func() {
+			_, err := tc.Fetch(context.Background(), "aKey")
+			done <- err
+		}()
+		<-time.After(10 * time.Millisecond)
+		go func() {
+			done <- tc.Refresh(context.Background(), "aKey")
+		}()
+		<-time.After(10 * time.Millisecond)
+		close(release)
+		assert.Nil(t, <-done)
+		assert.Nil(t, <-done)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("withoutLoader", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		assert.ErrorIs(t, tc.Refresh(context.Background(), "aKey"), ErrNoLoader)
+	})
+}
@@ -0,0 +1,63 @@
+package go_cache
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// LoadStats The loads of a cache created WithMaxConcurrentLoads, counting each call of a loader, each attempt of a
+// retried one and each call of a batch loader as one load.
+type LoadStats struct {
+	// InFlight Number of loaders currently running.
+	InFlight int
+	// Queued Number of loads currently waiting for another load to complete.
+	Queued int
+	// PeakQueued The largest number of loads ever waiting at once.
+	PeakQueued int
+}
+
+// loadLimiter A semaphore bounding the number of loaders running at once across all keys, see WithMaxConcurrentLoads.
+type loadLimiter struct {
+	slots chan struct{}
+
+	queued     atomic.Int64
+	peakQueued atomic.Int64
+}
+
+func newLoadLimiter(n int) *loadLimiter {
+	return &loadLimiter{slots: make(chan struct{}, n)}
+}
+
+// acquire Waits for a slot to run a loader, returning the error of the context if it is done first.
+func (l *loadLimiter) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	queued := l.queued.Add(1)
+	defer l.queued.Add(-1)
+	for peak := l.peakQueued.Load(); queued > peak && !l.peakQueued.CompareAndSwap(peak, queued); {
+		peak = l.peakQueued.Load()
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release Frees the slot taken by acquire.
+func (l *loadLimiter) release() {
+	<-l.slots
+}
+
+func (l *loadLimiter) stats() LoadStats {
+	return LoadStats{
+		InFlight:   len(l.slots),
+		Queued:     int(l.queued.Load()),
+		PeakQueued: int(l.peakQueued.Load()),
+	}
+}
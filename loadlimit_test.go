@@ -0,0 +1,155 @@
+package go_cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// concurrencyRecorder Records the largest number of loaders running at once.
+type concurrencyRecorder struct {
+	running atomic.Int32
+	peak    atomic.Int32
+}
+
+func (r *concurrencyRecorder) enter() {
+	running := r.running.Add(1)
+	for peak := r.peak.Load(); running > peak && !r.peak.CompareAndSwap(peak, running); {
+		peak = r.peak.Load()
+	}
+}
+
+func (r *concurrencyRecorder) leave() {
+	r.running.Add(-1)
+}
+
+func TestCache_WithMaxConcurrentLoads(t *testing.T) {
+	t.Run("bounded", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMaxConcurrentLoads(5))
+		defer tc.Stop()
+
+		var rec concurrencyRecorder
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			rec.enter()
+			defer rec.leave()
+			time.Sleep(time.Millisecond)
+			return key + "Value", DefaultExpiration, nil
+		}
+
+		var wg sync.WaitGroup
+		for i := range 100 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				key := fmt.Sprintf("key%d", i)
+				value, err := tc.GetOrLoad(context.Background(), key, loader)
+				assert.NoError(t, err)
+				assert.Equal(t, key+"Value", value)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(5), rec.peak.Load())
+		assert.Equal(t, 100, tc.ItemCount())
+		stats := tc.Stats().Loads
+		assert.Equal(t, 0, stats.InFlight)
+		assert.Equal(t, 0, stats.Queued)
+		assert.Greater(t, stats.PeakQueued, 0)
+		assert.LessOrEqual(t, stats.PeakQueued, 95)
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMaxConcurrentLoads(1))
+		defer tc.Stop()
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		go tc.GetOrLoad(context.Background(), "aKey", func(ctx context.Context, key string) (any, time.Duration, error) {
+			close(started)
+			<-release
+			return "aValue", DefaultExpiration, nil
+		})
+		<-started
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			value, err := tc.GetOrLoad(context.Background(), "bKey", func(ctx context.Context, key string) (any, time.Duration, error) {
+				return "bValue", DefaultExpiration, nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, "bValue", value)
+		}()
+
+		assert.Eventually(t, func() bool {
+			return tc.Stats().Loads == LoadStats{InFlight: 1, Queued: 1, PeakQueued: 1}
+		}, 5*time.Second, time.Millisecond)
+		close(release)
+		<-done
+		assert.Equal(t, LoadStats{PeakQueued: 1}, tc.Stats().Loads)
+	})
+
+	t.Run("callerGivesUpWhileQueued", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMaxConcurrentLoads(1))
+		defer tc.Stop()
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		defer close(release)
+		go tc.GetOrLoad(context.Background(), "aKey", func(ctx context.Context, key string) (any, time.Duration, error) {
+			close(started)
+			<-release
+			return "aValue", DefaultExpiration, nil
+		})
+		<-started
+
+		var called atomic.Bool
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := tc.GetOrLoad(ctx, "bKey", func(ctx context.Context, key string) (any, time.Duration, error) {
+			called.Store(true)
+			return "bValue", DefaultExpiration, nil
+		})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Eventually(t, func() bool {
+			return tc.Stats().Loads.Queued == 0
+		}, 5*time.Second, time.Millisecond)
+		assert.False(t, called.Load())
+	})
+
+	t.Run("getOrLoadMany", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMaxConcurrentLoads(1))
+		defer tc.Stop()
+
+		// Each batch waits for the keys loaded by the other, without holding the slot meanwhile.
+		var rec concurrencyRecorder
+		var wg sync.WaitGroup
+		for _, keys := range [][]string{{"aKey", "bKey"}, {"bKey", "aKey"}, {"aKey", "cKey"}} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				values, err := tc.GetOrLoadMany(context.Background(), keys, NoExpiration,
+					func(ctx context.Context, missing []string) (map[string]any, error) {
+						rec.enter()
+						defer rec.leave()
+						time.Sleep(time.Millisecond)
+						loaded := make(map[string]any, len(missing))
+						for _, key := range missing {
+							loaded[key] = key + "Value"
+						}
+						return loaded, nil
+					})
+				assert.NoError(t, err)
+				assert.Len(t, values, 2)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), rec.peak.Load())
+		assert.Equal(t, 3, tc.ItemCount())
+	})
+}
@@ -0,0 +1,230 @@
+package go_cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// BatchLoader Loads the values of several keys missing from the cache in a single call, e.g. with a multi-get
+// upstream. Keys which do not exist upstream are left out of the returned map.
+type BatchLoader func(ctx context.Context, missing []string) (map[string]any, error)
+
+// GetOrLoadMany Looks up the values of the given keys like GetOrLoad, calling the loader once with all the keys
+// which missed and caching the values it returns for the given duration, with the same semantics as the duration
+// passed to Set. Keys already being loaded by other calls (of GetOrLoad as well) are not requested again: their
+// values are waited for instead. Only the keys found are in the returned map: keys holding a negative entry or left
+// out by the loader are not, and the latter get a negative entry if the cache was created WithNegativeTTL.
+//
+// If the loader fails, the values it returned anyway are cached and returned along with its error, and the keys
+// it did not return are neither cached nor negatively cached. The returned error joins the errors of the loads
+// which failed, including those of other calls waited for. Once the cache is stopped, the loader is not called
+// and ErrCacheClosed is returned. A panic of the loader is recovered like for GetOrLoad, and the loader is bounded
+// WithLoadTimeout, retried WithLoaderRetry and subject to WithErrorCaching and WithLoaderBreaker like it too: while
+// the breaker is open, the expired values still held under the keys are returned, with an error wrapping
+// ErrBreakerOpen for the others. A caller whose context is done stops waiting and returns the values it got along
+// with ctx.Err(), while the loads go on for the other callers, the loader being cancelled once they all gave up.
+// With WithBloomFilter, the keys the filter has never seen are left out of the returned map without being
+// requested, like those left out by the loader.
+func (c *Cache) GetOrLoadMany(ctx context.Context, keys []string, d time.Duration, loader BatchLoader) (map[string]any, error) {
+	values := make(map[string]any, len(keys))
+	var misses []string
+
+	c.mu.RLock()
+	now := c.now().UnixNano()
+	for _, key := range keys {
+		hashed := c.hashKey(key)
+		if !c.bloom.mayContain(hashed) {
+			c.miss(hashed)
+			continue
+		}
+		item, state := c.lookup(hashed, now)
+		switch state {
+		case LookupHit:
+			values[key] = c.value(hashed, item)
+		case LookupMiss:
+			misses = append(misses, key)
+		}
+	}
+	c.mu.RUnlock()
+
+	if len(misses) > 0 && ctx.Err() != nil {
+		return values, ctx.Err()
+	}
+
+	// The keys loaded by this call are registered before waiting for the others, so that concurrent calls
+	// asking for overlapping keys wait for one another rather than load them twice.
+	var errs []error
+	var joined, missing []string
+	calls := make(map[string]*loadCall, len(misses))
+	for _, key := range misses {
+		if _, found := calls[key]; found {
+			continue
+		}
+		if c.errorCache != nil {
+			if err := c.cachedLoadError(key); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		call, leader := c.joinLoad(key)
+		calls[key] = call
+		joined = append(joined, key)
+		if leader {
+			missing = append(missing, key)
+		}
+	}
+	led := make(map[*loadCall]bool, len(missing))
+	if len(missing) > 0 {
+		started := make([]*loadCall, len(missing))
+		for i, key := range missing {
+			started[i] = calls[key]
+			led[calls[key]] = true
+		}
+		c.startLoad(ctx, started, func(loadCtx context.Context) {
+			c.runLoadMany(loadCtx, missing, d, loader, calls)
+		})
+	}
+
+	// The keys loaded by this call all fail with the error of the loader, which is only reported once.
+	var failed bool
+	for i, key := range joined {
+		call := calls[key]
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			for _, key := range joined[i:] {
+				c.leaveLoad(key, calls[key])
+			}
+			return values, errors.Join(append(errs, ctx.Err())...)
+		}
+		switch {
+		case call.err == nil:
+			values[key] = call.value
+		case errors.Is(call.err, ErrItemNotFound):
+		case !led[call]:
+			errs = append(errs, call.err)
+		case !failed:
+			failed = true
+			errs = append(errs, call.err)
+		}
+	}
+
+	return values, errors.Join(errs...)
+}
+
+// runLoadMany Calls the loader for the given keys, stores the values it returns, and completes the calls in flight
+// for the keys.
+func (c *Cache) runLoadMany(
+	ctx context.Context, missing []string, d time.Duration, loader BatchLoader, calls map[string]*loadCall,
+) {
+	loaded, stale, err := c.callBatchLoader(ctx, missing, loader)
+
+	c.mu.Lock()
+	for _, key := range missing {
+		call := calls[key]
+		if value, found := loaded[key]; found {
+			if !stale {
+				c.set(c.hashKey(key), value, d)
+			}
+			call.value = value
+			continue
+		}
+		if err != nil {
+			call.err = err
+			continue
+		}
+		call.err = fmt.Errorf("%w: %s", ErrItemNotFound, key)
+		if c.negativeTTL != 0 {
+			c.setNegative(c.hashKey(key), c.negativeTTL)
+		}
+	}
+	c.unlock()
+
+	c.loadMu.Lock()
+	for _, key := range missing {
+		if c.loads[key] == calls[key] {
+			delete(c.loads, key)
+		}
+	}
+	c.loadMu.Unlock()
+	for _, key := range missing {
+		close(calls[key].done)
+	}
+}
+
+// callBatchLoader Calls the loader for the given keys like callLoader, through the breaker and with the retries set
+// WithLoaderRetry, recording its failure WithErrorCaching. While the breaker is open, the expired values still held
+// under the keys are returned instead, reported stale, along with an error wrapping ErrBreakerOpen for the others.
+func (c *Cache) callBatchLoader(ctx context.Context, missing []string, loader BatchLoader) (
+	loaded map[string]any, stale bool, err error,
+) {
+	if c.isClosed() {
+		return nil, false, ErrCacheClosed
+	}
+	var probe bool
+	if c.breaker != nil {
+		var allowed bool
+		if allowed, probe = c.breaker.allow(c.now().UnixNano()); !allowed {
+			loaded = make(map[string]any)
+			for _, key := range missing {
+				if value, found := c.staleValue(c.hashKey(key)); found {
+					c.breaker.staleServed.Add(1)
+					loaded[key] = value
+				}
+			}
+			return loaded, true, ErrBreakerOpen
+		}
+	}
+
+	if c.retry != nil {
+		err = c.retryLoad(ctx, func() (err error) {
+			loaded, err = c.invokeBatchLoader(ctx, missing, loader)
+			return err
+		})
+	} else {
+		loaded, err = c.invokeBatchLoader(ctx, missing, loader)
+	}
+	notFound := errors.Is(err, ErrItemNotFound)
+	// A load timing out is a failure, while one cancelled since its callers gave up is not.
+	gaveUp := ctx.Err() != nil && !errors.Is(context.Cause(ctx), errLoadTimeout)
+	if c.breaker != nil {
+		c.breaker.done(c.now().UnixNano(), probe, err != nil && !notFound, err != nil && gaveUp)
+	}
+	if c.errorCache != nil {
+		now := c.now().UnixNano()
+		for _, key := range missing {
+			if _, found := loaded[key]; found {
+				c.errorCache.succeeded(key)
+			} else if err != nil && !notFound && !gaveUp {
+				c.errorCache.failed(key, err, now)
+			}
+		}
+	}
+
+	return loaded, false, err
+}
+
+// invokeBatchLoader Calls the loader like invokeLoader, waiting for a slot and turning a panic into an error
+// wrapping ErrLoaderPanicked.
+func (c *Cache) invokeBatchLoader(ctx context.Context, missing []string, loader BatchLoader) (
+	loaded map[string]any, err error,
+) {
+	if c.loadLimit != nil {
+		if err := c.loadLimit.acquire(ctx); err != nil {
+			return nil, err
+		}
+		defer c.loadLimit.release()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			loaded = nil
+			err = fmt.Errorf("%w: %v\n%s", ErrLoaderPanicked, r, debug.Stack())
+			c.handleError(err)
+		}
+	}()
+
+	return loader(ctx, missing)
+}
@@ -0,0 +1,340 @@
+package go_cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_GetOrLoadMany(t *testing.T) {
+	t.Run("loadsMissesOnce", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithNegativeTTL(time.Minute))
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.SetNegative("bKey", DefaultExpiration)
+
+		var calls [][]string
+		loader := func(_ context.Context, missing []string) (map[string]any, error) {
+			calls = append(calls, missing)
+			return map[string]any{"cKey": "cValue", "dKey": "dValue"}, nil
+		}
+		values, err := tc.GetOrLoadMany(context.Background(), []string{"aKey", "bKey", "cKey", "dKey", "eKey", "cKey"}, NoExpiration, loader)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"aKey": "aValue", "cKey": "cValue", "dKey": "dValue"}, values)
+		assert.Equal(t, [][]string{{"cKey", "dKey", "eKey"}}, calls)
+
+		// Loaded values are cached, and the key left out got a negative entry.
+		value, _ := tc.Get("dKey")
+		assert.Equal(t, "dValue", value)
+		_, state := tc.Lookup("eKey")
+		assert.Equal(t, LookupNegativeHit, state)
+
+		values, err = tc.GetOrLoadMany(context.Background(), []string{"cKey", "eKey"}, NoExpiration, loader)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"cKey": "cValue"}, values)
+		assert.Len(t, calls, 1)
+	})
+
+	t.Run("partialFailure", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithNegativeTTL(time.Minute))
+		defer tc.Stop()
+
+		errUpstream := errors.New("upstream timeout")
+		values, err := tc.GetOrLoadMany(context.Background(), []string{"aKey", "bKey"}, NoExpiration,
+			func(context.Context, []string) (map[string]any, error) {
+				return map[string]any{"aKey": "aValue"}, errUpstream
+			})
+		assert.ErrorIs(t, err, errUpstream)
+		assert.Equal(t, map[string]any{"aKey": "aValue"}, values)
+
+		// The key the loader did not return may exist: it is not negatively cached.
+		value, _ := tc.Get("aKey")
+		assert.Equal(t, "aValue", value)
+		_, state := tc.Lookup("bKey")
+		assert.Equal(t, LookupMiss, state)
+	})
+
+	t.Run("concurrentOverlapping", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		var mu sync.Mutex
+		loads := make(map[string]int)
+		started, release := make(chan struct{}, 2), make(chan struct{})
+		loader := func(_ context.Context, missing []string) (map[string]any, error) {
+			mu.Lock()
+			for _, key := range missing {
+				loads[key]++
+			}
+			mu.Unlock()
+			started <- struct{}{}
+			<-release
+
+			values := make(map[string]any, len(missing))
+			for _, key := range missing {
+				values[key] = key + "Value"
+			}
+			return values, nil
+		}
+
+		var wg sync.WaitGroup
+		results := make([]map[string]any, 2)
+		run := func(i int, keys []string) {
+			defer wg.Done()
+			values, err := tc.GetOrLoadMany(context.Background(), keys, NoExpiration, loader)
+			assert.NoError(t, err)
+			results[i] = values
+		}
+		wg.Add(2)
+		go run(0, []string{"aKey", "bKey", "cKey"})
+		<-started
+		go run(1, []string{"bKey", "cKey", "dKey"})
+		<-started
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, map[string]int{"aKey": 1, "bKey": 1, "cKey": 1, "dKey": 1}, loads)
+		assert.Equal(t, map[string]any{"aKey": "aKeyValue", "bKey": "bKeyValue", "cKey": "cKeyValue"}, results[0])
+		assert.Equal(t, map[string]any{"bKey": "bKeyValue", "cKey": "cKeyValue", "dKey": "dKeyValue"}, results[1])
+	})
+
+	t.Run("sharedWithGetOrLoad", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		started, release := make(chan struct{}), make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			value, err := tc.GetOrLoad(context.Background(), "aKey", func(context.Context, string) (any, time.Duration, error) {
+				close(started)
+				<-release
+				return "aValue", NoExpiration, nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, "aValue", value)
+		}()
+		<-started
+
+		values, err := tc.GetOrLoadMany(context.Background(), []string{"aKey", "bKey"}, NoExpiration,
+			func(_ context.Context, missing []string) (map[string]any, error) {
+				// The load of aKey in flight was joined before this call, it can complete now.
+				close(release)
+				assert.Equal(t, []string{"bKey"}, missing)
+				return map[string]any{"bKey": "bValue"}, nil
+			})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"aKey": "aValue", "bKey": "bValue"}, values)
+		<-done
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		tc.Stop()
+
+		values, err := tc.GetOrLoadMany(context.Background(), []string{"aKey"}, NoExpiration,
+			func(context.Context, []string) (map[string]any, error) {
+				t.Fatal("loader called on a stopped cache")
+				return nil, nil
+			})
+		assert.ErrorIs(t, err, ErrCacheClosed)
+		assert.Empty(t, values)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		_, err := tc.GetOrLoadMany(context.Background(), []string{"aKey"}, DefaultExpiration,
+			func(ctx context.Context, missing []string) (map[string]any, error) {
+				panic("boom")
+			})
+		assert.ErrorIs(t, err, ErrLoaderPanicked)
+
+		values, err := tc.GetOrLoadMany(context.Background(), []string{"aKey"}, DefaultExpiration,
+			func(ctx context.Context, missing []string) (map[string]any, error) {
+				return map[string]any{"aKey": "aValue"}, nil
+			})
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]any{"aKey": "aValue"}, values)
+	})
+
+	t.Run("waiterDeadline", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		started, release := make(chan struct{}), make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			value, err := tc.GetOrLoad(context.Background(), "aKey", func(context.Context, string) (any, time.Duration, error) {
+				close(started)
+				<-release
+				return "aValue", NoExpiration, nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, "aValue", value)
+		}()
+		<-started
+
+		tc.Set("bKey", "bValue", NoExpiration)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		values, err := tc.GetOrLoadMany(ctx, []string{"aKey", "bKey"}, NoExpiration,
+			func(context.Context, []string) (map[string]any, error) {
+				t.Error("loader called for a key in flight")
+				return nil, nil
+			})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, map[string]any{"bKey": "bValue"}, values)
+
+		// The load goes on for the other caller.
+		close(release)
+		<-done
+	})
+
+	t.Run("leaderCancelled", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		started, release := make(chan struct{}), make(chan struct{})
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := make(chan error, 1)
+		go func() {
+			_, err := tc.GetOrLoadMany(ctx, []string{"aKey"}, NoExpiration,
+				func(ctx context.Context, missing []string) (map[string]any, error) {
+					close(started)
+					<-release
+					return map[string]any{"aKey": "aValue"}, ctx.Err()
+				})
+			errs <- err
+		}()
+		<-started
+
+		joined := make(chan struct{})
+		go func() {
+			defer close(joined)
+			value, err := tc.GetOrLoad(context.Background(), "aKey", func(context.Context, string) (any, time.Duration, error) {
+				t.Error("loader called for a key in flight")
+				return nil, 0, nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, "aValue", value)
+		}()
+		assert.Eventually(t, func() bool {
+			tc.loadMu.Lock()
+			defer tc.loadMu.Unlock()
+			return tc.loads["aKey"].waiters == 2
+		}, time.Second, time.Millisecond)
+
+		// The leader returns, while the loader goes on uncancelled for the caller which joined it.
+		cancel()
+		assert.ErrorIs(t, <-errs, context.Canceled)
+		close(release)
+		<-joined
+	})
+
+	t.Run("allCallersCancelled", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		cancelled := make(chan error, 1)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := tc.GetOrLoadMany(ctx, []string{"aKey", "bKey"}, NoExpiration,
+			func(ctx context.Context, missing []string) (map[string]any, error) {
+				<-ctx.Done()
+				cancelled <- ctx.Err()
+				return nil, ctx.Err()
+			})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorIs(t, <-cancelled, context.Canceled)
+	})
+
+	t.Run("loadTimeout", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithLoadTimeout(10 * time.Millisecond))
+		defer tc.Stop()
+
+		start := time.Now()
+		_, err := tc.GetOrLoadMany(context.Background(), []string{"aKey"}, NoExpiration,
+			func(ctx context.Context, missing []string) (map[string]any, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithLoaderRetry(3, nil, nil))
+		defer tc.Stop()
+
+		errUpstream := errors.New("upstream timeout")
+		attempts := 0
+		values, err := tc.GetOrLoadMany(context.Background(), []string{"aKey"}, NoExpiration,
+			func(context.Context, []string) (map[string]any, error) {
+				if attempts++; attempts < 3 {
+					return nil, errUpstream
+				}
+				return map[string]any{"aKey": "aValue"}, nil
+			})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"aKey": "aValue"}, values)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("errorCaching", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithErrorCaching(time.Minute, time.Minute))
+		defer tc.Stop()
+
+		errUpstream := errors.New("upstream timeout")
+		calls := 0
+		loader := func(context.Context, []string) (map[string]any, error) {
+			calls++
+			return map[string]any{"aKey": "aValue"}, errUpstream
+		}
+		_, err := tc.GetOrLoadMany(context.Background(), []string{"aKey", "bKey"}, NoExpiration, loader)
+		assert.ErrorIs(t, err, errUpstream)
+
+		// Only the key which failed is not requested again.
+		values, err := tc.GetOrLoadMany(context.Background(), []string{"aKey", "bKey"}, NoExpiration, loader)
+		assert.ErrorIs(t, err, ErrLoadFailureCached)
+		assert.ErrorIs(t, err, errUpstream)
+		assert.Equal(t, map[string]any{"aKey": "aValue"}, values)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("breaker", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithLoaderBreaker(0.5, time.Minute, 10*time.Second))
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		errDown := errors.New("database is down")
+		calls := 0
+		loader := func(context.Context, []string) (map[string]any, error) {
+			calls++
+			return nil, errDown
+		}
+		tc.Set("aKey", "aValue", time.Second)
+		clock.Advance(time.Second)
+		for range 5 {
+			_, err := tc.GetOrLoadMany(context.Background(), []string{"bKey"}, NoExpiration, loader)
+			assert.ErrorIs(t, err, errDown)
+		}
+		assert.Equal(t, BreakerOpen, tc.Health().BreakerState)
+
+		// The expired value is served while the breaker is open.
+		values, err := tc.GetOrLoadMany(context.Background(), []string{"aKey", "bKey"}, NoExpiration, loader)
+		assert.ErrorIs(t, err, ErrBreakerOpen)
+		assert.Equal(t, map[string]any{"aKey": "aValue"}, values)
+		assert.Equal(t, 5, calls)
+		_, found := tc.Get("aKey")
+		assert.False(t, found)
+	})
+}
@@ -0,0 +1,203 @@
+package go_cache
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// MigrateOption Configures Migrate.
+type MigrateOption func(*migrateOptions)
+
+type migrateOptions struct {
+	rate      float64
+	batchSize int
+	overwrite bool
+	move      bool
+	cursor    string
+}
+
+// WithMigrateRate Caps the number of items Migrate goes through per second, e.g. to avoid a latency spike on a live
+// cache. Items are throttled a batch at a time, see WithMigrateBatchSize. No limit by default.
+func WithMigrateRate(itemsPerSecond float64) MigrateOption {
+	return func(o *migrateOptions) {
+		o.rate = itemsPerSecond
+	}
+}
+
+// WithMigrateBatchSize Sets the number of items copied each time the locks of the caches are taken, 1024 by default.
+func WithMigrateBatchSize(n int) MigrateOption {
+	return func(o *migrateOptions) {
+		o.batchSize = n
+	}
+}
+
+// WithMigrateOverwrite Makes Migrate replace the items already held by the destination, which are kept otherwise.
+func WithMigrateOverwrite() MigrateOption {
+	return func(o *migrateOptions) {
+		o.overwrite = true
+	}
+}
+
+// WithMigrateMove Makes Migrate delete the items from the source once copied, unless written again meanwhile.
+func WithMigrateMove() MigrateOption {
+	return func(o *migrateOptions) {
+		o.move = true
+	}
+}
+
+// WithMigrateCursor Makes Migrate resume after the given cursor, as returned in the report of an interrupted call.
+func WithMigrateCursor(cursor string) MigrateOption {
+	return func(o *migrateOptions) {
+		o.cursor = cursor
+	}
+}
+
+// MigrateReport Describes the outcome of Migrate.
+type MigrateReport struct {
+	// Copied Number of items written to the destination.
+	Copied int
+	// Skipped Number of items the destination already held, see WithMigrateOverwrite.
+	Skipped int
+	// Failed Number of items the destination rejected, e.g. for a value larger than the size set WithMaxValueSize.
+	Failed int
+	// Deleted Number of items deleted from the source, see WithMigrateMove.
+	Deleted int
+	// Cursor The position reached, to pass to WithMigrateCursor to resume an interrupted migration.
+	Cursor string
+}
+
+// Migrate Copies the live items of the cache to dst, in ascending key order and in batches (see
+// WithMigrateBatchSize) so that neither cache is blocked for the whole migration, possibly throttled (see
+// WithMigrateRate). Items keep their expiration time, and the items dst already holds are kept unless
+// WithMigrateOverwrite is set. With WithMigrateMove, the items copied are deleted from the cache.
+//
+// Writes racing with the migration follow a last-writer-wins rule: the value copied for a key is the one the cache
+// holds when its batch is read, and a later write to the cache is not carried over to dst, so writers should be
+// switched to dst first. Such a write is not lost either: the item is then not deleted in move mode. Keys written
+// to the cache after the migration started are not copied.
+//
+// If ctx is done, Migrate stops between two batches and returns ctx.Err() along with the report so far, whose
+// Cursor resumes the migration when passed to WithMigrateCursor. Returns ErrHashedKeys error if the cache was created
+// WithHashedKeys, since its keys are not stored, and ErrCacheClosed error if either cache is stopped.
+func (c *Cache) Migrate(ctx context.Context, dst *Cache, opts ...MigrateOption) (MigrateReport, error) {
+	o := migrateOptions{batchSize: iterationChunkSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.batchSize < 1 {
+		o.batchSize = 1
+	}
+	report := MigrateReport{Cursor: o.cursor}
+	if c.hasher != nil {
+		return report, ErrHashedKeys
+	}
+
+	c.mu.RLock()
+	keys := make([]string, 0, len(c.items))
+	for key := range c.items {
+		if key > o.cursor {
+			keys = append(keys, key)
+		}
+	}
+	c.mu.RUnlock()
+	slices.Sort(keys)
+
+	start := time.Now()
+	processed := 0
+	entries := make([]entry, 0, min(o.batchSize, len(keys)))
+	values := make([]any, 0, min(o.batchSize, len(keys)))
+	for len(keys) > 0 {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		batch := keys[:min(o.batchSize, len(keys))]
+		keys = keys[len(batch):]
+
+		entries, values = entries[:0], values[:0]
+		c.mu.RLock()
+		closed := c.closed
+		now := c.now().UnixNano()
+		for _, key := range batch {
+			if item, found := c.get(key, now); found {
+				entries = append(entries, entry{key: key, item: item})
+				values = append(values, c.value(key, item))
+			}
+		}
+		c.mu.RUnlock()
+		if closed {
+			return report, ErrCacheClosed
+		}
+
+		copied, err := dst.migrated(entries, values, o.overwrite, &report)
+		if err != nil {
+			return report, err
+		}
+		if o.move && len(copied) > 0 {
+			report.Deleted += c.deleteMigrated(copied)
+		}
+		report.Cursor = batch[len(batch)-1]
+
+		processed += len(batch)
+		if o.rate > 0 {
+			wait := time.Until(start.Add(time.Duration(float64(processed) / o.rate * float64(time.Second))))
+			if wait > 0 && len(keys) > 0 {
+				t := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					t.Stop()
+				case <-t.C:
+				}
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// migrated Writes the entries migrated from another cache with the given values, keeping their expiration time,
+// counts them in the report, and returns those written.
+func (c *Cache) migrated(entries []entry, values []any, overwrite bool, report *MigrateReport) ([]entry, error) {
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.closed {
+		return nil, ErrCacheClosed
+	}
+	var copied []entry
+	now := c.now().UnixNano()
+	for i, e := range entries {
+		key := c.hashKey(e.key)
+		if _, found := c.get(key, now); found && !overwrite {
+			report.Skipped++
+			continue
+		}
+		it := c.newItem(key, values[i], NoExpiration)
+		it.expiration = e.item.expiration
+		if err := c.insert(key, it); err != nil {
+			report.Failed++
+			continue
+		}
+		report.Copied++
+		copied = append(copied, e)
+	}
+
+	return copied, nil
+}
+
+// deleteMigrated Deletes the entries copied to another cache, unless written again since they were read, and
+// returns the number of items deleted.
+func (c *Cache) deleteMigrated(entries []entry) int {
+	c.mu.Lock()
+	defer c.unlock()
+
+	deleted := 0
+	for _, e := range entries {
+		if it, found := c.items[e.key]; found && it.version == e.item.version && it.created == e.item.created {
+			c.delete(e.key, removalDeleted)
+			deleted++
+		}
+	}
+
+	return deleted
+}
@@ -0,0 +1,179 @@
+package go_cache
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_Migrate(t *testing.T) {
+	t.Run("copy", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+		oc := NewCache(DefaultExpiration, 0)
+		oc.now = clock.Now
+		defer oc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", "bValue", 1*time.Minute)
+		tc.Set("cKey", "cValue", 1*time.Second)
+		tc.SetNegative("dKey", DefaultExpiration)
+		clock.Advance(2 * time.Second)
+
+		report, err := tc.Migrate(context.Background(), oc)
+		assert.NoError(t, err)
+		assert.Equal(t, MigrateReport{Copied: 2, Cursor: "dKey"}, report)
+		assert.Equal(t, 2, oc.ItemCount())
+		value, found := oc.Get("bKey")
+		assert.True(t, found)
+		assert.Equal(t, "bValue", value)
+		assert.Equal(t, tc.items["bKey"].expiration, oc.items["bKey"].expiration)
+		assert.Equal(t, int64(0), oc.items["aKey"].expiration)
+		assert.Equal(t, 4, tc.ItemCount())
+	})
+
+	t.Run("hugeBatch", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+		oc := NewCache(DefaultExpiration, 0)
+		defer oc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		report, err := tc.Migrate(context.Background(), oc, WithMigrateBatchSize(math.MaxInt))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Copied)
+	})
+
+	t.Run("skipExisting", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+		oc := NewCache(DefaultExpiration, 0)
+		defer oc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", "bValue", NoExpiration)
+		oc.Set("aKey", "existing", NoExpiration)
+
+		report, err := tc.Migrate(context.Background(), oc)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Copied)
+		assert.Equal(t, 1, report.Skipped)
+		value, _ := oc.Get("aKey")
+		assert.Equal(t, "existing", value)
+
+		report, err = tc.Migrate(context.Background(), oc, WithMigrateOverwrite())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Copied)
+		assert.Equal(t, 0, report.Skipped)
+		value, _ = oc.Get("aKey")
+		assert.Equal(t, "aValue", value)
+	})
+
+	t.Run("move", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+		oc := NewCache(DefaultExpiration, 0)
+		defer oc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", "bValue", NoExpiration)
+		tc.Set("cKey", "cValue", NoExpiration)
+		oc.Set("cKey", "existing", NoExpiration)
+
+		report, err := tc.Migrate(context.Background(), oc, WithMigrateMove())
+		assert.NoError(t, err)
+		assert.Equal(t, MigrateReport{Copied: 2, Skipped: 1, Deleted: 2, Cursor: "cKey"}, report)
+		assert.Equal(t, 1, tc.ItemCount())
+		_, found := tc.Get("cKey")
+		assert.True(t, found)
+		assert.Equal(t, 3, oc.ItemCount())
+	})
+
+	t.Run("moveKeepsLaterWrites", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+		oc := NewCache(DefaultExpiration, 0)
+		defer oc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", "bValue", NoExpiration)
+		entries := []entry{{key: "aKey", item: tc.items["aKey"]}, {key: "bKey", item: tc.items["bKey"]}}
+		tc.Set("bKey", "newValue", NoExpiration)
+
+		assert.Equal(t, 1, tc.deleteMigrated(entries))
+		value, found := tc.Get("bKey")
+		assert.True(t, found)
+		assert.Equal(t, "newValue", value)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+		oc := NewCache(DefaultExpiration, 0)
+		defer oc.Stop()
+
+		for i := 0; i < 10; i++ {
+			tc.Set(fmt.Sprintf("key%d", i), i, NoExpiration)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		report, err := tc.Migrate(ctx, oc, WithMigrateBatchSize(2), WithMigrateRate(20))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 2, report.Copied)
+		assert.Equal(t, "key1", report.Cursor)
+		assert.Equal(t, 2, oc.ItemCount())
+
+		report, err = tc.Migrate(context.Background(), oc, WithMigrateBatchSize(2), WithMigrateCursor(report.Cursor))
+		assert.NoError(t, err)
+		assert.Equal(t, 8, report.Copied)
+		assert.Equal(t, 0, report.Skipped)
+		assert.Equal(t, "key9", report.Cursor)
+		assert.Equal(t, 10, oc.ItemCount())
+	})
+
+	t.Run("throttled", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+		oc := NewCache(DefaultExpiration, 0)
+		defer oc.Stop()
+
+		for i := 0; i < 6; i++ {
+			tc.Set(fmt.Sprintf("key%d", i), i, NoExpiration)
+		}
+
+		start := time.Now()
+		report, err := tc.Migrate(context.Background(), oc, WithMigrateBatchSize(2), WithMigrateRate(40))
+		assert.NoError(t, err)
+		assert.Equal(t, 6, report.Copied)
+		// The last batch is not waited for: 4 items at 40 items per second.
+		assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	})
+
+	t.Run("hashedKeys", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithHashedKeys())
+		defer tc.Stop()
+		oc := NewCache(DefaultExpiration, 0)
+		defer oc.Stop()
+
+		_, err := tc.Migrate(context.Background(), oc)
+		assert.ErrorIs(t, err, ErrHashedKeys)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+		oc := NewCache(DefaultExpiration, 0)
+		oc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		_, err := tc.Migrate(context.Background(), oc)
+		assert.ErrorIs(t, err, ErrCacheClosed)
+	})
+}
@@ -0,0 +1,136 @@
+package go_cache
+
+import (
+	"cmp"
+	"container/heap"
+	"slices"
+	"sync"
+)
+
+// KeyCount A key and the number of times it was missed, as reported by TopMisses.
+type KeyCount struct {
+	Key string
+	// Count Number of misses counted for the key. It may overestimate the actual number by up to Error.
+	Count uint64
+	// Error Number of misses the key inherited when it took the place of the least missed key tracked, 0 if it has
+	// been tracked since its first miss.
+	Error uint64
+}
+
+// missTracker Counts the most missed keys of a cache created WithMissTracking with the space-saving algorithm:
+// at most capacity keys are tracked, and a key missed while none of its counts is tracked replaces the least
+// missed key, inheriting its count.
+type missTracker struct {
+	mu       sync.Mutex
+	capacity int
+	keys     map[string]*missCount
+	// counts is a min-heap of the tracked counts, keeping the least missed key on top.
+	counts missHeap
+}
+
+type missCount struct {
+	KeyCount
+	index int
+}
+
+func newMissTracker(capacity int) *missTracker {
+	return &missTracker{capacity: capacity, keys: make(map[string]*missCount, capacity)}
+}
+
+// record Counts a miss of the key.
+func (t *missTracker) record(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if m, found := t.keys[key]; found {
+		m.Count++
+		heap.Fix(&t.counts, m.index)
+		return
+	}
+	if len(t.counts) < t.capacity {
+		m := &missCount{KeyCount: KeyCount{Key: key, Count: 1}}
+		t.keys[key] = m
+		heap.Push(&t.counts, m)
+		return
+	}
+
+	m := t.counts[0]
+	delete(t.keys, m.Key)
+	m.Key, m.Error = key, m.Count
+	m.Count++
+	t.keys[key] = m
+	heap.Fix(&t.counts, 0)
+}
+
+// top Returns the n most missed keys, most missed first.
+func (t *missTracker) top(n int) []KeyCount {
+	t.mu.Lock()
+	counts := make([]KeyCount, len(t.counts))
+	for i, m := range t.counts {
+		counts[i] = m.KeyCount
+	}
+	t.mu.Unlock()
+
+	slices.SortFunc(counts, func(a, b KeyCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Key, b.Key))
+	})
+
+	return counts[:min(len(counts), max(n, 0))]
+}
+
+func (t *missTracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.keys = make(map[string]*missCount, t.capacity)
+	t.counts = nil
+}
+
+type missHeap []*missCount
+
+func (h missHeap) Len() int           { return len(h) }
+func (h missHeap) Less(i, j int) bool { return h[i].Count < h[j].Count }
+func (h missHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *missHeap) Push(x any) {
+	m := x.(*missCount)
+	m.index = len(*h)
+	*h = append(*h, m)
+}
+func (h *missHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// miss Counts a miss of the key in the stats, and in the tracked misses if the cache was created WithMissTracking.
+func (c *Cache) miss(key string) {
+	c.stats.misses.Add(1)
+	if c.misses != nil {
+		c.misses.record(key)
+	}
+}
+
+// TopMisses Returns the n keys missed the most by the lookups of the cache (Get, GetOrLoad, Fetch and the other
+// reads) since it was created or ResetMisses was last called, most missed first, e.g. to pick the keys to warm the
+// cache up with. Counts are approximate once more keys were missed than the capacity given to WithMissTracking, see
+// KeyCount. Returns nil unless the cache was created WithMissTracking.
+// With hashed keys (see WithHashedKeys), the returned keys are the hashed keys.
+func (c *Cache) TopMisses(n int) []KeyCount {
+	if c.misses == nil {
+		return nil
+	}
+
+	return c.misses.top(n)
+}
+
+// ResetMisses Forgets the misses counted for TopMisses.
+func (c *Cache) ResetMisses() {
+	if c.misses != nil {
+		c.misses.reset()
+	}
+}
@@ -0,0 +1,83 @@
+package go_cache
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_TopMisses(t *testing.T) {
+	t.Run("skewed", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMissTracking(100))
+		defer tc.Stop()
+
+		// A Zipf distribution over 100,000 keys: key0 is missed the most, then key1, and so on.
+		zipf := rand.NewZipf(rand.New(rand.NewPCG(1, 2)), 1.2, 1, 99_999)
+		for i := 0; i < 200_000; i++ {
+			tc.Get(fmt.Sprintf("key%d", zipf.Uint64()))
+			assert.LessOrEqual(t, len(tc.misses.keys), 100)
+			assert.LessOrEqual(t, len(tc.misses.counts), 100)
+		}
+
+		top := tc.TopMisses(5)
+		assert.Len(t, top, 5)
+		for i, kc := range top {
+			assert.Equal(t, fmt.Sprintf("key%d", i), kc.Key)
+		}
+		assert.Len(t, tc.TopMisses(1000), 100)
+	})
+
+	t.Run("exactBelowCapacity", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMissTracking(10))
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		for i := 0; i < 3; i++ {
+			tc.Get("bKey")
+			tc.Get("aKey")
+		}
+		tc.Get("cKey")
+		_, _ = tc.GetOrLoad(context.Background(), "cKey", func(ctx context.Context, key string) (any, time.Duration, error) {
+			return nil, 0, ErrItemNotFound
+		})
+
+		assert.Equal(t, []KeyCount{{Key: "bKey", Count: 3}, {Key: "cKey", Count: 2}}, tc.TopMisses(10))
+	})
+
+	t.Run("replacesLeastMissed", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMissTracking(2))
+		defer tc.Stop()
+
+		tc.Get("aKey")
+		tc.Get("aKey")
+		tc.Get("bKey")
+		tc.Get("cKey")
+
+		assert.Equal(t, []KeyCount{{Key: "aKey", Count: 2}, {Key: "cKey", Count: 2, Error: 1}}, tc.TopMisses(10))
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMissTracking(10))
+		defer tc.Stop()
+
+		tc.Get("aKey")
+		tc.ResetMisses()
+		assert.Empty(t, tc.TopMisses(10))
+
+		tc.Get("bKey")
+		assert.Equal(t, []KeyCount{{Key: "bKey", Count: 1}}, tc.TopMisses(10))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Get("aKey")
+		tc.ResetMisses()
+		assert.Nil(t, tc.TopMisses(10))
+	})
+}
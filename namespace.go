@@ -0,0 +1,102 @@
+package go_cache
+
+import "time"
+
+// Namespace A view of the cache in which all keys are transparently prefixed, see Cache.Namespace.
+type Namespace struct {
+	c      *Cache
+	prefix string
+	// defaultExpiration is the default expiration time of the namespace, 0 if it uses the cache's one.
+	defaultExpiration time.Duration
+}
+
+// NamespaceOption Configures a Namespace.
+type NamespaceOption func(*Namespace)
+
+// WithNamespaceDefaultExpiration Sets the expiration time used for the items written with DefaultExpiration
+// through the namespace, instead of the cache's default expiration time.
+// If the duration is -1 (NoExpiration), such items never expire.
+func WithNamespaceDefaultExpiration(d time.Duration) NamespaceOption {
+	return func(n *Namespace) {
+		n.defaultExpiration = d
+	}
+}
+
+// Namespace Returns a view of the cache in which every key is stored under the given prefix, so that
+// components sharing a cache cannot collide. The items remain visible through the cache with their full key.
+func (c *Cache) Namespace(prefix string, opts ...NamespaceOption) *Namespace {
+	n := &Namespace{c: c, prefix: prefix}
+	for _, opt := range opts {
+		opt(n)
+	}
+	c.namespaces.Store(prefix, struct{}{})
+
+	return n
+}
+
+// Namespace Returns a view nested in the namespace, whose keys are prefixed with both prefixes.
+// The nested namespace inherits the default expiration time of its parent unless given its own.
+func (n *Namespace) Namespace(prefix string, opts ...NamespaceOption) *Namespace {
+	nested := &Namespace{c: n.c, prefix: n.prefix + prefix, defaultExpiration: n.defaultExpiration}
+	for _, opt := range opts {
+		opt(nested)
+	}
+	n.c.namespaces.Store(nested.prefix, struct{}{})
+
+	return nested
+}
+
+// Prefix Returns the prefix added to the keys of the namespace.
+func (n *Namespace) Prefix() string {
+	return n.prefix
+}
+
+// DefaultExpirationValue Returns the default expiration time of the namespace, that of the cache if it has none.
+func (n *Namespace) DefaultExpirationValue() time.Duration {
+	if n.defaultExpiration != DefaultExpiration {
+		return n.defaultExpiration
+	}
+
+	return n.c.DefaultExpirationValue()
+}
+
+// Set Adds an item to the namespace like Cache.Set. If the duration is 0 (DefaultExpiration),
+// the namespace's default expiration time is used.
+func (n *Namespace) Set(key string, object any, duration time.Duration) {
+	n.c.Set(n.prefix+key, object, n.duration(object, duration))
+}
+
+// Add Inserts an item to the namespace like Cache.Add. If the duration is 0 (DefaultExpiration),
+// the namespace's default expiration time is used.
+func (n *Namespace) Add(key string, object any, duration time.Duration) error {
+	return n.c.Add(n.prefix+key, object, n.duration(object, duration))
+}
+
+// Replace Sets a new value for an item of the namespace like Cache.Replace. If the duration is 0
+// (DefaultExpiration), the namespace's default expiration time is used.
+func (n *Namespace) Replace(key string, object any, duration time.Duration) error {
+	return n.c.Replace(n.prefix+key, object, n.duration(object, duration))
+}
+
+// Get Looks up a key's value from the namespace like Cache.Get.
+func (n *Namespace) Get(key string) (any, bool) {
+	return n.c.Get(n.prefix + key)
+}
+
+// Delete Removes the provided key from the namespace like Cache.Delete.
+func (n *Namespace) Delete(key string) {
+	n.c.Delete(n.prefix + key)
+}
+
+// duration Returns the duration to write the object with through the cache, resolving DefaultExpiration
+// to the namespace's default expiration time unless the object reports its own TTL (see Expirer).
+func (n *Namespace) duration(object any, d time.Duration) time.Duration {
+	if d != DefaultExpiration {
+		return d
+	}
+	if _, ok := object.(Expirer); ok {
+		return DefaultExpiration
+	}
+
+	return n.defaultExpiration
+}
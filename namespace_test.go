@@ -0,0 +1,128 @@
+package go_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_Namespace(t *testing.T) {
+	t.Run("prefixesKeys", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		sessions := tc.Namespace("sessions:")
+		dns := tc.Namespace("dns:")
+
+		sessions.Set("aKey", "aSession", DefaultExpiration)
+		dns.Set("aKey", "aRecord", DefaultExpiration)
+
+		value, found := sessions.Get("aKey")
+		assert.True(t, found)
+		assert.Equal(t, "aSession", value)
+
+		value, found = tc.Get("dns:aKey")
+		assert.True(t, found)
+		assert.Equal(t, "aRecord", value)
+
+		_, found = tc.Get("aKey")
+		assert.False(t, found)
+
+		assert.ErrorIs(t, sessions.Add("aKey", "other", DefaultExpiration), ErrItemAlreadyExists)
+		assert.ErrorIs(t, dns.Replace("bKey", "other", DefaultExpiration), ErrItemNotFound)
+
+		sessions.Delete("aKey")
+		_, found = sessions.Get("aKey")
+		assert.False(t, found)
+		_, found = dns.Get("aKey")
+		assert.True(t, found)
+	})
+
+	t.Run("defaultExpiration", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(10*time.Minute, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		sessions := tc.Namespace("sessions:", WithNamespaceDefaultExpiration(30*time.Minute))
+		dns := tc.Namespace("dns:", WithNamespaceDefaultExpiration(60*time.Second))
+		other := tc.Namespace("other:")
+
+		assert.Equal(t, 30*time.Minute, sessions.DefaultExpirationValue())
+		assert.Equal(t, 10*time.Minute, other.DefaultExpirationValue())
+
+		sessions.Set("aKey", "aValue", DefaultExpiration)
+		dns.Set("aKey", "aValue", DefaultExpiration)
+		other.Set("aKey", "aValue", DefaultExpiration)
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		sessions.Set("bKey", "bValue", 1*time.Second)
+
+		clock.Advance(2 * time.Minute)
+		_, found := dns.Get("aKey")
+		assert.False(t, found)
+		_, found = sessions.Get("bKey")
+		assert.False(t, found)
+
+		clock.Advance(10 * time.Minute)
+		_, found = other.Get("aKey")
+		assert.False(t, found)
+		_, found = tc.Get("aKey")
+		assert.False(t, found)
+		_, found = sessions.Get("aKey")
+		assert.True(t, found)
+
+		clock.Advance(20 * time.Minute)
+		_, found = sessions.Get("aKey")
+		assert.False(t, found)
+
+		assert.Equal(t, 10*time.Minute, tc.DefaultExpirationValue())
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(10*time.Minute, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		app := tc.Namespace("app:", WithNamespaceDefaultExpiration(1*time.Hour))
+		inherited := app.Namespace("users:")
+		overridden := app.Namespace("tokens:", WithNamespaceDefaultExpiration(1*time.Minute))
+		plain := tc.Namespace("plain:").Namespace("nested:")
+
+		assert.Equal(t, "app:users:", inherited.Prefix())
+		assert.Equal(t, 1*time.Hour, inherited.DefaultExpirationValue())
+		assert.Equal(t, 1*time.Minute, overridden.DefaultExpirationValue())
+		assert.Equal(t, 10*time.Minute, plain.DefaultExpirationValue())
+
+		inherited.Set("aKey", "aValue", DefaultExpiration)
+		overridden.Set("aKey", "aValue", DefaultExpiration)
+		plain.Set("aKey", "aValue", DefaultExpiration)
+
+		_, found := tc.Get("app:users:aKey")
+		assert.True(t, found)
+
+		clock.Advance(2 * time.Minute)
+		_, found = overridden.Get("aKey")
+		assert.False(t, found)
+
+		clock.Advance(10 * time.Minute)
+		_, found = plain.Get("aKey")
+		assert.False(t, found)
+		_, found = inherited.Get("aKey")
+		assert.True(t, found)
+
+		clock.Advance(1 * time.Hour)
+		_, found = inherited.Get("aKey")
+		assert.False(t, found)
+	})
+
+	t.Run("followsCacheDefault", func(t *testing.T) {
+		tc := NewCache(10*time.Minute, 0)
+		defer tc.Stop()
+
+		ns := tc.Namespace("ns:")
+		tc.SetDefaultExpiration(20 * time.Minute)
+		assert.Equal(t, 20*time.Minute, ns.DefaultExpirationValue())
+	})
+}
@@ -0,0 +1,65 @@
+package go_cache
+
+import "time"
+
+// LookupState Describes the outcome of a Lookup.
+type LookupState int
+
+const (
+	// LookupMiss The key is not in the cache, has expired or is not visible.
+	LookupMiss LookupState = iota
+	// LookupHit The key is in the cache with a value.
+	LookupHit
+	// LookupNegativeHit The key is in the cache as a negative entry, i.e. it is known not to exist upstream.
+	LookupNegativeHit
+)
+
+// SetNegative Records in the cache that the given key does not exist upstream, so that repeated lookups
+// for it can be answered without hitting the upstream source. Get misses on a negative entry, while
+// Lookup reports it as LookupNegativeHit. A later Set or Add of the key replaces the negative entry.
+// If the duration is 0 (DefaultExpiration), the cache's default expiration time is used.
+// If it is -1 (NoExpiration), the entry never expires.
+// If the duration is positive, the entry expires after that time has passed.
+func (c *Cache) SetNegative(key string, duration time.Duration) {
+	key = c.hashKey(key)
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	c.setNegative(key, duration)
+}
+
+// setNegativeUnlessLive Records that the given key does not exist upstream like SetNegative, unless it holds a live
+// value, e.g. written while the loader was called, or kept by Refresh.
+func (c *Cache) setNegativeUnlessLive(key string, duration time.Duration) {
+	key = c.hashKey(key)
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	if it, found := c.items[key]; found && !it.placeholder && !it.negative && !it.isExpired(c.now().UnixNano()) {
+		return
+	}
+	c.setNegative(key, duration)
+}
+
+func (c *Cache) setNegative(key string, duration time.Duration) {
+	item := c.newItem(key, nil, duration)
+	item.negative = true
+	c.insert(key, item)
+}
+
+// Lookup Looks up a key's value from the cache like Get, telling apart a hit, a negative hit and a miss.
+func (c *Cache) Lookup(key string) (any, LookupState) {
+	key = c.hashKey(key)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	item, state := c.lookup(key, c.now().UnixNano())
+	if state != LookupHit {
+		return nil, state
+	}
+
+	return c.value(key, item), state
+}
@@ -0,0 +1,76 @@
+package go_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_SetNegative(t *testing.T) {
+	t.Run("lookupStates", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		tc.SetNegative("bKey", DefaultExpiration)
+
+		a, state := tc.Lookup("aKey")
+		assert.Equal(t, "aValue", a)
+		assert.Equal(t, LookupHit, state)
+
+		b, state := tc.Lookup("bKey")
+		assert.Nil(t, b)
+		assert.Equal(t, LookupNegativeHit, state)
+
+		c, state := tc.Lookup("cKey")
+		assert.Nil(t, c)
+		assert.Equal(t, LookupMiss, state)
+
+		b, found := tc.Get("bKey")
+		assert.Nil(t, b)
+		assert.False(t, found)
+
+		stats := tc.Stats()
+		assert.Equal(t, uint64(1), stats.Hits)
+		assert.Equal(t, uint64(1), stats.Misses)
+		assert.Equal(t, uint64(2), stats.NegativeHits)
+	})
+
+	t.Run("promotionToPositive", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.SetNegative("aKey", DefaultExpiration)
+		tc.SetNegative("bKey", DefaultExpiration)
+
+		err := tc.Replace("aKey", "aValue", DefaultExpiration)
+		assert.ErrorIs(t, err, ErrItemNotFound)
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+
+		err = tc.Add("bKey", "bValue", DefaultExpiration)
+		assert.Nil(t, err)
+
+		a, state := tc.Lookup("aKey")
+		assert.Equal(t, "aValue", a)
+		assert.Equal(t, LookupHit, state)
+
+		b, state := tc.Lookup("bKey")
+		assert.Equal(t, "bValue", b)
+		assert.Equal(t, LookupHit, state)
+	})
+
+	t.Run("withExpirationTime", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.SetNegative("aKey", 20*time.Millisecond)
+
+		<-time.After(25 * time.Millisecond)
+
+		a, state := tc.Lookup("aKey")
+		assert.Nil(t, a)
+		assert.Equal(t, LookupMiss, state)
+	})
+}
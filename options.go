@@ -0,0 +1,592 @@
+package go_cache
+
+import "time"
+
+// Option Configures a cache created with NewCacheWithOptions.
+type Option func(*options)
+
+type options struct {
+	defaultExpiration time.Duration
+	cleanupInterval   time.Duration
+	compactRatio      float64
+	maxLifetime       time.Duration
+	loader            Loader
+	negativeTTL       time.Duration
+	initialItems      map[string]InitialItem
+	sizer             Sizer
+	hashedKeys        bool
+	insertionOrder    bool
+	resetOnOverwrite  bool
+	sortedKeys        bool
+	accessTracking    bool
+	closeOnEvict      bool
+	errorHandler      func(err error)
+	codec             Codec
+	snapshotStore     SnapshotStore
+	snapshotInterval  time.Duration
+	snapshotRetention int
+	maxItems          int
+	maxCost           int64
+	onFull            func(pressure CapacityPressure)
+	name              string
+	initialCapacity   int
+	presizeOnFlush    bool
+	retainExpired     bool
+	tombstoneTTL      time.Duration
+	evictionPolicy    EvictionPolicy
+	latencySampleRate float64
+	writeCoalescing   time.Duration
+
+	bloomExpectedItems int
+	bloomFPRate        float64
+	missTracking       int
+	flushInterval      time.Duration
+	alignedFlush       bool
+	onFlush            func(c *Cache)
+
+	compressionThreshold int
+	compressionCodec     CompressionCodec
+	spillDir             string
+	spillThreshold       int
+	maxValueSize         int64
+	namespaceQuotas      map[string]int
+	namespaceBudgets     map[string]int64
+	errorTTL             time.Duration
+	maxErrorTTL          time.Duration
+	breakerThreshold     float64
+	breakerWindow        time.Duration
+	breakerCooldown      time.Duration
+	retryAttempts        int
+	retryBackoff         func(attempt int) time.Duration
+	retryable            func(err error) bool
+	loadTimeout          time.Duration
+	changeTracking       int
+	indexes              []indexSpec
+	weakValues           bool
+	onCleanup            func(c *Cache, pass CleanupPass)
+	maxConcurrentLoads   int
+	onEvicted            func(key string, value any)
+	overwriteEvictions   bool
+}
+
+// InitialItem An item to populate the cache with at construction, see WithInitialItemsDurations.
+type InitialItem struct {
+	Object   any
+	Duration time.Duration
+}
+
+// WithDefaultExpiration Sets the default expiration duration of the cache, see NewCache.
+func WithDefaultExpiration(d time.Duration) Option {
+	return func(o *options) {
+		o.defaultExpiration = d
+	}
+}
+
+// WithCleanupInterval Sets the interval at which expired items are deleted from the cache, see NewCache.
+func WithCleanupInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.cleanupInterval = d
+	}
+}
+
+// WithAutoCompact Makes the cleanup goroutine call Compact after each pass in which the number of items
+// dropped below the given ratio (between 0 and 1) of the highest number of items ever held by the cache.
+func WithAutoCompact(ratio float64) Option {
+	return func(o *options) {
+		o.compactRatio = ratio
+	}
+}
+
+// WithLoader Sets the loader used by Fetch to load keys missing from the cache.
+func WithLoader(loader Loader) Option {
+	return func(o *options) {
+		o.loader = loader
+	}
+}
+
+// WithNegativeTTL Makes GetOrLoad and Fetch store a negative entry for the given duration when the loader
+// reports that a key does not exist by returning ErrItemNotFound, see SetNegative.
+func WithNegativeTTL(d time.Duration) Option {
+	return func(o *options) {
+		o.negativeTTL = d
+	}
+}
+
+// WithInitialItems Populates the cache with the given items, all stored with the given duration as if passed to Set,
+// before NewCacheWithOptions returns. The cache is thus fully populated before anyone can access it.
+func WithInitialItems(items map[string]any, duration time.Duration) Option {
+	return func(o *options) {
+		if o.initialItems == nil {
+			o.initialItems = make(map[string]InitialItem, len(items))
+		}
+		for key, object := range items {
+			o.initialItems[key] = InitialItem{Object: object, Duration: duration}
+		}
+	}
+}
+
+// WithInitialItemsDurations Populates the cache with the given items like WithInitialItems,
+// each one being stored with its own duration.
+func WithInitialItemsDurations(items map[string]InitialItem) Option {
+	return func(o *options) {
+		if o.initialItems == nil {
+			o.initialItems = make(map[string]InitialItem, len(items))
+		}
+		for key, item := range items {
+			o.initialItems[key] = item
+		}
+	}
+}
+
+// WithSizer Sets the function used to estimate the size of the values stored in the cache, e.g. by LargestItems.
+func WithSizer(sizer Sizer) Option {
+	return func(o *options) {
+		o.sizer = sizer
+	}
+}
+
+// WithHashedKeys Makes the cache store items under a fixed-size hash of their key instead of the key itself,
+// which saves memory when keys are long, see keyHasher for the trade-offs involved.
+// Keys are then not available: Keys returns ErrHashedKeys error, and reports such as LargestItems
+// and errors mentioning a key show its hash instead.
+func WithHashedKeys() Option {
+	return func(o *options) {
+		o.hashedKeys = true
+	}
+}
+
+// WithInsertionOrder Makes the cache keep track of the order in which items were inserted, see InOrder.
+// If resetOnOverwrite is true, overwriting an item moves it to the end as if it was inserted anew,
+// otherwise it keeps its original position. Writing an expired item always moves it to the end.
+func WithInsertionOrder(resetOnOverwrite bool) Option {
+	return func(o *options) {
+		o.insertionOrder = true
+		o.resetOnOverwrite = resetOnOverwrite
+	}
+}
+
+// WithSortedKeys Makes the cache maintain an ordered index of its keys, enabling RangeKeys and AscendPrefix,
+// at the cost of O(log n) extra work when a key is inserted or deleted. It has no effect together with
+// WithHashedKeys, since keys are not stored then.
+func WithSortedKeys() Option {
+	return func(o *options) {
+		o.sortedKeys = true
+	}
+}
+
+// WithAccessTracking Makes the cache record the time at which each item was last read, see IdleLongerThan.
+func WithAccessTracking() Option {
+	return func(o *options) {
+		o.accessTracking = true
+	}
+}
+
+// WithMaxItems Caps the number of items held by the cache, expired ones included until they are deleted.
+// Once the cap is reached, each write of a new key evicts the oldest written item, writing a key again making it
+// the newest, unless another policy is set WithEvictionPolicy. Evicted items are removed like by Delete, and counted in Stats. See SetMaxItems to change the cap.
+func WithMaxItems(n int) Option {
+	return func(o *options) {
+		o.maxItems = n
+	}
+}
+
+// WithMaxCost Caps the total estimated size of the items held by the cache, as computed by the sizer of the cache
+// (see WithSizer) when they are written, or given WithItemCost. Items are evicted like WithMaxItems to stay within
+// the cap, and an item larger than the cap on its own is evicted as soon as it is written. See SetMaxCost to change
+// the cap.
+func WithMaxCost(cost int64) Option {
+	return func(o *options) {
+		o.maxCost = cost
+	}
+}
+
+// WithOnFull Sets a function called when a write makes the cache evict items to stay within its capacity limits
+// (see WithMaxItems and WithMaxCost), e.g. to shed load. It is called at most once per second, after the cache lock
+// is released, with the current usage of the cache and the evictions since the previous call.
+func WithOnFull(fn func(pressure CapacityPressure)) Option {
+	return func(o *options) {
+		o.onFull = fn
+	}
+}
+
+// WithMaxLifetime Caps the time an item can be kept alive by Touch and GetAndTouch to the given duration
+// from when its value was written. Extensions past that bound are clamped to it.
+func WithMaxLifetime(d time.Duration) Option {
+	return func(o *options) {
+		o.maxLifetime = d
+	}
+}
+
+// WithCloseOnEvict Makes the cache release the values removed from it which implement Evictable, by calling
+// OnEvict, or io.Closer, by calling Close, errors being reported to the handler set WithErrorHandler.
+// This happens once per removal, after the cache lock is released, whenever a value leaves the cache: when it is
+// deleted, expires and is cleaned up, is overwritten, or is flushed, except by FlushAndReturn whose caller takes
+// over the values. A value is only released once it is no longer in the cache, but it may have been returned by
+// a Get shortly before: callers holding on to such values must tolerate them being closed under them.
+func WithCloseOnEvict() Option {
+	return func(o *options) {
+		o.closeOnEvict = true
+	}
+}
+
+// WithErrorHandler Sets the function to which the cache reports the errors of its background work,
+// which cannot be returned to a caller.
+func WithErrorHandler(handler func(err error)) Option {
+	return func(o *options) {
+		o.errorHandler = handler
+	}
+}
+
+// WithCodec Sets the codec used to encode the values of the cache into snapshots, GobCodec by default.
+func WithCodec(codec Codec) Option {
+	return func(o *options) {
+		o.codec = codec
+	}
+}
+
+// WithAutoSnapshot Makes the cache save a snapshot to the given store at the given interval until it is stopped,
+// see SaveSnapshot. Failures are reported to the handler set WithErrorHandler and by Health.
+// Use RestoreLatest to load the most recent snapshot back when starting up.
+func WithAutoSnapshot(store SnapshotStore, interval time.Duration) Option {
+	return func(o *options) {
+		o.snapshotStore = store
+		o.snapshotInterval = interval
+	}
+}
+
+// WithSnapshotRetention Makes SaveSnapshot, and thus WithAutoSnapshot, keep only the given number of most recent
+// snapshots in the store, deleting older ones after each save.
+func WithSnapshotRetention(keep int) Option {
+	return func(o *options) {
+		o.snapshotRetention = keep
+	}
+}
+
+// WithName Gives the cache a name, e.g. to place it on the ring of a Router.
+func WithName(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// WithInitialCapacity Allocates room for the given number of items when the cache is created,
+// so that filling it up to that number does not grow its internal map step by step.
+func WithInitialCapacity(n int) Option {
+	return func(o *options) {
+		o.initialCapacity = n
+	}
+}
+
+// WithPresizeOnFlush Makes Flush and FlushAndReturn allocate room for as many items as the cache held before
+// flushing, or for the capacity set WithInitialCapacity if larger, so that refilling the cache does not grow its
+// internal map step by step. This keeps the memory of the map allocated after a flush.
+func WithPresizeOnFlush() Option {
+	return func(o *options) {
+		o.presizeOnFlush = true
+	}
+}
+
+// WithRetainExpired Makes the cleanup goroutine keep expired values instead of deleting them, so that they remain
+// available to GetStale. They are still misses for Get, and are counted as Expired by Counts. They are removed by
+// an explicit Delete, DeleteExpired or Flush, when overwritten, or by capacity eviction (see WithMaxItems), for
+// which the cleanup goroutine makes them the first candidates. Negative entries are deleted as usual.
+func WithRetainExpired() Option {
+	return func(o *options) {
+		o.retainExpired = true
+	}
+}
+
+// WithTombstones Makes Delete and DeleteMany remember the deleted keys for the given duration, so that a write
+// racing with the deletion does not resurrect the item: during that window, Add fails with ErrRecentlyDeleted and
+// SetIfNewer rejects versions older than the deletion. Set and the other unconditional writes still go through,
+// clearing the tombstone. Tombstones are not items: they are invisible to Get, Keys and ItemCount, and are purged
+// by the cleanup goroutine or DeleteExpired once the window has passed.
+func WithTombstones(d time.Duration) Option {
+	return func(o *options) {
+		o.tombstoneTTL = d
+	}
+}
+
+// WithEvictionPolicy Sets which items are evicted to stay within the capacity limits of the cache (see WithMaxItems
+// and WithMaxCost), NewFIFOPolicy by default. The package provides NewLRUPolicy, NewRandomPolicy and NewClockPolicy,
+// and any implementation of EvictionPolicy can be given. It is only used once a limit is set.
+func WithEvictionPolicy(p EvictionPolicy) Option {
+	return func(o *options) {
+		o.evictionPolicy = p
+	}
+}
+
+// WithLatencyTracking Makes the cache time the given share (between 0 and 1) of its calls to Get, Set and
+// GetOrLoad, waiting for the lock included, into histograms returned by LatencyStats. Sampling bounds the overhead
+// of timing on hot paths; a rate of 0, the default, disables tracking entirely.
+func WithLatencyTracking(sampleRate float64) Option {
+	return func(o *options) {
+		o.latencySampleRate = sampleRate
+	}
+}
+
+// WithWriteCoalescing Merges the watch events of the writes to a key within the given window: the value in the
+// cache is updated right away, but once an event was sent for a key, the events of the writes which follow within
+// the window are held back, and only the latest one is sent at the end of the window. A removal of the key sends
+// the event held back first, and Flush and Stop send all of them. Values overwritten are still released one by one
+// WithCloseOnEvict, and passed one by one to the function set WithOnEvicted WithOverwriteEvictions.
+func WithWriteCoalescing(window time.Duration) Option {
+	return func(o *options) {
+		o.writeCoalescing = window
+	}
+}
+
+// WithBloomFilter Puts a bloom filter sized for the given number of keys and false-positive rate (e.g. 0.01) in front
+// of the cache, to answer the lookups of keys never written to it without taking the lock: Get misses, and GetOrLoad,
+// Fetch and GetOrLoadMany report the key as not found without calling the loader, as if the keys which exist
+// upstream were all written to the cache (e.g. warmed up) beforehand. This cheaply fends off floods of lookups of
+// keys which do not exist.
+//
+// The filter only has false positives: a key ever written (Set, Add and the other writes, pending values
+// included) is always looked up as usual, while a few other keys, at about the given rate, are too. Since keys
+// cannot be removed from a bloom filter, deleted and expired keys stay in it until it is rebuilt from the keys in
+// the cache, which the cleanup goroutine does once more keys were added than it was sized for (see
+// RebuildBloomFilter). A key dropped by a rebuild is then treated like a key never written. Flush empties the filter.
+func WithBloomFilter(expectedItems int, fpRate float64) Option {
+	return func(o *options) {
+		o.bloomExpectedItems = expectedItems
+		o.bloomFPRate = fpRate
+	}
+}
+
+// WithMissTracking Makes the cache count the misses of its lookups by key, to report the most missed keys with
+// TopMisses. At most the given number of keys are tracked, whatever the number of keys missed: a key missed while
+// the tracked keys are at capacity replaces the least missed one, so the counts of the most missed keys are
+// accurate as long as the capacity is a few times the number of keys looked at.
+func WithMissTracking(capacity int) Option {
+	return func(o *options) {
+		o.missTracking = capacity
+	}
+}
+
+// WithPeriodicFlush Makes the cleanup goroutine flush the whole cache every interval, e.g. for a cache mirroring a
+// dataset republished wholesale, and then call onFlush, if not nil, without holding any lock so that it can
+// repopulate the cache. The flush is made by the first run of the cleanup goroutine after each boundary, so it needs
+// a cleanup interval (see WithCleanupInterval), which bounds how late it can be. Boundaries are counted from the
+// start of the cleanup goroutine, or aligned to the wall clock WithAlignedFlush. onFlush runs on the cleanup
+// goroutine: it must not call Stop or SetCleanupInterval, and it is not called once the cache is stopped.
+func WithPeriodicFlush(interval time.Duration, onFlush func(c *Cache)) Option {
+	return func(o *options) {
+		o.flushInterval = interval
+		o.onFlush = onFlush
+	}
+}
+
+// WithAlignedFlush Aligns the boundaries of the flushes set WithPeriodicFlush to the multiples of the interval since
+// midnight UTC, e.g. :00, :15, :30 and :45 for an interval of 15 minutes.
+func WithAlignedFlush() Option {
+	return func(o *options) {
+		o.alignedFlush = true
+	}
+}
+
+// WithCompression Makes the cache store the byte slice values longer than threshold bytes compressed with the given
+// codec, GzipCompression if nil, and decompress them whenever they are read, so that readers get back what was
+// written. A value is kept as is if compressing it does not save room. Values of other types are never compressed,
+// since the cache does not serialize values. The sizer sees the compressed values, so that the cost of an item (see
+// WithMaxCost) is the room it actually takes; the savings are counted in Stats.Compression. Each read of a
+// compressed value decompresses it anew, which costs far more than a lookup: it is meant for large values.
+func WithCompression(threshold int, codec CompressionCodec) Option {
+	return func(o *options) {
+		if codec == nil {
+			codec = GzipCompression{}
+		}
+		o.compressionThreshold = threshold
+		o.compressionCodec = codec
+	}
+}
+
+// WithSpillover Makes the cache keep the byte slice values longer than threshold bytes in files of the given
+// directory rather than in memory, the item only holding the path of the file: the sizer sees a pointer, so that
+// such values cost next to nothing towards WithMaxCost. Reads load the value back from its file, and GetReader
+// streams it. The file is deleted once the item is removed from the cache, whatever the reason, or overwritten.
+// With WithCompression, values are compressed before the threshold is checked.
+//
+// The directory must exist and be dedicated to the cache: the files matching spill-* found in it when the cache is
+// created are deleted, as left over by a previous process. The files of the items still held by a cache once
+// stopped are left in place. Writes of values spilled to disk hold the lock of the cache while the file is written,
+// and iterators, which read values without holding it, read the values removed in the meantime as nil.
+func WithSpillover(dir string, threshold int) Option {
+	return func(o *options) {
+		o.spillDir = dir
+		o.spillThreshold = threshold
+	}
+}
+
+// WithMaxValueSize Makes the cache reject the values larger than the given number of bytes, as estimated by the
+// sizer (see WithSizer), before anything is evicted to make room for them. Rejected writes leave the key as it was,
+// and are counted in Stats.OversizedWrites. The writes returning an error (Add, Replace, SetIfVersion, SetIfNewer,
+// SetMany, AddMany, IncrementWithTTL, Tx, Load and LoadDiff) return one wrapping ErrValueTooLarge, while the others,
+// e.g. Set, drop the value silently. With WithSpillover, byte slice values larger than the limit are spilled to disk
+// instead, unless they fit once compressed (see WithCompression).
+func WithMaxValueSize(bytes int64) Option {
+	return func(o *options) {
+		o.maxValueSize = bytes
+	}
+}
+
+// WithNamespaceQuota Limits the number of items held under the given prefix, e.g. that of a namespace (see
+// Cache.Namespace), so that a tenant sharing the cache cannot evict the items of the others. Writing an item over
+// the quota evicts items of the same namespace, picked by a policy of the same kind as the one of the cache (see
+// WithEvictionPolicy; FIFO for policies not provided by the package), instead of items of other namespaces. Each
+// key counts towards the quota of every prefix it starts with, so nested namespaces can have their own quota.
+// Items count until removed, as for WithMaxItems. Quotas can also be set with Namespace.SetQuota. They do not
+// apply to caches created WithHashedKeys, whose keys no longer start with the prefix.
+func WithNamespaceQuota(prefix string, maxItems int) Option {
+	return func(o *options) {
+		if o.namespaceQuotas == nil {
+			o.namespaceQuotas = make(map[string]int)
+		}
+		o.namespaceQuotas[prefix] = maxItems
+	}
+}
+
+// WithNamespaceCostBudget Limits the total cost of the items held under the given prefix, as computed by the sizer
+// of the cache (see WithSizer) when they are written, so that a tenant with large values cannot evict the items of
+// the others. Items over the budget are evicted from the same namespace like WithNamespaceQuota, overwriting an item
+// counting the difference between the cost of its new and previous value. A prefix can have both a quota and a
+// budget. Budgets can also be set with Namespace.SetCostBudget, and Cache.NamespaceUsage reports the usage of each
+// namespace.
+func WithNamespaceCostBudget(prefix string, maxCost int64) Option {
+	return func(o *options) {
+		if o.namespaceBudgets == nil {
+			o.namespaceBudgets = make(map[string]int64)
+		}
+		o.namespaceBudgets[prefix] = maxCost
+	}
+}
+
+// WithErrorCaching Makes GetOrLoad and Fetch remember the failures of the loader for the given duration, returning
+// the error again instead of calling the loader, so that an unavailable upstream source is not hit by every miss.
+// The error is wrapped along with ErrLoadFailureCached, so errors.Is matches both. The duration doubles with each
+// failure in a row for a key, up to maxTTL, and a success resets it. Errors wrapping ErrItemNotFound are not
+// failures (see WithNegativeTTL), nor are those returned once the context of the call is done.
+func WithErrorCaching(ttl time.Duration, maxTTL time.Duration) Option {
+	return func(o *options) {
+		o.errorTTL = ttl
+		o.maxErrorTTL = maxTTL
+	}
+}
+
+// WithLoaderBreaker Makes GetOrLoad and Fetch stop calling the loader once the share of its calls failing within a
+// window of the given duration reaches failureThreshold (between 0 and 1), over at least 5 calls. The breaker is
+// then open for the cooldown duration: loads return an expired value still held under the key, as GetStale would
+// (see WithRetainExpired), or fail fast with an error wrapping ErrBreakerOpen. After the cooldown, the breaker is
+// half-open: a single load probes the loader, closing the breaker if it succeeds and opening it again otherwise.
+// Errors wrapping ErrItemNotFound are not failures, and loads whose context is done are not counted. The breaker is
+// shared by all the keys, and its state is reported by Health and Stats.
+func WithLoaderBreaker(failureThreshold float64, window, cooldown time.Duration) Option {
+	return func(o *options) {
+		o.breakerThreshold = failureThreshold
+		o.breakerWindow = window
+		o.breakerCooldown = cooldown
+	}
+}
+
+// WithLoaderRetry Makes GetOrLoad, Fetch, Refresh, GetOrLoadMany and Prefetch call the loader up to the given
+// number of attempts when it fails with an error for which retryable returns true, waiting backoff(n) after the nth
+// failed attempt. A nil retryable
+// retries all errors but those wrapping ErrItemNotFound or ErrLoaderPanicked, and a nil backoff retries right away.
+// Concurrent loads of a key share the same attempts, so retries do not multiply with callers, and get the error of
+// the last attempt. A caller whose context is done stops waiting and gets ctx.Err() instead, and the attempts stop
+// once the contexts of all the callers are done.
+func WithLoaderRetry(attempts int, backoff func(attempt int) time.Duration, retryable func(error) bool) Option {
+	return func(o *options) {
+		o.retryAttempts = attempts
+		o.retryBackoff = backoff
+		o.retryable = retryable
+	}
+}
+
+// WithLoadTimeout Bounds the time the loader is given by GetOrLoad and Fetch, whose context is otherwise only
+// cancelled once all the callers waiting for it gave up, since it does not follow the context of any single caller.
+// A load timing out counts as a failure for WithErrorCaching and WithLoaderBreaker, and bounds the attempts made
+// WithLoaderRetry.
+func WithLoadTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.loadTimeout = d
+	}
+}
+
+// WithChangeTracking Makes the cache number its changes, so that SaveDiff can write only the changes since a
+// previous snapshot or diff. Each write gives the item a new sequence number, while removals are recorded in a log
+// of the given number of most recent ones, beyond which SaveDiff needs an older base: it then returns ErrDiffTooOld.
+func WithChangeTracking(maxDeletions int) Option {
+	return func(o *options) {
+		o.changeTracking = maxDeletions
+	}
+}
+
+// WithIndex Maintains an index of the given name, mapping index keys to the keys of the items, so that GetByIndex and
+// DeleteByIndex find the items by an attribute of their value without scanning the cache, e.g. all the items of a
+// tenant. extract is called on every write with the key and the new value, and returns the index key of the item, or
+// false to leave it out of the index. It is called with the write lock held, so it must be cheap and must never
+// call back into the cache. Removed items leave the index, and expired ones are skipped until they are removed.
+// Values staged with SetVisibleAt are indexed once the cleanup makes them visible. The option can be given several
+// times for different indexes, the last one winning for a same name.
+func WithIndex(name string, extract func(key string, value any) (indexKey string, ok bool)) Option {
+	return func(o *options) {
+		o.indexes = append(o.indexes, indexSpec{name: name, extract: extract})
+	}
+}
+
+// WithWeakValues Makes the cache hold pointer values weakly, so that the garbage collector can reclaim a value once
+// nothing else in the program references it, e.g. large values already owned elsewhere. A reclaimed value is then
+// treated like an expired one: lookups miss, and the cleanup goroutine or DeleteExpired deletes its item, counted as
+// expired. When this happens depends entirely on the garbage collector: a value without other references may still
+// be returned for an unpredictable time, or be gone by the next lookup, so that a value must never be cached only
+// in the cache. Small values may outlive their last reference much longer, the runtime batching their allocations.
+// Values which are not pointers (including nil pointers and pointers to values of size zero), counters (see Counter)
+// and values staged with SetVisibleAt are held as usual.
+func WithWeakValues() Option {
+	return func(o *options) {
+		o.weakValues = true
+	}
+}
+
+// WithCleanupHook Makes the cleanup goroutine call fn after each of its passes, with how long the pass took and how
+// many items it scanned and removed, e.g. to export them as metrics and tune the cleanup interval. fn runs on the
+// cleanup goroutine without holding any lock: it must not call Stop or SetCleanupInterval, and it is not called once
+// the cache is stopped. The passes are recorded in Stats.Cleanup as well.
+func WithCleanupHook(fn func(c *Cache, pass CleanupPass)) Option {
+	return func(o *options) {
+		o.onCleanup = fn
+	}
+}
+
+// WithMaxConcurrentLoads Bounds the number of loaders running at once across all keys to n, e.g. so that a burst of
+// misses on distinct keys does not overload the database behind the loader. Loads beyond n wait for a slot, until
+// the contexts of all their callers are done; each attempt of a retried load (see WithLoaderRetry) and each call of
+// a batch loader (see GetOrLoadMany) takes a slot of its own, and no slot is held while waiting for the loads of
+// other calls. The loads running and waiting show in Stats.Loads.
+func WithMaxConcurrentLoads(n int) Option {
+	return func(o *options) {
+		o.maxConcurrentLoads = n
+	}
+}
+
+// WithOnEvicted Sets a function called with the key and the value of each item removed from the cache, e.g. to
+// release the resources the value holds: when it is deleted, expires and is cleaned up, is evicted, or is flushed,
+// except by FlushAndReturn whose caller takes over the values. Overwrites only call it WithOverwriteEvictions.
+// fn is called once per removal after the cache lock is released, so it can call back into the cache, by the
+// goroutine which removed the item, e.g. the cleanup goroutine for expired items, on which it must not call Stop or
+// SetCleanupInterval. With hashed keys (see WithHashedKeys), fn gets the hashed key. See SetOnEvicted to change it.
+func WithOnEvicted(fn func(key string, value any)) Option {
+	return func(o *options) {
+		o.onEvicted = fn
+	}
+}
+
+// WithOverwriteEvictions Makes the function set WithOnEvicted be called with the previous value of the items
+// overwritten by a write, e.g. Set or Replace, as well as with the removed ones. It is called for every overwrite,
+// even WithWriteCoalescing, which only merges watch events.
+func WithOverwriteEvictions() Option {
+	return func(o *options) {
+		o.overwriteEvictions = true
+	}
+}
@@ -0,0 +1,158 @@
+package go_cache
+
+import (
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_WithInitialItems(t *testing.T) {
+	t.Run("withSameDuration", func(t *testing.T) {
+		tc := NewCacheWithOptions(
+			WithCleanupInterval(1*time.Millisecond),
+			WithInitialItems(map[string]any{
+				"aKey": "aValue",
+				"bKey": 1,
+				"cKey": "cValue",
+			}, NoExpiration),
+		)
+		defer tc.Stop()
+
+		ic := tc.ItemCount()
+		assert.Equal(t, 3, ic)
+
+		a, found := tc.Get("aKey")
+		assert.Equal(t, "aValue", a)
+		assert.True(t, found)
+
+		b, found := tc.Get("bKey")
+		assert.Equal(t, 1, b)
+		assert.True(t, found)
+
+		c, found := tc.Get("cKey")
+		assert.Equal(t, "cValue", c)
+		assert.True(t, found)
+	})
+
+	t.Run("withPerItemDurations", func(t *testing.T) {
+		tc := NewCacheWithOptions(
+			WithDefaultExpiration(20*time.Millisecond),
+			WithInitialItemsDurations(map[string]InitialItem{
+				"aKey": {Object: "aValue", Duration: DefaultExpiration},
+				"bKey": {Object: "bValue", Duration: NoExpiration},
+				"cKey": {Object: "cValue", Duration: 50 * time.Millisecond},
+			}),
+		)
+		defer tc.Stop()
+
+		ic := tc.ItemCount()
+		assert.Equal(t, 3, ic)
+
+		<-time.After(25 * time.Millisecond)
+
+		a, found := tc.Get("aKey")
+		assert.Nil(t, a)
+		assert.False(t, found)
+
+		b, found := tc.Get("bKey")
+		assert.Equal(t, "bValue", b)
+		assert.True(t, found)
+
+		c, found := tc.Get("cKey")
+		assert.Equal(t, "cValue", c)
+		assert.True(t, found)
+	})
+
+	t.Run("laterOptionWins", func(t *testing.T) {
+		tc := NewCacheWithOptions(
+			WithInitialItems(map[string]any{"aKey": "aValue"}, NoExpiration),
+			WithInitialItems(map[string]any{"aKey": "a2Value"}, NoExpiration),
+		)
+		defer tc.Stop()
+
+		a, version, found := tc.GetWithVersion("aKey")
+		assert.Equal(t, "a2Value", a)
+		assert.Equal(t, uint64(1), version)
+		assert.True(t, found)
+	})
+}
+
+// fillMallocs Returns the number of heap allocations made while writing the given keys to the cache.
+func fillMallocs(tc *Cache, keys []string, value any) uint64 {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	for _, key := range keys {
+		tc.Set(key, value, NoExpiration)
+	}
+	runtime.ReadMemStats(&after)
+
+	return after.Mallocs - before.Mallocs
+}
+
+func TestCache_WithInitialCapacity(t *testing.T) {
+	keys := make([]string, 10_000)
+	for i := range keys {
+		keys[i] = "key" + strconv.Itoa(i)
+	}
+	var value any = 1
+
+	t.Run("create", func(t *testing.T) {
+		plain := NewCache(NoExpiration, 0)
+		defer plain.Stop()
+		presized := NewCacheWithOptions(WithInitialCapacity(len(keys)))
+		defer presized.Stop()
+
+		plainMallocs := fillMallocs(plain, keys, value)
+		presizedMallocs := fillMallocs(presized, keys, value)
+		t.Logf("allocations for %d keys: plain %d, presized %d", len(keys), plainMallocs, presizedMallocs)
+
+		assert.Less(t, presizedMallocs*10, plainMallocs)
+	})
+
+	t.Run("flush", func(t *testing.T) {
+		plain := NewCacheWithOptions(WithInitialCapacity(len(keys)))
+		defer plain.Stop()
+		presized := NewCacheWithOptions(WithInitialCapacity(len(keys)), WithPresizeOnFlush())
+		defer presized.Stop()
+
+		for _, tc := range []*Cache{plain, presized} {
+			fillMallocs(tc, keys, value)
+			tc.Flush()
+			assert.Equal(t, 0, tc.ItemCount())
+		}
+
+		plainMallocs := fillMallocs(plain, keys, value)
+		presizedMallocs := fillMallocs(presized, keys, value)
+		t.Logf("allocations for %d keys after a flush: plain %d, presized %d", len(keys), plainMallocs, presizedMallocs)
+
+		assert.Less(t, presizedMallocs*10, plainMallocs)
+	})
+}
+
+func BenchmarkCache_WarmUp(b *testing.B) {
+	const n = 2_000_000
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = "key" + strconv.Itoa(i)
+	}
+	var value any = 1
+
+	for name, opts := range map[string][]Option{
+		"plain":    nil,
+		"presized": {WithInitialCapacity(n)},
+	} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				tc := NewCacheWithOptions(opts...)
+				for _, key := range keys {
+					tc.Set(key, value, NoExpiration)
+				}
+				tc.Stop()
+			}
+		})
+	}
+}
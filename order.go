@@ -0,0 +1,80 @@
+package go_cache
+
+import (
+	"container/list"
+	"iter"
+)
+
+// insertionOrder Keeps the keys of the cache in the order they were inserted, oldest first.
+type insertionOrder struct {
+	resetOnOverwrite bool
+	keys             *list.List
+	elements         map[string]*list.Element
+}
+
+func newInsertionOrder(resetOnOverwrite bool) *insertionOrder {
+	return &insertionOrder{
+		resetOnOverwrite: resetOnOverwrite,
+		keys:             list.New(),
+		elements:         make(map[string]*list.Element),
+	}
+}
+
+func (o *insertionOrder) inserted(key string, overwrite bool) {
+	if e, found := o.elements[key]; found {
+		if overwrite && !o.resetOnOverwrite {
+			return
+		}
+		o.keys.MoveToBack(e)
+		return
+	}
+	o.elements[key] = o.keys.PushBack(key)
+}
+
+// first Moves the key to the front, making it the oldest.
+func (o *insertionOrder) first(key string) {
+	if e, found := o.elements[key]; found {
+		o.keys.MoveToFront(e)
+	}
+}
+
+func (o *insertionOrder) removed(key string) {
+	if e, found := o.elements[key]; found {
+		o.keys.Remove(e)
+		delete(o.elements, key)
+	}
+}
+
+func (o *insertionOrder) reset() {
+	o.keys.Init()
+	o.elements = make(map[string]*list.Element)
+}
+
+// InOrder Returns an iterator over the keys and values of the items in the cache which have not expired,
+// from the oldest inserted to the newest. The cache must have been created WithInsertionOrder, otherwise
+// nothing is yielded. Like All, the order is snapshotted when the iteration starts and each value is read
+// again right before being yielded, so the cache may be modified from the loop body.
+func (c *Cache) InOrder() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		if c.order == nil {
+			return
+		}
+
+		c.mu.RLock()
+		keys := make([]string, 0, c.order.keys.Len())
+		for e := c.order.keys.Front(); e != nil; e = e.Next() {
+			keys = append(keys, e.Value.(string))
+		}
+		c.mu.RUnlock()
+
+		for _, key := range keys {
+			value, found := c.liveValue(key, true)
+			if !found {
+				continue
+			}
+			if !yield(key, value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package go_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func inOrder(tc *Cache) []string {
+	var keys []string
+	for key := range tc.InOrder() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestCache_InOrder(t *testing.T) {
+	t.Run("keepPositionOnOverwrite", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithInsertionOrder(false))
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		tc.Set("bKey", "bValue", DefaultExpiration)
+		tc.Set("cKey", "cValue", DefaultExpiration)
+		tc.Set("aKey", "a2Value", DefaultExpiration)
+		tc.Delete("bKey")
+		tc.Set("dKey", "dValue", DefaultExpiration)
+		tc.Set("bKey", "b2Value", DefaultExpiration)
+
+		assert.Equal(t, []string{"aKey", "cKey", "dKey", "bKey"}, inOrder(tc))
+
+		var values []any
+		for _, value := range tc.InOrder() {
+			values = append(values, value)
+		}
+		assert.Equal(t, []any{"a2Value", "cValue", "dValue", "b2Value"}, values)
+	})
+
+	t.Run("resetPositionOnOverwrite", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithInsertionOrder(true))
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		tc.Set("bKey", "bValue", DefaultExpiration)
+		tc.Set("cKey", "cValue", DefaultExpiration)
+		err := tc.Replace("aKey", "a2Value", DefaultExpiration)
+		assert.Nil(t, err)
+		tc.Delete("cKey")
+		tc.Set("dKey", "dValue", DefaultExpiration)
+
+		assert.Equal(t, []string{"bKey", "aKey", "dKey"}, inOrder(tc))
+	})
+
+	t.Run("withExpiration", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithInsertionOrder(false))
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", 10*time.Second)
+		tc.Set("bKey", "bValue", DefaultExpiration)
+		tc.Set("cKey", "cValue", 10*time.Second)
+
+		clock.Advance(10 * time.Second)
+
+		assert.Equal(t, []string{"bKey"}, inOrder(tc))
+
+		tc.Set("cKey", "c2Value", DefaultExpiration)
+		assert.Equal(t, []string{"bKey", "cKey"}, inOrder(tc))
+
+		tc.DeleteExpired()
+
+		tc.mu.RLock()
+		assert.Equal(t, 2, tc.order.keys.Len())
+		assert.Len(t, tc.order.elements, 2)
+		tc.mu.RUnlock()
+	})
+
+	t.Run("withFlush", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithInsertionOrder(false))
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		tc.Set("bKey", "bValue", DefaultExpiration)
+		tc.Flush()
+		tc.Set("cKey", "cValue", DefaultExpiration)
+
+		assert.Equal(t, []string{"cKey"}, inOrder(tc))
+	})
+
+	t.Run("withoutInsertionOrder", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+
+		assert.Empty(t, inOrder(tc))
+		assert.Nil(t, tc.order)
+	})
+}
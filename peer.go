@@ -0,0 +1,59 @@
+package go_cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PeerFiller Fetches the value of a key missing from the local cache, e.g. from the peer owning the key in a
+// groupcache-style setup, or from the origin.
+type PeerFiller interface {
+	Fill(ctx context.Context, key string) ([]byte, error)
+}
+
+// PeerFillerFunc Adapts a function to the PeerFiller interface.
+type PeerFillerFunc func(ctx context.Context, key string) ([]byte, error)
+
+// Fill Calls f.
+func (f PeerFillerFunc) Fill(ctx context.Context, key string) ([]byte, error) {
+	return f(ctx, key)
+}
+
+// PeerCache Serves []byte values from a local cache, filling missing keys through a PeerFiller.
+type PeerCache struct {
+	c      *Cache
+	filler PeerFiller
+	ttl    time.Duration
+}
+
+// NewPeerCache Returns a PeerCache storing the values filled in the given cache for the given duration,
+// with the same semantics as the duration passed to Set.
+func NewPeerCache(c *Cache, filler PeerFiller, ttl time.Duration) *PeerCache {
+	return &PeerCache{c: c, filler: filler, ttl: ttl}
+}
+
+// Get Returns the value of the key from the local cache, or fills it on a miss. Concurrent calls for the same
+// missing key share a single call to the filler, see GetOrLoad. Errors of the filler are returned as is, and
+// nothing is cached for the key then, unless it reports ErrItemNotFound and the cache was created WithNegativeTTL.
+func (p *PeerCache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := p.c.GetOrLoad(ctx, key, p.load)
+	if err != nil {
+		return nil, err
+	}
+	data, ok := value.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("value of %s is a %T, not []byte", key, value)
+	}
+
+	return data, nil
+}
+
+func (p *PeerCache) load(ctx context.Context, key string) (any, time.Duration, error) {
+	data, err := p.filler.Fill(ctx, key)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return data, p.ttl, nil
+}
@@ -0,0 +1,107 @@
+package go_cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("dedup", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		var fills atomic.Int32
+		release := make(chan struct{})
+		p := NewPeerCache(tc, PeerFillerFunc(func(ctx context.Context, key string) ([]byte, error) {
+			fills.Add(1)
+			<-release
+			return []byte("filled " + key), nil
+		}), time.Minute)
+
+		const callers = 20
+		var wg sync.WaitGroup
+		results := make([][]byte, callers)
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				data, err := p.Get(ctx, "aKey")
+				assert.NoError(t, err)
+				results[i] = data
+			}()
+		}
+		<-time.After(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), fills.Load())
+		for _, data := range results {
+			assert.Equal(t, []byte("filled aKey"), data)
+		}
+
+		data, err := p.Get(ctx, "aKey")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("filled aKey"), data)
+		assert.Equal(t, int32(1), fills.Load())
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		var fills atomic.Int32
+		p := NewPeerCache(tc, PeerFillerFunc(func(ctx context.Context, key string) ([]byte, error) {
+			fills.Add(1)
+			return []byte("aValue"), nil
+		}), time.Minute)
+
+		_, err := p.Get(ctx, "aKey")
+		assert.NoError(t, err)
+		info, found := tc.Info("aKey")
+		assert.True(t, found)
+		assert.True(t, clock.Now().Add(time.Minute).Equal(info.Expiration))
+
+		clock.Advance(time.Minute)
+		_, err = p.Get(ctx, "aKey")
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), fills.Load())
+	})
+
+	t.Run("fillerError", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		errPeer := errors.New("peer unavailable")
+		p := NewPeerCache(tc, PeerFillerFunc(func(ctx context.Context, key string) ([]byte, error) {
+			return nil, errPeer
+		}), time.Minute)
+
+		_, err := p.Get(ctx, "aKey")
+		assert.ErrorIs(t, err, errPeer)
+		_, found := tc.Get("aKey")
+		assert.False(t, found)
+	})
+
+	t.Run("notBytes", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		p := NewPeerCache(tc, PeerFillerFunc(func(ctx context.Context, key string) ([]byte, error) {
+			return nil, nil
+		}), time.Minute)
+
+		_, err := p.Get(ctx, "aKey")
+		assert.Error(t, err)
+	})
+}
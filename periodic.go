@@ -0,0 +1,44 @@
+package go_cache
+
+import "time"
+
+// periodicFlush Flushes a cache created WithPeriodicFlush on schedule.
+type periodicFlush struct {
+	interval time.Duration
+	aligned  bool
+	onFlush  func(c *Cache)
+	// next holds the time of the next flush, 0 until the cleanup goroutine is first started. It is only accessed by
+	// the cleanup goroutine, and before it starts.
+	next int64
+}
+
+// schedule Sets the time of the next flush, the first boundary after now.
+func (p *periodicFlush) schedule(now time.Time) {
+	if p.aligned {
+		p.next = now.Truncate(p.interval).Add(p.interval).UnixNano()
+		return
+	}
+	if p.next == 0 {
+		p.next = now.Add(p.interval).UnixNano()
+	}
+	// Boundaries missed, e.g. while the cleanup goroutine was disabled, are skipped.
+	for p.next <= now.UnixNano() {
+		p.next += int64(p.interval)
+	}
+}
+
+// flushIfDue Flushes the cache and calls the hook if the time of the flush has come, then schedules the next one.
+// It is called by the cleanup goroutine.
+func (c *Cache) flushIfDue() {
+	p := c.periodicFlush
+	now := c.now()
+	if now.UnixNano() < p.next {
+		return
+	}
+
+	c.Flush()
+	p.schedule(now)
+	if p.onFlush != nil && !c.isClosed() {
+		p.onFlush(c)
+	}
+}
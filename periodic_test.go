@@ -0,0 +1,134 @@
+package go_cache
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// startClockedJanitor Drives the cache with the given fake clock, and starts its cleanup goroutine only then so that
+// the clock is in place before the goroutine reads it.
+func startClockedJanitor(tc *Cache, clock *fakeClock) {
+	tc.now = clock.Now
+	tc.SetCleanupInterval(time.Millisecond)
+}
+
+func TestCache_WithPeriodicFlush(t *testing.T) {
+	t.Run("cadence", func(t *testing.T) {
+		var flushes atomic.Int32
+		tc := NewCacheWithOptions(WithPeriodicFlush(15*time.Minute, func(c *Cache) {
+			n := flushes.Add(1)
+			c.Set("aKey", n, NoExpiration)
+		}))
+		defer tc.Stop()
+		clock := newFakeClock()
+		startClockedJanitor(tc, clock)
+		tc.Set("aKey", int32(0), NoExpiration)
+		tc.Set("bKey", "bValue", NoExpiration)
+
+		clock.Advance(14 * time.Minute)
+		<-time.After(20 * time.Millisecond)
+		assert.Equal(t, int32(0), flushes.Load())
+		assert.Equal(t, 2, tc.ItemCount())
+
+		clock.Advance(time.Minute)
+		assert.Eventually(t, func() bool { return flushes.Load() == 1 }, time.Second, time.Millisecond)
+		a, found := tc.Get("aKey")
+		assert.True(t, found)
+		assert.Equal(t, int32(1), a)
+		_, found = tc.Get("bKey")
+		assert.False(t, found)
+		assert.Equal(t, uint64(2), tc.Stats().Removals.Flushed)
+
+		clock.Advance(15 * time.Minute)
+		assert.Eventually(t, func() bool { return flushes.Load() == 2 }, time.Second, time.Millisecond)
+
+		// Boundaries missed are skipped rather than caught up with.
+		clock.Advance(50 * time.Minute)
+		assert.Eventually(t, func() bool { return flushes.Load() == 3 }, time.Second, time.Millisecond)
+		<-time.After(20 * time.Millisecond)
+		assert.Equal(t, int32(3), flushes.Load())
+		clock.Advance(9 * time.Minute)
+		<-time.After(20 * time.Millisecond)
+		assert.Equal(t, int32(3), flushes.Load())
+		clock.Advance(time.Minute)
+		assert.Eventually(t, func() bool { return flushes.Load() == 4 }, time.Second, time.Millisecond)
+	})
+
+	t.Run("aligned", func(t *testing.T) {
+		var flushes atomic.Int32
+		tc := NewCacheWithOptions(WithPeriodicFlush(15*time.Minute, func(c *Cache) {
+			flushes.Add(1)
+		}), WithAlignedFlush())
+		defer tc.Stop()
+		clock := newFakeClock()
+		clock.Advance(7 * time.Minute)
+		startClockedJanitor(tc, clock)
+
+		// Started at 12:07, the first flush is due at 12:15.
+		clock.Advance(7 * time.Minute)
+		<-time.After(20 * time.Millisecond)
+		assert.Equal(t, int32(0), flushes.Load())
+
+		clock.Advance(time.Minute)
+		assert.Eventually(t, func() bool { return flushes.Load() == 1 }, time.Second, time.Millisecond)
+
+		// Flushed late at 12:31, the next one is still due at 12:45.
+		clock.Advance(16 * time.Minute)
+		assert.Eventually(t, func() bool { return flushes.Load() == 2 }, time.Second, time.Millisecond)
+		clock.Advance(13 * time.Minute)
+		<-time.After(20 * time.Millisecond)
+		assert.Equal(t, int32(2), flushes.Load())
+		clock.Advance(time.Minute)
+		assert.Eventually(t, func() bool { return flushes.Load() == 3 }, time.Second, time.Millisecond)
+	})
+
+	t.Run("withoutHook", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithPeriodicFlush(time.Minute, nil))
+		defer tc.Stop()
+		clock := newFakeClock()
+		startClockedJanitor(tc, clock)
+		tc.Set("aKey", "aValue", NoExpiration)
+
+		clock.Advance(time.Minute)
+		assert.Eventually(t, func() bool { return tc.ItemCount() == 0 }, time.Second, time.Millisecond)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var flushes atomic.Int32
+		tc := NewCacheWithOptions(WithPeriodicFlush(time.Minute, func(c *Cache) {
+			if flushes.Add(1) == 1 {
+				close(started)
+				<-release
+			}
+			c.Set("aKey", "aValue", NoExpiration)
+		}))
+		clock := newFakeClock()
+		startClockedJanitor(tc, clock)
+
+		clock.Advance(time.Minute)
+		<-started
+		stopped := make(chan struct{})
+		go func() {
+			tc.Stop()
+			close(stopped)
+		}()
+		<-time.After(20 * time.Millisecond)
+		select {
+		case <-stopped:
+			t.Fatal("Stop returned while the hook was running")
+		default:
+		}
+
+		close(release)
+		<-stopped
+		clock.Advance(time.Minute)
+		<-time.After(20 * time.Millisecond)
+		assert.Equal(t, int32(1), flushes.Load())
+		assert.Equal(t, 0, tc.ItemCount())
+	})
+}
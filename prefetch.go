@@ -0,0 +1,108 @@
+package go_cache
+
+import (
+	"context"
+	"sync"
+)
+
+// PrefetchReport Describes the outcome of a Prefetch.
+type PrefetchReport struct {
+	// Loaded Number of keys successfully loaded.
+	Loaded int
+	// Skipped Number of keys skipped because they were already in the cache or being loaded.
+	Skipped int
+	// Errors Errors returned by the loader, or the context error for keys which were not attempted, by key.
+	Errors map[string]error
+}
+
+// Prefetch Loads the given keys into the cache using the loader the cache was created with, running at most
+// concurrency loads at the same time. Keys already in the cache (including negative entries) or already being
+// loaded by GetOrLoad or Fetch are skipped. A failing key does not abort the others, its error is reported instead.
+// The loads run like those of Fetch, bounded WithLoadTimeout, so that a GetOrLoad joining one does not depend on the
+// context of the prefetch. Once ctx is done, the keys whose load is not complete are reported with the context
+// error, their loads going on for other callers if any, and the keys not yet started are not loaded.
+func (c *Cache) Prefetch(ctx context.Context, keys []string, concurrency int) PrefetchReport {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	report := PrefetchReport{Errors: make(map[string]error)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	skip := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		report.Skipped++
+	}
+	fail := func(key string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		report.Errors[key] = err
+	}
+
+	for _, key := range keys {
+		if c.isPresent(c.hashKey(key)) {
+			skip()
+			continue
+		}
+		if c.loader == nil {
+			fail(key, ErrNoLoader)
+			continue
+		}
+
+		if ctx.Err() != nil {
+			fail(key, ctx.Err())
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			fail(key, ctx.Err())
+			continue
+		case sem <- struct{}{}:
+		}
+
+		call, leader := c.joinLoad(key)
+		if !leader {
+			<-sem
+			skip()
+			continue
+		}
+
+		wg.Add(1)
+		go func(key string, call *loadCall) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			c.startLoad(ctx, []*loadCall{call}, func(loadCtx context.Context) {
+				c.runLoad(loadCtx, key, c.loader, call)
+			})
+			if _, err := c.waitLoad(ctx, key, call); err != nil {
+				fail(key, err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Loaded++
+		}(key, call)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// isPresent Reports whether the given key holds a live value or negative entry.
+func (c *Cache) isPresent(key string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.now().UnixNano()
+	if _, found := c.get(key, now); found {
+		return true
+	}
+	item, found := c.items[key]
+
+	return found && item.negative && !item.isExpired(now)
+}
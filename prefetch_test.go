@@ -0,0 +1,189 @@
+package go_cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_Prefetch(t *testing.T) {
+	t.Run("concurrencyBound", func(t *testing.T) {
+		var current, peak atomic.Int32
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			n := current.Add(1)
+			defer current.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			<-time.After(5 * time.Millisecond)
+			return key + "Value", DefaultExpiration, nil
+		}
+		tc := NewCacheWithOptions(WithLoader(loader))
+		defer tc.Stop()
+
+		keys := make([]string, 20)
+		for i := range keys {
+			keys[i] = strconv.Itoa(i)
+		}
+
+		report := tc.Prefetch(context.Background(), keys, 3)
+		assert.Equal(t, 20, report.Loaded)
+		assert.Equal(t, 0, report.Skipped)
+		assert.Empty(t, report.Errors)
+		assert.LessOrEqual(t, peak.Load(), int32(3))
+
+		v, found := tc.Get("7")
+		assert.Equal(t, "7Value", v)
+		assert.True(t, found)
+	})
+
+	t.Run("skipIfPresent", func(t *testing.T) {
+		var calls atomic.Int32
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			calls.Add(1)
+			return key + "Value", DefaultExpiration, nil
+		}
+		tc := NewCacheWithOptions(WithLoader(loader))
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		tc.SetNegative("bKey", DefaultExpiration)
+
+		report := tc.Prefetch(context.Background(), []string{"aKey", "bKey", "cKey"}, 2)
+		assert.Equal(t, 1, report.Loaded)
+		assert.Equal(t, 2, report.Skipped)
+		assert.Equal(t, int32(1), calls.Load())
+
+		a, found := tc.Get("aKey")
+		assert.Equal(t, "aValue", a)
+		assert.True(t, found)
+	})
+
+	t.Run("skipIfInFlight", func(t *testing.T) {
+		release := make(chan struct{})
+		var calls atomic.Int32
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			calls.Add(1)
+			<-release
+			return key + "Value", DefaultExpiration, nil
+		}
+		tc := NewCacheWithOptions(WithLoader(loader))
+		defer tc.Stop()
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, _ = tc.Fetch(context.Background(), "aKey")
+		}()
+		<-time.After(10 * time.Millisecond)
+
+		report := tc.Prefetch(context.Background(), []string{"aKey"}, 1)
+		assert.Equal(t, 0, report.Loaded)
+		assert.Equal(t, 1, report.Skipped)
+
+		close(release)
+		<-done
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("partialFailure", func(t *testing.T) {
+		errLoad := errors.New("load failed")
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			if key == "bKey" {
+				return nil, 0, errLoad
+			}
+			return key + "Value", DefaultExpiration, nil
+		}
+		tc := NewCacheWithOptions(WithLoader(loader))
+		defer tc.Stop()
+
+		report := tc.Prefetch(context.Background(), []string{"aKey", "bKey", "cKey"}, 2)
+		assert.Equal(t, 2, report.Loaded)
+		assert.Len(t, report.Errors, 1)
+		assert.ErrorIs(t, report.Errors["bKey"], errLoad)
+
+		ic := tc.ItemCount()
+		assert.Equal(t, 2, ic)
+	})
+
+	t.Run("cancelledContext", func(t *testing.T) {
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			return key + "Value", DefaultExpiration, nil
+		}
+		tc := NewCacheWithOptions(WithLoader(loader))
+		defer tc.Stop()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		report := tc.Prefetch(ctx, []string{"aKey", "bKey"}, 2)
+		assert.Equal(t, 0, report.Loaded)
+		assert.ErrorIs(t, report.Errors["aKey"], context.Canceled)
+		assert.ErrorIs(t, report.Errors["bKey"], context.Canceled)
+	})
+
+	t.Run("joinedByGetOrLoad", func(t *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			close(started)
+			<-release
+			return key + "Value", DefaultExpiration, ctx.Err()
+		}
+		tc := NewCacheWithOptions(WithLoader(loader))
+		defer tc.Stop()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		reports := make(chan PrefetchReport, 1)
+		go func() {
+			reports <- tc.Prefetch(ctx, []string{"aKey"}, 1)
+		}()
+		<-started
+
+		values := make(chan any, 1)
+		go func() {
+			value, err := tc.Fetch(context.Background(), "aKey")
+			assert.NoError(t, err)
+			values <- value
+		}()
+		assert.Eventually(t, func() bool {
+			tc.loadMu.Lock()
+			defer tc.loadMu.Unlock()
+			return tc.loads["aKey"].waiters == 2
+		}, time.Second, time.Millisecond)
+
+		// The prefetch gives up, while the load goes on uncancelled for the caller which joined it.
+		cancel()
+		report := <-reports
+		assert.ErrorIs(t, report.Errors["aKey"], context.Canceled)
+		close(release)
+		assert.Equal(t, "aKeyValue", <-values)
+	})
+
+	t.Run("loadTimeout", func(t *testing.T) {
+		loader := func(ctx context.Context, key string) (any, time.Duration, error) {
+			<-ctx.Done()
+			return nil, 0, ctx.Err()
+		}
+		tc := NewCacheWithOptions(WithLoader(loader), WithLoadTimeout(10*time.Millisecond))
+		defer tc.Stop()
+
+		report := tc.Prefetch(context.Background(), []string{"aKey"}, 1)
+		assert.ErrorIs(t, report.Errors["aKey"], context.DeadlineExceeded)
+	})
+
+	t.Run("withoutLoader", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		report := tc.Prefetch(context.Background(), []string{"aKey"}, 1)
+		assert.ErrorIs(t, report.Errors["aKey"], ErrNoLoader)
+	})
+}
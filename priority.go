@@ -0,0 +1,55 @@
+package go_cache
+
+import "time"
+
+// Priority The importance of an item when the cache evicts items to stay within its capacity limits, see
+// SetWithPriority.
+type Priority int8
+
+const (
+	// PriorityLow Items evicted first, e.g. values cheap to compute again.
+	PriorityLow Priority = iota - 1
+	// PriorityNormal The priority of the items written without SetWithPriority.
+	PriorityNormal
+	// PriorityHigh Items evicted last, e.g. values expensive to compute again.
+	PriorityHigh
+)
+
+// priorityLevels is the number of priorities, from PriorityLow to PriorityHigh.
+const priorityLevels = int(PriorityHigh-PriorityLow) + 1
+
+// String Returns the priority in lower case, e.g. "high".
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	default:
+		return "unknown"
+	}
+}
+
+// level Returns the index of the priority, from 0 for PriorityLow, a priority out of range being clamped.
+func (p Priority) level() int {
+	return int(min(max(p, PriorityLow), PriorityHigh) - PriorityLow)
+}
+
+// SetWithPriority Adds an item to the cache like Set, with the given priority. When the cache evicts items to stay
+// within its capacity limits (see WithMaxItems and WithMaxCost), all the items of a lower priority are evicted before
+// any item of a higher one, expired items kept WithRetainExpired still coming first. Within a priority, items are
+// picked by the eviction policy of the cache (see WithEvictionPolicy), or by a policy of the same kind for the other
+// priorities than PriorityNormal, FIFO for a custom policy. Namespace quotas ignore priorities.
+// Writing the key again with Set gives it PriorityNormal.
+func (c *Cache) SetWithPriority(key string, object any, duration time.Duration, p Priority) {
+	key = c.hashKey(key)
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	it := c.newItem(key, object, duration)
+	it.priority = min(max(p, PriorityLow), PriorityHigh)
+	c.insert(key, it)
+}
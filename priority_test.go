@@ -0,0 +1,102 @@
+package go_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// hasItem Reports whether the cache holds the key, without counting a hit.
+func hasItem(tc *Cache, key string) bool {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+
+	_, found := tc.items[key]
+	return found
+}
+
+func TestCache_SetWithPriority(t *testing.T) {
+	t.Run("lowEvictedFirst", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMaxItems(4), WithEvictionPolicy(NewLRUPolicy()))
+		defer tc.Stop()
+
+		tc.SetWithPriority("high1", "dashboard", NoExpiration, PriorityHigh)
+		tc.SetWithPriority("low1", "string", NoExpiration, PriorityLow)
+		tc.Set("normal1", "value", NoExpiration)
+		tc.SetWithPriority("low2", "string", NoExpiration, PriorityLow)
+		// Recently read low priority items are still evicted before any other.
+		tc.Get("low1")
+		tc.Get("low2")
+
+		tc.Set("normal2", "value", NoExpiration)
+		tc.Set("normal3", "value", NoExpiration)
+		assert.False(t, hasItem(tc, "low1"))
+		assert.False(t, hasItem(tc, "low2"))
+		assert.True(t, hasItem(tc, "high1"))
+		assert.True(t, hasItem(tc, "normal1"))
+
+		// Then normal priority items, least recently used first.
+		tc.Get("normal1")
+		tc.SetWithPriority("high2", "dashboard", NoExpiration, PriorityHigh)
+		assert.False(t, hasItem(tc, "normal2"))
+		tc.SetWithPriority("high3", "dashboard", NoExpiration, PriorityHigh)
+		assert.False(t, hasItem(tc, "normal3"))
+		tc.SetWithPriority("high4", "dashboard", NoExpiration, PriorityHigh)
+		assert.False(t, hasItem(tc, "normal1"))
+
+		// And high priority items last.
+		tc.SetWithPriority("high5", "dashboard", NoExpiration, PriorityHigh)
+		assert.False(t, hasItem(tc, "high1"))
+		assert.Equal(t, 4, tc.ItemCount())
+		assert.Equal(t, uint64(6), tc.Stats().Evictions)
+	})
+
+	t.Run("rewritten", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMaxItems(2))
+		defer tc.Stop()
+
+		tc.SetWithPriority("aKey", "aValue", NoExpiration, PriorityLow)
+		tc.Set("bKey", "bValue", NoExpiration)
+		// Writing aKey again with Set gives it the normal priority, making bKey the oldest.
+		tc.Set("aKey", "aValue2", NoExpiration)
+		tc.SetWithPriority("bKey", "bValue2", NoExpiration, PriorityHigh)
+		tc.Set("cKey", "cValue", NoExpiration)
+
+		assert.False(t, hasItem(tc, "aKey"))
+		assert.True(t, hasItem(tc, "bKey"))
+		assert.True(t, hasItem(tc, "cKey"))
+	})
+
+	t.Run("trackedLater", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.SetWithPriority("aKey", "aValue", NoExpiration, PriorityHigh)
+		tc.SetWithPriority("bKey", "bValue", NoExpiration, PriorityLow)
+		tc.Set("cKey", "cValue", NoExpiration)
+		tc.SetMaxItems(1)
+
+		assert.True(t, hasItem(tc, "aKey"))
+		assert.Equal(t, 1, tc.ItemCount())
+	})
+
+	t.Run("itemInfo", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.SetWithPriority("aKey", "aValue", NoExpiration, PriorityHigh)
+		tc.SetWithPriority("bKey", "bValue", 1*time.Minute, Priority(10))
+		tc.Set("cKey", "cValue", NoExpiration)
+
+		items, err := tc.Items()
+		assert.NoError(t, err)
+		assert.Equal(t, PriorityHigh, items["aKey"].Priority)
+		assert.Equal(t, PriorityHigh, items["bKey"].Priority)
+		assert.Equal(t, PriorityNormal, items["cKey"].Priority)
+		assert.True(t, clock.Now().Add(1*time.Minute).Equal(items["bKey"].Expiration))
+		assert.Equal(t, "high", items["aKey"].Priority.String())
+	})
+}
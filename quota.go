@@ -0,0 +1,187 @@
+package go_cache
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// quota Limits the number of items and the cost of a namespace, see WithNamespaceQuota and WithNamespaceCostBudget.
+type quota struct {
+	prefix   string
+	maxItems int
+	maxCost  int64
+	// items and cost are the number of items under the prefix and their total cost, maintained as they are written
+	// and removed.
+	items int
+	cost  int64
+	// policy orders the items under the prefix, to pick those to evict when over the quota.
+	policy EvictionPolicy
+}
+
+// over Reports whether the namespace exceeds its limits.
+func (q *quota) over() bool {
+	return (q.maxItems > 0 && q.items > q.maxItems) || (q.maxCost > 0 && q.cost > q.maxCost)
+}
+
+// Usage The number of items held under a namespace and their total cost, see NamespaceUsage.
+type Usage struct {
+	Items int
+	Cost  int64
+}
+
+// newPolicyLike Returns a new empty policy of the same kind as the given one, the built-in policies being
+// recognized, and FIFO otherwise.
+func newPolicyLike(p EvictionPolicy) EvictionPolicy {
+	switch p.(type) {
+	case *lruPolicy:
+		return NewLRUPolicy()
+	case *randomPolicy:
+		return NewRandomPolicy()
+	case *clockPolicy:
+		return NewClockPolicy()
+	default:
+		return NewFIFOPolicy()
+	}
+}
+
+// setQuota Changes the limits of the namespace under the prefix through the given function, evicting its items over
+// them. The items already in the cache are counted once the namespace gets its first limit, ordered by the time
+// their value was written, and the namespace is no longer tracked once it has no limit left.
+// It must be called with the write lock held.
+func (c *Cache) setQuota(prefix string, set func(q *quota)) {
+	i := slices.IndexFunc(c.quotas, func(q *quota) bool { return q.prefix == prefix })
+	if i >= 0 {
+		q := c.quotas[i]
+		set(q)
+		if q.maxItems <= 0 && q.maxCost <= 0 {
+			c.quotas = slices.Delete(c.quotas, i, i+1)
+			return
+		}
+		c.evictQuota(q)
+		return
+	}
+
+	q := &quota{prefix: prefix, policy: newPolicyLike(c.evictionPolicy)}
+	set(q)
+	if q.maxItems <= 0 && q.maxCost <= 0 {
+		return
+	}
+	// Costs are only computed as items are written while the cache tracks its capacity or a namespace.
+	computeCosts := c.capacity == nil && c.quotas == nil
+	var keys []string
+	for key, it := range c.items {
+		if computeCosts && !it.fixedCost {
+			it.cost = c.sizer(key, it.object)
+			c.items[key] = it
+		}
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+			q.cost += it.cost
+		}
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		return cmp.Compare(c.items[a].created, c.items[b].created)
+	})
+	for _, key := range keys {
+		q.policy.OnAdd(key)
+	}
+	q.items = len(keys)
+	c.quotas = append(c.quotas, q)
+	c.evictQuota(q)
+}
+
+// quotaInserted Counts the item written under the key against the limits of its namespaces, the cost of the previous
+// item being replaced if found, and evicts items of a namespace over its limits.
+// It must be called with the write lock held.
+func (c *Cache) quotaInserted(key string, it, previous item, found bool) {
+	for _, q := range c.quotas {
+		if strings.HasPrefix(key, q.prefix) {
+			if !found {
+				q.items++
+			}
+			q.cost += it.cost - previous.cost
+			q.policy.OnAdd(key)
+		}
+	}
+	// The item is counted everywhere first, since an eviction, possibly of the item itself, is counted everywhere.
+	for _, q := range c.quotas {
+		if strings.HasPrefix(key, q.prefix) {
+			c.evictQuota(q)
+		}
+	}
+}
+
+// quotaRemoved Stops counting the item removed from under the key against the limits of its namespaces.
+// It must be called with the write lock held.
+func (c *Cache) quotaRemoved(key string, it item) {
+	for _, q := range c.quotas {
+		if strings.HasPrefix(key, q.prefix) {
+			q.items--
+			q.cost -= it.cost
+			q.policy.OnRemove(key)
+		}
+	}
+}
+
+// quotaAccessed Tells the policies of the namespaces of the key about a hit. It is called with the read lock held.
+func (c *Cache) quotaAccessed(key string) {
+	for _, q := range c.quotas {
+		if strings.HasPrefix(key, q.prefix) {
+			q.policy.OnAccess(key)
+		}
+	}
+}
+
+// quotaFlushed Resets the quotas once the cache is flushed. It must be called with the write lock held.
+func (c *Cache) quotaFlushed() {
+	for _, q := range c.quotas {
+		q.items, q.cost = 0, 0
+		q.policy = newPolicyLike(c.evictionPolicy)
+	}
+}
+
+// evictQuota Evicts items of the namespace, picked by its policy, until it is back within its limits.
+// It must be called with the write lock held.
+func (c *Cache) evictQuota(q *quota) {
+	for q.over() {
+		key, found := q.policy.Victim()
+		if !found {
+			return
+		}
+		c.delete(key, removalEvicted)
+	}
+}
+
+// NamespaceUsage Returns the number of items and their total cost, as computed by the sizer of the cache (see
+// WithSizer), held under each prefix given a quota (see WithNamespaceQuota) or a cost budget (see
+// WithNamespaceCostBudget), e.g. for chargeback. Expired items count until they are deleted.
+func (c *Cache) NamespaceUsage() map[string]Usage {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	usage := make(map[string]Usage, len(c.quotas))
+	for _, q := range c.quotas {
+		usage[q.prefix] = Usage{Items: q.items, Cost: q.cost}
+	}
+
+	return usage
+}
+
+// SetQuota Limits the number of items of the namespace like WithNamespaceQuota, evicting its items over the limit
+// right away. A limit less than one removes the quota.
+func (n *Namespace) SetQuota(maxItems int) {
+	n.c.mu.Lock()
+	defer n.c.unlock()
+
+	n.c.setQuota(n.prefix, func(q *quota) { q.maxItems = maxItems })
+}
+
+// SetCostBudget Limits the total cost of the items of the namespace like WithNamespaceCostBudget, evicting its items
+// over the budget right away. A budget less than one removes it.
+func (n *Namespace) SetCostBudget(maxCost int64) {
+	n.c.mu.Lock()
+	defer n.c.unlock()
+
+	n.c.setQuota(n.prefix, func(q *quota) { q.maxCost = maxCost })
+}
@@ -0,0 +1,258 @@
+package go_cache
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_WithNamespaceQuota(t *testing.T) {
+	t.Run("otherNamespacesUntouched", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithNamespaceQuota("a:", 3), WithNamespaceQuota("b:", 3))
+		defer tc.Stop()
+		a, b := tc.Namespace("a:"), tc.Namespace("b:")
+
+		for i := 0; i < 3; i++ {
+			b.Set(fmt.Sprintf("key%d", i), i, NoExpiration)
+		}
+		tc.Set("other", "value", NoExpiration)
+		for i := 0; i < 10; i++ {
+			a.Set(fmt.Sprintf("key%d", i), i, NoExpiration)
+		}
+
+		for i := 0; i < 10; i++ {
+			_, found := a.Get(fmt.Sprintf("key%d", i))
+			assert.Equal(t, i >= 7, found, i)
+		}
+		for i := 0; i < 3; i++ {
+			_, found := b.Get(fmt.Sprintf("key%d", i))
+			assert.True(t, found)
+		}
+		_, found := tc.Get("other")
+		assert.True(t, found)
+		assert.Equal(t, uint64(7), tc.Stats().Removals.Evicted)
+	})
+
+	t.Run("incrementalAccounting", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithNamespaceQuota("a:", 3))
+		defer tc.Stop()
+		clock := newFakeClock()
+		tc.now = clock.Now
+		a := tc.Namespace("a:")
+
+		a.Set("aKey", "aValue", NoExpiration)
+		a.Set("bKey", "bValue", time.Second)
+		a.Set("cKey", "cValue", NoExpiration)
+		// Overwrites do not count twice.
+		a.Set("cKey", "cValue", NoExpiration)
+		a.Delete("aKey")
+		clock.Advance(time.Second)
+		tc.DeleteExpired()
+
+		a.Set("dKey", "dValue", NoExpiration)
+		a.Set("eKey", "eValue", NoExpiration)
+		assert.Equal(t, 3, tc.ItemCount())
+		assert.Equal(t, uint64(0), tc.Stats().Removals.Evicted)
+
+		a.Set("fKey", "fValue", NoExpiration)
+		assert.Equal(t, 3, tc.ItemCount())
+		_, found := a.Get("cKey")
+		assert.False(t, found)
+
+		tc.Flush()
+		for _, key := range []string{"aKey", "bKey", "cKey"} {
+			a.Set(key, "value", NoExpiration)
+		}
+		assert.Equal(t, 3, tc.ItemCount())
+		assert.Equal(t, uint64(1), tc.Stats().Removals.Evicted)
+	})
+
+	t.Run("evictionPolicy", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithNamespaceQuota("a:", 2), WithEvictionPolicy(NewLRUPolicy()))
+		defer tc.Stop()
+		a := tc.Namespace("a:")
+
+		a.Set("aKey", "aValue", NoExpiration)
+		a.Set("bKey", "bValue", NoExpiration)
+		a.Get("aKey")
+		a.Set("cKey", "cValue", NoExpiration)
+
+		_, found := a.Get("aKey")
+		assert.True(t, found)
+		_, found = a.Get("bKey")
+		assert.False(t, found)
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithNamespaceQuota("a:", 3))
+		defer tc.Stop()
+		a := tc.Namespace("a:")
+		nested := a.Namespace("b:")
+		nested.SetQuota(1)
+
+		a.Set("aKey", "aValue", NoExpiration)
+		nested.Set("aKey", "aValue", NoExpiration)
+		nested.Set("bKey", "bValue", NoExpiration)
+		assert.Equal(t, 2, tc.ItemCount())
+
+		a.Set("bKey", "bValue", NoExpiration)
+		a.Set("cKey", "cValue", NoExpiration)
+		assert.Equal(t, 3, tc.ItemCount())
+		_, found := a.Get("aKey")
+		assert.False(t, found)
+	})
+
+	t.Run("setQuota", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+		clock := newFakeClock()
+		tc.now = clock.Now
+		a := tc.Namespace("a:")
+
+		for i := 0; i < 5; i++ {
+			a.Set(fmt.Sprintf("key%d", i), i, NoExpiration)
+			clock.Advance(time.Second)
+		}
+		a.SetQuota(2)
+		assert.Equal(t, 2, tc.ItemCount())
+		_, found := a.Get("key4")
+		assert.True(t, found)
+
+		a.SetQuota(1)
+		assert.Equal(t, 1, tc.ItemCount())
+
+		a.SetQuota(0)
+		a.Set("key5", 5, NoExpiration)
+		a.Set("key6", 6, NoExpiration)
+		assert.Equal(t, 3, tc.ItemCount())
+	})
+
+	t.Run("withMaxItems", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithNamespaceQuota("a:", 2), WithMaxItems(3))
+		defer tc.Stop()
+		a := tc.Namespace("a:")
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		a.Set("aKey", "aValue", NoExpiration)
+		a.Set("bKey", "bValue", NoExpiration)
+		a.Set("cKey", "cValue", NoExpiration)
+		assert.Equal(t, 3, tc.ItemCount())
+		_, found := tc.Get("aKey")
+		assert.True(t, found)
+
+		tc.Set("bKey", "bValue", NoExpiration)
+		tc.Set("cKey", "cValue", NoExpiration)
+		assert.Equal(t, 3, tc.ItemCount())
+		_, found = a.Get("bKey")
+		assert.False(t, found)
+		tc.mu.RLock()
+		assert.Equal(t, 1, tc.quotas[0].items)
+		tc.mu.RUnlock()
+	})
+}
+
+func lenSizer(key string, value any) int64 {
+	return int64(len(value.(string)))
+}
+
+func TestCache_WithNamespaceCostBudget(t *testing.T) {
+	t.Run("enforcement", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithNamespaceCostBudget("a:", 10), WithSizer(lenSizer))
+		defer tc.Stop()
+		a, b := tc.Namespace("a:"), tc.Namespace("b:")
+
+		b.Set("aKey", "0123456789abcdef", NoExpiration)
+		a.Set("aKey", "0123", NoExpiration)
+		a.Set("bKey", "0123", NoExpiration)
+		a.Set("cKey", "0123", NoExpiration)
+
+		_, found := a.Get("aKey")
+		assert.False(t, found)
+		_, found = b.Get("aKey")
+		assert.True(t, found)
+		assert.Equal(t, Usage{Items: 2, Cost: 8}, tc.NamespaceUsage()["a:"])
+
+		// A value over the budget on its own is evicted as soon as it is written.
+		a.Set("dKey", "0123456789a", NoExpiration)
+		assert.Equal(t, Usage{}, tc.NamespaceUsage()["a:"])
+		assert.Equal(t, uint64(4), tc.Stats().Removals.Evicted)
+	})
+
+	t.Run("overwriteDelta", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithNamespaceCostBudget("a:", 10), WithSizer(lenSizer))
+		defer tc.Stop()
+		a := tc.Namespace("a:")
+
+		a.Set("aKey", "01", NoExpiration)
+		a.Set("bKey", "0123", NoExpiration)
+		a.Set("bKey", "01", NoExpiration)
+		assert.Equal(t, Usage{Items: 2, Cost: 4}, tc.NamespaceUsage()["a:"])
+
+		a.Set("bKey", "01234567", NoExpiration)
+		assert.Equal(t, Usage{Items: 2, Cost: 10}, tc.NamespaceUsage()["a:"])
+		assert.Equal(t, uint64(0), tc.Stats().Removals.Evicted)
+
+		a.Set("aKey", "012", NoExpiration)
+		assert.Equal(t, Usage{Items: 1, Cost: 3}, tc.NamespaceUsage()["a:"])
+		_, found := a.Get("bKey")
+		assert.False(t, found)
+
+		a.Delete("aKey")
+		assert.Equal(t, Usage{}, tc.NamespaceUsage()["a:"])
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithNamespaceQuota("a:", 100), WithNamespaceCostBudget("a:", 1000),
+			WithNamespaceCostBudget("b:", 1000), WithSizer(lenSizer))
+		defer tc.Stop()
+		tc.Set("cKey", "value", NoExpiration)
+
+		for i := 0; i < 20; i++ {
+			key := fmt.Sprintf("a:key%d", i)
+			if i%2 == 1 {
+				key = fmt.Sprintf("b:key%d", i)
+			}
+			tc.Set(key, fmt.Sprintf("value%d", i*i), NoExpiration)
+		}
+		tc.Namespace("b:").Namespace("c:").SetCostBudget(1000)
+
+		want := map[string]Usage{}
+		tc.mu.RLock()
+		for key, it := range tc.items {
+			for _, prefix := range []string{"a:", "b:", "b:c:"} {
+				if strings.HasPrefix(key, prefix) {
+					u := want[prefix]
+					u.Items++
+					u.Cost += lenSizer(key, it.object)
+					want[prefix] = u
+				}
+			}
+		}
+		tc.mu.RUnlock()
+		want["b:c:"] = Usage{}
+		assert.Equal(t, want, tc.NamespaceUsage())
+
+		tc.Namespace("b:").SetCostBudget(0)
+		assert.NotContains(t, tc.NamespaceUsage(), "b:")
+	})
+
+	t.Run("existingItems", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithSizer(lenSizer))
+		defer tc.Stop()
+		clock := newFakeClock()
+		tc.now = clock.Now
+		a := tc.Namespace("a:")
+
+		for _, key := range []string{"aKey", "bKey", "cKey"} {
+			a.Set(key, "0123", NoExpiration)
+			clock.Advance(time.Second)
+		}
+		a.SetCostBudget(8)
+		assert.Equal(t, Usage{Items: 2, Cost: 8}, tc.NamespaceUsage()["a:"])
+		_, found := a.Get("aKey")
+		assert.False(t, found)
+	})
+}
@@ -0,0 +1,133 @@
+package go_cache
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// replicaItem A value held by a Replica, and the time it expires at, in nanoseconds, 0 if it never expires.
+type replicaItem struct {
+	object     any
+	expiration int64
+}
+
+// Replica A read-only copy of the live items of a cache, see Cache.Replica.
+type Replica struct {
+	c        *Cache
+	interval time.Duration
+	// items is the current copy, never modified once stored, and refreshed the time it was built at.
+	items     atomic.Pointer[map[string]replicaItem]
+	refreshed atomic.Int64
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// Replica Returns a read-only copy of the live items of the cache, rebuilt in the background every refreshInterval
+// until the replica or the cache is stopped, for read paths which can tolerate stale values but not contention on the
+// lock of the cache. Reads never take a lock: each rebuild makes a new copy, swapped in atomically once complete.
+//
+// The values returned may be up to refreshInterval stale, plus the time of a rebuild: writes and deletions become
+// visible at the next rebuild, while items still expire on time. The copy costs a second map holding all the live
+// items of the cache, the values being shared with the cache rather than copied, and each rebuild reads the cache
+// in chunks (see All) so that writers are never blocked for the whole rebuild.
+func (c *Cache) Replica(refreshInterval time.Duration) *Replica {
+	r := &Replica{c: c, interval: refreshInterval, stop: make(chan struct{})}
+	r.refresh()
+	if refreshInterval > 0 {
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.run()
+		}()
+	}
+
+	return r
+}
+
+// run Rebuilds the copy every interval, as measured by the clock of the cache, until the replica or the cache is
+// stopped.
+func (r *Replica) run() {
+	t := time.NewTicker(r.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-r.c.stop:
+			return
+		case <-t.C:
+			if r.c.now().UnixNano()-r.refreshed.Load() >= int64(r.interval) {
+				r.refresh()
+			}
+		}
+	}
+}
+
+// refresh Rebuilds the copy from the live items of the cache.
+func (r *Replica) refresh() {
+	var n int
+	if previous := r.items.Load(); previous != nil {
+		n = len(*previous)
+	}
+	items := make(map[string]replicaItem, n)
+	r.c.forEachValueChunked(func(key string, item item, value any) bool {
+		items[key] = replicaItem{object: value, expiration: item.expiration}
+		return true
+	})
+	r.items.Store(&items)
+	r.refreshed.Store(r.c.now().UnixNano())
+}
+
+// get Returns the item held under the key if it has not expired.
+func (r *Replica) get(key string) (replicaItem, bool) {
+	items := r.items.Load()
+	it, found := (*items)[r.c.hashKey(key)]
+	if !found || (it.expiration > 0 && it.expiration <= r.c.now().UnixNano()) {
+		return replicaItem{}, false
+	}
+
+	return it, true
+}
+
+// Get Returns the value held under the key as of the last rebuild, and whether it was found and has not expired.
+func (r *Replica) Get(key string) (any, bool) {
+	it, found := r.get(key)
+	return it.object, found
+}
+
+// Has Reports whether a value was held under the key as of the last rebuild and has not expired.
+func (r *Replica) Has(key string) bool {
+	_, found := r.get(key)
+	return found
+}
+
+// Keys Returns the keys of the items held as of the last rebuild which have not expired, in no particular order.
+// Returns ErrHashedKeys error if the cache was created WithHashedKeys, since the keys are not stored.
+func (r *Replica) Keys() ([]string, error) {
+	if r.c.hasher != nil {
+		return nil, ErrHashedKeys
+	}
+
+	items := r.items.Load()
+	now := r.c.now().UnixNano()
+	keys := make([]string, 0, len(*items))
+	for key, it := range *items {
+		if it.expiration == 0 || it.expiration > now {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+// Stop Stops rebuilding the copy, which remains readable as of the last rebuild. Calling Stop again is a no-op.
+func (r *Replica) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+	r.wg.Wait()
+}
@@ -0,0 +1,134 @@
+package go_cache
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_Replica(t *testing.T) {
+	t.Run("refresh", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", "bValue", NoExpiration)
+		r := tc.Replica(10 * time.Millisecond)
+		defer r.Stop()
+
+		value, found := r.Get("aKey")
+		assert.True(t, found)
+		assert.Equal(t, "aValue", value)
+
+		tc.Set("aKey", "newValue", NoExpiration)
+		tc.Delete("bKey")
+		tc.Set("cKey", "cValue", NoExpiration)
+		// The clock of the cache did not move: the copy is not rebuilt however many ticks pass.
+		time.Sleep(50 * time.Millisecond)
+		value, _ = r.Get("aKey")
+		assert.Equal(t, "aValue", value)
+		assert.True(t, r.Has("bKey"))
+		assert.False(t, r.Has("cKey"))
+
+		clock.Advance(10 * time.Millisecond)
+		assert.Eventually(t, func() bool { return r.Has("cKey") }, time.Second, time.Millisecond)
+		value, _ = r.Get("aKey")
+		assert.Equal(t, "newValue", value)
+		assert.False(t, r.Has("bKey"))
+		keys, err := r.Keys()
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"aKey", "cKey"}, keys)
+	})
+
+	t.Run("expiration", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", 1*time.Second)
+		tc.Set("bKey", "bValue", NoExpiration)
+		r := tc.Replica(0)
+
+		assert.True(t, r.Has("aKey"))
+		clock.Advance(2 * time.Second)
+		assert.False(t, r.Has("aKey"))
+		keys, err := r.Keys()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"bKey"}, keys)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		r := tc.Replica(time.Millisecond)
+		r.Stop()
+		r.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		clock.Advance(time.Second)
+		time.Sleep(10 * time.Millisecond)
+		assert.False(t, r.Has("aKey"))
+	})
+
+	t.Run("cacheStopped", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		tc.Set("aKey", "aValue", NoExpiration)
+		r := tc.Replica(time.Millisecond)
+
+		tc.Stop()
+		r.wg.Wait()
+		assert.True(t, r.Has("aKey"))
+	})
+
+	t.Run("hashedKeys", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithHashedKeys())
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		r := tc.Replica(0)
+
+		value, found := r.Get("aKey")
+		assert.True(t, found)
+		assert.Equal(t, "aValue", value)
+		_, err := r.Keys()
+		assert.ErrorIs(t, err, ErrHashedKeys)
+	})
+}
+
+func BenchmarkCache_GetReplica(b *testing.B) {
+	tc := NewCache(DefaultExpiration, 0)
+	defer tc.Stop()
+	for i := 0; i < 1_000; i++ {
+		tc.Set(strconv.Itoa(i), i, NoExpiration)
+	}
+
+	b.Run("cache", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				tc.Get(strconv.Itoa(i % 1_000))
+				i++
+			}
+		})
+	})
+	b.Run("replica", func(b *testing.B) {
+		r := tc.Replica(time.Second)
+		defer r.Stop()
+
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				r.Get(strconv.Itoa(i % 1_000))
+				i++
+			}
+		})
+	})
+}
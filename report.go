@@ -0,0 +1,225 @@
+package go_cache
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// DebugOption Configures the report served by DebugHandler.
+type DebugOption func(*debugOptions)
+
+type debugOptions struct {
+	topN        int
+	maxKeyLen   int
+	redactKey   func(key string) string
+	withoutKeys bool
+}
+
+// WithDebugTopN Sets the number of largest and oldest items listed by the report, 10 by default.
+func WithDebugTopN(n int) DebugOption {
+	return func(o *debugOptions) {
+		o.topN = n
+	}
+}
+
+// WithKeyTruncation Makes the report cut the keys longer than n runes, ending them with "…".
+func WithKeyTruncation(n int) DebugOption {
+	return func(o *debugOptions) {
+		o.maxKeyLen = n
+	}
+}
+
+// WithKeyRedaction Makes the report show the keys as returned by fn, e.g. to mask personal data, before truncation.
+func WithKeyRedaction(fn func(key string) string) DebugOption {
+	return func(o *debugOptions) {
+		o.redactKey = fn
+	}
+}
+
+// DebugReport Describes what takes up room in a cache, see DebugHandler.
+type DebugReport struct {
+	Name  string `json:"name"`
+	Items int    `json:"items"`
+	// Largest The largest live items by estimated size, largest first, see LargestItems.
+	Largest []ItemSize `json:"largest"`
+	// Oldest The live items written the longest ago, oldest first.
+	Oldest []DebugItemAge `json:"oldest"`
+	// Types The number and total estimated size of the live items by type of value, most frequent first.
+	Types []DebugTypeCount `json:"types"`
+	// Namespaces The number of live items of each namespace created on the cache, see Cache.Namespace.
+	// Items of a nested namespace count towards its parents as well.
+	Namespaces []DebugNamespaceCount `json:"namespaces,omitempty"`
+}
+
+// DebugItemAge The time at which the value of an item was written, as reported by DebugReport.
+type DebugItemAge struct {
+	Key     string        `json:"key"`
+	Created time.Time     `json:"created"`
+	Age     time.Duration `json:"age"`
+}
+
+// DebugTypeCount The number and total estimated size of the items holding a type of value, see DebugReport.
+type DebugTypeCount struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+	Size  int64  `json:"size"`
+}
+
+// DebugNamespaceCount The number of live items of a namespace, see DebugReport.
+type DebugNamespaceCount struct {
+	Prefix string `json:"prefix"`
+	Count  int    `json:"count"`
+}
+
+// DebugHandler Returns a read-only HTTP handler reporting what takes up room in the cache, meant to be mounted under
+// /debug: the largest and oldest items, and the number of items by type of value and by namespace. The report is
+// plain text, or JSON with ?format=json. It is computed by scanning the cache in chunks, so writers are not blocked
+// for the whole scan. Keys can be truncated and redacted with the given options, since they may hold personal data.
+func DebugHandler(c *Cache, opts ...DebugOption) http.Handler {
+	o := debugOptions{topN: 10}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		report := c.debugReport(o)
+		if r.URL.Query().Get("format") == "json" {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(report)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_ = report.WriteText(w)
+	})
+}
+
+// debugReport Builds the report served by DebugHandler.
+func (c *Cache) debugReport(o debugOptions) DebugReport {
+	var prefixes []string
+	c.namespaces.Range(func(prefix, _ any) bool {
+		prefixes = append(prefixes, prefix.(string))
+		return true
+	})
+	slices.Sort(prefixes)
+
+	report := DebugReport{Name: c.Name()}
+	types := make(map[string]*DebugTypeCount)
+	namespaces := make([]int, len(prefixes))
+	// The top lists are trimmed whenever they double, and sorted with the key as tie-breaker to be deterministic.
+	bySize := func(a, b ItemSize) int {
+		return cmp.Or(cmp.Compare(b.Size, a.Size), cmp.Compare(a.Key, b.Key))
+	}
+	byAge := func(a, b DebugItemAge) int {
+		return cmp.Or(a.Created.Compare(b.Created), cmp.Compare(a.Key, b.Key))
+	}
+	c.forEachChunked(func(key string, item item) bool {
+		report.Items++
+		size := c.sizer(key, item.object)
+		typ := fmt.Sprintf("%T", item.object)
+
+		report.Largest = append(report.Largest, ItemSize{Key: key, Size: size, Type: typ})
+		report.Oldest = append(report.Oldest, DebugItemAge{Key: key, Created: time.Unix(0, item.created)})
+		if len(report.Largest) >= 2*max(o.topN, 1) {
+			report.Largest = topN(report.Largest, o.topN, bySize)
+			report.Oldest = topN(report.Oldest, o.topN, byAge)
+		}
+
+		t, found := types[typ]
+		if !found {
+			t = &DebugTypeCount{Type: typ}
+			types[typ] = t
+		}
+		t.Count++
+		t.Size += size
+
+		for i, prefix := range prefixes {
+			if strings.HasPrefix(key, prefix) {
+				namespaces[i]++
+			}
+		}
+		return true
+	})
+
+	report.Largest = topN(report.Largest, o.topN, bySize)
+	report.Oldest = topN(report.Oldest, o.topN, byAge)
+	now := c.now()
+	for i := range report.Largest {
+		report.Largest[i].Key = o.key(report.Largest[i].Key)
+	}
+	for i := range report.Oldest {
+		report.Oldest[i].Key = o.key(report.Oldest[i].Key)
+		report.Oldest[i].Age = now.Sub(report.Oldest[i].Created)
+	}
+	for _, t := range types {
+		report.Types = append(report.Types, *t)
+	}
+	slices.SortFunc(report.Types, func(a, b DebugTypeCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Type, b.Type))
+	})
+	for i, prefix := range prefixes {
+		report.Namespaces = append(report.Namespaces, DebugNamespaceCount{Prefix: o.key(prefix), Count: namespaces[i]})
+	}
+
+	return report
+}
+
+// topN Returns the n first elements of s in the order given by compare.
+func topN[T any](s []T, n int, compare func(a, b T) int) []T {
+	slices.SortFunc(s, compare)
+
+	return s[:min(len(s), max(n, 0))]
+}
+
+// key Returns the key as shown by the report.
+func (o debugOptions) key(key string) string {
+	if o.redactKey != nil {
+		key = o.redactKey(key)
+	}
+	if o.maxKeyLen > 0 {
+		if runes := []rune(key); len(runes) > o.maxKeyLen {
+			key = string(runes[:o.maxKeyLen]) + "…"
+		}
+	}
+
+	return key
+}
+
+// WriteText Writes the report as aligned plain text.
+func (r DebugReport) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "cache %s: %d items\n", r.Name, r.Items)
+
+	fmt.Fprintf(tw, "\nlargest items:\n")
+	for _, it := range r.Largest {
+		fmt.Fprintf(tw, "  %d\t%s\t%s\n", it.Size, it.Type, it.Key)
+	}
+	fmt.Fprintf(tw, "\noldest items:\n")
+	for _, it := range r.Oldest {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", it.Created.UTC().Format(time.RFC3339), it.Age, it.Key)
+	}
+	fmt.Fprintf(tw, "\ntypes:\n")
+	for _, t := range r.Types {
+		fmt.Fprintf(tw, "  %d\t%d\t%s\n", t.Count, t.Size, t.Type)
+	}
+	if len(r.Namespaces) > 0 {
+		fmt.Fprintf(tw, "\nnamespaces:\n")
+		for _, ns := range r.Namespaces {
+			fmt.Fprintf(tw, "  %d\t%s\n", ns.Count, ns.Prefix)
+		}
+	}
+
+	return tw.Flush()
+}
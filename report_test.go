@@ -0,0 +1,133 @@
+package go_cache
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	golden := filepath.Join("testdata", name+".golden")
+	if *update {
+		assert.NoError(t, os.WriteFile(golden, got, 0o644))
+	}
+	want, err := os.ReadFile(golden)
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+// newReportCache Returns a cache with items of various sizes, types, ages and namespaces.
+func newReportCache() (*Cache, *fakeClock) {
+	clock := newFakeClock()
+	tc := NewCacheWithOptions(WithName("sessions"))
+	tc.now = clock.Now
+
+	users := tc.Namespace("users:")
+	admins := users.Namespace("admins:")
+	tc.Namespace("orders:")
+
+	users.Set("alice@example.com", strings.Repeat("a", 300), NoExpiration)
+	clock.Advance(time.Minute)
+	admins.Set("bob@example.com", []byte(strings.Repeat("b", 2048)), NoExpiration)
+	clock.Advance(time.Minute)
+	tc.Set("config", map[string]int{"a": 1}, NoExpiration)
+	clock.Advance(time.Minute)
+	for i := 0; i < 5; i++ {
+		tc.Set("counter"+strconv.Itoa(i), i, NoExpiration)
+		clock.Advance(time.Second)
+	}
+	tc.Set("expired", strings.Repeat("e", 10_000), time.Millisecond)
+	clock.Advance(time.Hour)
+
+	return tc, clock
+}
+
+func TestDebugHandler(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		tc, _ := newReportCache()
+		defer tc.Stop()
+
+		rec := httptest.NewRecorder()
+		DebugHandler(tc, WithDebugTopN(3)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/cache", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+		assertGolden(t, "debug", rec.Body.Bytes())
+	})
+
+	t.Run("redacted", func(t *testing.T) {
+		tc, _ := newReportCache()
+		defer tc.Stop()
+
+		redact := func(key string) string {
+			if i := strings.LastIndexByte(key, ':'); i >= 0 && strings.Contains(key, "@") {
+				return key[:i+1] + "<email>"
+			}
+			return key
+		}
+		rec := httptest.NewRecorder()
+		DebugHandler(tc, WithDebugTopN(3), WithKeyRedaction(redact), WithKeyTruncation(10)).
+			ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/cache", nil))
+		assertGolden(t, "debugRedacted", rec.Body.Bytes())
+		assert.NotContains(t, rec.Body.String(), "alice")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		tc, clock := newReportCache()
+		defer tc.Stop()
+
+		rec := httptest.NewRecorder()
+		DebugHandler(tc, WithDebugTopN(2)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/cache?format=json", nil))
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var report DebugReport
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		assert.Equal(t, 8, report.Items)
+		assert.Equal(t, []ItemSize{
+			{Key: "users:admins:bob@example.com", Size: 2048, Type: "[]uint8"},
+			{Key: "users:alice@example.com", Size: 300, Type: "string"},
+		}, report.Largest)
+		if assert.Len(t, report.Oldest, 2) {
+			assert.Equal(t, "users:alice@example.com", report.Oldest[0].Key)
+			assert.Equal(t, time.Hour+3*time.Minute+5*time.Second, report.Oldest[0].Age)
+			assert.True(t, clock.Now().Add(-report.Oldest[1].Age).Equal(report.Oldest[1].Created))
+		}
+		assert.Equal(t, DebugTypeCount{Type: "int", Count: 5, Size: 40}, report.Types[0])
+		assert.Equal(t, []DebugNamespaceCount{
+			{Prefix: "orders:", Count: 0},
+			{Prefix: "users:", Count: 2},
+			{Prefix: "users:admins:", Count: 1},
+		}, report.Namespaces)
+	})
+
+	t.Run("manyItems", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		for i := 0; i < 10_000; i++ {
+			tc.Set(strconv.Itoa(i), strings.Repeat("x", i), NoExpiration)
+		}
+
+		report := tc.debugReport(debugOptions{topN: 3})
+		assert.Equal(t, 10_000, report.Items)
+		assert.Equal(t, []ItemSize{
+			{Key: "9999", Size: 9999, Type: "string"},
+			{Key: "9998", Size: 9998, Type: "string"},
+			{Key: "9997", Size: 9997, Type: "string"},
+		}, report.Largest)
+		assert.Len(t, report.Oldest, 3)
+		assert.Empty(t, report.Namespaces)
+	})
+}
@@ -0,0 +1,56 @@
+package go_cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// retry Holds the settings of a cache created WithLoaderRetry.
+type retry struct {
+	attempts  int
+	backoff   func(attempt int) time.Duration
+	retryable func(err error) bool
+}
+
+// shouldRetry Reports whether a load failing with the given error is worth another attempt.
+func (r *retry) shouldRetry(err error) bool {
+	if r.retryable != nil {
+		return r.retryable(err)
+	}
+
+	return !errors.Is(err, ErrItemNotFound) && !errors.Is(err, ErrLoaderPanicked)
+}
+
+// loadWithRetry Calls the loader for the key, trying again on retryable errors up to the number of attempts set
+// WithLoaderRetry, unless the context is done first, and returns the outcome of the last attempt.
+func (c *Cache) loadWithRetry(ctx context.Context, key string, loader Loader) (any, time.Duration, error) {
+	var object any
+	var duration time.Duration
+	err := c.retryLoad(ctx, func() (err error) {
+		object, duration, err = c.invokeLoader(ctx, key, loader)
+		return err
+	})
+
+	return object, duration, err
+}
+
+// retryLoad Calls attempt, trying again on retryable errors up to the number of attempts set WithLoaderRetry,
+// unless the context is done first, and returns the error of the last attempt.
+func (c *Cache) retryLoad(ctx context.Context, attempt func() error) error {
+	err := attempt()
+	for n := 1; err != nil && n < c.retry.attempts && ctx.Err() == nil && c.retry.shouldRetry(err); n++ {
+		if c.retry.backoff != nil {
+			t := time.NewTimer(c.retry.backoff(n))
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return err
+			case <-t.C:
+			}
+		}
+		err = attempt()
+	}
+
+	return err
+}
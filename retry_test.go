@@ -0,0 +1,187 @@
+package go_cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_WithLoaderRetry(t *testing.T) {
+	errReset := errors.New("connection reset")
+
+	t.Run("sharedAttempts", func(t *testing.T) {
+		var attempts atomic.Int32
+		release := make(chan struct{})
+		var backoffs []int
+		tc := NewCacheWithOptions(WithLoaderRetry(3, func(attempt int) time.Duration {
+			backoffs = append(backoffs, attempt)
+			return time.Millisecond
+		}, nil), WithLoader(func(ctx context.Context, key string) (any, time.Duration, error) {
+			if attempts.Add(1) < 3 {
+				<-release
+				return nil, 0, errReset
+			}
+			return key + "Value", DefaultExpiration, nil
+		}))
+		defer tc.Stop()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				a, err := tc.Fetch(context.Background(), "aKey")
+				assert.Nil(t, err)
+				assert.Equal(t, "aKeyValue", a)
+			}()
+		}
+		<-time.After(10 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(3), attempts.Load())
+		assert.Equal(t, []int{1, 2}, backoffs)
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithLoaderRetry(3, nil, nil), WithNegativeTTL(time.Minute))
+		defer tc.Stop()
+
+		var attempts atomic.Int32
+		_, err := tc.GetOrLoad(context.Background(), "aKey", func(ctx context.Context, key string) (any, time.Duration, error) {
+			attempts.Add(1)
+			return nil, 0, errReset
+		})
+		assert.ErrorIs(t, err, errReset)
+		assert.Equal(t, int32(3), attempts.Load())
+
+		// Not found is final.
+		attempts.Store(0)
+		_, err = tc.GetOrLoad(context.Background(), "bKey", func(ctx context.Context, key string) (any, time.Duration, error) {
+			if attempts.Add(1) == 1 {
+				return nil, 0, errReset
+			}
+			return nil, 0, ErrItemNotFound
+		})
+		assert.ErrorIs(t, err, ErrItemNotFound)
+		assert.Equal(t, int32(2), attempts.Load())
+		_, state := tc.Lookup("bKey")
+		assert.Equal(t, LookupNegativeHit, state)
+	})
+
+	t.Run("retryable", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithLoaderRetry(5, nil, func(err error) bool {
+			return errors.Is(err, errReset)
+		}))
+		defer tc.Stop()
+
+		var attempts atomic.Int32
+		errFatal := errors.New("fatal")
+		_, err := tc.GetOrLoad(context.Background(), "aKey", func(ctx context.Context, key string) (any, time.Duration, error) {
+			if attempts.Add(1) == 1 {
+				return nil, 0, errReset
+			}
+			return nil, 0, errFatal
+		})
+		assert.ErrorIs(t, err, errFatal)
+		assert.Equal(t, int32(2), attempts.Load())
+	})
+
+	t.Run("contextBound", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithLoaderRetry(100, func(attempt int) time.Duration {
+			return time.Hour
+		}, nil))
+		defer tc.Stop()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		var attempts atomic.Int32
+		_, err := tc.GetOrLoad(ctx, "aKey", func(ctx context.Context, key string) (any, time.Duration, error) {
+			attempts.Add(1)
+			return nil, 0, errReset
+		})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+
+	t.Run("callerContextBatch", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithLoaderRetry(3, func(attempt int) time.Duration {
+			return 50 * time.Millisecond
+		}, nil))
+		defer tc.Stop()
+
+		var attempts atomic.Int32
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := make(chan error, 1)
+		go func() {
+			_, err := tc.GetOrLoadMany(ctx, []string{"aKey"}, NoExpiration,
+				func(context.Context, []string) (map[string]any, error) {
+					attempts.Add(1)
+					return nil, errReset
+				})
+			errs <- err
+		}()
+		assert.Eventually(t, func() bool { return attempts.Load() == 1 }, time.Second, time.Millisecond)
+
+		// The caller joining the batch is unaffected by the cancellation of the one which started it.
+		joined := make(chan error, 1)
+		go func() {
+			_, err := tc.GetOrLoad(context.Background(), "aKey", func(context.Context, string) (any, time.Duration, error) {
+				t.Error("loader called for a key in flight")
+				return nil, 0, nil
+			})
+			joined <- err
+		}()
+		waitForWaiters(t, tc, "aKey", 2)
+		cancel()
+		assert.ErrorIs(t, <-errs, context.Canceled)
+		assert.ErrorIs(t, <-joined, errReset)
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+
+	t.Run("callerContextPrefetch", func(t *testing.T) {
+		var attempts atomic.Int32
+		tc := NewCacheWithOptions(WithLoaderRetry(3, func(attempt int) time.Duration {
+			return 50 * time.Millisecond
+		}, nil), WithLoader(func(ctx context.Context, key string) (any, time.Duration, error) {
+			attempts.Add(1)
+			return nil, 0, errReset
+		}))
+		defer tc.Stop()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		reports := make(chan PrefetchReport, 1)
+		go func() {
+			reports <- tc.Prefetch(ctx, []string{"aKey"}, 1)
+		}()
+		assert.Eventually(t, func() bool { return attempts.Load() == 1 }, time.Second, time.Millisecond)
+
+		joined := make(chan error, 1)
+		go func() {
+			_, err := tc.Fetch(context.Background(), "aKey")
+			joined <- err
+		}()
+		waitForWaiters(t, tc, "aKey", 2)
+		cancel()
+		report := <-reports
+		assert.ErrorIs(t, report.Errors["aKey"], context.Canceled)
+		assert.ErrorIs(t, <-joined, errReset)
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+}
+
+// waitForWaiters Waits until n callers wait for the load in flight for the given key.
+func waitForWaiters(t *testing.T, tc *Cache, key string, n int) {
+	t.Helper()
+	assert.Eventually(t, func() bool {
+		tc.loadMu.Lock()
+		defer tc.loadMu.Unlock()
+		call, found := tc.loads[key]
+		return found && call.waiters == n
+	}, time.Second, time.Millisecond)
+}
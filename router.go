@@ -0,0 +1,214 @@
+package go_cache
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrNoInstance is returned by the methods of a Router which has no instance to route a key to.
+var ErrNoInstance = errors.New("no cache instance")
+
+// defaultVirtualNodes Number of points each instance gets on the ring of a Router by default.
+const defaultVirtualNodes = 128
+
+// RouterOption Configures a router created with NewRouter.
+type RouterOption func(*Router)
+
+// WithVirtualNodes Sets the number of points each instance gets on the ring of the router, 128 by default.
+// More points spread keys more evenly between instances, at the cost of a larger ring.
+func WithVirtualNodes(n int) RouterOption {
+	return func(r *Router) {
+		if n > 0 {
+			r.virtualNodes = n
+		}
+	}
+}
+
+// Router Spreads keys across several caches with consistent hashing, so that adding or removing an instance only
+// moves the keys of the ring segments it owns. Instances are placed on the ring by name (see WithName), so a
+// given set of names always routes keys the same way, across processes too.
+// Items are not moved between instances when the ring changes: keys routed to another instance simply miss there.
+type Router struct {
+	virtualNodes int
+
+	mu        sync.RWMutex
+	instances map[string]*Cache
+	// points holds the positions of the instances on the ring, sorted, and owners the name of the instance at
+	// each position.
+	points []uint64
+	owners map[uint64]string
+}
+
+// NewRouter Returns a router spreading keys across the given caches, see AddInstance.
+func NewRouter(caches []*Cache, opts ...RouterOption) *Router {
+	r := &Router{
+		virtualNodes: defaultVirtualNodes,
+		instances:    make(map[string]*Cache),
+		owners:       make(map[uint64]string),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	for _, c := range caches {
+		r.AddInstance(c)
+	}
+
+	return r
+}
+
+// AddInstance Adds the given cache to the router under its name, replacing any instance with the same name.
+// Only the keys falling into the ring segments taken over by the new instance are routed differently.
+func (r *Router) AddInstance(c *Cache) {
+	name := c.Name()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.instances[name]; !found {
+		for i := 0; i < r.virtualNodes; i++ {
+			point := ringHash(name + "#" + strconv.Itoa(i))
+			if _, taken := r.owners[point]; taken {
+				continue
+			}
+			r.owners[point] = name
+			r.points = append(r.points, point)
+		}
+		slices.Sort(r.points)
+	}
+	r.instances[name] = c
+}
+
+// RemoveInstance Removes the instance with the given name from the router, and reports whether it was found.
+// Only the keys it owned are routed differently, to the instances following it on the ring.
+// The cache itself is left as is.
+func (r *Router) RemoveInstance(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, found := r.instances[name]; !found {
+		return false
+	}
+	delete(r.instances, name)
+	r.points = slices.DeleteFunc(r.points, func(point uint64) bool {
+		if r.owners[point] != name {
+			return false
+		}
+		delete(r.owners, point)
+		return true
+	})
+
+	return true
+}
+
+// Instance Returns the cache the given key is routed to, nil if the router has no instance.
+func (r *Router) Instance(key string) *Cache {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.points) == 0 {
+		return nil
+	}
+	hash := ringHash(key)
+	i, _ := slices.BinarySearch(r.points, hash)
+	if i == len(r.points) {
+		i = 0
+	}
+
+	return r.instances[r.owners[r.points[i]]]
+}
+
+// Instances Returns the caches of the router, in no particular order.
+func (r *Router) Instances() []*Cache {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	caches := make([]*Cache, 0, len(r.instances))
+	for _, c := range r.instances {
+		caches = append(caches, c)
+	}
+
+	return caches
+}
+
+// Set Adds an item to the cache the key is routed to, see Cache.Set.
+// It is a no-op if the router has no instance.
+func (r *Router) Set(key string, object any, duration time.Duration) {
+	if c := r.Instance(key); c != nil {
+		c.Set(key, object, duration)
+	}
+}
+
+// Add Adds an item to the cache the key is routed to, see Cache.Add.
+func (r *Router) Add(key string, object any, duration time.Duration) error {
+	c := r.Instance(key)
+	if c == nil {
+		return fmt.Errorf("%w: %s", ErrNoInstance, key)
+	}
+
+	return c.Add(key, object, duration)
+}
+
+// Replace Replaces the item in the cache the key is routed to, see Cache.Replace.
+func (r *Router) Replace(key string, object any, duration time.Duration) error {
+	c := r.Instance(key)
+	if c == nil {
+		return fmt.Errorf("%w: %s", ErrNoInstance, key)
+	}
+
+	return c.Replace(key, object, duration)
+}
+
+// Get Looks up a key's value from the cache it is routed to, see Cache.Get.
+func (r *Router) Get(key string) (any, bool) {
+	c := r.Instance(key)
+	if c == nil {
+		return nil, false
+	}
+
+	return c.Get(key)
+}
+
+// Delete Deletes the key from the cache it is routed to, see Cache.Delete.
+func (r *Router) Delete(key string) {
+	if c := r.Instance(key); c != nil {
+		c.Delete(key)
+	}
+}
+
+// Flush Flushes all the instances of the router.
+func (r *Router) Flush() {
+	for _, c := range r.Instances() {
+		c.Flush()
+	}
+}
+
+// ItemCount Returns the total number of items held by the instances of the router, see Cache.ItemCount.
+func (r *Router) ItemCount() int {
+	n := 0
+	for _, c := range r.Instances() {
+		n += c.ItemCount()
+	}
+
+	return n
+}
+
+// ringHash Returns the position of the given string on the ring: its FNV-1a hash, mixed so that similar strings,
+// such as the names of the virtual nodes of an instance, land far apart.
+func ringHash(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+
+	return x
+}
@@ -0,0 +1,137 @@
+package go_cache
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRouter(t *testing.T, names ...string) *Router {
+	t.Helper()
+
+	caches := make([]*Cache, 0, len(names))
+	for _, name := range names {
+		c := NewCacheWithOptions(WithName(name))
+		t.Cleanup(c.Stop)
+		caches = append(caches, c)
+	}
+
+	return NewRouter(caches)
+}
+
+func TestRouter(t *testing.T) {
+	t.Run("routesToOneInstance", func(t *testing.T) {
+		r := newTestRouter(t, "a", "b", "c", "d")
+
+		for i := 0; i < 1000; i++ {
+			r.Set(strconv.Itoa(i), i, NoExpiration)
+		}
+		assert.Equal(t, 1000, r.ItemCount())
+		for _, c := range r.Instances() {
+			// Each instance gets a fair share of the keys.
+			assert.InDelta(t, 250, c.ItemCount(), 100, c.Name())
+		}
+
+		for i := 0; i < 1000; i++ {
+			key := strconv.Itoa(i)
+			value, found := r.Get(key)
+			assert.True(t, found)
+			assert.Equal(t, i, value)
+
+			value, found = r.Instance(key).Get(key)
+			assert.True(t, found)
+			assert.Equal(t, i, value)
+		}
+
+		assert.ErrorIs(t, r.Add("0", 0, NoExpiration), ErrItemAlreadyExists)
+		assert.NoError(t, r.Replace("0", -1, NoExpiration))
+		r.Delete("1")
+		_, found := r.Get("1")
+		assert.False(t, found)
+
+		r.Flush()
+		assert.Equal(t, 0, r.ItemCount())
+	})
+
+	t.Run("removeInstance", func(t *testing.T) {
+		r := newTestRouter(t, "a", "b", "c", "d")
+
+		const keys = 10_000
+		before := make(map[string]string, keys)
+		for i := 0; i < keys; i++ {
+			key := strconv.Itoa(i)
+			before[key] = r.Instance(key).Name()
+		}
+
+		assert.True(t, r.RemoveInstance("c"))
+		assert.False(t, r.RemoveInstance("c"))
+		assert.Len(t, r.Instances(), 3)
+
+		moved := 0
+		for key, name := range before {
+			after := r.Instance(key).Name()
+			if name == "c" {
+				assert.NotEqual(t, "c", after)
+				moved++
+				continue
+			}
+			// Keys of the remaining instances stay put.
+			assert.Equal(t, name, after, key)
+		}
+		assert.InDelta(t, keys/4, moved, keys/10)
+	})
+
+	t.Run("addInstance", func(t *testing.T) {
+		r := newTestRouter(t, "a", "b", "c")
+
+		const keys = 10_000
+		before := make(map[string]string, keys)
+		for i := 0; i < keys; i++ {
+			key := strconv.Itoa(i)
+			before[key] = r.Instance(key).Name()
+		}
+
+		d := NewCacheWithOptions(WithName("d"))
+		defer d.Stop()
+		r.AddInstance(d)
+
+		moved := 0
+		for key, name := range before {
+			if after := r.Instance(key).Name(); after != name {
+				// Keys only move to the new instance.
+				assert.Equal(t, "d", after)
+				moved++
+			}
+		}
+		assert.InDelta(t, keys/4, moved, keys/10)
+	})
+
+	t.Run("virtualNodes", func(t *testing.T) {
+		a := NewCacheWithOptions(WithName("a"))
+		defer a.Stop()
+		r := NewRouter([]*Cache{a}, WithVirtualNodes(3))
+
+		assert.Len(t, r.points, 3)
+	})
+
+	t.Run("noInstance", func(t *testing.T) {
+		r := NewRouter(nil)
+
+		r.Set("aKey", "aValue", NoExpiration)
+		_, found := r.Get("aKey")
+		assert.False(t, found)
+		assert.ErrorIs(t, r.Add("aKey", "aValue", NoExpiration), ErrNoInstance)
+		assert.Equal(t, 0, r.ItemCount())
+	})
+
+	t.Run("unnamed", func(t *testing.T) {
+		a, b := NewCache(DefaultExpiration, 0), NewCache(DefaultExpiration, 0)
+		defer a.Stop()
+		defer b.Stop()
+
+		assert.NotEqual(t, a.Name(), b.Name())
+		r := NewRouter([]*Cache{a, b})
+		assert.Len(t, r.Instances(), 2)
+	})
+}
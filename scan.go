@@ -0,0 +1,61 @@
+package go_cache
+
+import "encoding/base64"
+
+// Scan Enumerates the keys of the cache in pages, in the fashion of the Redis SCAN command. Start with an empty
+// cursor, then pass the returned cursor to the next call until it comes back empty. Each call returns at most
+// count keys. Cursors are opaque and stateless: a scan can be abandoned at any time, and an invalid cursor
+// starts over. Keys are visited in ascending order, so every key present for the whole scan is returned exactly
+// once, while keys added or removed during the scan may or may not be returned.
+// Each call only walks the keys it returns, through the ordered index of the keys maintained WithSortedKeys. If the
+// cache was created without it, the first call builds the index, which is then maintained like WithSortedKeys,
+// without enabling RangeKeys and AscendPrefix. With hashed keys (see WithHashedKeys), the hashes are returned
+// instead.
+func (c *Cache) Scan(cursor string, count int) ([]string, string) {
+	if count < 1 {
+		count = 1
+	}
+
+	var after string
+	started := false
+	if b, err := base64.RawURLEncoding.DecodeString(cursor); err == nil && cursor != "" {
+		after, started = string(b), true
+	}
+
+	c.mu.RLock()
+	indexed, n := c.sorted != nil, len(c.items)
+	c.mu.RUnlock()
+	if !indexed {
+		c.indexKeys()
+	}
+
+	keys := make([]string, 0, min(count, n))
+	c.ascend(after, false, func(key string, _ any) bool {
+		if started && key == after {
+			return true
+		}
+		keys = append(keys, key)
+		return len(keys) < count
+	})
+
+	if len(keys) < count {
+		return keys, ""
+	}
+
+	return keys, base64.RawURLEncoding.EncodeToString([]byte(keys[len(keys)-1]))
+}
+
+// indexKeys Builds the ordered index of the keys of the cache, unless it exists already.
+func (c *Cache) indexKeys() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sorted != nil {
+		return
+	}
+	sorted := newSkipList()
+	for key := range c.items {
+		sorted.insert(key)
+	}
+	c.sorted = sorted
+}
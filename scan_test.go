@@ -0,0 +1,127 @@
+package go_cache
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_Scan(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"withSortedKeys":    {WithSortedKeys()},
+		"withoutSortedKeys": nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Run("fullCoverageWithConcurrentWrites", func(t *testing.T) {
+				tc := NewCacheWithOptions(opts...)
+				defer tc.Stop()
+
+				const n = 5000
+				for i := 0; i < n; i++ {
+					tc.Set("stable:"+strconv.Itoa(i), i, DefaultExpiration)
+				}
+
+				stop := make(chan struct{})
+				var wg sync.WaitGroup
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; ; i++ {
+						select {
+						case <-stop:
+							return
+						default:
+						}
+						tc.Set("volatile:"+strconv.Itoa(i%500), i, DefaultExpiration)
+						tc.Delete("volatile:" + strconv.Itoa((i+250)%500))
+					}
+				}()
+
+				seen := map[string]int{}
+				calls := 0
+				cursor := ""
+				for {
+					var keys []string
+					keys, cursor = tc.Scan(cursor, 100)
+					assert.LessOrEqual(t, len(keys), 100)
+					for _, key := range keys {
+						seen[key]++
+					}
+					calls++
+					if cursor == "" {
+						break
+					}
+				}
+				close(stop)
+				wg.Wait()
+
+				for i := 0; i < n; i++ {
+					assert.Equal(t, 1, seen["stable:"+strconv.Itoa(i)])
+				}
+				assert.Greater(t, calls, n/100)
+			})
+
+			t.Run("emptyCache", func(t *testing.T) {
+				tc := NewCacheWithOptions(opts...)
+				defer tc.Stop()
+
+				keys, cursor := tc.Scan("", 10)
+				assert.Empty(t, keys)
+				assert.Empty(t, cursor)
+			})
+
+			t.Run("exactPage", func(t *testing.T) {
+				tc := NewCacheWithOptions(opts...)
+				defer tc.Stop()
+
+				tc.Set("aKey", "aValue", DefaultExpiration)
+				tc.Set("bKey", "bValue", DefaultExpiration)
+
+				keys, cursor := tc.Scan("", 2)
+				assert.Equal(t, []string{"aKey", "bKey"}, keys)
+				assert.NotEmpty(t, cursor)
+
+				keys, cursor = tc.Scan(cursor, 2)
+				assert.Empty(t, keys)
+				assert.Empty(t, cursor)
+			})
+
+			t.Run("hugeCount", func(t *testing.T) {
+				tc := NewCacheWithOptions(opts...)
+				defer tc.Stop()
+
+				tc.Set("aKey", "aValue", DefaultExpiration)
+				keys, cursor := tc.Scan("", math.MaxInt)
+				assert.Equal(t, []string{"aKey"}, keys)
+				assert.Empty(t, cursor)
+			})
+		})
+	}
+}
+
+func TestCache_ScanIndex(t *testing.T) {
+	tc := NewCache(DefaultExpiration, 0)
+	defer tc.Stop()
+
+	tc.Set("bKey", "bValue", DefaultExpiration)
+	assert.Nil(t, tc.sorted)
+
+	// The first call indexes the keys, which are kept indexed from then on.
+	keys, _ := tc.Scan("", 10)
+	assert.Equal(t, []string{"bKey"}, keys)
+	assert.NotNil(t, tc.sorted)
+	tc.Set("aKey", "aValue", DefaultExpiration)
+	tc.Set("cKey", "cValue", DefaultExpiration)
+	tc.Delete("bKey")
+
+	keys, cursor := tc.Scan("", 1)
+	assert.Equal(t, []string{"aKey"}, keys)
+	keys, _ = tc.Scan(cursor, 10)
+	assert.Equal(t, []string{"cKey"}, keys)
+
+	// Range queries still need WithSortedKeys.
+	assert.Empty(t, tc.RangeKeys("", "", 0))
+}
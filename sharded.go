@@ -0,0 +1,85 @@
+package go_cache
+
+import (
+	"hash/maphash"
+	"runtime"
+	"time"
+)
+
+// ShardedCache A cache split into independent shards, each with its own items and lock, to which keys are spread by
+// hash, so that goroutines working on keys of different shards do not contend for the same lock.
+// Each shard is a Cache with its own cleanup goroutine, so that sweeping a large shard does not hold up the others.
+type ShardedCache struct {
+	shards []*Cache
+	seed   maphash.Seed
+}
+
+// NewShardedCache Returns a new cache split into the given number of shards, each with the given default expiration
+// duration and cleanup interval, see NewCache. If the number of shards is less than 1, GOMAXPROCS shards are used.
+func NewShardedCache(shards int, defaultExpiration, cleanupInterval time.Duration) *ShardedCache {
+	if shards < 1 {
+		shards = runtime.GOMAXPROCS(0)
+	}
+	s := &ShardedCache{shards: make([]*Cache, shards), seed: maphash.MakeSeed()}
+	for i := range s.shards {
+		s.shards[i] = NewCache(defaultExpiration, cleanupInterval)
+	}
+
+	return s
+}
+
+// shard Returns the shard holding the given key, picked by the key's hash.
+func (s *ShardedCache) shard(key string) *Cache {
+	return s.shards[maphash.String(s.seed, key)%uint64(len(s.shards))]
+}
+
+// Set Adds an item to the shard of the key, replacing any existing item, see Cache.Set.
+func (s *ShardedCache) Set(key string, object any, duration time.Duration) {
+	s.shard(key).Set(key, object, duration)
+}
+
+// Add Inserts an item to the shard of the key only if an item doesn't already exist for it, see Cache.Add.
+func (s *ShardedCache) Add(key string, object any, duration time.Duration) error {
+	return s.shard(key).Add(key, object, duration)
+}
+
+// Replace Sets a new value for the key only if it already exists in its shard, see Cache.Replace.
+func (s *ShardedCache) Replace(key string, object any, duration time.Duration) error {
+	return s.shard(key).Replace(key, object, duration)
+}
+
+// Get Looks up a key's value from its shard, see Cache.Get.
+func (s *ShardedCache) Get(key string) (any, bool) {
+	return s.shard(key).Get(key)
+}
+
+// Delete Removes the provided key from its shard, see Cache.Delete.
+func (s *ShardedCache) Delete(key string) {
+	s.shard(key).Delete(key)
+}
+
+// Flush Completely clears all the shards, one after the other: unlike Cache.Flush, it is not atomic, and an item
+// written to a shard already flushed may be seen.
+func (s *ShardedCache) Flush() {
+	for _, c := range s.shards {
+		c.Flush()
+	}
+}
+
+// ItemCount Returns the total number of items in the shards, see Cache.ItemCount. The shards are counted one after
+// the other, so that the total may not match any single point in time under concurrent writes.
+func (s *ShardedCache) ItemCount() int {
+	n := 0
+	for _, c := range s.shards {
+		n += c.ItemCount()
+	}
+
+	return n
+}
+
+// Stop Stops the cleanup goroutines of the shards and closes them, see Cache.Stop.
+func (s *ShardedCache) Stop() {
+	for _, c := range s.shards {
+		c.Stop()
+	}
+}
@@ -0,0 +1,155 @@
+package go_cache
+
+import (
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_ShardedCache(t *testing.T) {
+	t.Run("methods", func(t *testing.T) {
+		sc := NewShardedCache(4, DefaultExpiration, 0)
+		defer sc.Stop()
+
+		sc.Set("aKey", "aValue", NoExpiration)
+		assert.NoError(t, sc.Add("bKey", "bValue", NoExpiration))
+		assert.ErrorIs(t, sc.Add("aKey", "aValue2", NoExpiration), ErrItemAlreadyExists)
+		assert.NoError(t, sc.Replace("aKey", "aValue2", NoExpiration))
+		assert.ErrorIs(t, sc.Replace("cKey", "cValue", NoExpiration), ErrItemNotFound)
+
+		value, found := sc.Get("aKey")
+		assert.True(t, found)
+		assert.Equal(t, "aValue2", value)
+		value, found = sc.Get("bKey")
+		assert.True(t, found)
+		assert.Equal(t, "bValue", value)
+		_, found = sc.Get("cKey")
+		assert.False(t, found)
+
+		sc.Delete("aKey")
+		_, found = sc.Get("aKey")
+		assert.False(t, found)
+		assert.Equal(t, 1, sc.ItemCount())
+	})
+
+	t.Run("spread", func(t *testing.T) {
+		sc := NewShardedCache(8, DefaultExpiration, 0)
+		defer sc.Stop()
+
+		const n = 8_000
+		for i := 0; i < n; i++ {
+			sc.Set(strconv.Itoa(i), i, NoExpiration)
+		}
+
+		assert.Equal(t, n, sc.ItemCount())
+		for _, c := range sc.shards {
+			assert.InDelta(t, n/8, c.ItemCount(), n/8/4)
+		}
+		sc.Flush()
+		assert.Equal(t, 0, sc.ItemCount())
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+		sc := NewShardedCache(4, 10*time.Millisecond, time.Millisecond)
+		defer sc.Stop()
+
+		for i := 0; i < 100; i++ {
+			sc.Set(strconv.Itoa(i), i, DefaultExpiration)
+		}
+		sc.Set("aKey", "aValue", NoExpiration)
+
+		assert.Eventually(t, func() bool { return sc.ItemCount() == 1 }, time.Second, time.Millisecond)
+		_, found := sc.Get("aKey")
+		assert.True(t, found)
+	})
+
+	t.Run("defaultShards", func(t *testing.T) {
+		sc := NewShardedCache(0, DefaultExpiration, 0)
+		defer sc.Stop()
+
+		assert.Len(t, sc.shards, runtime.GOMAXPROCS(0))
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		sc := NewShardedCache(4, DefaultExpiration, 0)
+		defer sc.Stop()
+
+		const workers, keys = 20, 100
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < keys; i++ {
+					key := strconv.Itoa(i)
+					sc.Set(key, i, NoExpiration)
+					value, found := sc.Get(key)
+					assert.True(t, found)
+					assert.Equal(t, i, value)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, keys, sc.ItemCount())
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		sc := NewShardedCache(2, DefaultExpiration, 0)
+		sc.Stop()
+
+		assert.ErrorIs(t, sc.Add("aKey", "aValue", NoExpiration), ErrCacheClosed)
+	})
+}
+
+// benchCache The methods of Cache and ShardedCache compared by BenchmarkCache_ShardedCache.
+type benchCache interface {
+	Set(key string, object any, duration time.Duration)
+	Get(key string) (any, bool)
+	Stop()
+}
+
+func BenchmarkCache_ShardedCache(b *testing.B) {
+	const n = 1_000
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+
+	for _, bc := range []struct {
+		name     string
+		newCache func() benchCache
+	}{
+		{"cache", func() benchCache { return NewCache(DefaultExpiration, 0) }},
+		{"sharded", func() benchCache { return NewShardedCache(32, DefaultExpiration, 0) }},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			c := bc.newCache()
+			defer c.Stop()
+			for i, key := range keys {
+				c.Set(key, i, NoExpiration)
+			}
+
+			// Many more goroutines than processors, each starting on a different key, one write for every three reads.
+			var goroutines atomic.Int64
+			b.SetParallelism(32)
+			b.RunParallel(func(pb *testing.PB) {
+				i := int(goroutines.Add(1)) * 7919
+				for pb.Next() {
+					key := keys[i%n]
+					if i%4 == 0 {
+						c.Set(key, i, NoExpiration)
+					} else {
+						c.Get(key)
+					}
+					i++
+				}
+			})
+		})
+	}
+}
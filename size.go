@@ -0,0 +1,95 @@
+package go_cache
+
+import (
+	"container/heap"
+	"fmt"
+	"reflect"
+)
+
+// Sizer Estimates the size in bytes of a value stored in the cache.
+type Sizer func(key string, value any) int64
+
+// ItemSize The estimated size of an item, as reported by LargestItems.
+type ItemSize struct {
+	Key  string
+	Size int64
+	// Type The type name of the value, e.g. "[]uint8" or "*main.User".
+	Type string
+}
+
+// defaultSizer Estimates the size of strings and byte slices as their length,
+// and of any other value as the size of its type, without following pointers.
+func defaultSizer(_ string, value any) int64 {
+	switch v := value.(type) {
+	case nil:
+		return 0
+	case string:
+		return int64(len(v))
+	case []byte:
+		return int64(len(v))
+	default:
+		return int64(reflect.TypeOf(value).Size())
+	}
+}
+
+// checkSize Returns an error wrapping ErrValueTooLarge, counted in the stats, if the value is larger than the size
+// set WithMaxValueSize, except for byte slices when the cache spills to disk, which are spilled instead.
+func (c *Cache) checkSize(key string, object any) error {
+	if c.maxValueSize <= 0 {
+		return nil
+	}
+	if _, ok := object.([]byte); ok && c.spill != nil {
+		return nil
+	}
+	if c.sizer(key, object) <= c.maxValueSize {
+		return nil
+	}
+	c.stats.oversizedWrites.Add(1)
+
+	return fmt.Errorf("%w: %s", ErrValueTooLarge, key)
+}
+
+// LargestItems Returns the n largest live items of the cache by estimated size, largest first, using the sizer
+// the cache was created with (see WithSizer), or the length of strings and byte slices and the shallow size of
+// any other type by default. The cache is scanned in chunks, so writers are not blocked for the whole scan.
+func (c *Cache) LargestItems(n int) []ItemSize {
+	if n <= 0 {
+		return nil
+	}
+	c.mu.RLock()
+	n = min(n, len(c.items))
+	c.mu.RUnlock()
+
+	h := make(itemSizeHeap, 0, n)
+	c.forEachChunked(func(key string, item item) bool {
+		size := c.sizer(key, item.object)
+		if len(h) < n {
+			heap.Push(&h, ItemSize{Key: key, Size: size, Type: fmt.Sprintf("%T", item.object)})
+		} else if size > h[0].Size {
+			h[0] = ItemSize{Key: key, Size: size, Type: fmt.Sprintf("%T", item.object)}
+			heap.Fix(&h, 0)
+		}
+		return true
+	})
+
+	largest := make([]ItemSize, len(h))
+	for i := len(h) - 1; i >= 0; i-- {
+		largest[i] = heap.Pop(&h).(ItemSize)
+	}
+
+	return largest
+}
+
+// itemSizeHeap A min-heap of item sizes, keeping the smallest of the largest items found so far on top.
+type itemSizeHeap []ItemSize
+
+func (h itemSizeHeap) Len() int           { return len(h) }
+func (h itemSizeHeap) Less(i, j int) bool { return h[i].Size < h[j].Size }
+func (h itemSizeHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *itemSizeHeap) Push(x any)        { *h = append(*h, x.(ItemSize)) }
+func (h *itemSizeHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,209 @@
+package go_cache
+
+import (
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_LargestItems(t *testing.T) {
+	t.Run("withDefaultSizer", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		tc.Set("bKey", make([]byte, 100), DefaultExpiration)
+		tc.Set("cKey", "cLongerValue", DefaultExpiration)
+		tc.Set("dKey", make([]byte, 1000), 1*time.Nanosecond)
+
+		<-time.After(1 * time.Millisecond)
+
+		largest := tc.LargestItems(2)
+		assert.Equal(t, []ItemSize{
+			{Key: "bKey", Size: 100, Type: "[]uint8"},
+			{Key: "cKey", Size: 12, Type: "string"},
+		}, largest)
+	})
+
+	t.Run("withSizer", func(t *testing.T) {
+		type TestStruct struct {
+			Size int64
+		}
+		sizer := func(key string, value any) int64 {
+			return value.(*TestStruct).Size
+		}
+		tc := NewCacheWithOptions(WithSizer(sizer))
+		defer tc.Stop()
+
+		for i := 0; i < 3*iterationChunkSize; i++ {
+			tc.Set(strconv.Itoa(i), &TestStruct{Size: int64(i)}, DefaultExpiration)
+		}
+
+		largest := tc.LargestItems(3)
+		assert.Equal(t, []ItemSize{
+			{Key: strconv.Itoa(3*iterationChunkSize - 1), Size: 3*iterationChunkSize - 1, Type: "*go_cache.TestStruct"},
+			{Key: strconv.Itoa(3*iterationChunkSize - 2), Size: 3*iterationChunkSize - 2, Type: "*go_cache.TestStruct"},
+			{Key: strconv.Itoa(3*iterationChunkSize - 3), Size: 3*iterationChunkSize - 3, Type: "*go_cache.TestStruct"},
+		}, largest)
+	})
+
+	t.Run("fewerItemsThanRequested", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+
+		largest := tc.LargestItems(5)
+		assert.Equal(t, []ItemSize{{Key: "aKey", Size: 6, Type: "string"}}, largest)
+
+		largest = tc.LargestItems(0)
+		assert.Empty(t, largest)
+
+		largest = tc.LargestItems(math.MaxInt)
+		assert.Len(t, largest, 1)
+	})
+
+	t.Run("doesNotBlockWriters", func(t *testing.T) {
+		written := make(chan struct{})
+		var once sync.Once
+		var tc *Cache
+		sizer := func(key string, value any) int64 {
+			once.Do(func() {
+				go func() {
+					tc.Set("newKey", "newValue", DefaultExpiration)
+					close(written)
+				}()
+				select {
+				case <-written:
+				case <-time.After(1 * time.Second):
+					t.Error("writer blocked by scan")
+				}
+			})
+			return 1
+		}
+		tc = NewCacheWithOptions(WithSizer(sizer))
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		tc.Set("bKey", "bValue", DefaultExpiration)
+
+		largest := tc.LargestItems(2)
+		assert.Len(t, largest, 2)
+	})
+}
+
+func TestCache_WithMaxValueSize(t *testing.T) {
+	t.Run("boundary", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMaxValueSize(10))
+		defer tc.Stop()
+
+		assert.Nil(t, tc.Add("aKey", "0123456789", DefaultExpiration))
+		err := tc.Add("bKey", "0123456789a", DefaultExpiration)
+		assert.ErrorIs(t, err, ErrValueTooLarge)
+		err = tc.Replace("aKey", make([]byte, 11), DefaultExpiration)
+		assert.ErrorIs(t, err, ErrValueTooLarge)
+
+		// Set drops the value, leaving the key as it was.
+		tc.Set("aKey", "0123456789a", DefaultExpiration)
+		a, found := tc.Get("aKey")
+		assert.True(t, found)
+		assert.Equal(t, "0123456789", a)
+		_, found = tc.Get("bKey")
+		assert.False(t, found)
+
+		assert.Equal(t, uint64(3), tc.Stats().OversizedWrites)
+	})
+
+	t.Run("errorReturningWrites", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMaxValueSize(10), WithSizer(func(key string, value any) int64 {
+			if n, ok := value.(int64); ok {
+				return n
+			}
+			return defaultSizer(key, value)
+		}))
+		defer tc.Stop()
+		large := "0123456789a"
+
+		assert.ErrorIs(t, tc.SetIfVersion("aKey", large, DefaultExpiration, 0), ErrValueTooLarge)
+		_, err := tc.SetIfNewer("aKey", large, DefaultExpiration, time.Now())
+		assert.ErrorIs(t, err, ErrValueTooLarge)
+		result, err := tc.SetMany(map[string]any{"aKey": large, "bKey": "bValue"}, DefaultExpiration)
+		assert.ErrorIs(t, err, ErrValueTooLarge)
+		assert.Equal(t, BatchFailed, result["aKey"].Status)
+		assert.Equal(t, BatchOK, result["bKey"].Status)
+		_, err = tc.AddMany(map[string]any{"cKey": large}, DefaultExpiration)
+		assert.ErrorIs(t, err, ErrValueTooLarge)
+		_, err = tc.IncrementWithTTL("dKey", 11, DefaultExpiration)
+		assert.ErrorIs(t, err, ErrValueTooLarge)
+
+		// A transaction is rejected as a whole.
+		err = tc.Tx(func(tx *Txn) error {
+			tx.Set("eKey", "eValue", DefaultExpiration)
+			tx.Set("fKey", large, DefaultExpiration)
+			return nil
+		})
+		assert.ErrorIs(t, err, ErrValueTooLarge)
+		_, found := tc.Get("eKey")
+		assert.False(t, found)
+
+		assert.Equal(t, 1, tc.ItemCount())
+		assert.Equal(t, uint64(6), tc.Stats().OversizedWrites)
+	})
+
+	t.Run("noEviction", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMaxValueSize(10), WithMaxCost(10))
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		assert.ErrorIs(t, tc.Add("bKey", "0123456789a", DefaultExpiration), ErrValueTooLarge)
+
+		_, found := tc.Get("aKey")
+		assert.True(t, found)
+		assert.Equal(t, uint64(0), tc.Stats().Evictions)
+	})
+
+	t.Run("spillover", func(t *testing.T) {
+		dir := t.TempDir()
+		tc := NewCacheWithOptions(WithMaxValueSize(10), WithSpillover(dir, 1024))
+		defer tc.Stop()
+
+		value := []byte("0123456789a")
+		assert.Nil(t, tc.Add("aKey", value, DefaultExpiration))
+		assert.ErrorIs(t, tc.Add("bKey", "0123456789a", DefaultExpiration), ErrValueTooLarge)
+
+		// Spilled although under the threshold of the spillover, since over the limit.
+		assert.Len(t, spillFiles(t, dir), 1)
+		a, _ := tc.Get("aKey")
+		assert.Equal(t, value, a)
+		assert.Equal(t, uint64(1), tc.Stats().OversizedWrites)
+
+		tc.Set("cKey", []byte("0123456789"), DefaultExpiration)
+		assert.Len(t, spillFiles(t, dir), 1)
+	})
+
+	t.Run("spillFailure", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMaxValueSize(10), WithSpillover(filepath.Join(t.TempDir(), "missing"), 1024))
+		defer tc.Stop()
+
+		assert.ErrorIs(t, tc.Add("aKey", []byte("0123456789a"), DefaultExpiration), ErrValueTooLarge)
+		assert.Nil(t, tc.Add("bKey", []byte("0123456789"), DefaultExpiration))
+		assert.Equal(t, 1, tc.ItemCount())
+	})
+
+	t.Run("loadJSONLines", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithMaxValueSize(10))
+		defer tc.Stop()
+
+		report, err := tc.LoadJSONLines(strings.NewReader(`{"key": "aKey", "value": "aValue"}
+{"key": "bKey", "value": "0123456789a"}
+`))
+		assert.Nil(t, err)
+		assert.Equal(t, LoadReport{Lines: 2, Loaded: 1, TooLarge: 1}, report)
+	})
+}
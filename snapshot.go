@@ -0,0 +1,296 @@
+package go_cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSnapshot is returned when reading data which is not a snapshot written by this package.
+var ErrInvalidSnapshot = errors.New("invalid snapshot")
+
+const (
+	snapshotFormat  = "go-cache snapshot"
+	snapshotVersion = 1
+)
+
+// Codec Encodes the values of the cache into snapshots, and decodes them back.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte) (any, error)
+}
+
+// GobCodec The default Codec, encoding values with encoding/gob. As with any value held in an interface,
+// the types of the values other than the basic ones must be registered with gob.Register.
+type GobCodec struct{}
+
+// Marshal Encodes the value with encoding/gob.
+func (GobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Unmarshal Decodes a value encoded by Marshal.
+func (GobCodec) Unmarshal(data []byte) (any, error) {
+	var v any
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// Snapshot The contents of a cache at a given time, as written by Save and read by Load.
+type Snapshot struct {
+	// CreatedAt The time at which the snapshot was taken.
+	CreatedAt time.Time
+	// Seq The sequence number of the last change included, see WithChangeTracking, 0 if changes were not tracked.
+	Seq   uint64
+	Items []SnapshotItem
+}
+
+// SnapshotItem An item of a snapshot, whose value is kept encoded so that snapshots can be handled without
+// knowing the types of their values.
+type SnapshotItem struct {
+	Key string
+	// Type The Go type of the value, as printed by the %T verb of the fmt package.
+	Type string
+	// Data The value, encoded with the codec of the cache.
+	Data []byte
+	// Expiration The time at which the item expires, or the zero time if it never expires.
+	Expiration time.Time
+	// Seq The sequence number of the last change of the item, see WithChangeTracking.
+	Seq uint64
+}
+
+// Expired Reports whether the item has expired at the given time.
+func (i SnapshotItem) Expired(at time.Time) bool {
+	return !i.Expiration.IsZero() && !i.Expiration.After(at)
+}
+
+type snapshotHeader struct {
+	Format    string
+	Version   int
+	CreatedAt time.Time
+	Seq       uint64
+}
+
+// WriteSnapshot Writes the snapshot to w, in the format read by ReadSnapshot and Load.
+func WriteSnapshot(w io.Writer, s Snapshot) error {
+	enc := gob.NewEncoder(w)
+	if err := enc.Encode(snapshotHeader{Format: snapshotFormat, Version: snapshotVersion, CreatedAt: s.CreatedAt, Seq: s.Seq}); err != nil {
+		return err
+	}
+	for _, item := range s.Items {
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadSnapshot Reads a snapshot written by Save or WriteSnapshot from r.
+func ReadSnapshot(r io.Reader) (Snapshot, error) {
+	var s Snapshot
+	err := readSnapshot(r, func(h snapshotHeader) {
+		s.CreatedAt, s.Seq = h.CreatedAt, h.Seq
+	}, func(item SnapshotItem) error {
+		s.Items = append(s.Items, item)
+		return nil
+	})
+
+	return s, err
+}
+
+// readSnapshot Reads a snapshot from r, streaming its items to fn.
+func readSnapshot(r io.Reader, header func(h snapshotHeader), fn func(item SnapshotItem) error) error {
+	dec := gob.NewDecoder(r)
+
+	var h snapshotHeader
+	if err := dec.Decode(&h); err != nil || h.Format != snapshotFormat {
+		return fmt.Errorf("%w: missing header", ErrInvalidSnapshot)
+	}
+	if h.Version > snapshotVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidSnapshot, h.Version)
+	}
+	header(h)
+
+	return decodeItems(dec, fn)
+}
+
+// decodeItems Decodes the items following the header of a snapshot or of a diff, streaming them to fn.
+func decodeItems(dec *gob.Decoder, fn func(item SnapshotItem) error) error {
+	for {
+		var item SnapshotItem
+		if err := dec.Decode(&item); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+}
+
+// Save Writes the live items of the cache to w, encoding their values with the codec of the cache
+// (see WithCodec), so that they can be restored with Load. The items are read in chunks, so writers are not
+// blocked for the whole save, and changes happening meanwhile may or may not be included.
+// Negative entries and values staged with SetVisibleAt that are not visible yet are not saved. With
+// WithChangeTracking, the sequence numbers of the cache and of its items are saved too, so that SaveDiff can
+// continue from the snapshot.
+// Returns ErrHashedKeys error if the cache was created WithHashedKeys, since the keys are not stored,
+// and ErrCacheClosed error if the cache is stopped before the save completes, since it no longer holds any item.
+func (c *Cache) Save(w io.Writer) error {
+	return c.save(w, "")
+}
+
+// SavePrefix Writes the live items of the cache whose key starts with the given prefix to w like Save, e.g. to move
+// a namespace (see Namespace) to another cache, where Load can import them under another prefix (see
+// WithLoadPrefix).
+func (c *Cache) SavePrefix(w io.Writer, prefix string) error {
+	return c.save(w, prefix)
+}
+
+// save Writes the live items of the cache whose key starts with the given prefix to w, see Save.
+func (c *Cache) save(w io.Writer, prefix string) error {
+	if c.hasher != nil {
+		return ErrHashedKeys
+	}
+	if c.isClosed() {
+		return ErrCacheClosed
+	}
+
+	h := snapshotHeader{Format: snapshotFormat, Version: snapshotVersion, CreatedAt: c.now()}
+	if c.changes != nil {
+		c.mu.RLock()
+		h.Seq = c.changes.seq
+		c.mu.RUnlock()
+	}
+	enc := gob.NewEncoder(w)
+	if err := enc.Encode(h); err != nil {
+		return err
+	}
+
+	var err error
+	c.forEachValueChunked(func(key string, item item, value any) bool {
+		if !strings.HasPrefix(key, prefix) {
+			return true
+		}
+		var data []byte
+		if data, err = c.codec.Marshal(value); err != nil {
+			err = fmt.Errorf("could not encode value of %s: %w", key, err)
+			return false
+		}
+		err = enc.Encode(c.snapshotItem(key, item, data))
+		return err == nil
+	})
+	if err == nil && c.isClosed() {
+		err = ErrCacheClosed
+	}
+
+	return err
+}
+
+// snapshotItem Returns the snapshot of the item, whose value was encoded into data.
+func (c *Cache) snapshotItem(key string, item item, data []byte) SnapshotItem {
+	return SnapshotItem{Key: key, Type: fmt.Sprintf("%T", item.object), Data: data, Expiration: item.info().Expiration, Seq: item.seq}
+}
+
+// Load Adds the items read from a snapshot written by Save to the cache, replacing any existing item with
+// the same key. Items keep their expiration time, and those which have expired by now are skipped.
+// The items are written in chunks, so readers may observe a partially loaded snapshot. With WithChangeTracking,
+// the items keep their sequence numbers, and those of the cache continue after the one of the snapshot.
+// With WithLoadPrefix, only the items whose key starts with the prefix are loaded, possibly under another prefix,
+// and other items of the cache are left alone. The other options of LoadJSONLines do not apply.
+// Returns an error wrapping ErrValueTooLarge if an item is larger than the size set WithMaxValueSize, leaving
+// the items read before it loaded, and ErrHashedKeys error if the cache was created WithHashedKeys, since the keys
+// were not stored.
+func (c *Cache) Load(r io.Reader, opts ...LoadOption) error {
+	if c.hasher != nil {
+		return ErrHashedKeys
+	}
+	var o loadOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return c.loadItems(o.rename, func(fn func(item SnapshotItem) error) error {
+		return readSnapshot(r, func(h snapshotHeader) {
+			if c.changes != nil {
+				c.mu.Lock()
+				c.changes.advance(h.Seq)
+				c.mu.Unlock()
+			}
+		}, fn)
+	})
+}
+
+// loadItems Writes the items streamed by read to the cache in chunks, see Load, under the key returned by rename
+// if not nil, skipping those it does not accept.
+func (c *Cache) loadItems(rename func(key string) (string, bool), read func(fn func(item SnapshotItem) error) error) error {
+	batch := make([]SnapshotItem, 0, iterationChunkSize)
+	flush := func() error {
+		c.mu.Lock()
+		defer c.unlock()
+
+		if c.closed {
+			return ErrCacheClosed
+		}
+		now := c.now()
+		for _, item := range batch {
+			if item.Expired(now) {
+				continue
+			}
+			if rename != nil {
+				var accepted bool
+				if item.Key, accepted = rename(item.Key); !accepted {
+					continue
+				}
+			}
+			object, err := c.codec.Unmarshal(item.Data)
+			if err != nil {
+				return fmt.Errorf("could not decode value of %s: %w", item.Key, err)
+			}
+			it := c.newItem(item.Key, object, NoExpiration)
+			if !item.Expiration.IsZero() {
+				it.expiration = item.Expiration.UnixNano()
+			}
+			if err := c.insert(item.Key, it); err != nil {
+				return err
+			}
+			if c.changes != nil && item.Seq != 0 {
+				if stored, found := c.items[item.Key]; found {
+					stored.seq = item.Seq
+					c.items[item.Key] = stored
+				}
+			}
+		}
+		batch = batch[:0]
+
+		return nil
+	}
+
+	err := read(func(item SnapshotItem) error {
+		batch = append(batch, item)
+		if len(batch) < iterationChunkSize {
+			return nil
+		}
+		return flush()
+	})
+	if err != nil {
+		return err
+	}
+
+	return flush()
+}
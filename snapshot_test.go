@@ -0,0 +1,233 @@
+package go_cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type snapshotValue struct {
+	Name  string
+	Count int
+}
+
+func init() {
+	gob.Register(snapshotValue{})
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte) (any, error) {
+	var v any
+	err := json.Unmarshal(data, &v)
+	return v, err
+}
+
+func TestCache_SaveAndLoad(t *testing.T) {
+	t.Run("roundTrip", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", snapshotValue{Name: "b", Count: 2}, 1*time.Minute)
+		tc.Set("cKey", []byte{1, 2, 3}, 1*time.Second)
+		tc.Set("dKey", 4, 1*time.Second)
+		tc.SetNegative("eKey", DefaultExpiration)
+		clock.Advance(2 * time.Second)
+		tc.Set("cKey", []byte{1, 2, 3}, 10*time.Second)
+
+		var buf bytes.Buffer
+		assert.NoError(t, tc.Save(&buf))
+
+		oc := NewCache(DefaultExpiration, 0)
+		oc.now = clock.Now
+		defer oc.Stop()
+		oc.Set("aKey", "existing", DefaultExpiration)
+		assert.NoError(t, oc.Load(&buf))
+
+		assert.Equal(t, 3, oc.ItemCount())
+		value, _ := oc.Get("aKey")
+		assert.Equal(t, "aValue", value)
+		value, _ = oc.Get("bKey")
+		assert.Equal(t, snapshotValue{Name: "b", Count: 2}, value)
+		value, _ = oc.Get("cKey")
+		assert.Equal(t, []byte{1, 2, 3}, value)
+
+		info, _ := oc.Info("bKey")
+		assert.True(t, clock.Now().Add(58*time.Second).Equal(info.Expiration))
+		info, _ = oc.Info("aKey")
+		assert.True(t, info.Expiration.IsZero())
+	})
+
+	t.Run("expiredOnLoad", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", 1*time.Minute)
+		tc.Set("bKey", "bValue", 1*time.Hour)
+
+		var buf bytes.Buffer
+		assert.NoError(t, tc.Save(&buf))
+
+		clock.Advance(30 * time.Minute)
+		oc := NewCache(DefaultExpiration, 0)
+		oc.now = clock.Now
+		defer oc.Stop()
+		assert.NoError(t, oc.Load(&buf))
+
+		_, found := oc.Get("aKey")
+		assert.False(t, found)
+		assert.Equal(t, 1, oc.ItemCount())
+	})
+
+	t.Run("customCodec", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithCodec(jsonCodec{}))
+		defer tc.Stop()
+
+		tc.Set("aKey", map[string]any{"a": 1.5}, DefaultExpiration)
+
+		var buf bytes.Buffer
+		assert.NoError(t, tc.Save(&buf))
+
+		s, err := ReadSnapshot(bytes.NewReader(buf.Bytes()))
+		assert.NoError(t, err)
+		assert.Len(t, s.Items, 1)
+		assert.Equal(t, `{"a":1.5}`, string(s.Items[0].Data))
+		assert.Equal(t, "map[string]interface {}", s.Items[0].Type)
+
+		oc := NewCacheWithOptions(WithCodec(jsonCodec{}))
+		defer oc.Stop()
+		assert.NoError(t, oc.Load(&buf))
+		value, _ := oc.Get("aKey")
+		assert.Equal(t, map[string]any{"a": 1.5}, value)
+	})
+
+	t.Run("unregisteredType", func(t *testing.T) {
+		type unregistered struct{ A int }
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", unregistered{A: 1}, DefaultExpiration)
+		err := tc.Save(&bytes.Buffer{})
+		assert.ErrorContains(t, err, "aKey")
+	})
+
+	t.Run("hashedKeys", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithHashedKeys())
+		defer tc.Stop()
+
+		assert.ErrorIs(t, tc.Save(&bytes.Buffer{}), ErrHashedKeys)
+		assert.ErrorIs(t, tc.Load(&bytes.Buffer{}), ErrHashedKeys)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Stop()
+
+		// An empty snapshot would otherwise replace the last good one.
+		assert.ErrorIs(t, tc.Save(&bytes.Buffer{}), ErrCacheClosed)
+	})
+
+	t.Run("invalidSnapshot", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		assert.ErrorIs(t, tc.Load(strings.NewReader("not a snapshot")), ErrInvalidSnapshot)
+	})
+
+	t.Run("valueTooLarge", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+		tc.Set("aKey", "a", NoExpiration)
+		tc.Set("bKey", strings.Repeat("b", 1024), NoExpiration)
+
+		var buf bytes.Buffer
+		assert.NoError(t, tc.Save(&buf))
+
+		oc := NewCacheWithOptions(WithMaxValueSize(64))
+		defer oc.Stop()
+
+		err := oc.Load(&buf)
+		assert.ErrorIs(t, err, ErrValueTooLarge)
+		assert.ErrorContains(t, err, "bKey")
+		_, found := oc.Get("bKey")
+		assert.False(t, found)
+	})
+}
+
+func TestWriteAndReadSnapshot(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := Snapshot{
+		CreatedAt: createdAt,
+		Items: []SnapshotItem{
+			{Key: "aKey", Type: "string", Data: []byte("a"), Expiration: createdAt.Add(1 * time.Minute)},
+			{Key: "bKey", Type: "int", Data: []byte("b")},
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteSnapshot(&buf, s))
+
+	read, err := ReadSnapshot(&buf)
+	assert.NoError(t, err)
+	assert.True(t, createdAt.Equal(read.CreatedAt))
+	assert.Len(t, read.Items, 2)
+	assert.Equal(t, "aKey", read.Items[0].Key)
+	assert.True(t, read.Items[0].Expired(createdAt.Add(1*time.Minute)))
+	assert.False(t, read.Items[0].Expired(createdAt))
+	assert.False(t, read.Items[1].Expired(createdAt.Add(100*time.Hour)))
+}
+
+func TestCache_SavePrefix(t *testing.T) {
+	clock := newFakeClock()
+	src := NewCache(NoExpiration, 0)
+	src.now = clock.Now
+	defer src.Stop()
+	src.Set("sessions:a", "aValue", time.Hour)
+	src.Set("sessions:b", snapshotValue{Name: "b", Count: 2}, NoExpiration)
+	src.Set("users:a", "uValue", NoExpiration)
+	src.Set("sessionsa", "other", NoExpiration)
+
+	var buf bytes.Buffer
+	assert.Nil(t, src.SavePrefix(&buf, "sessions:"))
+	s, err := ReadSnapshot(bytes.NewReader(buf.Bytes()))
+	assert.Nil(t, err)
+	assert.Len(t, s.Items, 2)
+
+	dst := NewCache(NoExpiration, 0)
+	dst.now = clock.Now
+	defer dst.Stop()
+	dst.Set("s2:a", "old", NoExpiration)
+	dst.Set("sessions:c", "cValue", NoExpiration)
+	assert.Nil(t, dst.Load(bytes.NewReader(buf.Bytes()), WithLoadPrefix("sessions:", "s2:")))
+
+	items, _ := dst.Items()
+	assert.Len(t, items, 3)
+	assert.Equal(t, "aValue", items["s2:a"].Object)
+	assert.True(t, clock.Now().Add(time.Hour).Equal(items["s2:a"].Expiration))
+	assert.Equal(t, ItemInfo{Object: snapshotValue{Name: "b", Count: 2}}, items["s2:b"])
+	assert.Equal(t, ItemInfo{Object: "cValue"}, items["sessions:c"])
+
+	// Only the keys under the prefix are imported from a full snapshot.
+	buf.Reset()
+	assert.Nil(t, src.Save(&buf))
+	other := NewCache(NoExpiration, 0)
+	defer other.Stop()
+	assert.Nil(t, other.Load(&buf, WithLoadPrefix("users:", "users:")))
+	keys, _ := other.Keys()
+	assert.Equal(t, []string{"users:a"}, keys)
+}
@@ -0,0 +1,244 @@
+package go_cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNoSnapshot is returned by RestoreLatest when the store holds no snapshot.
+var ErrNoSnapshot = errors.New("no snapshot found")
+
+const (
+	// snapshotPrefix Prefix of the names given to snapshots by SaveSnapshot, followed by the time they were taken,
+	// so that names sort in chronological order.
+	snapshotPrefix     = "snapshot-"
+	snapshotTimeLayout = "20060102T150405.000000000Z"
+)
+
+// SnapshotStore Stores the snapshots of a cache by name, see SaveSnapshot, RestoreLatest and WithAutoSnapshot.
+// Implementations may store snapshots anywhere, e.g. in object storage, and must be safe for concurrent use.
+type SnapshotStore interface {
+	// Put Stores the snapshot read from r under the given name, replacing any snapshot with the same name.
+	Put(ctx context.Context, name string, r io.Reader) error
+	// Get Returns a reader for the snapshot stored under the given name, which the caller closes.
+	// Returns an error wrapping ErrItemNotFound if there is none.
+	Get(ctx context.Context, name string) (io.ReadCloser, error)
+	// List Returns the names of the stored snapshots, in any order.
+	List(ctx context.Context) ([]string, error)
+	// Delete Removes the snapshot stored under the given name. Deleting a missing snapshot is not an error.
+	Delete(ctx context.Context, name string) error
+}
+
+// SaveSnapshot Writes a snapshot of the cache, as written by Save, to the given store under a name made of the
+// current time, and returns that name. If the cache was created WithSnapshotRetention, older snapshots are then
+// deleted from the store so that only the configured number of them is kept.
+func (c *Cache) SaveSnapshot(ctx context.Context, store SnapshotStore) (string, error) {
+	name := snapshotPrefix + c.now().UTC().Format(snapshotTimeLayout)
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(c.Save(pw))
+	}()
+	err := store.Put(ctx, name, pr)
+	pr.CloseWithError(err)
+	if err != nil {
+		return "", fmt.Errorf("could not store snapshot %s: %w", name, err)
+	}
+
+	if c.snapshotRetention > 0 {
+		if err := c.pruneSnapshots(ctx, store); err != nil {
+			return name, err
+		}
+	}
+
+	return name, nil
+}
+
+// RestoreLatest Loads the most recent snapshot saved to the given store by SaveSnapshot into the cache, see Load,
+// and returns its name. Returns ErrNoSnapshot error if the store holds no snapshot.
+func (c *Cache) RestoreLatest(ctx context.Context, store SnapshotStore) (string, error) {
+	names, err := listSnapshots(ctx, store)
+	if err != nil {
+		return "", err
+	}
+	if len(names) == 0 {
+		return "", ErrNoSnapshot
+	}
+
+	name := names[len(names)-1]
+	r, err := store.Get(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("could not read snapshot %s: %w", name, err)
+	}
+	defer r.Close()
+
+	if err := c.Load(r); err != nil {
+		return "", fmt.Errorf("could not load snapshot %s: %w", name, err)
+	}
+
+	return name, nil
+}
+
+// pruneSnapshots Deletes the oldest snapshots of the store so that only snapshotRetention of them are left.
+func (c *Cache) pruneSnapshots(ctx context.Context, store SnapshotStore) error {
+	names, err := listSnapshots(ctx, store)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, name := range names[:max(0, len(names)-c.snapshotRetention)] {
+		if err := store.Delete(ctx, name); err != nil {
+			errs = append(errs, fmt.Errorf("could not delete snapshot %s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// listSnapshots Returns the names of the snapshots saved to the store by SaveSnapshot, oldest first.
+// Other entries of the store are ignored.
+func listSnapshots(ctx context.Context, store SnapshotStore) ([]string, error) {
+	all, err := store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list snapshots: %w", err)
+	}
+
+	names := make([]string, 0, len(all))
+	for _, name := range all {
+		if _, err := time.Parse(snapshotTimeLayout, strings.TrimPrefix(name, snapshotPrefix)); err == nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// startSnapshotter Starts the goroutine saving a snapshot of the cache to the given store at the given interval
+// until the cache is stopped.
+func (c *Cache) startSnapshotter(store SnapshotStore, interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer cancel()
+
+		t := time.NewTicker(interval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-t.C:
+				_, err := c.SaveSnapshot(ctx, store)
+				if errors.Is(err, ErrCacheClosed) {
+					// Stop was called during the save.
+					return
+				}
+				if err != nil {
+					c.handleError(err)
+				}
+				c.health.snapshotRan(c.now(), err)
+			}
+		}
+	}()
+}
+
+// DirSnapshotStore A SnapshotStore keeping snapshots as files of a directory.
+type DirSnapshotStore struct {
+	dir string
+}
+
+// NewDirSnapshotStore Returns a SnapshotStore keeping snapshots as files of the given directory,
+// which must exist.
+func NewDirSnapshotStore(dir string) *DirSnapshotStore {
+	return &DirSnapshotStore{dir: dir}
+}
+
+// Put Writes the snapshot to a temporary file of the directory, renamed once complete, so that
+// a partially written snapshot is never visible under its name.
+func (s *DirSnapshotStore) Put(_ context.Context, name string, r io.Reader) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(s.dir, "."+name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// Get Opens the file of the snapshot.
+func (s *DirSnapshotStore) Get(_ context.Context, name string) (io.ReadCloser, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrItemNotFound, name)
+	}
+
+	return f, err
+}
+
+// List Returns the names of the regular files of the directory, skipping the temporary files of Put.
+func (s *DirSnapshotStore) List(_ context.Context) ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && !strings.HasPrefix(entry.Name(), ".") {
+			names = append(names, entry.Name())
+		}
+	}
+
+	return names, nil
+}
+
+// Delete Removes the file of the snapshot.
+func (s *DirSnapshotStore) Delete(_ context.Context, name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *DirSnapshotStore) path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid snapshot name %q", name)
+	}
+
+	return filepath.Join(s.dir, name), nil
+}
@@ -0,0 +1,231 @@
+package go_cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memSnapshotStore A SnapshotStore keeping snapshots in memory.
+type memSnapshotStore struct {
+	mu        sync.Mutex
+	snapshots map[string][]byte
+}
+
+func newMemSnapshotStore() *memSnapshotStore {
+	return &memSnapshotStore{snapshots: make(map[string][]byte)}
+}
+
+func (s *memSnapshotStore) Put(_ context.Context, name string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshots[name] = data
+
+	return nil
+}
+
+func (s *memSnapshotStore) Get(_ context.Context, name string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, found := s.snapshots[name]
+	if !found {
+		return nil, ErrItemNotFound
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *memSnapshotStore) List(_ context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.snapshots))
+	for name := range s.snapshots {
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+func (s *memSnapshotStore) Delete(_ context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.snapshots, name)
+
+	return nil
+}
+
+func (s *memSnapshotStore) names() []string {
+	names, _ := s.List(context.Background())
+	sort.Strings(names)
+	return names
+}
+
+// failingSnapshotStore A SnapshotStore failing to store anything.
+type failingSnapshotStore struct {
+	memSnapshotStore
+}
+
+func (s *failingSnapshotStore) Put(context.Context, string, io.Reader) error {
+	return errors.New("store unavailable")
+}
+
+func TestCache_SaveSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("restoreLatest", func(t *testing.T) {
+		store := newMemSnapshotStore()
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		for _, value := range []string{"first", "second", "third"} {
+			tc.Set("aKey", value, NoExpiration)
+			_, err := tc.SaveSnapshot(ctx, store)
+			assert.NoError(t, err)
+			clock.Advance(time.Minute)
+		}
+		assert.Len(t, store.names(), 3)
+
+		restored := NewCache(DefaultExpiration, 0)
+		defer restored.Stop()
+		name, err := restored.RestoreLatest(ctx, store)
+		assert.NoError(t, err)
+		assert.Equal(t, "snapshot-20240101T120200.000000000Z", name)
+
+		value, found := restored.Get("aKey")
+		assert.True(t, found)
+		assert.Equal(t, "third", value)
+	})
+
+	t.Run("retention", func(t *testing.T) {
+		store := newMemSnapshotStore()
+		assert.NoError(t, store.Put(ctx, "other", bytes.NewReader(nil)))
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithSnapshotRetention(2))
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		for i := 0; i < 4; i++ {
+			_, err := tc.SaveSnapshot(ctx, store)
+			assert.NoError(t, err)
+			clock.Advance(time.Minute)
+		}
+
+		// Entries which were not saved by SaveSnapshot are left alone.
+		assert.Equal(t, []string{
+			"other",
+			"snapshot-20240101T120200.000000000Z",
+			"snapshot-20240101T120300.000000000Z",
+		}, store.names())
+	})
+
+	t.Run("noSnapshot", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		_, err := tc.RestoreLatest(ctx, newMemSnapshotStore())
+		assert.ErrorIs(t, err, ErrNoSnapshot)
+	})
+
+	t.Run("storeFailure", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+		tc.Set("aKey", "aValue", NoExpiration)
+
+		_, err := tc.SaveSnapshot(ctx, &failingSnapshotStore{})
+		assert.Error(t, err)
+	})
+}
+
+func TestCache_AutoSnapshot(t *testing.T) {
+	t.Run("saves", func(t *testing.T) {
+		store := newMemSnapshotStore()
+		tc := NewCacheWithOptions(WithAutoSnapshot(store, 10*time.Millisecond), WithSnapshotRetention(2))
+		tc.Set("aKey", "aValue", NoExpiration)
+
+		<-time.After(100 * time.Millisecond)
+		tc.Stop()
+
+		assert.Len(t, store.names(), 2)
+		report := tc.Health()
+		assert.True(t, report.SnapshotEnabled)
+		assert.False(t, report.SnapshotLastRun.IsZero())
+		assert.NoError(t, report.SnapshotLastError)
+
+		restored := NewCache(DefaultExpiration, 0)
+		defer restored.Stop()
+		_, err := restored.RestoreLatest(context.Background(), store)
+		assert.NoError(t, err)
+		value, found := restored.Get("aKey")
+		assert.True(t, found)
+		assert.Equal(t, "aValue", value)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		errs := make(chan error, 100)
+		tc := NewCacheWithOptions(
+			WithAutoSnapshot(&failingSnapshotStore{}, 10*time.Millisecond),
+			WithErrorHandler(func(err error) {
+				select {
+				case errs <- err:
+				default:
+				}
+			}),
+		)
+		defer tc.Stop()
+
+		<-time.After(50 * time.Millisecond)
+
+		assert.NotEmpty(t, errs)
+		report := tc.Health()
+		assert.Equal(t, HealthDegraded, report.Status)
+		assert.Error(t, report.SnapshotLastError)
+	})
+}
+
+func TestDirSnapshotStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewDirSnapshotStore(t.TempDir())
+
+	tc := NewCache(DefaultExpiration, 0)
+	defer tc.Stop()
+	tc.Set("aKey", "aValue", NoExpiration)
+	name, err := tc.SaveSnapshot(ctx, store)
+	assert.NoError(t, err)
+
+	names, err := store.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{name}, names)
+
+	restored := NewCache(DefaultExpiration, 0)
+	defer restored.Stop()
+	_, err = restored.RestoreLatest(ctx, store)
+	assert.NoError(t, err)
+	value, found := restored.Get("aKey")
+	assert.True(t, found)
+	assert.Equal(t, "aValue", value)
+
+	assert.NoError(t, store.Delete(ctx, name))
+	assert.NoError(t, store.Delete(ctx, name))
+	_, err = store.Get(ctx, name)
+	assert.ErrorIs(t, err, ErrItemNotFound)
+
+	for _, name := range []string{"", "../escape", "a/b", ".hidden"} {
+		assert.Error(t, store.Put(ctx, name, bytes.NewReader(nil)), name)
+	}
+}
@@ -0,0 +1,174 @@
+package go_cache
+
+import (
+	"math/bits"
+	"math/rand/v2"
+	"strings"
+)
+
+const skipListMaxLevel = 32
+
+// skipList An ordered set of keys supporting insertion, removal and seeking in O(log n).
+type skipList struct {
+	head  *skipNode
+	level int
+}
+
+type skipNode struct {
+	key  string
+	next []*skipNode
+}
+
+func newSkipList() *skipList {
+	return &skipList{head: &skipNode{next: make([]*skipNode, skipListMaxLevel)}, level: 1}
+}
+
+// randomLevel Returns a level between 1 and skipListMaxLevel, each level being 4 times less likely than the previous one.
+func randomLevel() int {
+	level := 1 + bits.TrailingZeros64(rand.Uint64()|1<<62)/2
+	if level > skipListMaxLevel {
+		level = skipListMaxLevel
+	}
+
+	return level
+}
+
+// predecessors Returns, for each level, the last node whose key is lower than the given one.
+func (s *skipList) predecessors(key string) [skipListMaxLevel]*skipNode {
+	var update [skipListMaxLevel]*skipNode
+	n := s.head
+	for l := s.level - 1; l >= 0; l-- {
+		for n.next[l] != nil && n.next[l].key < key {
+			n = n.next[l]
+		}
+		update[l] = n
+	}
+
+	return update
+}
+
+func (s *skipList) insert(key string) {
+	update := s.predecessors(key)
+	if next := update[0].next[0]; next != nil && next.key == key {
+		return
+	}
+
+	level := randomLevel()
+	for l := s.level; l < level; l++ {
+		update[l] = s.head
+	}
+	if level > s.level {
+		s.level = level
+	}
+
+	n := &skipNode{key: key, next: make([]*skipNode, level)}
+	for l := 0; l < level; l++ {
+		n.next[l] = update[l].next[l]
+		update[l].next[l] = n
+	}
+}
+
+func (s *skipList) remove(key string) {
+	update := s.predecessors(key)
+	n := update[0].next[0]
+	if n == nil || n.key != key {
+		return
+	}
+
+	for l := 0; l < len(n.next); l++ {
+		update[l].next[l] = n.next[l]
+	}
+	for s.level > 1 && s.head.next[s.level-1] == nil {
+		s.level--
+	}
+}
+
+// seek Returns the first node whose key is greater than or equal to the given one, or nil.
+func (s *skipList) seek(key string) *skipNode {
+	return s.predecessors(key)[0].next[0]
+}
+
+func (s *skipList) reset() {
+	s.head = &skipNode{next: make([]*skipNode, skipListMaxLevel)}
+	s.level = 1
+}
+
+// ascend Calls fn, in ascending key order, for each live item whose key is greater than or equal to from.
+// The read lock is taken for chunks of iterationChunkSize keys at a time, and fn is called without holding it,
+// each item being read again right before, so that items removed in the meantime are skipped. With resolve, fn
+// is passed the value of the item, see liveValue, and nil otherwise.
+// The iteration stops as soon as fn returns false.
+// Nothing is called unless the keys are indexed, see sorted.
+func (c *Cache) ascend(from string, resolve bool, fn func(key string, value any) bool) {
+	entries := make([]entry, 0, iterationChunkSize)
+	inclusive := true
+	for {
+		entries = entries[:0]
+		c.mu.RLock()
+		if c.sorted == nil {
+			c.mu.RUnlock()
+			return
+		}
+		now := c.now().UnixNano()
+		n := c.sorted.seek(from)
+		if n != nil && !inclusive && n.key == from {
+			n = n.next[0]
+		}
+		for ; n != nil && len(entries) < cap(entries); n = n.next[0] {
+			if _, found := c.get(n.key, now); found {
+				entries = append(entries, entry{key: n.key})
+			}
+			from = n.key
+		}
+		exhausted := n == nil
+		c.mu.RUnlock()
+
+		for _, e := range entries {
+			value, found := c.liveValue(e.key, resolve)
+			if !found {
+				continue
+			}
+			if !fn(e.key, value) {
+				return
+			}
+		}
+		if exhausted {
+			return
+		}
+		inclusive = false
+	}
+}
+
+// RangeKeys Returns, in ascending order, the keys of the live items within from (inclusive) and to (exclusive).
+// An empty to means no upper bound, and a limit lower than 1 means no limit.
+// The cache must have been created WithSortedKeys, otherwise nothing is returned.
+func (c *Cache) RangeKeys(from, to string, limit int) []string {
+	if !c.sortedKeys {
+		return nil
+	}
+	var keys []string
+	c.ascend(from, false, func(key string, _ any) bool {
+		if to != "" && key >= to {
+			return false
+		}
+		keys = append(keys, key)
+		return limit < 1 || len(keys) < limit
+	})
+
+	return keys
+}
+
+// AscendPrefix Calls fn, in ascending key order, for each live item whose key starts with the given prefix,
+// until fn returns false. The cache is not locked while fn runs, so it may be modified from fn.
+// The cache must have been created WithSortedKeys, otherwise fn is never called.
+func (c *Cache) AscendPrefix(prefix string, fn func(key string, value any) bool) {
+	if !c.sortedKeys {
+		return
+	}
+	c.ascend(prefix, true, func(key string, value any) bool {
+		if !strings.HasPrefix(key, prefix) {
+			return false
+		}
+		return fn(key, value)
+	})
+}
@@ -0,0 +1,155 @@
+package go_cache
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkipList(t *testing.T) {
+	s := newSkipList()
+	present := map[string]bool{}
+	for i := 0; i < 5000; i++ {
+		key := strconv.Itoa(rand.IntN(1000))
+		if rand.IntN(3) == 0 {
+			s.remove(key)
+			delete(present, key)
+		} else {
+			s.insert(key)
+			present[key] = true
+		}
+	}
+
+	expected := make([]string, 0, len(present))
+	for key := range present {
+		expected = append(expected, key)
+	}
+	sort.Strings(expected)
+
+	var actual []string
+	for n := s.seek(""); n != nil; n = n.next[0] {
+		actual = append(actual, n.key)
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestCache_RangeKeys(t *testing.T) {
+	newEventsCache := func() *Cache {
+		tc := NewCacheWithOptions(WithSortedKeys())
+		for i := 1; i <= 5; i++ {
+			tc.Set(fmt.Sprintf("evt:2024010%d:id", i), i, DefaultExpiration)
+		}
+		tc.Set("other", 0, DefaultExpiration)
+		return tc
+	}
+
+	t.Run("bounds", func(t *testing.T) {
+		tc := newEventsCache()
+		defer tc.Stop()
+
+		keys := tc.RangeKeys("evt:20240102:id", "evt:20240104:id", 0)
+		assert.Equal(t, []string{"evt:20240102:id", "evt:20240103:id"}, keys)
+
+		keys = tc.RangeKeys("evt:20240104", "", 0)
+		assert.Equal(t, []string{"evt:20240104:id", "evt:20240105:id", "other"}, keys)
+
+		keys = tc.RangeKeys("evt:20240106", "other", 0)
+		assert.Empty(t, keys)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		tc := newEventsCache()
+		defer tc.Stop()
+
+		keys := tc.RangeKeys("evt:", "evt;", 2)
+		assert.Equal(t, []string{"evt:20240101:id", "evt:20240102:id"}, keys)
+	})
+
+	t.Run("afterDeletesAndExpirations", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithSortedKeys())
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("a", 1, DefaultExpiration)
+		tc.Set("b", 2, 10*time.Second)
+		tc.Set("c", 3, DefaultExpiration)
+		tc.Set("d", 4, DefaultExpiration)
+		tc.Delete("c")
+
+		clock.Advance(10 * time.Second)
+
+		keys := tc.RangeKeys("", "", 0)
+		assert.Equal(t, []string{"a", "d"}, keys)
+
+		tc.DeleteExpired()
+		tc.Set("c", 3, DefaultExpiration)
+		tc.Set("b", 2, DefaultExpiration)
+
+		keys = tc.RangeKeys("", "", 0)
+		assert.Equal(t, []string{"a", "b", "c", "d"}, keys)
+
+		tc.Flush()
+
+		keys = tc.RangeKeys("", "", 0)
+		assert.Empty(t, keys)
+	})
+
+	t.Run("acrossChunks", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithSortedKeys())
+		defer tc.Stop()
+
+		const n = 3*iterationChunkSize + 7
+		for i := 0; i < n; i++ {
+			tc.Set(fmt.Sprintf("%06d", i), i, DefaultExpiration)
+		}
+
+		keys := tc.RangeKeys("", "", 0)
+		assert.Len(t, keys, n)
+		assert.True(t, sort.StringsAreSorted(keys))
+	})
+
+	t.Run("withoutSortedKeys", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("a", 1, DefaultExpiration)
+
+		keys := tc.RangeKeys("", "", 0)
+		assert.Empty(t, keys)
+	})
+}
+
+func TestCache_AscendPrefix(t *testing.T) {
+	tc := NewCacheWithOptions(WithSortedKeys())
+	defer tc.Stop()
+
+	tc.Set("flags:b", 2, DefaultExpiration)
+	tc.Set("flags:a", 1, DefaultExpiration)
+	tc.Set("flags:c", 3, DefaultExpiration)
+	tc.Set("flagsx", 4, DefaultExpiration)
+	tc.Set("dns:a", 5, DefaultExpiration)
+
+	var keys []string
+	var values []any
+	tc.AscendPrefix("flags:", func(key string, value any) bool {
+		keys = append(keys, key)
+		values = append(values, value)
+		return true
+	})
+	assert.Equal(t, []string{"flags:a", "flags:b", "flags:c"}, keys)
+	assert.Equal(t, []any{1, 2, 3}, values)
+
+	keys = nil
+	tc.AscendPrefix("flags:", func(key string, value any) bool {
+		keys = append(keys, key)
+		tc.Delete("flags:b")
+		return len(keys) < 2
+	})
+	assert.Equal(t, []string{"flags:a", "flags:c"}, keys)
+}
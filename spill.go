@@ -0,0 +1,120 @@
+package go_cache
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// spillPattern Pattern of the names of the files values are spilled to, see WithSpillover.
+const spillPattern = "spill-*"
+
+// spill Holds the settings of a cache created WithSpillover.
+type spill struct {
+	dir       string
+	threshold int
+}
+
+// spillFile The object of an item whose value was spilled to disk, see WithSpillover.
+type spillFile struct {
+	path string
+	size int
+}
+
+// spillOut Returns the item with its value written to a file of the spill directory if it is a byte slice longer
+// than the threshold, or larger than the size set WithMaxValueSize, compressed or not. A value which cannot be
+// spilled is kept in memory, unless too large, in which case an error wrapping ErrValueTooLarge is returned.
+func (c *Cache) spillOut(key string, it item) (item, error) {
+	data, ok := it.object.([]byte)
+	if !ok || it.placeholder || it.negative {
+		return it, nil
+	}
+	oversized := c.maxValueSize > 0 && c.sizer(key, data) > c.maxValueSize
+	if len(data) <= c.spill.threshold && !oversized {
+		return it, nil
+	}
+
+	path, err := writeSpillFile(c.spill.dir, data)
+	if err != nil {
+		c.handleError(fmt.Errorf("could not spill value of %s: %w", key, err))
+		if oversized {
+			c.stats.oversizedWrites.Add(1)
+			return it, fmt.Errorf("%w: %s", ErrValueTooLarge, key)
+		}
+		return it, nil
+	}
+	it.object = &spillFile{path: path, size: len(data)}
+
+	return it, nil
+}
+
+func writeSpillFile(dir string, data []byte) (string, error) {
+	f, err := os.CreateTemp(dir, spillPattern)
+	if err != nil {
+		return "", err
+	}
+	if _, err = f.Write(data); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	if err = f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+
+	return f.Name(), nil
+}
+
+// removeSpillFile Deletes the file of a value spilled to disk, once removed from the cache.
+func (c *Cache) removeSpillFile(key string, f *spillFile) {
+	if err := os.Remove(f.path); err != nil {
+		c.handleError(fmt.Errorf("could not remove spilled value of %s: %w", key, err))
+	}
+}
+
+// removeSpillFiles Deletes the files left in the spill directory, e.g. by a process which did not stop its cache.
+func (c *Cache) removeSpillFiles() {
+	paths, _ := filepath.Glob(filepath.Join(c.spill.dir, spillPattern))
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil {
+			c.handleError(fmt.Errorf("could not remove spill file: %w", err))
+		}
+	}
+}
+
+// GetReader Looks up a key's value from the cache like Get, returning a reader of the value, which must be a byte
+// slice. A value spilled to disk (see WithSpillover) is streamed from its file, unless it was compressed (see
+// WithCompression), in which case it is read and decompressed first. The file stays readable until the reader is
+// closed, even if the item is removed from the cache in the meantime.
+// Returns ErrItemNotFound error if the key is not in the cache, and ErrNotBytes error if its value is not a byte slice.
+func (c *Cache) GetReader(key string) (io.ReadCloser, error) {
+	hashed := c.hashKey(key)
+
+	c.mu.RLock()
+	item, state := c.lookup(hashed, c.now().UnixNano())
+	var f *os.File
+	var err error
+	if spilled, ok := item.object.(*spillFile); ok && state == LookupHit && !item.compressed {
+		// The file is opened under the lock, before a removal of the item gets a chance to delete it.
+		f, err = os.Open(spilled.path)
+	}
+	c.mu.RUnlock()
+
+	switch {
+	case state != LookupHit:
+		return nil, fmt.Errorf("%w: %s", ErrItemNotFound, key)
+	case err != nil:
+		return nil, err
+	case f != nil:
+		return f, nil
+	}
+	data, ok := c.value(hashed, item).([]byte)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotBytes, key)
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
@@ -0,0 +1,157 @@
+package go_cache
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func spillFiles(t *testing.T, dir string) []string {
+	paths, err := filepath.Glob(filepath.Join(dir, spillPattern))
+	assert.Nil(t, err)
+	return paths
+}
+
+func largeValue(n int) []byte {
+	value := make([]byte, n)
+	_, _ = rand.Read(value)
+	return value
+}
+
+func TestCache_WithSpillover(t *testing.T) {
+	t.Run("roundTrip", func(t *testing.T) {
+		dir := t.TempDir()
+		tc := NewCacheWithOptions(WithSpillover(dir, 1024), WithMaxCost(2048))
+		defer tc.Stop()
+
+		value := largeValue(4 << 20)
+		tc.Set("aKey", value, DefaultExpiration)
+		tc.Set("bKey", largeValue(1024), DefaultExpiration)
+		tc.Set("cKey", "cValue", DefaultExpiration)
+
+		assert.Len(t, spillFiles(t, dir), 1)
+		a, found := tc.Get("aKey")
+		assert.True(t, found)
+		assert.Equal(t, value, a)
+		// Only the path of the spilled value is held in memory.
+		tc.mu.RLock()
+		assert.Less(t, tc.sizer("aKey", tc.items["aKey"].object), int64(64))
+		assert.Less(t, tc.capacity.cost, int64(1024+64+6))
+		tc.mu.RUnlock()
+		assert.Equal(t, 3, tc.ItemCount())
+	})
+
+	t.Run("getReader", func(t *testing.T) {
+		dir := t.TempDir()
+		tc := NewCacheWithOptions(WithSpillover(dir, 1024))
+		defer tc.Stop()
+
+		value := largeValue(1 << 20)
+		tc.Set("aKey", value, DefaultExpiration)
+		tc.Set("bKey", []byte("bValue"), DefaultExpiration)
+		tc.Set("cKey", "cValue", DefaultExpiration)
+
+		r, err := tc.GetReader("aKey")
+		assert.Nil(t, err)
+		// The reader outlives the item.
+		tc.Delete("aKey")
+		data, err := io.ReadAll(r)
+		assert.Nil(t, err)
+		assert.Equal(t, value, data)
+		assert.Nil(t, r.Close())
+
+		r, err = tc.GetReader("bKey")
+		assert.Nil(t, err)
+		data, _ = io.ReadAll(r)
+		assert.Equal(t, []byte("bValue"), data)
+
+		_, err = tc.GetReader("cKey")
+		assert.ErrorIs(t, err, ErrNotBytes)
+		_, err = tc.GetReader("aKey")
+		assert.ErrorIs(t, err, ErrItemNotFound)
+	})
+
+	t.Run("fileDeletion", func(t *testing.T) {
+		dir := t.TempDir()
+		tc := NewCacheWithOptions(WithSpillover(dir, 1024), WithMaxItems(3))
+		defer tc.Stop()
+		clock := newFakeClock()
+		tc.now = clock.Now
+
+		tc.Set("aKey", largeValue(2048), DefaultExpiration)
+		tc.Set("bKey", largeValue(2048), time.Second)
+		tc.Set("cKey", largeValue(2048), DefaultExpiration)
+		assert.Len(t, spillFiles(t, dir), 3)
+
+		tc.Delete("aKey")
+		assert.Len(t, spillFiles(t, dir), 2)
+
+		clock.Advance(time.Second)
+		tc.DeleteExpired()
+		assert.Len(t, spillFiles(t, dir), 1)
+
+		value := largeValue(2048)
+		tc.Set("cKey", value, DefaultExpiration)
+		assert.Len(t, spillFiles(t, dir), 1)
+		a, _ := tc.Get("cKey")
+		assert.Equal(t, value, a)
+
+		// Evicted.
+		for _, key := range []string{"dKey", "eKey", "fKey"} {
+			tc.Set(key, largeValue(2048), DefaultExpiration)
+		}
+		assert.Equal(t, 3, tc.ItemCount())
+		assert.Len(t, spillFiles(t, dir), 3)
+
+		tc.Flush()
+		assert.Empty(t, spillFiles(t, dir))
+
+		tc.Set("aKey", value, DefaultExpiration)
+		items := tc.FlushAndReturn()
+		assert.Equal(t, value, items["aKey"].Object)
+		assert.Empty(t, spillFiles(t, dir))
+	})
+
+	t.Run("orphans", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, "spill-123"), []byte("orphan"), 0o600))
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, "other"), []byte("other"), 0o600))
+
+		tc := NewCacheWithOptions(WithSpillover(dir, 1024))
+		defer tc.Stop()
+
+		assert.Empty(t, spillFiles(t, dir))
+		_, err := os.Stat(filepath.Join(dir, "other"))
+		assert.Nil(t, err)
+	})
+
+	t.Run("withCompression", func(t *testing.T) {
+		dir := t.TempDir()
+		tc := NewCacheWithOptions(WithSpillover(dir, 1024), WithCompression(0, nil))
+		defer tc.Stop()
+
+		compressible := bytes.Repeat([]byte("a"), 256<<10)
+		incompressible := largeValue(1 << 20)
+		tc.Set("aKey", compressible, DefaultExpiration)
+		tc.Set("bKey", incompressible, DefaultExpiration)
+
+		// The compressed value fits under the threshold, while the other does not.
+		assert.Len(t, spillFiles(t, dir), 1)
+		a, _ := tc.Get("aKey")
+		assert.Equal(t, compressible, a)
+		a, _ = tc.Get("bKey")
+		assert.Equal(t, incompressible, a)
+
+		tc.Set("cKey", append(largeValue(1<<20), compressible...), DefaultExpiration)
+		r, err := tc.GetReader("cKey")
+		assert.Nil(t, err)
+		data, _ := io.ReadAll(r)
+		assert.Equal(t, compressible, data[1<<20:])
+	})
+}
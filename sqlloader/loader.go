@@ -0,0 +1,74 @@
+// Package sqlloader Provides a loader reading the values of a cache with a single-row SQL query,
+// for use with WithLoader or GetOrLoad.
+package sqlloader
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	go_cache "github.com/J4NN0/go-cache"
+)
+
+// Option Configures a loader created with New.
+type Option func(*loader)
+
+// WithArgs Sets the function deriving the arguments of the query from the key being loaded,
+// e.g. to split a composite key. By default, the key is passed as the only argument.
+func WithArgs(args func(key string) []any) Option {
+	return func(l *loader) {
+		l.args = args
+	}
+}
+
+type loader struct {
+	db    *sql.DB
+	query string
+	scan  func(rows *sql.Rows) (any, error)
+	ttl   time.Duration
+	args  func(key string) []any
+}
+
+// New Returns a loader running the given query with the context of the load, and building the value of the key
+// from the first row returned with the scan function, which is called once rows.Next reported a row.
+// Values are cached for the given duration, with the same semantics as the duration passed to Set.
+// A query returning no row loads nothing: the loader returns an error wrapping both go_cache.ErrItemNotFound,
+// so that a negative entry is stored if the cache was created WithNegativeTTL, and sql.ErrNoRows.
+func New(db *sql.DB, query string, scan func(rows *sql.Rows) (any, error), ttl time.Duration, opts ...Option) go_cache.Loader {
+	l := &loader{
+		db:    db,
+		query: query,
+		scan:  scan,
+		ttl:   ttl,
+		args: func(key string) []any {
+			return []any{key}
+		},
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l.load
+}
+
+func (l *loader) load(ctx context.Context, key string) (any, time.Duration, error) {
+	rows, err := l.db.QueryContext(ctx, l.query, l.args(key)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not query %s: %w", key, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, 0, fmt.Errorf("could not query %s: %w", key, err)
+		}
+		return nil, 0, fmt.Errorf("%w: %s: %w", go_cache.ErrItemNotFound, key, sql.ErrNoRows)
+	}
+	value, err := l.scan(rows)
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not scan %s: %w", key, err)
+	}
+
+	return value, l.ttl, rows.Close()
+}
@@ -0,0 +1,178 @@
+package sqlloader
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	go_cache "github.com/J4NN0/go-cache"
+	"github.com/stretchr/testify/assert"
+)
+
+var errQuery = errors.New("query failed")
+
+// fakeDriver A database/sql driver answering any query with the rows of a table keyed by the first argument,
+// and failing for the key "fail".
+type fakeDriver struct {
+	table   map[string][]driver.Value
+	queries atomic.Int32
+	// lastArgs holds the arguments of the last query.
+	lastArgs atomic.Value
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return fakeStmt(c), nil }
+func (c fakeConn) Close() error                        { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+type fakeStmt struct{ d *fakeDriver }
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+func (s fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.queries.Add(1)
+	s.d.lastArgs.Store(args)
+	key, _ := args[0].(string)
+	if key == "fail" {
+		return nil, errQuery
+	}
+
+	rows := &fakeRows{}
+	if row, found := s.d.table[key]; found {
+		rows.rows = [][]driver.Value{row}
+	}
+	return rows, nil
+}
+
+type fakeRows struct {
+	rows [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return []string{"name", "age"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+var registered atomic.Int32
+
+func openFakeDB(t *testing.T, table map[string][]driver.Value) (*sql.DB, *fakeDriver) {
+	t.Helper()
+
+	d := &fakeDriver{table: table}
+	name := "fake" + strconv.Itoa(int(registered.Add(1)))
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return db, d
+}
+
+type user struct {
+	Name string
+	Age  int64
+}
+
+func scanUser(rows *sql.Rows) (any, error) {
+	var u user
+	err := rows.Scan(&u.Name, &u.Age)
+	return u, err
+}
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("hitAfterLoad", func(t *testing.T) {
+		db, d := openFakeDB(t, map[string][]driver.Value{"1": {"Ada", int64(36)}})
+		tc := go_cache.NewCacheWithOptions(go_cache.WithLoader(New(db, "SELECT name, age FROM users WHERE id = ?", scanUser, time.Hour)))
+		defer tc.Stop()
+
+		value, err := tc.Fetch(ctx, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, user{Name: "Ada", Age: 36}, value)
+
+		value, err = tc.Fetch(ctx, "1")
+		assert.NoError(t, err)
+		assert.Equal(t, user{Name: "Ada", Age: 36}, value)
+		assert.Equal(t, int32(1), d.queries.Load())
+
+		info, found := tc.Info("1")
+		assert.True(t, found)
+		assert.False(t, info.Expiration.IsZero())
+	})
+
+	t.Run("notFound", func(t *testing.T) {
+		db, d := openFakeDB(t, nil)
+		tc := go_cache.NewCacheWithOptions(
+			go_cache.WithLoader(New(db, "SELECT name, age FROM users WHERE id = ?", scanUser, time.Hour)),
+			go_cache.WithNegativeTTL(time.Minute),
+		)
+		defer tc.Stop()
+
+		_, err := tc.Fetch(ctx, "2")
+		assert.ErrorIs(t, err, go_cache.ErrItemNotFound)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		// The negative entry answers the next lookup.
+		_, err = tc.Fetch(ctx, "2")
+		assert.ErrorIs(t, err, go_cache.ErrItemNotFound)
+		assert.Equal(t, int32(1), d.queries.Load())
+	})
+
+	t.Run("queryError", func(t *testing.T) {
+		db, _ := openFakeDB(t, nil)
+		tc := go_cache.NewCacheWithOptions(go_cache.WithLoader(New(db, "SELECT name, age FROM users WHERE id = ?", scanUser, time.Hour)))
+		defer tc.Stop()
+
+		_, err := tc.Fetch(ctx, "fail")
+		assert.ErrorContains(t, err, errQuery.Error())
+		assert.NotErrorIs(t, err, go_cache.ErrItemNotFound)
+		_, found := tc.Get("fail")
+		assert.False(t, found)
+	})
+
+	t.Run("withArgs", func(t *testing.T) {
+		db, d := openFakeDB(t, map[string][]driver.Value{"acme": {"Ada", int64(36)}})
+		load := New(db, "SELECT name, age FROM users WHERE tenant = ? AND id = ?", scanUser, time.Hour, WithArgs(func(key string) []any {
+			tenant, id, _ := strings.Cut(key, "/")
+			return []any{tenant, id}
+		}))
+
+		value, ttl, err := load(ctx, "acme/1")
+		assert.NoError(t, err)
+		assert.Equal(t, user{Name: "Ada", Age: 36}, value)
+		assert.Equal(t, time.Hour, ttl)
+		assert.Equal(t, []driver.Value{"acme", "1"}, d.lastArgs.Load())
+	})
+
+	t.Run("canceledContext", func(t *testing.T) {
+		db, _ := openFakeDB(t, map[string][]driver.Value{"1": {"Ada", int64(36)}})
+		load := New(db, "SELECT name, age FROM users WHERE id = ?", scanUser, time.Hour)
+
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, _, err := load(ctx, "1")
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
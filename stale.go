@@ -0,0 +1,41 @@
+package go_cache
+
+import "time"
+
+// StaleState The state of the item returned by GetStale.
+type StaleState int
+
+const (
+	// StateMissing The key is not in the cache, is not visible, or holds a negative entry.
+	StateMissing StaleState = iota
+	// StateLive The key holds a value which has not expired.
+	StateLive
+	// StateStale The key holds a value which has expired but has not been deleted yet.
+	StateStale
+)
+
+// GetStale Looks up a key's value from the cache like Get, but also returns a value which has expired as long as it
+// has not been deleted yet, e.g. to show the last known value while it is being refreshed. The expiration time of
+// the item is returned too, zero if it never expires. Expired items stay in the cache until they are deleted, by
+// the cleanup goroutine (so at most a cleanup interval after expiring), DeleteExpired, or explicitly.
+// GetStale itself never deletes anything.
+func (c *Cache) GetStale(key string) (any, time.Time, StaleState) {
+	key = c.hashKey(key)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	item, found := c.items[key]
+	now := c.now().UnixNano()
+	item = item.promote(now)
+	if !found || c.closed || item.placeholder || item.negative || item.isLeased(now) {
+		return nil, time.Time{}, StateMissing
+	}
+
+	state := StateLive
+	if item.isExpired(now) {
+		state = StateStale
+	}
+
+	return c.value(key, item), item.info().Expiration, state
+}
@@ -0,0 +1,114 @@
+package go_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_GetStale(t *testing.T) {
+	t.Run("states", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", time.Minute)
+		tc.Set("bKey", "bValue", NoExpiration)
+		expiration := clock.Now().Add(time.Minute)
+
+		value, expiredAt, state := tc.GetStale("aKey")
+		assert.Equal(t, StateLive, state)
+		assert.Equal(t, "aValue", value)
+		assert.True(t, expiration.Equal(expiredAt))
+
+		value, expiredAt, state = tc.GetStale("bKey")
+		assert.Equal(t, StateLive, state)
+		assert.Equal(t, "bValue", value)
+		assert.True(t, expiredAt.IsZero())
+
+		clock.Advance(time.Minute)
+		_, found := tc.Get("aKey")
+		assert.False(t, found)
+		value, expiredAt, state = tc.GetStale("aKey")
+		assert.Equal(t, StateStale, state)
+		assert.Equal(t, "aValue", value)
+		assert.True(t, expiration.Equal(expiredAt))
+
+		// GetStale does not delete the stale item, a cleanup pass does.
+		assert.Equal(t, 2, tc.ItemCount())
+		tc.DeleteExpired()
+		value, _, state = tc.GetStale("aKey")
+		assert.Equal(t, StateMissing, state)
+		assert.Nil(t, value)
+	})
+
+	t.Run("cleanupInterval", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 100*time.Millisecond)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", time.Millisecond)
+		<-time.After(10 * time.Millisecond)
+		_, _, state := tc.GetStale("aKey")
+		assert.Equal(t, StateStale, state)
+
+		<-time.After(150 * time.Millisecond)
+		_, _, state = tc.GetStale("aKey")
+		assert.Equal(t, StateMissing, state)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithNegativeTTL(time.Minute))
+		defer tc.Stop()
+
+		_, _, state := tc.GetStale("aKey")
+		assert.Equal(t, StateMissing, state)
+
+		tc.SetNegative("bKey", time.Minute)
+		_, _, state = tc.GetStale("bKey")
+		assert.Equal(t, StateMissing, state)
+	})
+}
+
+func TestCache_WithRetainExpired(t *testing.T) {
+	t.Run("keptAcrossCleanups", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithRetainExpired(), WithCleanupInterval(10*time.Millisecond))
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", time.Millisecond)
+		tc.Set("bKey", "bValue", NoExpiration)
+		<-time.After(50 * time.Millisecond)
+
+		_, found := tc.Get("aKey")
+		assert.False(t, found)
+		value, _, state := tc.GetStale("aKey")
+		assert.Equal(t, StateStale, state)
+		assert.Equal(t, "aValue", value)
+		counts := tc.Counts()
+		assert.Equal(t, 1, counts.Live)
+		assert.Equal(t, 1, counts.Expired)
+
+		// Explicit removals still apply.
+		tc.DeleteExpired()
+		_, _, state = tc.GetStale("aKey")
+		assert.Equal(t, StateMissing, state)
+	})
+
+	t.Run("evictedFirst", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithRetainExpired(), WithCleanupInterval(10*time.Millisecond), WithMaxItems(3))
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", "bValue", NoExpiration)
+		tc.Set("cKey", "cValue", time.Millisecond)
+		<-time.After(50 * time.Millisecond)
+		assert.Equal(t, 3, tc.ItemCount())
+
+		tc.Set("dKey", "dValue", NoExpiration)
+		_, _, state := tc.GetStale("cKey")
+		assert.Equal(t, StateMissing, state)
+		_, found := tc.Get("aKey")
+		assert.True(t, found)
+	})
+}
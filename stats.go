@@ -0,0 +1,180 @@
+package go_cache
+
+import (
+	"expvar"
+	"sync/atomic"
+)
+
+// Stats Holds counters describing how the cache has been used since it was created.
+type Stats struct {
+	// Hits Number of lookups which found a value.
+	Hits uint64
+	// Misses Number of lookups which found nothing.
+	Misses uint64
+	// NegativeHits Number of lookups which found a negative entry, see SetNegative.
+	NegativeHits uint64
+	// ClosedWrites Number of writes dropped because the cache was stopped.
+	ClosedWrites uint64
+	// OversizedWrites Number of writes rejected because their value was larger than the size set WithMaxValueSize.
+	OversizedWrites uint64
+	// Evictions Number of items removed to keep the cache within its capacity limits, same as Removals.Evicted.
+	Evictions uint64
+	// Removals Number of items removed from the cache, by reason.
+	Removals Removals
+	// ExpiredDisplaced Number of expired items, not deleted yet, which Add replaced with a new value.
+	ExpiredDisplaced uint64
+	// Compression Sizes of the values compressed, see WithCompression.
+	Compression CompressionStats
+	// Breaker Activity of the circuit breaker of the loader, see WithLoaderBreaker.
+	Breaker BreakerStats
+	// Loads The loads running and waiting, see WithMaxConcurrentLoads.
+	Loads LoadStats
+	// Cleanup Activity of the cleanup goroutine, see WithCleanupInterval.
+	Cleanup CleanupStats
+	// Counts Breakdown of the items currently held by the cache.
+	Counts Counts
+}
+
+// Removals Number of items removed from the cache since it was created, broken down by the reason of their removal.
+// Each item is counted once, by the operation which actually removed it. Overwritten values are not counted.
+type Removals struct {
+	// Deleted Number of items removed by Delete, DeleteMany, DeleteByIndex or DeleteCreatedBefore.
+	Deleted uint64
+	// Expired Number of expired items deleted by the cleanup goroutine, DeleteExpired or DeleteByIndex.
+	Expired uint64
+	// Evicted Number of items removed to keep the cache within its capacity limits, see WithMaxItems.
+	Evicted uint64
+	// Idle Number of items removed by DeleteIdle.
+	Idle uint64
+	// Flushed Number of items removed by Flush or FlushAndReturn, or marked by SoftFlush and removed since.
+	Flushed uint64
+}
+
+// CompressionStats Number and sizes of the values compressed by a cache created WithCompression since it was created.
+// The savings of the values still in the cache show in their cost, see WithMaxCost.
+type CompressionStats struct {
+	// Values Number of values stored compressed.
+	Values uint64
+	// UncompressedBytes Total size of the values before compression.
+	UncompressedBytes uint64
+	// CompressedBytes Total size of the values once compressed.
+	CompressedBytes uint64
+}
+
+// removalReason Why an item is removed from the cache, see Removals.
+type removalReason int
+
+const (
+	removalDeleted removalReason = iota
+	removalExpired
+	removalEvicted
+	removalIdle
+	removalFlushed
+	removalReasons
+)
+
+// Counts Breakdown of the items held by the cache at a given time.
+type Counts struct {
+	// Total Number of items held by the cache, as returned by ItemCount.
+	Total int
+	// Live Number of items which have not expired.
+	Live int
+	// Expired Number of items which have expired but have not been deleted yet.
+	Expired int
+	// NoExpiration Number of live items which never expire.
+	NoExpiration int
+}
+
+type stats struct {
+	hits             atomic.Uint64
+	misses           atomic.Uint64
+	negativeHits     atomic.Uint64
+	closedWrites     atomic.Uint64
+	oversizedWrites  atomic.Uint64
+	expiredDisplaced atomic.Uint64
+	removals         [removalReasons]atomic.Uint64
+
+	compressedValues  atomic.Uint64
+	uncompressedBytes atomic.Uint64
+	compressedBytes   atomic.Uint64
+}
+
+// Stats Returns the current counters of the cache. Since it includes Counts, it goes through the whole cache.
+func (c *Cache) Stats() Stats {
+	stats := c.loadStats()
+	stats.Counts = c.Counts()
+
+	return stats
+}
+
+// loadStats Returns the stats of the cache without Counts, without going through the cache.
+func (c *Cache) loadStats() Stats {
+	stats := Stats{
+		Hits:             c.stats.hits.Load(),
+		Misses:           c.stats.misses.Load(),
+		NegativeHits:     c.stats.negativeHits.Load(),
+		ClosedWrites:     c.stats.closedWrites.Load(),
+		OversizedWrites:  c.stats.oversizedWrites.Load(),
+		Evictions:        c.stats.removals[removalEvicted].Load(),
+		ExpiredDisplaced: c.stats.expiredDisplaced.Load(),
+		Removals: Removals{
+			Deleted: c.stats.removals[removalDeleted].Load(),
+			Expired: c.stats.removals[removalExpired].Load(),
+			Evicted: c.stats.removals[removalEvicted].Load(),
+			Idle:    c.stats.removals[removalIdle].Load(),
+			Flushed: c.stats.removals[removalFlushed].Load(),
+		},
+		Compression: CompressionStats{
+			Values:            c.stats.compressedValues.Load(),
+			UncompressedBytes: c.stats.uncompressedBytes.Load(),
+			CompressedBytes:   c.stats.compressedBytes.Load(),
+		},
+	}
+	if c.breaker != nil {
+		stats.Breaker = c.breaker.stats()
+	}
+	if c.loadLimit != nil {
+		stats.Loads = c.loadLimit.stats()
+	}
+	stats.Cleanup = c.health.cleanupStats()
+
+	return stats
+}
+
+// Expvar Returns the stats of the cache as an expvar variable, e.g. to publish with expvar.Publish.
+// Like Stats, each read of the variable goes through the whole cache.
+func (c *Cache) Expvar() expvar.Var {
+	return expvar.Func(func() any {
+		return c.Stats()
+	})
+}
+
+// Counts Returns a breakdown of the items currently held by the cache, computed in a single pass.
+// Values staged with SetVisibleAt count as live once visible, and only towards Total before that
+// if the key had no prior value.
+func (c *Cache) Counts() Counts {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.now().UnixNano()
+	counts := Counts{Total: len(c.items)}
+	for _, item := range c.items {
+		counts.add(item.promote(now), now)
+	}
+
+	return counts
+}
+
+// add Counts the given item, as seen at the given time, in the breakdown, except towards Total.
+func (counts *Counts) add(it item, now int64) {
+	switch {
+	case it.placeholder:
+	case it.isExpired(now):
+		counts.Expired++
+	case it.expiration == 0:
+		counts.Live++
+		counts.NoExpiration++
+	default:
+		counts.Live++
+	}
+}
@@ -0,0 +1,151 @@
+package go_cache
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_Counts(t *testing.T) {
+	t.Run("withHigherCleanUpInterval", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(20*time.Millisecond, 1*time.Hour)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		tc.Set("bKey", "bValue", NoExpiration)
+		tc.Set("cKey", 3, 50*time.Millisecond)
+
+		counts := tc.Counts()
+		assert.Equal(t, Counts{Total: 3, Live: 3, Expired: 0, NoExpiration: 1}, counts)
+
+		clock.Advance(25 * time.Millisecond)
+
+		counts = tc.Counts()
+		assert.Equal(t, Counts{Total: 3, Live: 2, Expired: 1, NoExpiration: 1}, counts)
+
+		clock.Advance(30 * time.Millisecond)
+
+		counts = tc.Counts()
+		assert.Equal(t, Counts{Total: 3, Live: 1, Expired: 2, NoExpiration: 1}, counts)
+
+		tc.DeleteExpired()
+
+		counts = tc.Counts()
+		assert.Equal(t, Counts{Total: 1, Live: 1, Expired: 0, NoExpiration: 1}, counts)
+	})
+
+	t.Run("withStagedValues", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(NoExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.SetVisibleAt("aKey", "aValue", clock.Now().Add(10*time.Second), DefaultExpiration)
+
+		counts := tc.Counts()
+		assert.Equal(t, Counts{Total: 1}, counts)
+
+		clock.Advance(10 * time.Second)
+
+		counts = tc.Counts()
+		assert.Equal(t, Counts{Total: 1, Live: 1, NoExpiration: 1}, counts)
+	})
+}
+
+func TestCache_Stats(t *testing.T) {
+	clock := newFakeClock()
+	tc := NewCache(NoExpiration, 0)
+	tc.now = clock.Now
+	defer tc.Stop()
+
+	tc.Set("aKey", "aValue", DefaultExpiration)
+	tc.Set("bKey", "bValue", 10*time.Millisecond)
+
+	tc.Get("aKey")
+	tc.Get("cKey")
+
+	clock.Advance(10 * time.Millisecond)
+
+	stats := tc.Stats()
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, Counts{Total: 2, Live: 1, Expired: 1, NoExpiration: 1}, stats.Counts)
+}
+
+func TestCache_Removals(t *testing.T) {
+	t.Run("byReason", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithMaxItems(4), WithAccessTracking())
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", "bValue", NoExpiration)
+		tc.Set("cKey", "cValue", time.Second)
+		tc.Set("dKey", "dValue", NoExpiration)
+		tc.Set("eKey", "eValue", NoExpiration)
+		assert.Equal(t, Removals{Evicted: 1}, tc.Stats().Removals)
+
+		tc.Delete("bKey")
+		tc.Delete("missing")
+		_, _ = tc.DeleteMany([]string{"dKey", "missing"})
+		assert.Equal(t, Removals{Deleted: 2, Evicted: 1}, tc.Stats().Removals)
+
+		tc.Set("hKey", "hValue", NoExpiration)
+		clock.Advance(time.Minute)
+		tc.DeleteExpired()
+		tc.DeleteExpired()
+		tc.Get("eKey")
+		tc.Set("fKey", "fValue", NoExpiration)
+		tc.Set("gKey", "gValue", NoExpiration)
+		assert.Equal(t, 1, tc.DeleteIdle(time.Second))
+		// Overwrites are not removals.
+		tc.Set("fKey", "fValue2", NoExpiration)
+		tc.Flush()
+
+		stats := tc.Stats()
+		assert.Equal(t, Removals{Deleted: 2, Expired: 1, Evicted: 1, Idle: 1, Flushed: 3}, stats.Removals)
+		assert.Equal(t, stats.Removals.Evicted, stats.Evictions)
+	})
+
+	t.Run("countedOnce", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		for i := 0; i < 100; i++ {
+			tc.Set(strconv.Itoa(i), i, time.Second)
+		}
+		clock.Advance(time.Minute)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				tc.DeleteExpired()
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, Removals{Expired: 100}, tc.Stats().Removals)
+	})
+
+	t.Run("expvar", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Delete("aKey")
+
+		var stats Stats
+		assert.NoError(t, json.Unmarshal([]byte(tc.Expvar().String()), &stats))
+		assert.Equal(t, uint64(1), stats.Removals.Deleted)
+	})
+}
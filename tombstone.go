@@ -0,0 +1,24 @@
+package go_cache
+
+// bury Records that the key was deleted now, if the cache was created WithTombstones.
+// It must be called with the write lock held.
+func (c *Cache) bury(key string) {
+	if c.tombstoneTTL <= 0 {
+		return
+	}
+	if c.tombstones == nil {
+		c.tombstones = make(map[string]int64)
+	}
+	c.tombstones[key] = c.now().UnixNano()
+}
+
+// deletedAt Returns the time at which the key was deleted, reporting false if it was not deleted within the window
+// set WithTombstones.
+func (c *Cache) deletedAt(key string, now int64) (int64, bool) {
+	at, found := c.tombstones[key]
+	if !found || at+int64(c.tombstoneTTL) <= now {
+		return 0, false
+	}
+
+	return at, true
+}
@@ -0,0 +1,98 @@
+package go_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_WithTombstones(t *testing.T) {
+	t.Run("add", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithTombstones(time.Minute))
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Delete("aKey")
+		assert.Equal(t, 0, tc.ItemCount())
+		keys, _ := tc.Keys()
+		assert.Empty(t, keys)
+		_, found := tc.Get("aKey")
+		assert.False(t, found)
+
+		clock.Advance(30 * time.Second)
+		assert.ErrorIs(t, tc.Add("aKey", "bValue", NoExpiration), ErrRecentlyDeleted)
+		assert.Equal(t, 0, tc.ItemCount())
+
+		clock.Advance(30 * time.Second)
+		assert.NoError(t, tc.Add("aKey", "bValue", NoExpiration))
+		value, _ := tc.Get("aKey")
+		assert.Equal(t, "bValue", value)
+	})
+
+	t.Run("setClears", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithTombstones(time.Minute))
+		defer tc.Stop()
+
+		tc.Delete("aKey")
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Delete("aKey")
+		tc.Set("aKey", "bValue", NoExpiration)
+		assert.ErrorIs(t, tc.Add("aKey", "cValue", NoExpiration), ErrItemAlreadyExists)
+	})
+
+	t.Run("setIfNewer", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithTombstones(time.Minute))
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		before := clock.Now()
+		clock.Advance(time.Second)
+		tc.Delete("aKey")
+
+		written, err := tc.SetIfNewer("aKey", "stale", NoExpiration, before)
+		assert.NoError(t, err)
+		assert.False(t, written)
+
+		written, err = tc.SetIfNewer("aKey", "fresh", NoExpiration, clock.Now().Add(time.Second))
+		assert.NoError(t, err)
+		assert.True(t, written)
+	})
+
+	t.Run("deleteMany", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithTombstones(time.Minute))
+		defer tc.Stop()
+
+		_, _ = tc.DeleteMany([]string{"aKey", "bKey"})
+		assert.ErrorIs(t, tc.Add("bKey", "bValue", NoExpiration), ErrRecentlyDeleted)
+	})
+
+	t.Run("purged", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithTombstones(time.Minute))
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Delete("aKey")
+		tc.Delete("bKey")
+		clock.Advance(time.Minute)
+		tc.Delete("cKey")
+		tc.DeleteExpired()
+
+		tc.mu.RLock()
+		assert.Len(t, tc.tombstones, 1)
+		tc.mu.RUnlock()
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Delete("aKey")
+		assert.NoError(t, tc.Add("aKey", "aValue", NoExpiration))
+		assert.Nil(t, tc.tombstones)
+	})
+}
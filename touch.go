@@ -0,0 +1,72 @@
+package go_cache
+
+import (
+	"fmt"
+	"time"
+)
+
+// Touch Pushes the expiration of the item stored under the given key to the given duration from now,
+// keeping its value. Returns ErrItemNotFound if the key does not exist or has expired.
+// If the cache was created WithMaxLifetime, the new expiration is clamped to the end of the item's lifetime,
+// and ErrLifetimeExceeded is returned once the item has reached it.
+// If the duration is 0 (DefaultExpiration), the cache's default expiration time is used.
+// If it is -1 (NoExpiration), the item never expires (up to the end of its lifetime).
+func (c *Cache) Touch(key string, duration time.Duration) error {
+	key = c.hashKey(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrCacheClosed
+	}
+	item, found := c.get(key, c.now().UnixNano())
+	if !found {
+		return fmt.Errorf("%w: %s", ErrItemNotFound, key)
+	}
+
+	return c.touch(key, item, duration)
+}
+
+// GetAndTouch Looks up a key's value from the cache like Get and, if found, pushes the expiration of the item
+// like Touch. An item which has reached the end of its lifetime (see WithMaxLifetime) is still returned,
+// without being extended.
+func (c *Cache) GetAndTouch(key string, duration time.Duration) (any, bool) {
+	key = c.hashKey(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, state := c.lookup(key, c.now().UnixNano())
+	if state != LookupHit {
+		return nil, false
+	}
+	_ = c.touch(key, item, duration)
+
+	return c.value(key, item), true
+}
+
+// touch Pushes the expiration of the given item, stored under the key, to the given duration from now.
+func (c *Cache) touch(key string, item item, duration time.Duration) error {
+	now := c.now()
+	duration = c.resolveDuration(item.object, duration)
+	var expiration int64
+	if duration > 0 {
+		expiration = now.Add(duration).UnixNano()
+	}
+
+	if c.maxLifetime > 0 {
+		end := item.created + int64(c.maxLifetime)
+		if end <= now.UnixNano() {
+			return fmt.Errorf("%w: %s", ErrLifetimeExceeded, key)
+		}
+		if expiration == 0 || expiration > end {
+			expiration = end
+		}
+	}
+
+	item.expiration = expiration
+	c.store(key, item)
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package go_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_Touch(t *testing.T) {
+	t.Run("extendsExpiration", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", 10*time.Second)
+
+		clock.Advance(8 * time.Second)
+		assert.NoError(t, tc.Touch("aKey", 10*time.Second))
+
+		clock.Advance(8 * time.Second)
+		value, found := tc.Get("aKey")
+		assert.True(t, found)
+		assert.Equal(t, "aValue", value)
+
+		clock.Advance(3 * time.Second)
+		_, found = tc.Get("aKey")
+		assert.False(t, found)
+	})
+
+	t.Run("missingKey", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		assert.ErrorIs(t, tc.Touch("aKey", 10*time.Second), ErrItemNotFound)
+	})
+
+	t.Run("maxLifetime", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithMaxLifetime(60 * time.Second))
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", 15*time.Second)
+
+		for i := 0; i < 5; i++ {
+			clock.Advance(10 * time.Second)
+			assert.NoError(t, tc.Touch("aKey", 15*time.Second))
+		}
+
+		clock.Advance(9 * time.Second)
+		_, found := tc.Get("aKey")
+		assert.True(t, found)
+
+		clock.Advance(1 * time.Second)
+		_, found = tc.Get("aKey")
+		assert.False(t, found)
+		assert.ErrorIs(t, tc.Touch("aKey", 15*time.Second), ErrItemNotFound)
+	})
+
+	t.Run("maxLifetimeExceeded", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithMaxLifetime(60 * time.Second))
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+
+		clock.Advance(30 * time.Second)
+		assert.NoError(t, tc.Touch("aKey", NoExpiration))
+
+		tc.Set("bKey", "bValue", 2*time.Minute)
+		clock.Advance(60 * time.Second)
+		assert.ErrorIs(t, tc.Touch("bKey", 10*time.Second), ErrLifetimeExceeded)
+		_, found := tc.Get("aKey")
+		assert.False(t, found)
+	})
+
+	t.Run("setResetsLifetime", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithMaxLifetime(60 * time.Second))
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", 15*time.Second)
+		clock.Advance(10 * time.Second)
+		tc.Set("aKey", "aNewValue", 15*time.Second)
+
+		clock.Advance(55 * time.Second)
+		_, found := tc.Get("aKey")
+		assert.False(t, found)
+
+		tc.Set("aKey", "aNewValue", 15*time.Second)
+		clock.Advance(10 * time.Second)
+		assert.NoError(t, tc.Touch("aKey", 15*time.Second))
+		clock.Advance(10 * time.Second)
+		_, found = tc.Get("aKey")
+		assert.True(t, found)
+	})
+}
+
+func TestCache_GetAndTouch(t *testing.T) {
+	t.Run("slidingExpiration", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCacheWithOptions(WithMaxLifetime(60 * time.Second))
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", 15*time.Second)
+
+		for i := 0; i < 5; i++ {
+			clock.Advance(10 * time.Second)
+			value, found := tc.GetAndTouch("aKey", 15*time.Second)
+			assert.True(t, found)
+			assert.Equal(t, "aValue", value)
+		}
+
+		clock.Advance(9 * time.Second)
+		_, found := tc.GetAndTouch("aKey", 15*time.Second)
+		assert.True(t, found)
+
+		clock.Advance(1 * time.Second)
+		_, found = tc.GetAndTouch("aKey", 15*time.Second)
+		assert.False(t, found)
+	})
+
+	t.Run("missingKey", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		_, found := tc.GetAndTouch("aKey", 10*time.Second)
+		assert.False(t, found)
+		assert.Equal(t, uint64(1), tc.Stats().Misses)
+	})
+}
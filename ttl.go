@@ -0,0 +1,86 @@
+package go_cache
+
+import "time"
+
+// expiryKind Tells how an Expiry sets the expiration time of an item.
+type expiryKind uint8
+
+const (
+	expiryDefault expiryKind = iota
+	expiryNever
+	expiryAfter
+)
+
+// Expiry Tells when an item written with it expires, without the sentinel durations DefaultExpiration and
+// NoExpiration, so that a computed duration of 0 or less cannot be mistaken for one of them: build it with TTL,
+// NoTTL, DefaultTTL or ExpireNow. The zero value is DefaultTTL. New methods take an Expiry rather than a duration,
+// while the methods taking a duration keep their semantics.
+type Expiry struct {
+	kind expiryKind
+	ttl  time.Duration
+}
+
+// TTL Returns an Expiry making the item expire once the given duration has passed since it was written. A duration
+// of 0 or less makes it expire right away, like ExpireNow, rather than meaning the default of the cache or never.
+func TTL(d time.Duration) Expiry {
+	return Expiry{kind: expiryAfter, ttl: max(d, 0)}
+}
+
+// NoTTL Returns an Expiry making the item never expire.
+func NoTTL() Expiry {
+	return Expiry{kind: expiryNever}
+}
+
+// DefaultTTL Returns an Expiry making the item expire after its own TTL if it is an Expirer, or after the default
+// expiration time of the cache otherwise, like DefaultExpiration.
+func DefaultTTL() Expiry {
+	return Expiry{}
+}
+
+// ExpireNow Returns an Expiry making the item expire as soon as it is written: it is a miss for Get, but remains
+// available to GetStale until deleted.
+func ExpireNow() Expiry {
+	return TTL(0)
+}
+
+// String Returns the expiry as "default", "never" or the duration of its TTL.
+func (e Expiry) String() string {
+	switch e.kind {
+	case expiryNever:
+		return "never"
+	case expiryAfter:
+		return e.ttl.String()
+	default:
+		return "default"
+	}
+}
+
+// expiration Returns the expiration time, in nanoseconds, of an item holding the object written at the given time
+// with the expiry, 0 if it never expires.
+func (c *Cache) expiration(object any, e Expiry, now time.Time) int64 {
+	switch e.kind {
+	case expiryNever:
+		return 0
+	case expiryAfter:
+		return now.Add(e.ttl).UnixNano()
+	default:
+		if d := c.resolveDuration(object, DefaultExpiration); d > 0 {
+			return now.Add(d).UnixNano()
+		}
+		return 0
+	}
+}
+
+// Put Adds an item to the cache like Set, replacing any existing item, expiring as told by the expiry: e.g.
+// Put(key, value, TTL(d)) expires the item right away if the computed duration d is 0, where Set would give it the
+// default expiration time of the cache.
+func (c *Cache) Put(key string, object any, e Expiry) {
+	key = c.hashKey(key)
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	it := c.newItem(key, object, NoExpiration)
+	it.expiration = c.expiration(object, e, time.Unix(0, it.created))
+	c.insert(key, it)
+}
@@ -0,0 +1,100 @@
+package go_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_Put(t *testing.T) {
+	t.Run("computedZero", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(time.Minute, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		var ttl time.Duration
+		tc.Set("aKey", "aValue", ttl)
+		tc.Put("bKey", "bValue", TTL(ttl))
+
+		value, found := tc.Get("aKey")
+		assert.True(t, found)
+		assert.Equal(t, "aValue", value)
+		info, _ := tc.Info("aKey")
+		assert.True(t, clock.Now().Add(time.Minute).Equal(info.Expiration))
+
+		_, found = tc.Get("bKey")
+		assert.False(t, found)
+		value, _, state := tc.GetStale("bKey")
+		assert.Equal(t, StateStale, state)
+		assert.Equal(t, "bValue", value)
+	})
+
+	t.Run("computedNegative", func(t *testing.T) {
+		tc := NewCache(time.Minute, 0)
+		defer tc.Stop()
+
+		ttl := -time.Second
+		tc.Set("aKey", "aValue", ttl)
+		tc.Put("bKey", "bValue", TTL(ttl))
+
+		_, found := tc.Get("aKey")
+		assert.True(t, found)
+		_, found = tc.Get("bKey")
+		assert.False(t, found)
+	})
+
+	t.Run("expiries", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(time.Hour, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Put("aKey", "aValue", TTL(time.Minute))
+		tc.Put("bKey", "bValue", NoTTL())
+		tc.Put("cKey", "cValue", DefaultTTL())
+		tc.Put("dKey", "dValue", Expiry{})
+		tc.Put("eKey", testToken{ttl: 90 * time.Second}, DefaultTTL())
+		tc.Put("fKey", "fValue", ExpireNow())
+
+		info, _ := tc.Info("aKey")
+		assert.True(t, clock.Now().Add(time.Minute).Equal(info.Expiration))
+		info, _ = tc.Info("bKey")
+		assert.True(t, info.Expiration.IsZero())
+		info, _ = tc.Info("cKey")
+		assert.True(t, clock.Now().Add(time.Hour).Equal(info.Expiration))
+		info, _ = tc.Info("dKey")
+		assert.True(t, clock.Now().Add(time.Hour).Equal(info.Expiration))
+		info, _ = tc.Info("eKey")
+		assert.True(t, clock.Now().Add(90*time.Second).Equal(info.Expiration))
+		_, found := tc.Get("fKey")
+		assert.False(t, found)
+
+		clock.Advance(time.Minute)
+		_, found = tc.Get("aKey")
+		assert.False(t, found)
+		_, found = tc.Get("bKey")
+		assert.True(t, found)
+	})
+
+	t.Run("defaultNoExpiration", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(NoExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Put("aKey", "aValue", DefaultTTL())
+		clock.Advance(24 * time.Hour)
+		_, found := tc.Get("aKey")
+		assert.True(t, found)
+	})
+}
+
+func TestExpiry_String(t *testing.T) {
+	assert.Equal(t, "default", DefaultTTL().String())
+	assert.Equal(t, "never", NoTTL().String())
+	assert.Equal(t, "1m0s", TTL(time.Minute).String())
+	assert.Equal(t, "0s", TTL(-time.Minute).String())
+	assert.Equal(t, ExpireNow(), TTL(0))
+}
@@ -0,0 +1,89 @@
+package go_cache
+
+import "time"
+
+// Txn Buffers writes to apply to the cache all at once, see Cache.Tx.
+type Txn struct {
+	c      *Cache
+	writes map[string]txWrite
+	// keys holds the written keys in the order they were first written, so that writes are applied in that order.
+	keys []string
+}
+
+type txWrite struct {
+	object   any
+	duration time.Duration
+	deleted  bool
+}
+
+// Tx Calls fn with a transaction buffering the writes made through it, then applies them all under a single
+// write lock if fn returns nil, so that readers see either none or all of them. If fn returns an error, the
+// writes are discarded and the error is returned. Returns ErrCacheClosed if the cache is stopped before the
+// writes are applied, and an error wrapping ErrValueTooLarge, without applying any write, if one of the values is
+// larger than the size set WithMaxValueSize.
+//
+// Transactions only make their writes atomic: they are not isolated from concurrent writes. Reads made through
+// the transaction see its own writes, and otherwise the current content of the cache, which may change before
+// the writes are applied. Writes of other goroutines made in the meantime are overwritten by those of the
+// transaction.
+func (c *Cache) Tx(fn func(tx *Txn) error) error {
+	tx := &Txn{c: c, writes: make(map[string]txWrite)}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.closed {
+		return ErrCacheClosed
+	}
+	// Oversized values are rejected upfront, so that the transaction is not applied in part.
+	for _, key := range tx.keys {
+		if w := tx.writes[key]; !w.deleted {
+			if err := c.checkSize(c.hashKey(key), w.object); err != nil {
+				return err
+			}
+		}
+	}
+	for _, key := range tx.keys {
+		w, hashed := tx.writes[key], c.hashKey(key)
+		if w.deleted {
+			c.delete(hashed, removalDeleted)
+			c.bury(hashed)
+			continue
+		}
+		c.set(hashed, w.object, w.duration)
+	}
+
+	return nil
+}
+
+// Get Looks up a key's value like Cache.Get, taking the writes made through the transaction into account.
+func (tx *Txn) Get(key string) (any, bool) {
+	if w, found := tx.writes[key]; found {
+		if w.deleted {
+			return nil, false
+		}
+		return w.object, true
+	}
+
+	return tx.c.Get(key)
+}
+
+// Set Buffers a write of the key like Cache.Set, applied when the transaction commits.
+func (tx *Txn) Set(key string, object any, duration time.Duration) {
+	tx.write(key, txWrite{object: object, duration: duration})
+}
+
+// Delete Buffers a deletion of the key like Cache.Delete, applied when the transaction commits.
+func (tx *Txn) Delete(key string) {
+	tx.write(key, txWrite{deleted: true})
+}
+
+func (tx *Txn) write(key string, w txWrite) {
+	if _, found := tx.writes[key]; !found {
+		tx.keys = append(tx.keys, key)
+	}
+	tx.writes[key] = w
+}
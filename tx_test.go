@@ -0,0 +1,127 @@
+package go_cache
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_Tx(t *testing.T) {
+	t.Run("commit", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("cKey", "cValue", NoExpiration)
+		err := tc.Tx(func(tx *Txn) error {
+			tx.Set("aKey", "aValue", NoExpiration)
+			tx.Set("bKey", "bValue", time.Minute)
+			tx.Delete("cKey")
+
+			// The transaction sees its own writes, the cache does not yet.
+			value, found := tx.Get("aKey")
+			assert.True(t, found)
+			assert.Equal(t, "aValue", value)
+			_, found = tx.Get("cKey")
+			assert.False(t, found)
+			_, found = tc.Get("aKey")
+			assert.False(t, found)
+			_, found = tc.Get("cKey")
+			assert.True(t, found)
+			return nil
+		})
+		assert.NoError(t, err)
+
+		value, _ := tc.Get("aKey")
+		assert.Equal(t, "aValue", value)
+		value, _ = tc.Get("bKey")
+		assert.Equal(t, "bValue", value)
+		_, found := tc.Get("cKey")
+		assert.False(t, found)
+	})
+
+	t.Run("readsThrough", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", 1, NoExpiration)
+		assert.NoError(t, tc.Tx(func(tx *Txn) error {
+			value, _ := tx.Get("aKey")
+			tx.Set("aKey", value.(int)+1, NoExpiration)
+			tx.Delete("aKey")
+			tx.Set("aKey", value.(int)+2, NoExpiration)
+			return nil
+		}))
+
+		value, _ := tc.Get("aKey")
+		assert.Equal(t, 3, value)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		errFailed := errors.New("failed")
+		err := tc.Tx(func(tx *Txn) error {
+			tx.Set("aKey", "bValue", NoExpiration)
+			tx.Set("bKey", "bValue", NoExpiration)
+			return errFailed
+		})
+		assert.ErrorIs(t, err, errFailed)
+
+		value, _ := tc.Get("aKey")
+		assert.Equal(t, "aValue", value)
+		assert.Equal(t, 1, tc.ItemCount())
+	})
+
+	t.Run("atomic", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		keys := []string{"object", "byName", "byEmail"}
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1_000; i++ {
+				_ = tc.Tx(func(tx *Txn) error {
+					for _, key := range keys {
+						tx.Set(key, i, NoExpiration)
+					}
+					return nil
+				})
+			}
+		}()
+		for r := 0; r < 4; r++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 1_000; i++ {
+					values := tc.GetMany(keys)
+					if len(values) == 0 {
+						continue
+					}
+					if !assert.Len(t, values, len(keys)) ||
+						!assert.Equal(t, values["object"], values["byName"]) ||
+						!assert.Equal(t, values["object"], values["byEmail"]) {
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		tc.Stop()
+
+		assert.ErrorIs(t, tc.Tx(func(tx *Txn) error {
+			tx.Set("aKey", "aValue", NoExpiration)
+			return nil
+		}), ErrCacheClosed)
+	})
+}
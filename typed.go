@@ -0,0 +1,115 @@
+package go_cache
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// GetString Looks up a key's value from the cache like Get, returning it as a string.
+// Returns ErrItemNotFound error if the key has no live value, and ErrWrongType error if its value is not a string.
+func (c *Cache) GetString(key string) (string, error) {
+	return getAs(c, key, func(value any) (string, error) {
+		s, ok := value.(string)
+		if !ok {
+			return "", typeMismatch("string", value)
+		}
+		return s, nil
+	})
+}
+
+// GetInt64 Looks up a key's value from the cache like Get, returning it as an int64. Values of any integer type are
+// accepted, as well as counters (see Counter), as long as they fit in an int64.
+// Returns ErrItemNotFound error if the key has no live value, and ErrWrongType error if its value is not an integer,
+// or is an unsigned integer larger than math.MaxInt64.
+func (c *Cache) GetInt64(key string) (int64, error) {
+	return getAs(c, key, func(value any) (int64, error) {
+		switch v := value.(type) {
+		case int64:
+			return v, nil
+		case int:
+			return int64(v), nil
+		case int8:
+			return int64(v), nil
+		case int16:
+			return int64(v), nil
+		case int32:
+			return int64(v), nil
+		case uint8:
+			return int64(v), nil
+		case uint16:
+			return int64(v), nil
+		case uint32:
+			return int64(v), nil
+		case uint:
+			return unsignedToInt64(v, uint64(v))
+		case uint64:
+			return unsignedToInt64(v, v)
+		case uintptr:
+			return unsignedToInt64(v, uint64(v))
+		case *Counter:
+			return v.Load(), nil
+		default:
+			return 0, typeMismatch("integer", value)
+		}
+	})
+}
+
+// GetBytes Looks up a key's value from the cache like Get, returning it as a byte slice, which is not copied: it
+// must not be modified. Returns ErrItemNotFound error if the key has no live value, and ErrWrongType error if its
+// value is not a byte slice.
+func (c *Cache) GetBytes(key string) ([]byte, error) {
+	return getAs(c, key, func(value any) ([]byte, error) {
+		b, ok := value.([]byte)
+		if !ok {
+			return nil, typeMismatch("[]byte", value)
+		}
+		return b, nil
+	})
+}
+
+// GetTime Looks up a key's value from the cache like Get, returning it as a time.Time.
+// Returns ErrItemNotFound error if the key has no live value, and ErrWrongType error if its value is not a time.Time.
+func (c *Cache) GetTime(key string) (time.Time, error) {
+	return getAs(c, key, func(value any) (time.Time, error) {
+		t, ok := value.(time.Time)
+		if !ok {
+			return time.Time{}, typeMismatch("time.Time", value)
+		}
+		return t, nil
+	})
+}
+
+// getAs Looks up a key's value from the cache like Get, and converts it with the given function, whose error is
+// wrapped along with ErrWrongType.
+func getAs[T any](c *Cache, key string, convert func(value any) (T, error)) (T, error) {
+	var zero T
+	value, found := c.Get(key)
+	if !found {
+		return zero, fmt.Errorf("%w: %s", ErrItemNotFound, key)
+	}
+	v, err := convert(value)
+	if err != nil {
+		return zero, fmt.Errorf("%w: %s: %w", ErrWrongType, key, err)
+	}
+
+	return v, nil
+}
+
+// typeMismatch Returns the error of a value not of the expected type.
+func typeMismatch(expected string, value any) error {
+	if value == nil {
+		return fmt.Errorf("expected %s, got nil", expected)
+	}
+
+	return fmt.Errorf("expected %s, got %T", expected, value)
+}
+
+// unsignedToInt64 Returns the unsigned integer value, whose value as an uint64 is u, as an int64 if it fits.
+func unsignedToInt64(value any, u uint64) (int64, error) {
+	if u > math.MaxInt64 {
+		return 0, fmt.Errorf("%T %d overflows int64", value, u)
+	}
+
+	return int64(u), nil
+}
@@ -0,0 +1,127 @@
+package go_cache
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_GetString(t *testing.T) {
+	tc := NewCache(DefaultExpiration, 0)
+	defer tc.Stop()
+
+	tc.Set("aKey", "aValue", NoExpiration)
+	tc.Set("bKey", []byte("bValue"), NoExpiration)
+
+	s, err := tc.GetString("aKey")
+	assert.NoError(t, err)
+	assert.Equal(t, "aValue", s)
+
+	_, err = tc.GetString("bKey")
+	assert.ErrorIs(t, err, ErrWrongType)
+	assert.EqualError(t, err, "value is of the wrong type: bKey: expected string, got []uint8")
+
+	_, err = tc.GetString("cKey")
+	assert.ErrorIs(t, err, ErrItemNotFound)
+	assert.NotErrorIs(t, err, ErrWrongType)
+}
+
+func TestCache_GetInt64(t *testing.T) {
+	t.Run("kinds", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		for value, expected := range map[any]int64{
+			int(-42): -42, int8(-42): -42, int16(-42): -42, int32(-42): -42, int64(-42): -42,
+			uint(42): 42, uint8(42): 42, uint16(42): 42, uint32(42): 42, uint64(42): 42, uintptr(42): 42,
+		} {
+			tc.Set("aKey", value, NoExpiration)
+			n, err := tc.GetInt64("aKey")
+			assert.NoError(t, err, "%T", value)
+			assert.Equal(t, expected, n, "%T", value)
+		}
+
+		tc.Set("aKey", uint64(math.MaxInt64), NoExpiration)
+		n, err := tc.GetInt64("aKey")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(math.MaxInt64), n)
+
+		tc.Counter("bKey", NoExpiration).Add(7)
+		n, err = tc.GetInt64("bKey")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), n)
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", uint64(math.MaxInt64+1), NoExpiration)
+		tc.Set("bKey", uint(math.MaxUint), NoExpiration)
+
+		_, err := tc.GetInt64("aKey")
+		assert.ErrorIs(t, err, ErrWrongType)
+		assert.EqualError(t, err, "value is of the wrong type: aKey: uint64 9223372036854775808 overflows int64")
+		_, err = tc.GetInt64("bKey")
+		assert.ErrorIs(t, err, ErrWrongType)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", 4.2, NoExpiration)
+		tc.Set("bKey", nil, NoExpiration)
+
+		_, err := tc.GetInt64("aKey")
+		assert.EqualError(t, err, "value is of the wrong type: aKey: expected integer, got float64")
+		_, err = tc.GetInt64("bKey")
+		assert.EqualError(t, err, "value is of the wrong type: bKey: expected integer, got nil")
+		_, err = tc.GetInt64("cKey")
+		assert.ErrorIs(t, err, ErrItemNotFound)
+	})
+}
+
+func TestCache_GetBytes(t *testing.T) {
+	tc := NewCache(DefaultExpiration, 0)
+	defer tc.Stop()
+
+	data := []byte("aValue")
+	tc.Set("aKey", data, NoExpiration)
+	tc.Set("bKey", "bValue", NoExpiration)
+
+	b, err := tc.GetBytes("aKey")
+	assert.NoError(t, err)
+	assert.Equal(t, data, b)
+
+	_, err = tc.GetBytes("bKey")
+	assert.EqualError(t, err, "value is of the wrong type: bKey: expected []byte, got string")
+
+	_, err = tc.GetBytes("cKey")
+	assert.ErrorIs(t, err, ErrItemNotFound)
+}
+
+func TestCache_GetTime(t *testing.T) {
+	clock := newFakeClock()
+	tc := NewCache(DefaultExpiration, 0)
+	tc.now = clock.Now
+	defer tc.Stop()
+
+	now := clock.Now()
+	tc.Set("aKey", now, NoExpiration)
+	tc.Set("bKey", now.Unix(), NoExpiration)
+	tc.Set("cKey", now, time.Second)
+	clock.Advance(time.Second)
+
+	got, err := tc.GetTime("aKey")
+	assert.NoError(t, err)
+	assert.True(t, now.Equal(got))
+
+	_, err = tc.GetTime("bKey")
+	assert.EqualError(t, err, "value is of the wrong type: bKey: expected time.Time, got int64")
+
+	_, err = tc.GetTime("cKey")
+	assert.ErrorIs(t, err, ErrItemNotFound)
+}
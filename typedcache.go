@@ -0,0 +1,72 @@
+package go_cache
+
+import "time"
+
+// TypedCache A cache whose values are all of type V, so that storing a value of another type is a compile error
+// and reading one needs no type assertion. It wraps a Cache, whose semantics it keeps.
+type TypedCache[V any] struct {
+	c *Cache
+}
+
+// NewTypedCache Returns a new typed cache with a given default expiration duration and cleanup interval,
+// see NewCache.
+func NewTypedCache[V any](defaultExpiration, cleanupInterval time.Duration) *TypedCache[V] {
+	return &TypedCache[V]{c: NewCache(defaultExpiration, cleanupInterval)}
+}
+
+// NewTypedCacheWithOptions Returns a new typed cache configured with the given options, see NewCacheWithOptions.
+func NewTypedCacheWithOptions[V any](opts ...Option) *TypedCache[V] {
+	return &TypedCache[V]{c: NewCacheWithOptions(opts...)}
+}
+
+// Set Adds an item to the cache, replacing any existing item, like Cache.Set.
+func (t *TypedCache[V]) Set(key string, value V, duration time.Duration) {
+	t.c.Set(key, value, duration)
+}
+
+// Add Inserts an item to the cache only if an item doesn't already exist for the given key, like Cache.Add.
+func (t *TypedCache[V]) Add(key string, value V, duration time.Duration) error {
+	return t.c.Add(key, value, duration)
+}
+
+// Replace Sets a new value for the cache only if the given key already exists, like Cache.Replace.
+func (t *TypedCache[V]) Replace(key string, value V, duration time.Duration) error {
+	return t.c.Replace(key, value, duration)
+}
+
+// Get Looks up a key's value from the cache like Cache.Get. If the key has no live value, or holds a value which is
+// not a V, e.g. one set WithInitialItems, the zero value of V is returned along with false.
+func (t *TypedCache[V]) Get(key string) (V, bool) {
+	var zero V
+	value, found := t.c.Get(key)
+	if !found {
+		return zero, false
+	}
+	if value == nil {
+		// A nil value is the zero value of an interface type.
+		return zero, any(zero) == nil
+	}
+	v, ok := value.(V)
+
+	return v, ok
+}
+
+// Delete Removes the provided key from the cache like Cache.Delete.
+func (t *TypedCache[V]) Delete(key string) {
+	t.c.Delete(key)
+}
+
+// ItemCount Returns the number of items in the cache like Cache.ItemCount.
+func (t *TypedCache[V]) ItemCount() int {
+	return t.c.ItemCount()
+}
+
+// Flush Completely clears the cache like Cache.Flush.
+func (t *TypedCache[V]) Flush() {
+	t.c.Flush()
+}
+
+// Stop Stops the cleanup goroutine and closes the cache like Cache.Stop.
+func (t *TypedCache[V]) Stop() {
+	t.c.Stop()
+}
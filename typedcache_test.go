@@ -0,0 +1,121 @@
+package go_cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testUser struct {
+	Name string
+	Age  int
+}
+
+func TestCache_TypedCache(t *testing.T) {
+	t.Run("struct", func(t *testing.T) {
+		tc := NewTypedCache[testUser](DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", testUser{Name: "alice", Age: 30}, NoExpiration)
+		u, found := tc.Get("aKey")
+		assert.True(t, found)
+		assert.Equal(t, testUser{Name: "alice", Age: 30}, u)
+
+		u, found = tc.Get("bKey")
+		assert.False(t, found)
+		assert.Equal(t, testUser{}, u)
+
+		assert.ErrorIs(t, tc.Add("aKey", testUser{Name: "bob"}, NoExpiration), ErrItemAlreadyExists)
+		assert.NoError(t, tc.Replace("aKey", testUser{Name: "bob"}, NoExpiration))
+		assert.ErrorIs(t, tc.Replace("bKey", testUser{Name: "bob"}, NoExpiration), ErrItemNotFound)
+		u, _ = tc.Get("aKey")
+		assert.Equal(t, "bob", u.Name)
+
+		tc.Delete("aKey")
+		_, found = tc.Get("aKey")
+		assert.False(t, found)
+	})
+
+	t.Run("pointer", func(t *testing.T) {
+		tc := NewTypedCache[*testUser](DefaultExpiration, 0)
+		defer tc.Stop()
+
+		alice := &testUser{Name: "alice"}
+		assert.NoError(t, tc.Add("aKey", alice, NoExpiration))
+		tc.Set("bKey", nil, NoExpiration)
+
+		u, found := tc.Get("aKey")
+		assert.True(t, found)
+		assert.Same(t, alice, u)
+
+		u, found = tc.Get("bKey")
+		assert.True(t, found)
+		assert.Nil(t, u)
+
+		u, found = tc.Get("cKey")
+		assert.False(t, found)
+		assert.Nil(t, u)
+	})
+
+	t.Run("interface", func(t *testing.T) {
+		tc := NewTypedCache[fmt.Stringer](DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", time.Second, NoExpiration)
+		tc.Set("bKey", nil, NoExpiration)
+
+		s, found := tc.Get("aKey")
+		assert.True(t, found)
+		assert.Equal(t, "1s", s.String())
+
+		s, found = tc.Get("bKey")
+		assert.True(t, found)
+		assert.Nil(t, s)
+
+		s, found = tc.Get("cKey")
+		assert.False(t, found)
+		assert.Nil(t, s)
+	})
+
+	t.Run("expiration", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewTypedCacheWithOptions[int](WithDefaultExpiration(time.Minute))
+		tc.c.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", 1, DefaultExpiration)
+		tc.Set("bKey", 2, NoExpiration)
+		clock.Advance(time.Minute)
+
+		n, found := tc.Get("aKey")
+		assert.False(t, found)
+		assert.Equal(t, 0, n)
+		n, _ = tc.Get("bKey")
+		assert.Equal(t, 2, n)
+		assert.NoError(t, tc.Add("aKey", 3, NoExpiration))
+
+		assert.Equal(t, 2, tc.ItemCount())
+		tc.Flush()
+		assert.Equal(t, 0, tc.ItemCount())
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		tc := NewTypedCacheWithOptions[int](WithInitialItems(map[string]any{
+			"aKey": "aValue",
+			"bKey": nil,
+			"cKey": 3,
+		}, NoExpiration))
+		defer tc.Stop()
+
+		for _, key := range []string{"aKey", "bKey"} {
+			n, found := tc.Get(key)
+			assert.False(t, found, key)
+			assert.Equal(t, 0, n, key)
+		}
+		n, found := tc.Get("cKey")
+		assert.True(t, found)
+		assert.Equal(t, 3, n)
+	})
+}
@@ -0,0 +1,159 @@
+package go_cache
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// minCleanupInterval is the shortest cleanup interval accepted, below which the cleanup goroutine would keep a CPU
+// busy.
+const minCleanupInterval = time.Microsecond
+
+// NewCacheE Returns a new cache configured with the given options like NewCacheWithOptions, or an error wrapping
+// ErrInvalidOption for each invalid option or combination of options, joined with errors.Join, e.g. a negative
+// duration or limit, a cleanup interval shorter than a microsecond, a ratio or rate out of range, or a maximum error
+// caching duration shorter than the initial one.
+func NewCacheE(opts ...Option) (*Cache, error) {
+	o := applyOptions(opts)
+	if err := o.validate(false); err != nil {
+		return nil, err
+	}
+
+	return newCache(o), nil
+}
+
+// applyOptions Returns the options set by the given functions.
+func applyOptions(opts []Option) options {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// validate Returns an error wrapping ErrInvalidOption for each invalid option, joined with errors.Join. With coerce,
+// invalid options are also replaced by the closest valid value, which keeps the behavior they used to have where
+// it was harmless: negative durations and limits are ignored, ratios and rates are clamped, and a cleanup interval
+// too short is raised to minCleanupInterval.
+func (o *options) validate(coerce bool) error {
+	var errs []error
+	invalid := func(fix func(), format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrInvalidOption, fmt.Sprintf(format, args...)))
+		if coerce && fix != nil {
+			fix()
+		}
+	}
+	nonNegative := func(d *time.Duration, name string) {
+		if *d < 0 {
+			invalid(func() { *d = 0 }, "negative %s %s", name, *d)
+		}
+	}
+
+	if o.defaultExpiration < 0 && o.defaultExpiration != NoExpiration {
+		invalid(func() { o.defaultExpiration = NoExpiration },
+			"negative default expiration %s, other than NoExpiration", o.defaultExpiration)
+	}
+	switch {
+	case o.cleanupInterval < 0:
+		invalid(func() { o.cleanupInterval = 0 }, "negative cleanup interval %s", o.cleanupInterval)
+	case o.cleanupInterval > 0 && o.cleanupInterval < minCleanupInterval:
+		invalid(func() { o.cleanupInterval = minCleanupInterval },
+			"cleanup interval %s shorter than %s", o.cleanupInterval, minCleanupInterval)
+	}
+	if o.flushInterval > 0 && o.cleanupInterval <= 0 {
+		invalid(nil, "periodic flush without a cleanup interval")
+	}
+	nonNegative(&o.maxLifetime, "max lifetime")
+	if o.negativeTTL < 0 && o.negativeTTL != NoExpiration {
+		invalid(func() { o.negativeTTL = NoExpiration }, "negative TTL %s, other than NoExpiration", o.negativeTTL)
+	}
+	nonNegative(&o.tombstoneTTL, "tombstone TTL")
+	nonNegative(&o.writeCoalescing, "write coalescing window")
+	nonNegative(&o.flushInterval, "flush interval")
+	nonNegative(&o.snapshotInterval, "snapshot interval")
+	nonNegative(&o.loadTimeout, "load timeout")
+	nonNegative(&o.errorTTL, "error TTL")
+	if o.maxErrorTTL != 0 && o.maxErrorTTL < o.errorTTL {
+		invalid(func() { o.maxErrorTTL = o.errorTTL },
+			"max error TTL %s shorter than the error TTL %s", o.maxErrorTTL, o.errorTTL)
+	}
+	if o.snapshotInterval > 0 && o.snapshotStore == nil {
+		invalid(nil, "automatic snapshots without a store")
+	}
+
+	if o.maxItems < 0 {
+		invalid(func() { o.maxItems = 0 }, "negative max items %d", o.maxItems)
+	}
+	if o.maxCost < 0 {
+		invalid(func() { o.maxCost = 0 }, "negative max cost %d", o.maxCost)
+	}
+	if o.maxValueSize < 0 {
+		invalid(func() { o.maxValueSize = 0 }, "negative max value size %d", o.maxValueSize)
+	}
+	if o.initialCapacity < 0 {
+		invalid(func() { o.initialCapacity = 0 }, "negative initial capacity %d", o.initialCapacity)
+	}
+	if o.missTracking < 0 {
+		invalid(func() { o.missTracking = 0 }, "negative miss tracking capacity %d", o.missTracking)
+	}
+	if o.changeTracking < 0 {
+		invalid(func() { o.changeTracking = 0 }, "negative change tracking capacity %d", o.changeTracking)
+	}
+	if o.maxConcurrentLoads < 0 {
+		invalid(func() { o.maxConcurrentLoads = 0 }, "negative max concurrent loads %d", o.maxConcurrentLoads)
+	}
+	if o.retryAttempts < 0 {
+		invalid(func() { o.retryAttempts = 0 }, "negative retry attempts %d", o.retryAttempts)
+	}
+	for prefix, maxItems := range o.namespaceQuotas {
+		if maxItems < 0 {
+			invalid(func() { delete(o.namespaceQuotas, prefix) }, "negative quota %d for %q", maxItems, prefix)
+		}
+	}
+	for prefix, maxCost := range o.namespaceBudgets {
+		if maxCost < 0 {
+			invalid(func() { delete(o.namespaceBudgets, prefix) }, "negative cost budget %d for %q", maxCost, prefix)
+		}
+	}
+
+	if o.compactRatio < 0 || o.compactRatio > 1 {
+		invalid(func() { o.compactRatio = min(max(o.compactRatio, 0), 1) },
+			"compaction ratio %g out of [0, 1]", o.compactRatio)
+	}
+	if o.latencySampleRate < 0 || o.latencySampleRate > 1 {
+		invalid(func() { o.latencySampleRate = min(max(o.latencySampleRate, 0), 1) },
+			"latency sample rate %g out of [0, 1]", o.latencySampleRate)
+	}
+	if o.bloomExpectedItems < 0 {
+		invalid(func() { o.bloomExpectedItems = 0 }, "negative bloom filter size %d", o.bloomExpectedItems)
+	}
+	if o.bloomExpectedItems > 0 && (o.bloomFPRate <= 0 || o.bloomFPRate >= 1) {
+		// The filter falls back to a rate of 0.01.
+		invalid(nil, "bloom filter false-positive rate %g out of (0, 1)", o.bloomFPRate)
+	}
+	if o.breakerThreshold < 0 || o.breakerThreshold > 1 {
+		// Such a breaker never opens.
+		invalid(func() { o.breakerThreshold = 0 },
+			"breaker failure threshold %g out of [0, 1]", o.breakerThreshold)
+	}
+	if o.breakerThreshold > 0 && o.breakerWindow <= 0 {
+		invalid(func() { o.breakerThreshold = 0 }, "breaker window %s not positive", o.breakerWindow)
+	}
+	nonNegative(&o.breakerCooldown, "breaker cooldown")
+
+	indexes := o.indexes[:0:0]
+	for _, spec := range o.indexes {
+		if spec.extract == nil {
+			invalid(nil, "index %q without an extract function", spec.name)
+			continue
+		}
+		indexes = append(indexes, spec)
+	}
+	if coerce {
+		o.indexes = indexes
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,104 @@
+package go_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_NewCacheE(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		tc, err := NewCacheE(
+			WithDefaultExpiration(NoExpiration),
+			WithCleanupInterval(time.Minute),
+			WithNegativeTTL(NoExpiration),
+			WithMaxItems(10),
+			WithErrorCaching(time.Second, 0),
+			WithLoaderBreaker(0.5, time.Minute, time.Second),
+			WithBloomFilter(100, 0.01),
+		)
+		assert.NoError(t, err)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		_, found := tc.Get("aKey")
+		assert.True(t, found)
+	})
+
+	for _, tt := range []struct {
+		name string
+		opts []Option
+		err  string
+	}{
+		{"defaultExpiration", []Option{WithDefaultExpiration(-2)},
+			"negative default expiration -2ns, other than NoExpiration"},
+		{"negativeCleanupInterval", []Option{WithCleanupInterval(-time.Second)}, "negative cleanup interval -1s"},
+		{"shortCleanupInterval", []Option{WithCleanupInterval(time.Nanosecond)}, "cleanup interval 1ns shorter than 1µs"},
+		{"flushWithoutCleanup", []Option{WithPeriodicFlush(time.Minute, nil)}, "periodic flush without a cleanup interval"},
+		{"maxLifetime", []Option{WithMaxLifetime(-time.Second)}, "negative max lifetime -1s"},
+		{"negativeTTL", []Option{WithNegativeTTL(-time.Second)}, "negative TTL -1s, other than NoExpiration"},
+		{"tombstones", []Option{WithTombstones(-time.Second)}, "negative tombstone TTL -1s"},
+		{"writeCoalescing", []Option{WithWriteCoalescing(-time.Second)}, "negative write coalescing window -1s"},
+		{"loadTimeout", []Option{WithLoadTimeout(-time.Second)}, "negative load timeout -1s"},
+		{"errorTTL", []Option{WithErrorCaching(-time.Second, 0)}, "negative error TTL -1s"},
+		{"maxErrorTTL", []Option{WithErrorCaching(time.Minute, time.Second)},
+			"max error TTL 1s shorter than the error TTL 1m0s"},
+		{"snapshotWithoutStore", []Option{WithAutoSnapshot(nil, time.Minute)}, "automatic snapshots without a store"},
+		{"maxItems", []Option{WithMaxItems(-1)}, "negative max items -1"},
+		{"maxCost", []Option{WithMaxCost(-1)}, "negative max cost -1"},
+		{"maxValueSize", []Option{WithMaxValueSize(-1)}, "negative max value size -1"},
+		{"initialCapacity", []Option{WithInitialCapacity(-1)}, "negative initial capacity -1"},
+		{"missTracking", []Option{WithMissTracking(-1)}, "negative miss tracking capacity -1"},
+		{"changeTracking", []Option{WithChangeTracking(-1)}, "negative change tracking capacity -1"},
+		{"maxConcurrentLoads", []Option{WithMaxConcurrentLoads(-1)}, "negative max concurrent loads -1"},
+		{"retryAttempts", []Option{WithLoaderRetry(-1, nil, nil)}, "negative retry attempts -1"},
+		{"namespaceQuota", []Option{WithNamespaceQuota("a:", -1)}, `negative quota -1 for "a:"`},
+		{"namespaceBudget", []Option{WithNamespaceCostBudget("a:", -1)}, `negative cost budget -1 for "a:"`},
+		{"compactRatio", []Option{WithAutoCompact(1.5)}, "compaction ratio 1.5 out of [0, 1]"},
+		{"latencySampleRate", []Option{WithLatencyTracking(-0.5)}, "latency sample rate -0.5 out of [0, 1]"},
+		{"bloomSize", []Option{WithBloomFilter(-1, 0.01)}, "negative bloom filter size -1"},
+		{"bloomRate", []Option{WithBloomFilter(100, 1)}, "bloom filter false-positive rate 1 out of (0, 1)"},
+		{"breakerThreshold", []Option{WithLoaderBreaker(2, time.Minute, time.Second)},
+			"breaker failure threshold 2 out of [0, 1]"},
+		{"breakerWindow", []Option{WithLoaderBreaker(0.5, 0, time.Second)}, "breaker window 0s not positive"},
+		{"breakerCooldown", []Option{WithLoaderBreaker(0.5, time.Minute, -time.Second)},
+			"negative breaker cooldown -1s"},
+		{"index", []Option{WithIndex("tenant", nil)}, `index "tenant" without an extract function`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tc, err := NewCacheE(tt.opts...)
+			assert.Nil(t, tc)
+			assert.ErrorIs(t, err, ErrInvalidOption)
+			assert.EqualError(t, err, "invalid option: "+tt.err)
+		})
+	}
+
+	t.Run("joined", func(t *testing.T) {
+		_, err := NewCacheE(WithMaxItems(-1), WithMaxCost(-1))
+		assert.ErrorIs(t, err, ErrInvalidOption)
+		assert.EqualError(t, err, "invalid option: negative max items -1\ninvalid option: negative max cost -1")
+	})
+}
+
+func TestCache_NewCacheWithOptionsCoerced(t *testing.T) {
+	tc := NewCacheWithOptions(
+		WithDefaultExpiration(-2),
+		WithCleanupInterval(time.Nanosecond),
+		WithMaxItems(-1),
+		WithInitialCapacity(-1),
+		WithAutoCompact(1.5),
+		WithIndex("tenant", nil),
+	)
+	defer tc.Stop()
+
+	assert.Equal(t, NoExpiration, tc.DefaultExpirationValue())
+	assert.Equal(t, time.Microsecond, tc.cleanupInterval)
+	assert.Equal(t, 0, tc.MaxItems())
+	assert.Equal(t, 1.0, tc.compactRatio)
+	assert.Empty(t, tc.indexes)
+
+	tc.Set("aKey", "aValue", DefaultExpiration)
+	_, found := tc.Get("aKey")
+	assert.True(t, found)
+}
@@ -0,0 +1,103 @@
+package go_cache
+
+import (
+	"fmt"
+	"time"
+)
+
+// GetWithVersion Looks up a key's value from the cache, like Get, also returning the version of the item.
+// The version of an item starts from 1 and is increased each time a value is written under its key.
+// Once the key is deleted (or expired and cleaned up), a new item written under it starts from 1 again.
+func (c *Cache) GetWithVersion(key string) (any, uint64, bool) {
+	key = c.hashKey(key)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.now().UnixNano()
+	item, found := c.get(key, now)
+	if !found || item.isLeased(now) {
+		return nil, 0, false
+	}
+	if item.accessed != nil {
+		item.accessed.Store(now)
+	}
+
+	return c.value(key, item), item.version, true
+}
+
+// SetIfVersion Sets a new value for the cache only if the current version of the item matches the
+// expected one, as returned by GetWithVersion. An expected version of 0 matches a key which does not exist
+// or has expired. Returns ErrVersionMismatch error otherwise.
+// If the duration is 0 (DefaultExpiration), the cache's default expiration time is used.
+// If it is -1 (NoExpiration), the item never expires.
+// If the duration is positive, the item expires after that time has passed.
+func (c *Cache) SetIfVersion(key string, object any, duration time.Duration, expected uint64) error {
+	key = c.hashKey(key)
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.closed {
+		return ErrCacheClosed
+	}
+	var version uint64
+	if item, found := c.get(key, c.now().UnixNano()); found {
+		version = item.version
+	}
+	if version != expected {
+		return fmt.Errorf("%w: %s: expected %d, got %d", ErrVersionMismatch, key, expected, version)
+	}
+
+	return c.set(key, object, duration)
+}
+
+// SetIfNewer Sets a new value for the cache only if the given version is strictly newer than the one the current
+// item was written with, and reports whether the value was written. This allows applying updates which may
+// arrive out of order without a stale one overwriting a fresher value. A key which does not exist, has expired
+// or was last written without a version (e.g. with Set) always accepts the write, unless it was deleted within
+// the window set WithTombstones after the given version.
+// The version is kept with the item and returned by Info.
+// If the duration is 0 (DefaultExpiration), the cache's default expiration time is used.
+// If it is -1 (NoExpiration), the item never expires.
+// If the duration is positive, the item expires after that time has passed.
+func (c *Cache) SetIfNewer(key string, object any, duration time.Duration, version time.Time) (bool, error) {
+	key = c.hashKey(key)
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	if c.closed {
+		return false, ErrCacheClosed
+	}
+	now := c.now().UnixNano()
+	if item, found := c.get(key, now); found && item.timestamp != 0 && version.UnixNano() <= item.timestamp {
+		return false, nil
+	}
+	if deletedAt, buried := c.deletedAt(key, now); buried && version.UnixNano() <= deletedAt {
+		return false, nil
+	}
+	item := c.newItem(key, object, duration)
+	item.timestamp = version.UnixNano()
+	if err := c.insert(key, item); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Info Returns a description of the item stored under the given key, reporting false if it does not exist
+// or has expired. Unlike Get, it does not count as a read of the item.
+func (c *Cache) Info(key string) (ItemInfo, bool) {
+	key = c.hashKey(key)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	item, found := c.get(key, c.now().UnixNano())
+	if !found {
+		return ItemInfo{}, false
+	}
+
+	return c.info(key, item), true
+}
@@ -0,0 +1,238 @@
+package go_cache
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_GetWithVersion(t *testing.T) {
+	t.Run("bumpedOnEveryWrite", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+
+		a, version, found := tc.GetWithVersion("aKey")
+		assert.Equal(t, "aValue", a)
+		assert.Equal(t, uint64(1), version)
+		assert.True(t, found)
+
+		tc.Set("aKey", "a2Value", DefaultExpiration)
+		err := tc.Replace("aKey", "a3Value", DefaultExpiration)
+		assert.Nil(t, err)
+
+		a, version, found = tc.GetWithVersion("aKey")
+		assert.Equal(t, "a3Value", a)
+		assert.Equal(t, uint64(3), version)
+		assert.True(t, found)
+	})
+
+	t.Run("restartAfterDelete", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		tc.Set("aKey", "a2Value", DefaultExpiration)
+		tc.Delete("aKey")
+
+		_, version, found := tc.GetWithVersion("aKey")
+		assert.Equal(t, uint64(0), version)
+		assert.False(t, found)
+
+		tc.Set("aKey", "a3Value", DefaultExpiration)
+
+		_, version, found = tc.GetWithVersion("aKey")
+		assert.Equal(t, uint64(1), version)
+		assert.True(t, found)
+	})
+}
+
+func TestCache_SetIfVersion(t *testing.T) {
+	t.Run("withMatchingVersion", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		err := tc.SetIfVersion("aKey", "aValue", DefaultExpiration, 0)
+		assert.Nil(t, err)
+
+		err = tc.SetIfVersion("aKey", "a2Value", DefaultExpiration, 1)
+		assert.Nil(t, err)
+
+		a, version, found := tc.GetWithVersion("aKey")
+		assert.Equal(t, "a2Value", a)
+		assert.Equal(t, uint64(2), version)
+		assert.True(t, found)
+	})
+
+	t.Run("withMismatchingVersion", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+
+		err := tc.SetIfVersion("aKey", "a2Value", DefaultExpiration, 0)
+		assert.ErrorIs(t, err, ErrVersionMismatch)
+
+		err = tc.SetIfVersion("aKey", "a2Value", DefaultExpiration, 2)
+		assert.ErrorIs(t, err, ErrVersionMismatch)
+
+		a, found := tc.Get("aKey")
+		assert.Equal(t, "aValue", a)
+		assert.True(t, found)
+	})
+
+	t.Run("withExpiredItem", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", 1*time.Millisecond)
+
+		<-time.After(5 * time.Millisecond)
+
+		err := tc.SetIfVersion("aKey", "a2Value", DefaultExpiration, 1)
+		assert.ErrorIs(t, err, ErrVersionMismatch)
+
+		err = tc.SetIfVersion("aKey", "a2Value", DefaultExpiration, 0)
+		assert.Nil(t, err)
+	})
+
+	t.Run("casRetryLoopUnderContention", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("counter", 0, DefaultExpiration)
+
+		const workers, increments = 20, 50
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < increments; j++ {
+					for {
+						v, version, _ := tc.GetWithVersion("counter")
+						err := tc.SetIfVersion("counter", v.(int)+1, DefaultExpiration, version)
+						if err == nil {
+							break
+						}
+						if !errors.Is(err, ErrVersionMismatch) {
+							t.Errorf("unexpected error: %v", err)
+							return
+						}
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		v, version, found := tc.GetWithVersion("counter")
+		assert.Equal(t, workers*increments, v)
+		assert.Equal(t, uint64(workers*increments+1), version)
+		assert.True(t, found)
+	})
+}
+
+func TestCache_SetIfNewer(t *testing.T) {
+	t.Run("onlyNewerVersionsWin", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		written, err := tc.SetIfNewer("aKey", "v2", DefaultExpiration, base.Add(2*time.Second))
+		assert.NoError(t, err)
+		assert.True(t, written)
+
+		written, err = tc.SetIfNewer("aKey", "v1", DefaultExpiration, base.Add(1*time.Second))
+		assert.NoError(t, err)
+		assert.False(t, written)
+
+		written, err = tc.SetIfNewer("aKey", "v2bis", DefaultExpiration, base.Add(2*time.Second))
+		assert.NoError(t, err)
+		assert.False(t, written)
+
+		value, _ := tc.Get("aKey")
+		assert.Equal(t, "v2", value)
+
+		info, found := tc.Info("aKey")
+		assert.True(t, found)
+		assert.Equal(t, "v2", info.Object)
+		assert.True(t, base.Add(2*time.Second).Equal(info.Timestamp))
+	})
+
+	t.Run("unversionedAndExpiredItemsAccept", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		written, _ := tc.SetIfNewer("aKey", "v1", DefaultExpiration, base)
+		assert.True(t, written)
+
+		tc.Set("aKey", "aValue", 1*time.Second)
+		info, _ := tc.Info("aKey")
+		assert.True(t, info.Timestamp.IsZero())
+		written, _ = tc.SetIfNewer("aKey", "v0", 1*time.Second, base.Add(-1*time.Hour))
+		assert.True(t, written)
+
+		clock.Advance(2 * time.Second)
+		written, _ = tc.SetIfNewer("aKey", "older", DefaultExpiration, base.Add(-2*time.Hour))
+		assert.True(t, written)
+	})
+
+	t.Run("shuffledEvents", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		events := rand.New(rand.NewSource(42)).Perm(100)
+
+		for _, i := range events {
+			_, err := tc.SetIfNewer("aKey", i, DefaultExpiration, base.Add(time.Duration(i)*time.Millisecond))
+			assert.NoError(t, err)
+		}
+
+		value, found := tc.Get("aKey")
+		assert.True(t, found)
+		assert.Equal(t, 99, value)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		tc.Stop()
+
+		written, err := tc.SetIfNewer("aKey", "aValue", DefaultExpiration, time.Now())
+		assert.ErrorIs(t, err, ErrCacheClosed)
+		assert.False(t, written)
+	})
+}
+
+func TestCache_Info(t *testing.T) {
+	clock := newFakeClock()
+	tc := NewCache(DefaultExpiration, 0)
+	tc.now = clock.Now
+	defer tc.Stop()
+
+	tc.Set("aKey", "aValue", 1*time.Minute)
+
+	info, found := tc.Info("aKey")
+	assert.True(t, found)
+	assert.Equal(t, "aValue", info.Object)
+	assert.True(t, clock.Now().Add(1*time.Minute).Equal(info.Expiration))
+	assert.Equal(t, uint64(0), tc.Stats().Hits)
+
+	_, found = tc.Info("bKey")
+	assert.False(t, found)
+
+	clock.Advance(2 * time.Minute)
+	_, found = tc.Info("aKey")
+	assert.False(t, found)
+}
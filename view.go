@@ -0,0 +1,91 @@
+package go_cache
+
+import "sync/atomic"
+
+// View A point-in-time copy of the live items of a cache, see SnapshotView.
+type View struct {
+	c *Cache
+	// items is nil once the view is closed.
+	items atomic.Pointer[map[string]item]
+}
+
+// SnapshotView Returns a consistent point-in-time view of the live items of the cache, unaffected by later writes,
+// deletions or flushes, e.g. to export the items without a concurrent Flush being half seen, as may happen with All.
+// The items are copied under a single acquisition of the read lock, the values themselves being shared with the
+// cache rather than copied, so that the view costs a map of the size of the cache until it is closed. Values
+// spilled to disk (see WithSpillover) are read while the lock is held, since their files may be removed afterwards.
+// The items of the view do not expire.
+func (c *Cache) SnapshotView() *View {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	items := make(map[string]item, len(c.items))
+	if !c.closed {
+		now := c.now().UnixNano()
+		for key := range c.items {
+			it, found := c.get(key, now)
+			if !found || it.isLeased(now) {
+				continue
+			}
+			if _, ok := it.object.(*spillFile); ok {
+				it.object, it.compressed = c.value(key, it), false
+			}
+			if it.weak {
+				// The view holds the values it was taken with.
+				if it.object = c.value(key, it); it.object == nil {
+					continue
+				}
+				it.weak = false
+			}
+			items[key] = it
+		}
+	}
+	v := &View{c: c}
+	v.items.Store(&items)
+
+	return v
+}
+
+// Get Returns the value held under the key when the view was taken, and whether it was found.
+func (v *View) Get(key string) (any, bool) {
+	items := v.items.Load()
+	if items == nil {
+		return nil, false
+	}
+	key = v.c.hashKey(key)
+	it, found := (*items)[key]
+	if !found {
+		return nil, false
+	}
+
+	return v.c.value(key, it), true
+}
+
+// Range Calls fn for each item of the view, in no particular order, until fn returns false.
+// With hashed keys (see WithHashedKeys), the hashes are passed instead of the keys.
+func (v *View) Range(fn func(key string, value any) bool) {
+	items := v.items.Load()
+	if items == nil {
+		return
+	}
+	for key, it := range *items {
+		if !fn(key, v.c.value(key, it)) {
+			return
+		}
+	}
+}
+
+// Len Returns the number of items of the view.
+func (v *View) Len() int {
+	items := v.items.Load()
+	if items == nil {
+		return 0
+	}
+
+	return len(*items)
+}
+
+// Close Releases the items of the view, which is then empty. Calling Close again is a no-op.
+func (v *View) Close() {
+	v.items.Store(nil)
+}
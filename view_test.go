@@ -0,0 +1,106 @@
+package go_cache
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_SnapshotView(t *testing.T) {
+	t.Run("flush", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", "bValue", 1*time.Minute)
+		tc.Set("cKey", "cValue", 1*time.Second)
+		tc.SetNegative("dKey", DefaultExpiration)
+		clock.Advance(2 * time.Second)
+
+		v := tc.SnapshotView()
+		defer v.Close()
+		tc.Flush()
+		tc.Set("aKey", "newValue", NoExpiration)
+		tc.Set("eKey", "eValue", NoExpiration)
+
+		assert.Equal(t, 2, v.Len())
+		value, found := v.Get("aKey")
+		assert.True(t, found)
+		assert.Equal(t, "aValue", value)
+		value, found = v.Get("bKey")
+		assert.True(t, found)
+		assert.Equal(t, "bValue", value)
+		_, found = v.Get("cKey")
+		assert.False(t, found)
+		_, found = v.Get("eKey")
+		assert.False(t, found)
+
+		seen := map[string]any{}
+		v.Range(func(key string, value any) bool {
+			seen[key] = value
+			return true
+		})
+		assert.Equal(t, map[string]any{"aKey": "aValue", "bKey": "bValue"}, seen)
+	})
+
+	t.Run("rangeStops", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("bKey", "bValue", NoExpiration)
+
+		v := tc.SnapshotView()
+		calls := 0
+		v.Range(func(string, any) bool {
+			calls++
+			return false
+		})
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		v := tc.SnapshotView()
+		v.Close()
+		v.Close()
+
+		assert.Equal(t, 0, v.Len())
+		_, found := v.Get("aKey")
+		assert.False(t, found)
+		v.Range(func(string, any) bool {
+			t.Fatal("unexpected item")
+			return true
+		})
+	})
+
+	t.Run("spilledAndCompressed", func(t *testing.T) {
+		dir := t.TempDir()
+		tc := NewCacheWithOptions(WithSpillover(dir, 1024), WithCompression(0, nil))
+		defer tc.Stop()
+
+		spilled := largeValue(4096)
+		compressed := bytes.Repeat([]byte("a"), 512)
+		tc.Set("aKey", spilled, NoExpiration)
+		tc.Set("bKey", compressed, NoExpiration)
+
+		v := tc.SnapshotView()
+		defer v.Close()
+		tc.Flush()
+		assert.Empty(t, spillFiles(t, dir))
+
+		value, found := v.Get("aKey")
+		assert.True(t, found)
+		assert.Equal(t, spilled, value)
+		value, found = v.Get("bKey")
+		assert.True(t, found)
+		assert.Equal(t, compressed, value)
+	})
+}
@@ -0,0 +1,38 @@
+package go_cache
+
+import "time"
+
+// SetVisibleAt Stages a new value for the given key which becomes visible to readers at visibleAt.
+// Until then, Get keeps returning the current value (or misses if there is none), and at visibleAt
+// all readers switch to the new value at once, the old one being dropped.
+// The duration is counted from visibleAt and follows the same rules as Set: if it is 0 (DefaultExpiration),
+// the cache's default expiration time is used, if it is -1 (NoExpiration), the item never expires.
+// Staging again before visibleAt replaces the pending value, while Set discards it.
+func (c *Cache) SetVisibleAt(key string, object any, visibleAt time.Time, duration time.Duration) {
+	key = c.hashKey(key)
+
+	c.mu.Lock()
+	defer c.unlock()
+
+	if !visibleAt.After(c.now()) {
+		c.set(key, object, duration)
+		return
+	}
+
+	duration = c.resolveDuration(object, duration)
+	var expiration int64
+	if duration > 0 {
+		expiration = visibleAt.Add(duration).UnixNano()
+	}
+
+	current, found := c.get(key, c.now().UnixNano())
+	if !found {
+		current = item{placeholder: true}
+	}
+	current.pending = &pendingItem{
+		object:     object,
+		visibleAt:  visibleAt.UnixNano(),
+		expiration: expiration,
+	}
+	c.insert(key, current)
+}
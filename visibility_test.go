@@ -0,0 +1,130 @@
+package go_cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_SetVisibleAt(t *testing.T) {
+	t.Run("flipAtBoundary", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(NoExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "oldValue", DefaultExpiration)
+		tc.SetVisibleAt("aKey", "newValue", clock.Now().Add(10*time.Second), DefaultExpiration)
+
+		clock.Advance(10*time.Second - time.Nanosecond)
+
+		a, found := tc.Get("aKey")
+		assert.Equal(t, "oldValue", a)
+		assert.True(t, found)
+
+		clock.Advance(time.Nanosecond)
+
+		a, found = tc.Get("aKey")
+		assert.Equal(t, "newValue", a)
+		assert.True(t, found)
+	})
+
+	t.Run("withoutPriorValue", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(NoExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.SetVisibleAt("aKey", "newValue", clock.Now().Add(10*time.Second), DefaultExpiration)
+
+		a, found := tc.Get("aKey")
+		assert.Nil(t, a)
+		assert.False(t, found)
+
+		err := tc.Replace("aKey", "otherValue", DefaultExpiration)
+		assert.ErrorIs(t, err, ErrItemNotFound)
+
+		clock.Advance(10 * time.Second)
+
+		a, found = tc.Get("aKey")
+		assert.Equal(t, "newValue", a)
+		assert.True(t, found)
+	})
+
+	t.Run("secondStagedWriteReplacesFirst", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(NoExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("aKey", "oldValue", DefaultExpiration)
+		tc.SetVisibleAt("aKey", "firstValue", clock.Now().Add(10*time.Second), DefaultExpiration)
+		tc.SetVisibleAt("aKey", "secondValue", clock.Now().Add(20*time.Second), DefaultExpiration)
+
+		clock.Advance(10 * time.Second)
+
+		a, found := tc.Get("aKey")
+		assert.Equal(t, "oldValue", a)
+		assert.True(t, found)
+
+		clock.Advance(10 * time.Second)
+
+		a, found = tc.Get("aKey")
+		assert.Equal(t, "secondValue", a)
+		assert.True(t, found)
+	})
+
+	t.Run("expirationCountedFromVisibleAt", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(NoExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.SetVisibleAt("aKey", "newValue", clock.Now().Add(10*time.Second), 5*time.Second)
+
+		clock.Advance(14 * time.Second)
+
+		a, found := tc.Get("aKey")
+		assert.Equal(t, "newValue", a)
+		assert.True(t, found)
+
+		clock.Advance(time.Second)
+
+		a, found = tc.Get("aKey")
+		assert.Nil(t, a)
+		assert.False(t, found)
+	})
+
+	t.Run("setDiscardsPendingValue", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(NoExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.SetVisibleAt("aKey", "newValue", clock.Now().Add(10*time.Second), DefaultExpiration)
+		tc.Set("aKey", "setValue", DefaultExpiration)
+
+		clock.Advance(10 * time.Second)
+
+		a, found := tc.Get("aKey")
+		assert.Equal(t, "setValue", a)
+		assert.True(t, found)
+	})
+
+	t.Run("promotedByCleanUp", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 1*time.Millisecond)
+		defer tc.Stop()
+
+		tc.Set("aKey", "oldValue", DefaultExpiration)
+		tc.SetVisibleAt("aKey", "newValue", time.Now().Add(10*time.Millisecond), DefaultExpiration)
+
+		<-time.After(25 * time.Millisecond)
+
+		tc.mu.RLock()
+		item := tc.items["aKey"]
+		tc.mu.RUnlock()
+		assert.Equal(t, "newValue", item.object)
+		assert.Nil(t, item.pending)
+	})
+}
@@ -0,0 +1,60 @@
+package go_cache
+
+// Visit Calls fn with the value of the key while holding the read lock, so that fn can read or hash a large value,
+// e.g. a []byte, without Get handing out a reference which outlives the lock, nor copying it. Returns false without
+// calling fn if the key has no live value, as Get would report it, the lookup counting in Stats like Get's.
+//
+// fn must follow strict rules: it must not retain the value, nor anything pointing into it, once it returns, since
+// the value may be overwritten or released as soon as the lock is; it must not modify the value; it must not call
+// any method of the cache, which would deadlock on a write and may deadlock on a read; and it must be fast, since
+// every writer waits for it. Values stored compressed or spilled to disk (see WithCompression and WithSpillover) are
+// decoded for fn as they would be for Get.
+func (c *Cache) Visit(key string, fn func(value any)) bool {
+	key = c.hashKey(key)
+	if !c.bloom.mayContain(key) {
+		c.miss(key)
+		return false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.visit(key, c.now().UnixNano(), fn)
+}
+
+// VisitMany Calls fn with each of the given keys holding a live value and its value, like Visit, under a single
+// hold of the read lock, in the order of the keys. fn must follow the same rules as for Visit. The keys without a
+// live value are skipped.
+func (c *Cache) VisitMany(keys []string, fn func(key string, value any)) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.now().UnixNano()
+	for _, key := range keys {
+		hashed := c.hashKey(key)
+		if !c.bloom.mayContain(hashed) {
+			c.miss(hashed)
+			continue
+		}
+		c.visit(hashed, now, func(value any) {
+			fn(key, value)
+		})
+	}
+}
+
+// visit Calls fn with the live value of the key as seen at the given time, reporting false if there is none.
+// It must be called with the read lock held.
+func (c *Cache) visit(key string, now int64, fn func(value any)) bool {
+	item, state := c.lookup(key, now)
+	if state != LookupHit {
+		return false
+	}
+	value := c.value(key, item)
+	if value == nil && item.weak {
+		// Collected since the lookup.
+		return false
+	}
+	fn(value)
+
+	return true
+}
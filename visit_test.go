@@ -0,0 +1,105 @@
+package go_cache
+
+import (
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_Visit(t *testing.T) {
+	t.Run("hit", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		data := []byte("aValue")
+		tc.Set("aKey", data, NoExpiration)
+
+		var visited []byte
+		found := tc.Visit("aKey", func(value any) {
+			visited = value.([]byte)
+		})
+		assert.True(t, found)
+		// The value is not copied, which is why fn must not retain it.
+		assert.Same(t, &data[0], &visited[0])
+		assert.Equal(t, uint64(1), tc.Stats().Hits)
+	})
+
+	t.Run("miss", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(DefaultExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		tc.Set("bKey", "bValue", time.Second)
+		tc.SetNegative("cKey", NoExpiration)
+		clock.Advance(time.Second)
+
+		for _, key := range []string{"aKey", "bKey", "cKey"} {
+			found := tc.Visit(key, func(value any) {
+				assert.Fail(t, "visited", key)
+			})
+			assert.False(t, found, key)
+		}
+		assert.Equal(t, uint64(2), tc.Stats().Misses)
+	})
+
+	t.Run("allocations", func(t *testing.T) {
+		tc := NewCache(DefaultExpiration, 0)
+		defer tc.Stop()
+
+		tc.Set("aKey", make([]byte, 1<<20), NoExpiration)
+		var sum byte
+		allocs := testing.AllocsPerRun(100, func() {
+			tc.Visit("aKey", func(value any) {
+				sum += value.([]byte)[0]
+			})
+		})
+		assert.Equal(t, float64(0), allocs)
+	})
+}
+
+func TestCache_VisitMany(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithHashedKeys()}} {
+		tc := NewCacheWithOptions(opts...)
+
+		tc.Set("aKey", "aValue", NoExpiration)
+		tc.Set("cKey", "cValue", NoExpiration)
+
+		var keys []string
+		var values []any
+		tc.VisitMany([]string{"cKey", "bKey", "aKey"}, func(key string, value any) {
+			keys = append(keys, key)
+			values = append(values, value)
+		})
+		assert.Equal(t, []string{"cKey", "aKey"}, keys)
+		assert.Equal(t, []any{"cValue", "aValue"}, values)
+		assert.Equal(t, uint64(1), tc.Stats().Misses)
+		tc.Stop()
+	}
+}
+
+func BenchmarkCache_Visit(b *testing.B) {
+	tc := NewCache(NoExpiration, 0)
+	defer tc.Stop()
+	tc.Set("aKey", make([]byte, 64<<10), DefaultExpiration)
+
+	b.Run("visit", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			tc.Visit("aKey", func(value any) {
+				sha256.Sum256(value.([]byte))
+			})
+		}
+	})
+
+	b.Run("getAndCopy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			value, _ := tc.Get("aKey")
+			data := append([]byte(nil), value.([]byte)...)
+			sha256.Sum256(data)
+		}
+	})
+}
@@ -0,0 +1,170 @@
+package go_cache
+
+import (
+	"context"
+	"strings"
+)
+
+// watchBufferSize Number of events buffered per watcher before older ones get dropped.
+const watchBufferSize = 16
+
+// WatchOp The kind of change reported by a WatchEvent.
+type WatchOp int
+
+const (
+	// WatchSet A value was written under a key which held no live value.
+	WatchSet WatchOp = iota + 1
+	// WatchReplace A value was written under a key which held a live value.
+	WatchReplace
+	// WatchDelete The item was removed by Delete, Flush or another explicit removal.
+	WatchDelete
+	// WatchExpire The item was removed by the cleanup goroutine or DeleteExpired after expiring.
+	WatchExpire
+)
+
+// WatchEvent A change of the item stored under a watched key.
+type WatchEvent struct {
+	Key string
+	Op  WatchOp
+	// Value The value written for WatchSet and WatchReplace, or the value removed for WatchDelete and WatchExpire.
+	Value any
+}
+
+type watcher struct {
+	// key is the watched key, or the watched prefix for prefix watchers.
+	key    string
+	prefix bool
+	events chan WatchEvent
+}
+
+// Watch Returns a channel receiving the changes of the item stored under the given key until ctx is done or the
+// cache is stopped, at which point the channel is closed. Changes are reported when they happen, and an item
+// which expires is only reported as such once it is removed by the cleanup goroutine or DeleteExpired.
+// Likewise, a value staged with SetVisibleAt is reported once promoted by the cleanup goroutine or DeleteExpired.
+// Each watcher has a buffer of a few events: when a consumer falls behind, the oldest pending event is dropped
+// to make room for the new one, so that the latest change is always delivered.
+func (c *Cache) Watch(ctx context.Context, key string) <-chan WatchEvent {
+	w := &watcher{key: key, events: make(chan WatchEvent, watchBufferSize)}
+	key = c.hashKey(key)
+
+	c.mu.Lock()
+	if c.watchers[key] == nil {
+		c.watchers[key] = make(map[*watcher]struct{})
+	}
+	c.watchers[key][w] = struct{}{}
+	c.mu.Unlock()
+
+	c.unwatchWhenDone(ctx, w, func() {
+		delete(c.watchers[key], w)
+		if len(c.watchers[key]) == 0 {
+			delete(c.watchers, key)
+		}
+	})
+
+	return w.events
+}
+
+// WatchPrefix Returns a channel receiving the changes of all the items whose key starts with the given prefix,
+// with the same semantics as Watch. Events carry the full key of the item which changed. Overlapping prefixes
+// watched separately each receive their own copy of an event, while a single channel never receives an event
+// twice. With hashed keys (see WithHashedKeys), keys cannot be matched and no event is ever received.
+func (c *Cache) WatchPrefix(ctx context.Context, prefix string) <-chan WatchEvent {
+	w := &watcher{key: prefix, prefix: true, events: make(chan WatchEvent, watchBufferSize)}
+
+	c.mu.Lock()
+	if c.hasher == nil {
+		c.prefixWatchers[w] = struct{}{}
+	}
+	c.mu.Unlock()
+
+	c.unwatchWhenDone(ctx, w, func() {
+		delete(c.prefixWatchers, w)
+	})
+
+	return w.events
+}
+
+// unwatchWhenDone Unregisters the watcher with the given function and closes its channel once ctx is done or the
+// cache is stopped.
+func (c *Cache) unwatchWhenDone(ctx context.Context, w *watcher, unregister func()) {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+
+		select {
+		case <-ctx.Done():
+		case <-c.stop:
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		unregister()
+		close(w.events)
+	}()
+}
+
+// watched Reports whether any watcher is registered, so that changes need to be notified.
+func (c *Cache) watched() bool {
+	return len(c.watchers) > 0 || len(c.prefixWatchers) > 0
+}
+
+// notify Sends the given change to the watchers of the key, unless it is a write held back WithWriteCoalescing.
+// It must be called with the write lock held.
+func (c *Cache) notify(key string, op WatchOp, value any) {
+	if c.coalescer != nil {
+		if op == WatchSet || op == WatchReplace {
+			c.coalesce(key, op, value)
+			return
+		}
+		c.endWindow(key)
+	}
+	c.send(key, op, value)
+}
+
+// send Sends the given change to the watchers of the key. It must be called with the write lock held.
+func (c *Cache) send(key string, op WatchOp, value any) {
+	for w := range c.watchers[key] {
+		w.send(WatchEvent{Key: w.key, Op: op, Value: value})
+	}
+	for w := range c.prefixWatchers {
+		if strings.HasPrefix(key, w.key) {
+			w.send(WatchEvent{Key: key, Op: op, Value: value})
+		}
+	}
+}
+
+// notifyFlush Notifies the deletion of every watched live item, before the cache is flushed.
+func (c *Cache) notifyFlush() {
+	now := c.now().UnixNano()
+	if len(c.prefixWatchers) == 0 {
+		for key := range c.watchers {
+			if it, found := c.get(key, now); found {
+				c.notify(key, WatchDelete, c.value(key, it))
+			}
+		}
+		return
+	}
+
+	for key := range c.items {
+		if it, found := c.get(key, now); found {
+			c.notify(key, WatchDelete, c.value(key, it))
+		}
+	}
+}
+
+func (w *watcher) send(event WatchEvent) {
+	select {
+	case w.events <- event:
+		return
+	default:
+	}
+
+	select {
+	case <-w.events:
+	default:
+	}
+	select {
+	case w.events <- event:
+	default:
+	}
+}
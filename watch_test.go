@@ -0,0 +1,212 @@
+package go_cache
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func receive(t *testing.T, events <-chan WatchEvent) WatchEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(1 * time.Second):
+		t.Fatal("no event received")
+		return WatchEvent{}
+	}
+}
+
+func assertClosed(t *testing.T, events <-chan WatchEvent) {
+	t.Helper()
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok)
+	case <-time.After(1 * time.Second):
+		t.Fatal("channel not closed")
+	}
+}
+
+func TestCache_Watch(t *testing.T) {
+	t.Run("twoWatchersSeeSameSequence", func(t *testing.T) {
+		clock := newFakeClock()
+		tc := NewCache(NoExpiration, 0)
+		tc.now = clock.Now
+		defer tc.Stop()
+
+		ctx := context.Background()
+		first := tc.Watch(ctx, "aKey")
+		second := tc.Watch(ctx, "aKey")
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		tc.Set("bKey", "bValue", DefaultExpiration)
+		err := tc.Replace("aKey", "a2Value", 10*time.Second)
+		assert.Nil(t, err)
+		clock.Advance(10 * time.Second)
+		tc.DeleteExpired()
+		tc.Set("aKey", "a3Value", DefaultExpiration)
+		tc.Delete("aKey")
+
+		expected := []WatchEvent{
+			{Key: "aKey", Op: WatchSet, Value: "aValue"},
+			{Key: "aKey", Op: WatchReplace, Value: "a2Value"},
+			{Key: "aKey", Op: WatchExpire, Value: "a2Value"},
+			{Key: "aKey", Op: WatchSet, Value: "a3Value"},
+			{Key: "aKey", Op: WatchDelete, Value: "a3Value"},
+		}
+		for _, events := range []<-chan WatchEvent{first, second} {
+			for _, event := range expected {
+				assert.Equal(t, event, receive(t, events))
+			}
+		}
+	})
+
+	t.Run("cancelOneWatcher", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		first := tc.Watch(ctx, "aKey")
+		second := tc.Watch(context.Background(), "aKey")
+
+		cancel()
+		assertClosed(t, first)
+
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		assert.Equal(t, WatchEvent{Key: "aKey", Op: WatchSet, Value: "aValue"}, receive(t, second))
+	})
+
+	t.Run("noLeakedWatchers", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		events := tc.Watch(ctx, "aKey")
+		cancel()
+		assertClosed(t, events)
+
+		tc.mu.RLock()
+		assert.Empty(t, tc.watchers)
+		tc.mu.RUnlock()
+	})
+
+	t.Run("stopClosesWatchers", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+
+		first := tc.Watch(context.Background(), "aKey")
+		second := tc.Watch(context.Background(), "bKey")
+
+		tc.Stop()
+
+		assertClosed(t, first)
+		assertClosed(t, second)
+	})
+
+	t.Run("slowConsumerKeepsLatest", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		events := tc.Watch(context.Background(), "aKey")
+
+		for i := 0; i < 3*watchBufferSize; i++ {
+			tc.Set("aKey", i, DefaultExpiration)
+		}
+
+		var last WatchEvent
+		for i := 0; i < watchBufferSize; i++ {
+			last = receive(t, events)
+		}
+		assert.Equal(t, 3*watchBufferSize-1, last.Value)
+		assert.Len(t, events, 0)
+	})
+
+	t.Run("withFlush", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		events := tc.Watch(context.Background(), "aKey")
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		tc.Flush()
+
+		assert.Equal(t, WatchSet, receive(t, events).Op)
+		assert.Equal(t, WatchEvent{Key: "aKey", Op: WatchDelete, Value: "aValue"}, receive(t, events))
+	})
+
+	t.Run("withHashedKeys", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithHashedKeys())
+		defer tc.Stop()
+
+		events := tc.Watch(context.Background(), "aKey")
+		tc.Set("aKey", "aValue", DefaultExpiration)
+		for i := 0; i < 10; i++ {
+			tc.Set(strconv.Itoa(i), i, DefaultExpiration)
+		}
+
+		assert.Equal(t, WatchEvent{Key: "aKey", Op: WatchSet, Value: "aValue"}, receive(t, events))
+		assert.Len(t, events, 0)
+	})
+}
+
+func TestCache_WatchPrefix(t *testing.T) {
+	t.Run("overlappingPrefixes", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		flags := tc.WatchPrefix(context.Background(), "flags:")
+		beta := tc.WatchPrefix(context.Background(), "flags:beta:")
+
+		tc.Set("flags:alpha", 1, DefaultExpiration)
+		tc.Set("flags:beta:x", 2, DefaultExpiration)
+		tc.Set("dns:a", 3, DefaultExpiration)
+		tc.Delete("flags:beta:x")
+
+		assert.Equal(t, WatchEvent{Key: "flags:alpha", Op: WatchSet, Value: 1}, receive(t, flags))
+		assert.Equal(t, WatchEvent{Key: "flags:beta:x", Op: WatchSet, Value: 2}, receive(t, flags))
+		assert.Equal(t, WatchEvent{Key: "flags:beta:x", Op: WatchDelete, Value: 2}, receive(t, flags))
+		assert.Len(t, flags, 0)
+
+		assert.Equal(t, WatchEvent{Key: "flags:beta:x", Op: WatchSet, Value: 2}, receive(t, beta))
+		assert.Equal(t, WatchEvent{Key: "flags:beta:x", Op: WatchDelete, Value: 2}, receive(t, beta))
+		assert.Len(t, beta, 0)
+	})
+
+	t.Run("withKeyWatcher", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+		defer tc.Stop()
+
+		key := tc.Watch(context.Background(), "flags:alpha")
+		prefix := tc.WatchPrefix(context.Background(), "flags:")
+
+		tc.Set("flags:alpha", 1, DefaultExpiration)
+		tc.Flush()
+
+		assert.Equal(t, WatchEvent{Key: "flags:alpha", Op: WatchSet, Value: 1}, receive(t, key))
+		assert.Equal(t, WatchEvent{Key: "flags:alpha", Op: WatchDelete, Value: 1}, receive(t, key))
+		assert.Len(t, key, 0)
+
+		assert.Equal(t, WatchEvent{Key: "flags:alpha", Op: WatchSet, Value: 1}, receive(t, prefix))
+		assert.Equal(t, WatchEvent{Key: "flags:alpha", Op: WatchDelete, Value: 1}, receive(t, prefix))
+		assert.Len(t, prefix, 0)
+	})
+
+	t.Run("cancelAndStop", func(t *testing.T) {
+		tc := NewCache(NoExpiration, 0)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		first := tc.WatchPrefix(ctx, "flags:")
+		second := tc.WatchPrefix(context.Background(), "flags:")
+
+		cancel()
+		assertClosed(t, first)
+
+		tc.mu.RLock()
+		assert.Len(t, tc.prefixWatchers, 1)
+		tc.mu.RUnlock()
+
+		tc.Stop()
+		assertClosed(t, second)
+	})
+}
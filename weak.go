@@ -0,0 +1,54 @@
+package go_cache
+
+import (
+	"reflect"
+	"unsafe"
+	"weak"
+)
+
+// weakValue A pointer value held weakly, see WithWeakValues. Two weak values made from the same pointer are equal.
+type weakValue struct {
+	ptr weak.Pointer[byte]
+	typ reflect.Type
+}
+
+// makeWeak Returns a weak reference to the object if it is a non-nil pointer to a value of non-zero size, other than
+// a counter of the cache (see Counter), which only the cache holds.
+func makeWeak(object any) (weakValue, bool) {
+	if _, ok := object.(*Counter); ok {
+		return weakValue{}, false
+	}
+	v := reflect.ValueOf(object)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Type().Elem().Size() == 0 {
+		return weakValue{}, false
+	}
+
+	return weakValue{ptr: weak.Make((*byte)(v.UnsafePointer())), typ: v.Type()}, true
+}
+
+// value Returns the pointer the weak value was made from, or nil once the garbage collector reclaimed its target.
+func (w weakValue) value() any {
+	p := w.ptr.Value()
+	if p == nil {
+		return nil
+	}
+
+	return reflect.NewAt(w.typ.Elem(), unsafe.Pointer(p)).Interface()
+}
+
+// collected Reports whether the garbage collector reclaimed the target of the weak value.
+func (w weakValue) collected() bool {
+	return w.ptr.Value() == nil
+}
+
+// weaken Returns the item holding its value weakly if it is a pointer, see WithWeakValues.
+func (c *Cache) weaken(it item) item {
+	if it.placeholder || it.negative {
+		return it
+	}
+	if w, ok := makeWeak(it.object); ok {
+		it.object, it.weak = w, true
+	}
+
+	return it
+}
@@ -0,0 +1,97 @@
+package go_cache
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// weakBlob A value large enough not to share its allocation with other small values.
+type weakBlob struct {
+	data [256]byte
+}
+
+func (b *weakBlob) OnEvict() {
+	b.data[0] = 1
+}
+
+// setWeakBlob Sets a new blob under the key, keeping no reference to it.
+func setWeakBlob(tc *Cache, key string) {
+	tc.Set(key, &weakBlob{}, NoExpiration)
+}
+
+func TestCache_WithWeakValues(t *testing.T) {
+	t.Run("collected", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithWeakValues())
+		defer tc.Stop()
+
+		setWeakBlob(tc, "aKey")
+		assert.Eventually(t, func() bool {
+			runtime.GC()
+			_, found := tc.Get("aKey")
+			return !found
+		}, 5*time.Second, time.Millisecond)
+
+		assert.Equal(t, 1, tc.ItemCount())
+		tc.DeleteExpired()
+		assert.Equal(t, 0, tc.ItemCount())
+		assert.Equal(t, uint64(1), tc.Stats().Removals.Expired)
+	})
+
+	t.Run("referenced", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithWeakValues())
+		defer tc.Stop()
+
+		value := &weakBlob{}
+		tc.Set("aKey", value, NoExpiration)
+		runtime.GC()
+		runtime.GC()
+
+		got, found := tc.Get("aKey")
+		assert.True(t, found)
+		assert.Same(t, value, got)
+		runtime.KeepAlive(value)
+	})
+
+	t.Run("notPointers", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithWeakValues())
+		defer tc.Stop()
+
+		tc.Set("aKey", weakBlob{}, NoExpiration)
+		tc.Set("bKey", []byte("bValue"), NoExpiration)
+		tc.Counter("cKey", NoExpiration)
+		runtime.GC()
+		runtime.GC()
+
+		for _, key := range []string{"aKey", "bKey", "cKey"} {
+			_, found := tc.Get(key)
+			assert.True(t, found, key)
+		}
+	})
+
+	t.Run("closeOnEvict", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithWeakValues(), WithCloseOnEvict())
+		defer tc.Stop()
+
+		value := &weakBlob{}
+		tc.Set("aKey", value, NoExpiration)
+		tc.Set("aKey", value, NoExpiration)
+		assert.Equal(t, byte(0), value.data[0])
+		tc.Delete("aKey")
+		assert.Equal(t, byte(1), value.data[0])
+	})
+
+	t.Run("janitor", func(t *testing.T) {
+		tc := NewCacheWithOptions(WithWeakValues(), WithCleanupInterval(time.Millisecond))
+		defer tc.Stop()
+
+		setWeakBlob(tc, "aKey")
+		setWeakBlob(tc, "bKey")
+		assert.Eventually(t, func() bool {
+			runtime.GC()
+			return tc.ItemCount() == 0
+		}, 5*time.Second, time.Millisecond)
+	})
+}